@@ -3,17 +3,22 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	_ "todo-api/docs"
+	"todo-api/docs"
 	"todo-api/internal/config"
 	"todo-api/internal/database"
+	"todo-api/internal/domain"
+	"todo-api/internal/graphql"
+	"todo-api/internal/grpc"
 	httphandlers "todo-api/internal/http"
 	"todo-api/internal/http/handlers"
+	"todo-api/internal/http/middleware"
 	"todo-api/internal/service"
 	"todo-api/internal/storage/postgres"
 )
@@ -31,16 +36,168 @@ func main() {
 	defer pool.Close()
 	log.Println("Connected to database")
 
+	txManager := postgres.NewTxManager(pool)
 	repo := postgres.NewListRepo(pool)
 	taskRepo := postgres.NewTaskRepo(pool)
+	searchRepo := postgres.NewSearchRepo(pool)
+	viewRepo := postgres.NewViewRepo(pool)
+	statsRepo := postgres.NewStatsRepo(pool)
+	auditRepo := postgres.NewAuditRepo(pool)
+	undoRepo := postgres.NewUndoRepo(pool)
+	webhookRepo := postgres.NewWebhookRepo(pool)
+	syncRepo := postgres.NewSyncRepo(pool)
+	exportRepo := postgres.NewExportRepo(pool)
+	calendarRepo := postgres.NewCalendarRepo(pool)
+	notificationRepo := postgres.NewNotificationRepo(pool)
+	notificationSvc := service.NewNotificationService(notificationRepo, repo)
+	reminderRepo := postgres.NewReminderRepo(pool)
+	jobRepo := postgres.NewJobRepo(pool)
+	ruleRepo := postgres.NewRuleRepo(pool)
+	templateRepo := postgres.NewTemplateRepo(pool)
+	folderRepo := postgres.NewFolderRepo(pool)
 
-	svc := service.NewListService(repo)
-	taskSvc := service.NewTaskService(taskRepo, repo)
+	// Фоновые задачи: обработчики регистрируются до запуска очереди.
+	jobs := service.NewJobQueue(jobRepo, txManager, cfg.JobWorkers, cfg.JobMaxAttempts)
+	jobs.Timeout = cfg.JobTimeout
+	jobs.HandlePurge(cfg.JobRetention)
+	if err := jobs.Schedule("jobs.purge", "30 3 * * *", service.PurgeJobsArgs{}, service.JobOptions{UniqueKey: "jobs.purge"}); err != nil {
+		log.Fatalf("Invalid job schedule: %v", err)
+	}
+	// Правила автоматизации выполняются в очереди фоновых задач.
+	rules := service.NewRuleEngine(ruleRepo, taskRepo, txManager, jobs)
 
-	listHandler := handlers.NewListHandler(svc)
-	taskHandler := handlers.NewTaskHandler(taskSvc)
+	// События вебхуков пишутся в outbox в транзакции изменения.
+	hooks := service.ChangeHooks{service.NewWebhookOutbox(webhookRepo), rules}
+	// Уведомления о назначениях и упоминаниях — тоже, если настроен SMTP-релей.
+	notify := cfg.SMTPHost != ""
+	if notify {
+		hooks = append(hooks, service.NewNotificationOutbox(notificationSvc))
+	} else {
+		log.Println("SMTP_HOST is not set, email notifications are disabled")
+	}
+	undoLog := service.NewUndoLog(undoRepo, cfg.UndoWindow)
+	svc := service.NewListService(repo, taskRepo, txManager, auditRepo, undoLog, hooks)
+	taskSvc := service.NewTaskService(taskRepo, repo, txManager, auditRepo, undoLog, hooks)
+	quickAddSvc := service.NewQuickAddService(taskSvc, notificationRepo)
+	rules.SetServices(taskSvc, svc)
+	ruleSvc := service.NewRuleService(ruleRepo, repo, taskRepo)
+	searchSvc, err := service.NewSearchService(searchRepo, cfg.SearchSimilarityThreshold)
+	if err != nil {
+		log.Fatalf("Invalid SEARCH_SIMILARITY_THRESHOLD: %v", err)
+	}
+	viewSvc := service.NewViewService(viewRepo, taskRepo)
+	statsSvc := service.NewStatsService(statsRepo, repo)
+	auditSvc := service.NewAuditService(auditRepo)
+	undoSvc := service.NewUndoService(undoRepo, repo, taskRepo, txManager, auditRepo, hooks)
+	undoPurgeCtx, stopUndoPurge := context.WithCancel(ctx)
+	defer stopUndoPurge()
+	go undoSvc.RunPurge(undoPurgeCtx, time.Hour)
+	webhookSvc := service.NewWebhookService(webhookRepo)
+	syncSvc := service.NewSyncService(syncRepo, repo, taskRepo, txManager, auditRepo, hooks)
+	exportSvc := service.NewExportService(exportRepo, repo)
+	importSvc := service.NewImportService(svc, taskSvc, txManager)
+	templateSvc := service.NewTemplateService(templateRepo, taskRepo, svc, taskSvc, txManager, notificationRepo)
+	folderSvc := service.NewFolderService(folderRepo, svc, txManager)
+	calendarSvc := service.NewCalendarService(calendarRepo, repo, taskSvc, txManager)
+	reminderSvc := service.NewReminderService(reminderRepo, taskRepo)
 
-	router := httphandlers.NewRouter(listHandler, taskHandler)
+	// События изменений приходят через LISTEN от всех экземпляров сервиса.
+	broker := service.NewEventBroker(cfg.SSEReplaySize)
+	listenCtx, stopListening := context.WithCancel(ctx)
+	defer stopListening()
+	go postgres.NewChangeListener(pool).Listen(listenCtx, broker.Publish)
+	collab := handlers.NewCollabHandler(taskSvc, svc, broker, service.NewPresenceHub())
+
+	dispatcher := service.NewWebhookDispatcher(webhookRepo, service.NewWebhookClient(cfg.WebhookTimeout), cfg.WebhookMaxAttempts)
+	dispatchCtx, stopDispatching := context.WithCancel(ctx)
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatcher.Run(dispatchCtx, cfg.WebhookPollInterval)
+	}()
+
+	// Письма отправляются в фоне, не в запросах к API.
+	notifyCtx, stopNotifying := context.WithCancel(ctx)
+	notifierDone := make(chan struct{})
+	if notify {
+		mailer, err := service.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		if err != nil {
+			log.Fatalf("Invalid SMTP settings: %v", err)
+		}
+		mailer.Timeout = cfg.SMTPTimeout
+		notifier := service.NewNotificationDispatcher(notificationRepo, notificationSvc, mailer, cfg.NotifyMaxAttempts)
+		scheduler := service.NewNotificationScheduler(notificationSvc, notificationRepo, cfg.NotifyDueSoon, cfg.NotifyDigestHour)
+		go scheduler.Run(notifyCtx, time.Minute)
+		go func() {
+			defer close(notifierDone)
+			notifier.Run(notifyCtx, cfg.NotifyPollInterval)
+		}()
+	} else {
+		close(notifierDone)
+	}
+
+	// Напоминания срабатывают в транзакции, которая захватывает их с SKIP
+	// LOCKED, поэтому планировщик работает на каждом экземпляре сервиса.
+	reminderChannels := map[string]service.ReminderChannel{
+		domain.ReminderChannelWebhook: service.NewWebhookReminderChannel(webhookRepo),
+		domain.ReminderChannelSSE:     service.NewSSEReminderChannel(reminderRepo),
+	}
+	if notify {
+		reminderChannels[domain.ReminderChannelEmail] = service.NewEmailReminderChannel(notificationSvc)
+	}
+	reminders := service.NewReminderScheduler(reminderRepo, txManager, reminderChannels)
+	remindCtx, stopReminding := context.WithCancel(ctx)
+	remindersDone := make(chan struct{})
+	go func() {
+		defer close(remindersDone)
+		reminders.Run(remindCtx, cfg.ReminderPollInterval)
+	}()
+
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		jobs.Run(jobsCtx, cfg.JobPollInterval)
+	}()
+
+	gql, err := graphql.NewHandler(svc, taskSvc, broker, graphql.Config{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+		Heartbeat:     cfg.SSEHeartbeat,
+	})
+	if err != nil {
+		log.Fatalf("Failed to build GraphQL schema: %v", err)
+	}
+
+	openAPI, err := middleware.NewOpenAPIValidator(docs.OpenAPI, false)
+	if err != nil {
+		log.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	router := httphandlers.NewRouter(httphandlers.Handlers{
+		Lists:         handlers.NewListHandler(svc),
+		Tasks:         handlers.NewTaskHandler(taskSvc, quickAddSvc),
+		Search:        handlers.NewSearchHandler(searchSvc),
+		Views:         handlers.NewViewHandler(viewSvc),
+		Stats:         handlers.NewStatsHandler(statsSvc),
+		Audit:         handlers.NewAuditHandler(auditSvc),
+		Undo:          handlers.NewUndoHandler(undoSvc),
+		Events:        handlers.NewEventHandler(broker, svc, cfg.SSEHeartbeat),
+		Collab:        collab,
+		Webhooks:      handlers.NewWebhookHandler(webhookSvc),
+		Sync:          handlers.NewSyncHandler(syncSvc),
+		Export:        handlers.NewExportHandler(exportSvc),
+		Import:        handlers.NewImportHandler(importSvc),
+		Calendar:      handlers.NewCalendarHandler(calendarSvc),
+		Notifications: handlers.NewNotificationHandler(notificationSvc),
+		Reminders:     handlers.NewReminderHandler(reminderSvc),
+		Jobs:          handlers.NewJobHandler(jobs, cfg.AdminUsers),
+		Rules:         handlers.NewRuleHandler(ruleSvc),
+		Templates:     handlers.NewTemplateHandler(templateSvc),
+		Folders:       handlers.NewFolderHandler(folderSvc),
+		GraphQL:       gql,
+		OpenAPI:       openAPI,
+	})
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -49,6 +206,26 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Shutdown не дожидается открытых SSE-потоков и WebSocket-соединений:
+	// их нужно завершить самим.
+	server.RegisterOnShutdown(func() {
+		stopListening()
+		collab.Close()
+		broker.Close()
+	})
+
+	// gRPC-API обслуживается тем же процессом на отдельном порту.
+	grpcServer := grpc.NewServer(svc, taskSvc, broker)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port: %v", err)
+	}
+	go func() {
+		log.Printf("Starting gRPC server on port %s...", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("gRPC server error: %v", err)
+		}
+	}()
 
 	go func() {
 		log.Printf("Starting server on port %s...", cfg.Port)
@@ -65,9 +242,31 @@ func main() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Очередь перестаёт захватывать задачи и доделывает начатые, пока
+	// сервер завершает запросы.
+	stopJobs()
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	grpcServer.Shutdown(shutdownCtx)
+
+	// Начатые доставки вебхуков завершаются, новые не захватываются.
+	stopDispatching()
+	<-dispatcherDone
+	stopReminding()
+	<-remindersDone
+	stopNotifying()
+	<-notifierDone
+	// У ожидания задач свой срок: shutdownCtx мог истечь, пока сервер
+	// завершал запросы. Задачу, не успевшую завершиться, после JOB_TIMEOUT
+	// подхватит другой экземпляр сервиса.
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.JobDrainTimeout)
+	defer cancelDrain()
+	select {
+	case <-jobsDone:
+	case <-drainCtx.Done():
+		log.Println("Background jobs did not finish before drain timeout")
+	}
 
 	log.Println("Server stopped")
 }
@@ -0,0 +1,9 @@
+package docs
+
+import _ "embed"
+
+// OpenAPI — спецификация REST API из openapi.yaml, встроенная в бинарник:
+// по ней проверяются запросы, и файл не нужен в образе.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
@@ -0,0 +1,2688 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Defines values for QuickAddPartKind.
+const (
+	QuickAddDate       QuickAddPartKind = "date"
+	QuickAddLabel      QuickAddPartKind = "label"
+	QuickAddPriority   QuickAddPartKind = "priority"
+	QuickAddRecurrence QuickAddPartKind = "recurrence"
+	QuickAddTime       QuickAddPartKind = "time"
+)
+
+// Defines values for TaskPriority.
+const (
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityMedium TaskPriority = "medium"
+)
+
+// Defines values for TaskRecurrence.
+const (
+	RecurrenceDaily   TaskRecurrence = "daily"
+	RecurrenceMonthly TaskRecurrence = "monthly"
+	RecurrenceWeekly  TaskRecurrence = "weekly"
+	RecurrenceYearly  TaskRecurrence = "yearly"
+)
+
+// CloneListRequest defines model for CloneListRequest.
+type CloneListRequest struct {
+	// IncludeTasks Копировать задачи; false — пустой список с тем же названием и описанием
+	IncludeTasks *bool `json:"include_tasks,omitempty"`
+
+	// KeepLabels Копировать метки задач
+	KeepLabels *bool `json:"keep_labels,omitempty"`
+
+	// KeepOrder Сохранить порядок задач исходного списка
+	KeepOrder *bool `json:"keep_order,omitempty"`
+
+	// ResetCompletion Снять отметки о выполнении у копий задач
+	ResetCompletion *bool `json:"reset_completion,omitempty"`
+
+	// Title Название копии; по умолчанию название исходного списка с «(copy)»
+	Title *string `json:"title,omitempty"`
+}
+
+// CloneListResponse defines model for CloneListResponse.
+type CloneListResponse struct {
+	List List `json:"list"`
+
+	// TasksCloned Сколько задач скопировано
+	TasksCloned int `json:"tasks_cloned"`
+}
+
+// CreateListRequest defines model for CreateListRequest.
+type CreateListRequest struct {
+	Description *string `json:"description,omitempty"`
+	Title       string  `json:"title"`
+}
+
+// CreateTaskRequest defines model for CreateTaskRequest.
+type CreateTaskRequest struct {
+	DueAt *time.Time `json:"due_at,omitempty"`
+	Text  string     `json:"text"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Code string `json:"code"`
+
+	// Details Подробности ошибки. Если запрос не соответствует этой спецификации,
+	// fields содержит нарушения: pointer — JSON Pointer внутри запроса
+	// (/path/{имя}, /query/{имя}, /header/{имя}, /body/... для тела),
+	// message — описание.
+	Details *map[string]interface{} `json:"details,omitempty"`
+	Message string                  `json:"message"`
+}
+
+// Folder defines model for Folder.
+type Folder struct {
+	CreatedAt time.Time          `json:"created_at"`
+	Id        openapi_types.UUID `json:"id"`
+	Name      string             `json:"name"`
+
+	// ParentId Родительская папка; null — папка верхнего уровня
+	ParentId  *openapi_types.UUID `json:"parent_id"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// FolderNode defines model for FolderNode.
+type FolderNode struct {
+	CreatedAt time.Time          `json:"created_at"`
+	Depth     int                `json:"depth"`
+	Folders   []FolderNode       `json:"folders"`
+	Id        openapi_types.UUID `json:"id"`
+	Lists     []ListNode         `json:"lists"`
+	Name      string             `json:"name"`
+
+	// OpenTasks Невыполненных задач во всех списках папки и вложенных папок
+	OpenTasks int `json:"open_tasks"`
+
+	// ParentId Родительская папка; null — папка верхнего уровня
+	ParentId  *openapi_types.UUID `json:"parent_id"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// List defines model for List.
+type List struct {
+	// ArchivedAt Когда список отправлен в архив; у активного списка отсутствует
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	// CreatedAt Время создания (RFC3339)
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Description Описание списка, в ответе опускается, если пустое
+	Description *string `json:"description,omitempty"`
+
+	// FolderId Папка списка; у списка вне папок отсутствует. Меняется через POST /api/v1/lists/{id}/move
+	FolderId *string `json:"folder_id,omitempty"`
+
+	// Id Идентификатор списка (UUID)
+	Id string `json:"id"`
+
+	// Title Название списка
+	Title string `json:"title"`
+
+	// Version Версия, увеличивается при каждом изменении
+	Version *int64 `json:"version,omitempty"`
+}
+
+// ListNode defines model for ListNode.
+type ListNode struct {
+	// ArchivedAt Когда список отправлен в архив; у активного списка отсутствует
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	// CreatedAt Время создания (RFC3339)
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Description Описание списка, в ответе опускается, если пустое
+	Description *string `json:"description,omitempty"`
+
+	// FolderId Папка списка; у списка вне папок отсутствует. Меняется через POST /api/v1/lists/{id}/move
+	FolderId *string `json:"folder_id,omitempty"`
+
+	// Id Идентификатор списка (UUID)
+	Id string `json:"id"`
+
+	// OpenTasks Невыполненных задач в списке
+	OpenTasks int `json:"open_tasks"`
+
+	// Title Название списка
+	Title string `json:"title"`
+
+	// Version Версия, увеличивается при каждом изменении
+	Version *int64 `json:"version,omitempty"`
+}
+
+// QuickAddParse defines model for QuickAddParse.
+type QuickAddParse struct {
+	// DueAt Срок в часовом поясе пользователя
+	DueAt  *time.Time `json:"due_at,omitempty"`
+	Input  string     `json:"input"`
+	Labels []string   `json:"labels"`
+
+	// Parts Распознанные фрагменты в порядке их следования в тексте
+	Parts []QuickAddPart `json:"parts"`
+
+	// Priority Приоритет задачи, задаётся быстрым добавлением
+	Priority *TaskPriority `json:"priority,omitempty"`
+
+	// Recurrence Правило повторения задачи, задаётся быстрым добавлением
+	Recurrence *TaskRecurrence `json:"recurrence,omitempty"`
+
+	// Title Текст без распознанных фрагментов — название задачи
+	Title string `json:"title"`
+}
+
+// QuickAddPart defines model for QuickAddPart.
+type QuickAddPart struct {
+	// End Смещение конца фрагмента в символах (не включается)
+	End  int              `json:"end"`
+	Kind QuickAddPartKind `json:"kind"`
+
+	// Start Смещение начала фрагмента в символах
+	Start int `json:"start"`
+
+	// Text Фрагмент исходного текста
+	Text string `json:"text"`
+
+	// Value Распознанное значение: дата ГГГГ-ММ-ДД, время ЧЧ:ММ, метка, приоритет или правило повторения
+	Value string `json:"value"`
+}
+
+// QuickAddPartKind defines model for QuickAddPart.Kind.
+type QuickAddPartKind string
+
+// QuickAddTaskRequest defines model for QuickAddTaskRequest.
+type QuickAddTaskRequest struct {
+	Text string `json:"text"`
+}
+
+// QuickAddTaskResponse defines model for QuickAddTaskResponse.
+type QuickAddTaskResponse struct {
+	Parsed QuickAddParse `json:"parsed"`
+	Task   Task          `json:"task"`
+}
+
+// Task defines model for Task.
+type Task struct {
+	// Assignee Исполнитель (идентификатор пользователя), см. PUT /api/v1/tasks/{taskID}/assignee
+	Assignee  *string `json:"assignee,omitempty"`
+	Completed bool    `json:"completed"`
+
+	// CompletedAt Когда задача была отмечена выполненной
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// DueAt Срок выполнения
+	DueAt *time.Time `json:"due_at,omitempty"`
+
+	// Id Идентификатор задачи (UUID)
+	Id string `json:"id"`
+
+	// Labels Метки задачи, задаются быстрым добавлением
+	Labels *[]string `json:"labels,omitempty"`
+
+	// ListId Идентификатор списка (UUID)
+	ListId string `json:"list_id"`
+
+	// Priority Приоритет задачи, задаётся быстрым добавлением
+	Priority *TaskPriority `json:"priority,omitempty"`
+
+	// Recurrence Правило повторения задачи, задаётся быстрым добавлением
+	Recurrence *TaskRecurrence `json:"recurrence,omitempty"`
+	Text       string          `json:"text"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+
+	// Version Версия, увеличивается при каждом изменении
+	Version *int64 `json:"version,omitempty"`
+}
+
+// TaskPriority Приоритет задачи, задаётся быстрым добавлением
+type TaskPriority string
+
+// TaskRecurrence Правило повторения задачи, задаётся быстрым добавлением
+type TaskRecurrence string
+
+// UpdateListRequest defines model for UpdateListRequest.
+type UpdateListRequest struct {
+	// Description Новое описание; если не передано, описание очищается
+	Description *string `json:"description,omitempty"`
+	Title       string  `json:"title"`
+}
+
+// UpdateTaskRequest defines model for UpdateTaskRequest.
+type UpdateTaskRequest struct {
+	Completed *bool      `json:"completed,omitempty"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+	Text      *string    `json:"text,omitempty"`
+}
+
+// FolderId defines model for FolderId.
+type FolderId = openapi_types.UUID
+
+// Id defines model for Id.
+type Id = string
+
+// Limit defines model for Limit.
+type Limit = int
+
+// Offset defines model for Offset.
+type Offset = int
+
+// RuleId defines model for RuleId.
+type RuleId = openapi_types.UUID
+
+// TemplateId defines model for TemplateId.
+type TemplateId = openapi_types.UUID
+
+// UserId defines model for UserId.
+type UserId = string
+
+// ViewId defines model for ViewId.
+type ViewId = string
+
+// WebhookId defines model for WebhookId.
+type WebhookId = openapi_types.UUID
+
+// NotFound defines model for NotFound.
+type NotFound = Error
+
+// ServerError defines model for ServerError.
+type ServerError = Error
+
+// ValidationError defines model for ValidationError.
+type ValidationError = Error
+
+// ListListsParams defines parameters for ListLists.
+type ListListsParams struct {
+	// Limit Максимум элементов в ответе
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Смещение в выборке
+	Offset *Offset `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// SearchListsParams defines parameters for SearchLists.
+type SearchListsParams struct {
+	// Title Подстрока названия, без учёта регистра
+	Title string `form:"title" json:"title"`
+}
+
+// GetTasksParams defines parameters for GetTasks.
+type GetTasksParams struct {
+	// Limit Максимум элементов в ответе
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Смещение в выборке
+	Offset *Offset `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// CompleteTasksJSONBody defines parameters for CompleteTasks.
+type CompleteTasksJSONBody struct {
+	Ids []string `json:"ids"`
+}
+
+// AssignTaskJSONBody defines parameters for AssignTask.
+type AssignTaskJSONBody struct {
+	Assignee string `json:"assignee"`
+}
+
+// CreateListJSONRequestBody defines body for CreateList for application/json ContentType.
+type CreateListJSONRequestBody = CreateListRequest
+
+// UpdateListJSONRequestBody defines body for UpdateList for application/json ContentType.
+type UpdateListJSONRequestBody = UpdateListRequest
+
+// CloneListJSONRequestBody defines body for CloneList for application/json ContentType.
+type CloneListJSONRequestBody = CloneListRequest
+
+// CreateTaskJSONRequestBody defines body for CreateTask for application/json ContentType.
+type CreateTaskJSONRequestBody = CreateTaskRequest
+
+// CompleteTasksJSONRequestBody defines body for CompleteTasks for application/json ContentType.
+type CompleteTasksJSONRequestBody CompleteTasksJSONBody
+
+// QuickAddTaskJSONRequestBody defines body for QuickAddTask for application/json ContentType.
+type QuickAddTaskJSONRequestBody = QuickAddTaskRequest
+
+// UpdateTaskJSONRequestBody defines body for UpdateTask for application/json ContentType.
+type UpdateTaskJSONRequestBody = UpdateTaskRequest
+
+// AssignTaskJSONRequestBody defines body for AssignTask for application/json ContentType.
+type AssignTaskJSONRequestBody AssignTaskJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Получить списки
+	// (GET /api/v1/lists)
+	ListLists(w http.ResponseWriter, r *http.Request, params ListListsParams)
+	// Создать список
+	// (POST /api/v1/lists)
+	CreateList(w http.ResponseWriter, r *http.Request)
+	// Найти списки по подстроке в названии
+	// (GET /api/v1/lists/search)
+	SearchLists(w http.ResponseWriter, r *http.Request, params SearchListsParams)
+	// Удалить список
+	// (DELETE /api/v1/lists/{id})
+	DeleteList(w http.ResponseWriter, r *http.Request, id Id)
+	// Получить список по id
+	// (GET /api/v1/lists/{id})
+	GetList(w http.ResponseWriter, r *http.Request, id Id)
+	// Обновить title списка
+	// (PATCH /api/v1/lists/{id})
+	UpdateList(w http.ResponseWriter, r *http.Request, id Id)
+	// Вернуть список из архива
+	// (DELETE /api/v1/lists/{id}/archive)
+	UnarchiveList(w http.ResponseWriter, r *http.Request, id Id)
+	// Отправить список в архив
+	// (POST /api/v1/lists/{id}/archive)
+	ArchiveList(w http.ResponseWriter, r *http.Request, id Id)
+	// Создать копию списка
+	// (POST /api/v1/lists/{id}/clone)
+	CloneList(w http.ResponseWriter, r *http.Request, id Id)
+	// Получить все задачи в списке
+	// (GET /api/v1/lists/{listID}/tasks)
+	GetTasks(w http.ResponseWriter, r *http.Request, listID string, params GetTasksParams)
+	// Создать задачу в списке
+	// (POST /api/v1/lists/{listID}/tasks)
+	CreateTask(w http.ResponseWriter, r *http.Request, listID string)
+	// Отметить выполненными несколько задач списка
+	// (POST /api/v1/lists/{listID}/tasks/complete)
+	CompleteTasks(w http.ResponseWriter, r *http.Request, listID string)
+	// Быстро добавить задачу из текста
+	// (POST /api/v1/lists/{listID}/tasks:quick)
+	QuickAddTask(w http.ResponseWriter, r *http.Request, listID string)
+	// Удалить задачу
+	// (DELETE /api/v1/tasks/{taskID})
+	DeleteTask(w http.ResponseWriter, r *http.Request, taskID string)
+	// Получить задачу по ID
+	// (GET /api/v1/tasks/{taskID})
+	GetTask(w http.ResponseWriter, r *http.Request, taskID string)
+	// Обновить задачу
+	// (PATCH /api/v1/tasks/{taskID})
+	UpdateTask(w http.ResponseWriter, r *http.Request, taskID string)
+	// Назначить исполнителя задачи
+	// (PUT /api/v1/tasks/{taskID}/assignee)
+	AssignTask(w http.ResponseWriter, r *http.Request, taskID string)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// Получить списки
+// (GET /api/v1/lists)
+func (_ Unimplemented) ListLists(w http.ResponseWriter, r *http.Request, params ListListsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать список
+// (POST /api/v1/lists)
+func (_ Unimplemented) CreateList(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Найти списки по подстроке в названии
+// (GET /api/v1/lists/search)
+func (_ Unimplemented) SearchLists(w http.ResponseWriter, r *http.Request, params SearchListsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить список
+// (DELETE /api/v1/lists/{id})
+func (_ Unimplemented) DeleteList(w http.ResponseWriter, r *http.Request, id Id) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить список по id
+// (GET /api/v1/lists/{id})
+func (_ Unimplemented) GetList(w http.ResponseWriter, r *http.Request, id Id) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Обновить title списка
+// (PATCH /api/v1/lists/{id})
+func (_ Unimplemented) UpdateList(w http.ResponseWriter, r *http.Request, id Id) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Вернуть список из архива
+// (DELETE /api/v1/lists/{id}/archive)
+func (_ Unimplemented) UnarchiveList(w http.ResponseWriter, r *http.Request, id Id) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отправить список в архив
+// (POST /api/v1/lists/{id}/archive)
+func (_ Unimplemented) ArchiveList(w http.ResponseWriter, r *http.Request, id Id) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать копию списка
+// (POST /api/v1/lists/{id}/clone)
+func (_ Unimplemented) CloneList(w http.ResponseWriter, r *http.Request, id Id) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить все задачи в списке
+// (GET /api/v1/lists/{listID}/tasks)
+func (_ Unimplemented) GetTasks(w http.ResponseWriter, r *http.Request, listID string, params GetTasksParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать задачу в списке
+// (POST /api/v1/lists/{listID}/tasks)
+func (_ Unimplemented) CreateTask(w http.ResponseWriter, r *http.Request, listID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отметить выполненными несколько задач списка
+// (POST /api/v1/lists/{listID}/tasks/complete)
+func (_ Unimplemented) CompleteTasks(w http.ResponseWriter, r *http.Request, listID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Быстро добавить задачу из текста
+// (POST /api/v1/lists/{listID}/tasks:quick)
+func (_ Unimplemented) QuickAddTask(w http.ResponseWriter, r *http.Request, listID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить задачу
+// (DELETE /api/v1/tasks/{taskID})
+func (_ Unimplemented) DeleteTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить задачу по ID
+// (GET /api/v1/tasks/{taskID})
+func (_ Unimplemented) GetTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Обновить задачу
+// (PATCH /api/v1/tasks/{taskID})
+func (_ Unimplemented) UpdateTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Назначить исполнителя задачи
+// (PUT /api/v1/tasks/{taskID}/assignee)
+func (_ Unimplemented) AssignTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListLists operation middleware
+func (siw *ServerInterfaceWrapper) ListLists(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListListsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListLists(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateList operation middleware
+func (siw *ServerInterfaceWrapper) CreateList(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateList(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SearchLists operation middleware
+func (siw *ServerInterfaceWrapper) SearchLists(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchListsParams
+
+	// ------------- Required query parameter "title" -------------
+
+	if paramValue := r.URL.Query().Get("title"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "title"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "title", r.URL.Query(), &params.Title)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "title", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SearchLists(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteList operation middleware
+func (siw *ServerInterfaceWrapper) DeleteList(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteList(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetList operation middleware
+func (siw *ServerInterfaceWrapper) GetList(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetList(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateList operation middleware
+func (siw *ServerInterfaceWrapper) UpdateList(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateList(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UnarchiveList operation middleware
+func (siw *ServerInterfaceWrapper) UnarchiveList(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnarchiveList(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ArchiveList operation middleware
+func (siw *ServerInterfaceWrapper) ArchiveList(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ArchiveList(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CloneList operation middleware
+func (siw *ServerInterfaceWrapper) CloneList(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CloneList(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTasks operation middleware
+func (siw *ServerInterfaceWrapper) GetTasks(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "listID" -------------
+	var listID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "listID", chi.URLParam(r, "listID"), &listID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "listID", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTasksParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTasks(w, r, listID, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateTask operation middleware
+func (siw *ServerInterfaceWrapper) CreateTask(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "listID" -------------
+	var listID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "listID", chi.URLParam(r, "listID"), &listID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "listID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateTask(w, r, listID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CompleteTasks operation middleware
+func (siw *ServerInterfaceWrapper) CompleteTasks(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "listID" -------------
+	var listID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "listID", chi.URLParam(r, "listID"), &listID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "listID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CompleteTasks(w, r, listID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// QuickAddTask operation middleware
+func (siw *ServerInterfaceWrapper) QuickAddTask(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "listID" -------------
+	var listID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "listID", chi.URLParam(r, "listID"), &listID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "listID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.QuickAddTask(w, r, listID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteTask operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTask(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "taskID" -------------
+	var taskID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "taskID", chi.URLParam(r, "taskID"), &taskID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "taskID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteTask(w, r, taskID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTask operation middleware
+func (siw *ServerInterfaceWrapper) GetTask(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "taskID" -------------
+	var taskID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "taskID", chi.URLParam(r, "taskID"), &taskID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "taskID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTask(w, r, taskID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateTask operation middleware
+func (siw *ServerInterfaceWrapper) UpdateTask(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "taskID" -------------
+	var taskID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "taskID", chi.URLParam(r, "taskID"), &taskID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "taskID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateTask(w, r, taskID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AssignTask operation middleware
+func (siw *ServerInterfaceWrapper) AssignTask(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "taskID" -------------
+	var taskID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "taskID", chi.URLParam(r, "taskID"), &taskID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "taskID", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AssignTask(w, r, taskID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/v1/lists", wrapper.ListLists)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/v1/lists", wrapper.CreateList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/v1/lists/search", wrapper.SearchLists)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/api/v1/lists/{id}", wrapper.DeleteList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/v1/lists/{id}", wrapper.GetList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/api/v1/lists/{id}", wrapper.UpdateList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/api/v1/lists/{id}/archive", wrapper.UnarchiveList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/v1/lists/{id}/archive", wrapper.ArchiveList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/v1/lists/{id}/clone", wrapper.CloneList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/v1/lists/{listID}/tasks", wrapper.GetTasks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/v1/lists/{listID}/tasks", wrapper.CreateTask)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/v1/lists/{listID}/tasks/complete", wrapper.CompleteTasks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/api/v1/lists/{listID}/tasks:quick", wrapper.QuickAddTask)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/api/v1/tasks/{taskID}", wrapper.DeleteTask)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/api/v1/tasks/{taskID}", wrapper.GetTask)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/api/v1/tasks/{taskID}", wrapper.UpdateTask)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/api/v1/tasks/{taskID}/assignee", wrapper.AssignTask)
+	})
+
+	return r
+}
+
+type NotFoundJSONResponse Error
+
+type ServerErrorJSONResponse Error
+
+type ValidationErrorJSONResponse Error
+
+type ListListsRequestObject struct {
+	Params ListListsParams
+}
+
+type ListListsResponseObject interface {
+	VisitListListsResponse(w http.ResponseWriter) error
+}
+
+type ListLists200ResponseHeaders struct {
+	XTotalCount int
+}
+
+type ListLists200JSONResponse struct {
+	Body    []List
+	Headers ListLists200ResponseHeaders
+}
+
+func (response ListLists200JSONResponse) VisitListListsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", fmt.Sprint(response.Headers.XTotalCount))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListLists500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response ListLists500JSONResponse) VisitListListsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateListRequestObject struct {
+	Body *CreateListJSONRequestBody
+}
+
+type CreateListResponseObject interface {
+	VisitCreateListResponse(w http.ResponseWriter) error
+}
+
+type CreateList201ResponseHeaders struct {
+	XUndoToken string
+}
+
+type CreateList201JSONResponse struct {
+	Body    List
+	Headers CreateList201ResponseHeaders
+}
+
+func (response CreateList201JSONResponse) VisitCreateListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateList400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response CreateList400JSONResponse) VisitCreateListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateList500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response CreateList500JSONResponse) VisitCreateListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchListsRequestObject struct {
+	Params SearchListsParams
+}
+
+type SearchListsResponseObject interface {
+	VisitSearchListsResponse(w http.ResponseWriter) error
+}
+
+type SearchLists200JSONResponse []List
+
+func (response SearchLists200JSONResponse) VisitSearchListsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchLists400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response SearchLists400JSONResponse) VisitSearchListsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchLists500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response SearchLists500JSONResponse) VisitSearchListsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteListRequestObject struct {
+	Id Id `json:"id"`
+}
+
+type DeleteListResponseObject interface {
+	VisitDeleteListResponse(w http.ResponseWriter) error
+}
+
+type DeleteList204ResponseHeaders struct {
+	XUndoToken string
+}
+
+type DeleteList204Response struct {
+	Headers DeleteList204ResponseHeaders
+}
+
+func (response DeleteList204Response) VisitDeleteListResponse(w http.ResponseWriter) error {
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteList404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response DeleteList404JSONResponse) VisitDeleteListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteList500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response DeleteList500JSONResponse) VisitDeleteListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetListRequestObject struct {
+	Id Id `json:"id"`
+}
+
+type GetListResponseObject interface {
+	VisitGetListResponse(w http.ResponseWriter) error
+}
+
+type GetList200JSONResponse List
+
+func (response GetList200JSONResponse) VisitGetListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetList404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetList404JSONResponse) VisitGetListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetList500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response GetList500JSONResponse) VisitGetListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateListRequestObject struct {
+	Id   Id `json:"id"`
+	Body *UpdateListJSONRequestBody
+}
+
+type UpdateListResponseObject interface {
+	VisitUpdateListResponse(w http.ResponseWriter) error
+}
+
+type UpdateList200ResponseHeaders struct {
+	XUndoToken string
+}
+
+type UpdateList200JSONResponse struct {
+	Body    List
+	Headers UpdateList200ResponseHeaders
+}
+
+func (response UpdateList200JSONResponse) VisitUpdateListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateList400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response UpdateList400JSONResponse) VisitUpdateListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateList404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response UpdateList404JSONResponse) VisitUpdateListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateList500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response UpdateList500JSONResponse) VisitUpdateListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnarchiveListRequestObject struct {
+	Id Id `json:"id"`
+}
+
+type UnarchiveListResponseObject interface {
+	VisitUnarchiveListResponse(w http.ResponseWriter) error
+}
+
+type UnarchiveList200ResponseHeaders struct {
+	XUndoToken string
+}
+
+type UnarchiveList200JSONResponse struct {
+	Body    List
+	Headers UnarchiveList200ResponseHeaders
+}
+
+func (response UnarchiveList200JSONResponse) VisitUnarchiveListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UnarchiveList404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response UnarchiveList404JSONResponse) VisitUnarchiveListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnarchiveList500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response UnarchiveList500JSONResponse) VisitUnarchiveListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ArchiveListRequestObject struct {
+	Id Id `json:"id"`
+}
+
+type ArchiveListResponseObject interface {
+	VisitArchiveListResponse(w http.ResponseWriter) error
+}
+
+type ArchiveList200ResponseHeaders struct {
+	XUndoToken string
+}
+
+type ArchiveList200JSONResponse struct {
+	Body    List
+	Headers ArchiveList200ResponseHeaders
+}
+
+func (response ArchiveList200JSONResponse) VisitArchiveListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ArchiveList404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response ArchiveList404JSONResponse) VisitArchiveListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ArchiveList500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response ArchiveList500JSONResponse) VisitArchiveListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CloneListRequestObject struct {
+	Id   Id `json:"id"`
+	Body *CloneListJSONRequestBody
+}
+
+type CloneListResponseObject interface {
+	VisitCloneListResponse(w http.ResponseWriter) error
+}
+
+type CloneList201JSONResponse CloneListResponse
+
+func (response CloneList201JSONResponse) VisitCloneListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CloneList400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response CloneList400JSONResponse) VisitCloneListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CloneList404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response CloneList404JSONResponse) VisitCloneListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CloneList500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response CloneList500JSONResponse) VisitCloneListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTasksRequestObject struct {
+	ListID string `json:"listID"`
+	Params GetTasksParams
+}
+
+type GetTasksResponseObject interface {
+	VisitGetTasksResponse(w http.ResponseWriter) error
+}
+
+type GetTasks200ResponseHeaders struct {
+	XTotalCount int
+}
+
+type GetTasks200JSONResponse struct {
+	Body    []Task
+	Headers GetTasks200ResponseHeaders
+}
+
+func (response GetTasks200JSONResponse) VisitGetTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", fmt.Sprint(response.Headers.XTotalCount))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetTasks404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetTasks404JSONResponse) VisitGetTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTasks500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response GetTasks500JSONResponse) VisitGetTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateTaskRequestObject struct {
+	ListID string `json:"listID"`
+	Body   *CreateTaskJSONRequestBody
+}
+
+type CreateTaskResponseObject interface {
+	VisitCreateTaskResponse(w http.ResponseWriter) error
+}
+
+type CreateTask201ResponseHeaders struct {
+	XUndoToken string
+}
+
+type CreateTask201JSONResponse struct {
+	Body    Task
+	Headers CreateTask201ResponseHeaders
+}
+
+func (response CreateTask201JSONResponse) VisitCreateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateTask400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response CreateTask400JSONResponse) VisitCreateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateTask404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response CreateTask404JSONResponse) VisitCreateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateTask500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response CreateTask500JSONResponse) VisitCreateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CompleteTasksRequestObject struct {
+	ListID string `json:"listID"`
+	Body   *CompleteTasksJSONRequestBody
+}
+
+type CompleteTasksResponseObject interface {
+	VisitCompleteTasksResponse(w http.ResponseWriter) error
+}
+
+type CompleteTasks200ResponseHeaders struct {
+	XUndoToken string
+}
+
+type CompleteTasks200JSONResponse struct {
+	Body    []Task
+	Headers CompleteTasks200ResponseHeaders
+}
+
+func (response CompleteTasks200JSONResponse) VisitCompleteTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CompleteTasks400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response CompleteTasks400JSONResponse) VisitCompleteTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CompleteTasks404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response CompleteTasks404JSONResponse) VisitCompleteTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CompleteTasks500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response CompleteTasks500JSONResponse) VisitCompleteTasksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type QuickAddTaskRequestObject struct {
+	ListID string `json:"listID"`
+	Body   *QuickAddTaskJSONRequestBody
+}
+
+type QuickAddTaskResponseObject interface {
+	VisitQuickAddTaskResponse(w http.ResponseWriter) error
+}
+
+type QuickAddTask201ResponseHeaders struct {
+	XUndoToken string
+}
+
+type QuickAddTask201JSONResponse struct {
+	Body    QuickAddTaskResponse
+	Headers QuickAddTask201ResponseHeaders
+}
+
+func (response QuickAddTask201JSONResponse) VisitQuickAddTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type QuickAddTask400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response QuickAddTask400JSONResponse) VisitQuickAddTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type QuickAddTask404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response QuickAddTask404JSONResponse) VisitQuickAddTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type QuickAddTask500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response QuickAddTask500JSONResponse) VisitQuickAddTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteTaskRequestObject struct {
+	TaskID string `json:"taskID"`
+}
+
+type DeleteTaskResponseObject interface {
+	VisitDeleteTaskResponse(w http.ResponseWriter) error
+}
+
+type DeleteTask204ResponseHeaders struct {
+	XUndoToken string
+}
+
+type DeleteTask204Response struct {
+	Headers DeleteTask204ResponseHeaders
+}
+
+func (response DeleteTask204Response) VisitDeleteTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteTask404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response DeleteTask404JSONResponse) VisitDeleteTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteTask500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response DeleteTask500JSONResponse) VisitDeleteTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTaskRequestObject struct {
+	TaskID string `json:"taskID"`
+}
+
+type GetTaskResponseObject interface {
+	VisitGetTaskResponse(w http.ResponseWriter) error
+}
+
+type GetTask200JSONResponse Task
+
+func (response GetTask200JSONResponse) VisitGetTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTask404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response GetTask404JSONResponse) VisitGetTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetTask500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response GetTask500JSONResponse) VisitGetTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateTaskRequestObject struct {
+	TaskID string `json:"taskID"`
+	Body   *UpdateTaskJSONRequestBody
+}
+
+type UpdateTaskResponseObject interface {
+	VisitUpdateTaskResponse(w http.ResponseWriter) error
+}
+
+type UpdateTask200ResponseHeaders struct {
+	XUndoToken string
+}
+
+type UpdateTask200JSONResponse struct {
+	Body    Task
+	Headers UpdateTask200ResponseHeaders
+}
+
+func (response UpdateTask200JSONResponse) VisitUpdateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateTask400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response UpdateTask400JSONResponse) VisitUpdateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateTask404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response UpdateTask404JSONResponse) VisitUpdateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateTask500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response UpdateTask500JSONResponse) VisitUpdateTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AssignTaskRequestObject struct {
+	TaskID string `json:"taskID"`
+	Body   *AssignTaskJSONRequestBody
+}
+
+type AssignTaskResponseObject interface {
+	VisitAssignTaskResponse(w http.ResponseWriter) error
+}
+
+type AssignTask200ResponseHeaders struct {
+	XUndoToken string
+}
+
+type AssignTask200JSONResponse struct {
+	Body    Task
+	Headers AssignTask200ResponseHeaders
+}
+
+func (response AssignTask200JSONResponse) VisitAssignTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Undo-Token", fmt.Sprint(response.Headers.XUndoToken))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type AssignTask400JSONResponse struct{ ValidationErrorJSONResponse }
+
+func (response AssignTask400JSONResponse) VisitAssignTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AssignTask404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response AssignTask404JSONResponse) VisitAssignTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AssignTask500JSONResponse struct{ ServerErrorJSONResponse }
+
+func (response AssignTask500JSONResponse) VisitAssignTaskResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Получить списки
+	// (GET /api/v1/lists)
+	ListLists(ctx context.Context, request ListListsRequestObject) (ListListsResponseObject, error)
+	// Создать список
+	// (POST /api/v1/lists)
+	CreateList(ctx context.Context, request CreateListRequestObject) (CreateListResponseObject, error)
+	// Найти списки по подстроке в названии
+	// (GET /api/v1/lists/search)
+	SearchLists(ctx context.Context, request SearchListsRequestObject) (SearchListsResponseObject, error)
+	// Удалить список
+	// (DELETE /api/v1/lists/{id})
+	DeleteList(ctx context.Context, request DeleteListRequestObject) (DeleteListResponseObject, error)
+	// Получить список по id
+	// (GET /api/v1/lists/{id})
+	GetList(ctx context.Context, request GetListRequestObject) (GetListResponseObject, error)
+	// Обновить title списка
+	// (PATCH /api/v1/lists/{id})
+	UpdateList(ctx context.Context, request UpdateListRequestObject) (UpdateListResponseObject, error)
+	// Вернуть список из архива
+	// (DELETE /api/v1/lists/{id}/archive)
+	UnarchiveList(ctx context.Context, request UnarchiveListRequestObject) (UnarchiveListResponseObject, error)
+	// Отправить список в архив
+	// (POST /api/v1/lists/{id}/archive)
+	ArchiveList(ctx context.Context, request ArchiveListRequestObject) (ArchiveListResponseObject, error)
+	// Создать копию списка
+	// (POST /api/v1/lists/{id}/clone)
+	CloneList(ctx context.Context, request CloneListRequestObject) (CloneListResponseObject, error)
+	// Получить все задачи в списке
+	// (GET /api/v1/lists/{listID}/tasks)
+	GetTasks(ctx context.Context, request GetTasksRequestObject) (GetTasksResponseObject, error)
+	// Создать задачу в списке
+	// (POST /api/v1/lists/{listID}/tasks)
+	CreateTask(ctx context.Context, request CreateTaskRequestObject) (CreateTaskResponseObject, error)
+	// Отметить выполненными несколько задач списка
+	// (POST /api/v1/lists/{listID}/tasks/complete)
+	CompleteTasks(ctx context.Context, request CompleteTasksRequestObject) (CompleteTasksResponseObject, error)
+	// Быстро добавить задачу из текста
+	// (POST /api/v1/lists/{listID}/tasks:quick)
+	QuickAddTask(ctx context.Context, request QuickAddTaskRequestObject) (QuickAddTaskResponseObject, error)
+	// Удалить задачу
+	// (DELETE /api/v1/tasks/{taskID})
+	DeleteTask(ctx context.Context, request DeleteTaskRequestObject) (DeleteTaskResponseObject, error)
+	// Получить задачу по ID
+	// (GET /api/v1/tasks/{taskID})
+	GetTask(ctx context.Context, request GetTaskRequestObject) (GetTaskResponseObject, error)
+	// Обновить задачу
+	// (PATCH /api/v1/tasks/{taskID})
+	UpdateTask(ctx context.Context, request UpdateTaskRequestObject) (UpdateTaskResponseObject, error)
+	// Назначить исполнителя задачи
+	// (PUT /api/v1/tasks/{taskID}/assignee)
+	AssignTask(ctx context.Context, request AssignTaskRequestObject) (AssignTaskResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListLists operation middleware
+func (sh *strictHandler) ListLists(w http.ResponseWriter, r *http.Request, params ListListsParams) {
+	var request ListListsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListLists(ctx, request.(ListListsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListLists")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListListsResponseObject); ok {
+		if err := validResponse.VisitListListsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateList operation middleware
+func (sh *strictHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	var request CreateListRequestObject
+
+	var body CreateListJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateList(ctx, request.(CreateListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateList")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateListResponseObject); ok {
+		if err := validResponse.VisitCreateListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SearchLists operation middleware
+func (sh *strictHandler) SearchLists(w http.ResponseWriter, r *http.Request, params SearchListsParams) {
+	var request SearchListsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchLists(ctx, request.(SearchListsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchLists")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SearchListsResponseObject); ok {
+		if err := validResponse.VisitSearchListsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteList operation middleware
+func (sh *strictHandler) DeleteList(w http.ResponseWriter, r *http.Request, id Id) {
+	var request DeleteListRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteList(ctx, request.(DeleteListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteList")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteListResponseObject); ok {
+		if err := validResponse.VisitDeleteListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetList operation middleware
+func (sh *strictHandler) GetList(w http.ResponseWriter, r *http.Request, id Id) {
+	var request GetListRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetList(ctx, request.(GetListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetList")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetListResponseObject); ok {
+		if err := validResponse.VisitGetListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateList operation middleware
+func (sh *strictHandler) UpdateList(w http.ResponseWriter, r *http.Request, id Id) {
+	var request UpdateListRequestObject
+
+	request.Id = id
+
+	var body UpdateListJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateList(ctx, request.(UpdateListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateList")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateListResponseObject); ok {
+		if err := validResponse.VisitUpdateListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UnarchiveList operation middleware
+func (sh *strictHandler) UnarchiveList(w http.ResponseWriter, r *http.Request, id Id) {
+	var request UnarchiveListRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UnarchiveList(ctx, request.(UnarchiveListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UnarchiveList")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UnarchiveListResponseObject); ok {
+		if err := validResponse.VisitUnarchiveListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ArchiveList operation middleware
+func (sh *strictHandler) ArchiveList(w http.ResponseWriter, r *http.Request, id Id) {
+	var request ArchiveListRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ArchiveList(ctx, request.(ArchiveListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ArchiveList")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ArchiveListResponseObject); ok {
+		if err := validResponse.VisitArchiveListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CloneList operation middleware
+func (sh *strictHandler) CloneList(w http.ResponseWriter, r *http.Request, id Id) {
+	var request CloneListRequestObject
+
+	request.Id = id
+
+	var body CloneListJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CloneList(ctx, request.(CloneListRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CloneList")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CloneListResponseObject); ok {
+		if err := validResponse.VisitCloneListResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTasks operation middleware
+func (sh *strictHandler) GetTasks(w http.ResponseWriter, r *http.Request, listID string, params GetTasksParams) {
+	var request GetTasksRequestObject
+
+	request.ListID = listID
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTasks(ctx, request.(GetTasksRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTasks")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTasksResponseObject); ok {
+		if err := validResponse.VisitGetTasksResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateTask operation middleware
+func (sh *strictHandler) CreateTask(w http.ResponseWriter, r *http.Request, listID string) {
+	var request CreateTaskRequestObject
+
+	request.ListID = listID
+
+	var body CreateTaskJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateTask(ctx, request.(CreateTaskRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateTask")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateTaskResponseObject); ok {
+		if err := validResponse.VisitCreateTaskResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CompleteTasks operation middleware
+func (sh *strictHandler) CompleteTasks(w http.ResponseWriter, r *http.Request, listID string) {
+	var request CompleteTasksRequestObject
+
+	request.ListID = listID
+
+	var body CompleteTasksJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CompleteTasks(ctx, request.(CompleteTasksRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CompleteTasks")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CompleteTasksResponseObject); ok {
+		if err := validResponse.VisitCompleteTasksResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// QuickAddTask operation middleware
+func (sh *strictHandler) QuickAddTask(w http.ResponseWriter, r *http.Request, listID string) {
+	var request QuickAddTaskRequestObject
+
+	request.ListID = listID
+
+	var body QuickAddTaskJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.QuickAddTask(ctx, request.(QuickAddTaskRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "QuickAddTask")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(QuickAddTaskResponseObject); ok {
+		if err := validResponse.VisitQuickAddTaskResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteTask operation middleware
+func (sh *strictHandler) DeleteTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	var request DeleteTaskRequestObject
+
+	request.TaskID = taskID
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteTask(ctx, request.(DeleteTaskRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteTask")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteTaskResponseObject); ok {
+		if err := validResponse.VisitDeleteTaskResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetTask operation middleware
+func (sh *strictHandler) GetTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	var request GetTaskRequestObject
+
+	request.TaskID = taskID
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetTask(ctx, request.(GetTaskRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetTask")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetTaskResponseObject); ok {
+		if err := validResponse.VisitGetTaskResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateTask operation middleware
+func (sh *strictHandler) UpdateTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	var request UpdateTaskRequestObject
+
+	request.TaskID = taskID
+
+	var body UpdateTaskJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateTask(ctx, request.(UpdateTaskRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateTask")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateTaskResponseObject); ok {
+		if err := validResponse.VisitUpdateTaskResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// AssignTask operation middleware
+func (sh *strictHandler) AssignTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	var request AssignTaskRequestObject
+
+	request.TaskID = taskID
+
+	var body AssignTaskJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.AssignTask(ctx, request.(AssignTaskRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "AssignTask")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AssignTaskResponseObject); ok {
+		if err := validResponse.VisitAssignTaskResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xde2/c1pX/Ktfs/mEBnIds2ZuO/3LtptXCTVxHTrpbGQY9vJJYz5ATkiNbawjQw45T",
+	"KLU2We+22E3Spl2gC+w/o7HGpl5joJ/g3q+wn2Rxzr0kL8nLmZEqy8mmMGDN8HGf531+584jo+m1O55L",
+	"3TAwGo+MJWrZ1MePc15ota55XTeEbzYNmr7TCR3PNRoG+5rt8F+zARsQts+G7IBF/Ckb8HW+wfpsSPhv",
+	"2AEbsEM2YEd8gw1Zn7AdNmCvCN/kT/nnfIP1SMtpOyFhEfEWFgIaGqYRNJdo24LuwpUONRqG44Z0kfrG",
+	"6qpp3HZtb867T13NcP7IhmwfOiNslx3wbXLz/Q/mSM3qOLXl6VrXtb3aoxDeXdX1EoS+4y4aq9BLx/Kt",
+	"Ng3lGrzrtWzqz9rFLm/fnr1O2GvWY6/ZPosM03DgcscKlwzTcK02Dt82TMOnH3cdn9pGI/S7VO1+wfPb",
+	"Vmg0jG4Xn8wPxzRKe+br7DWL+DrbZ70T9V3s6wZsR7E79iXrsX2+ziJ2yDfZoXZv+4QNcesHfIMN4gF9",
+	"3KX+Sjoi3O/M+rcd12l320ajbup2/H1BF8UhfQO9I/0dsQhoEIbAt9gOG/I1tl86Ag2hjRnCrW6Ljth+",
+	"vsZ6rM8idsB6b4gE5mi707LC8lHwT1mP7bADNmRHb2wUtwM9G7DfsV1JCBF/zCKgRyAKvgbMMWQH/DP2",
+	"CmgELw+QN8/DHQK0hA88ZT3YRv6MWK7nrrS9bjAVz0KIo3Qev6jAQCqzdnYPrYc3qLsYLhmN6XpdN/wP",
+	"Hfpg9DYO2C5KL9jOA0FYfJvgzkbwp3SWfXiNr7EhvnTEhuwFG5LzoWdbKybpdppe23EXTQJytkVDak+d",
+	"Er9+RO8ted79EdNiQ7abiIk3I6JW4eWg47kBRYH5nhe+63VdHFPTc0MqtIfV6bScpgXDq/0q8FCEp538",
+	"nU8XjIbxg1qqi2riblD7se97vugoR3lfAeMfsR7bE5vDhsaqaXxA/WXqi7fe/Bi+5p+yiO0APYBIHvA1",
+	"lIFrrAdj+dBqOTZ2OOl46EMLqEQ8acNKf3j1xuz1q3Oz7793992rszd+fN2AQYSW08L1XnBoyw6Mxi8f",
+	"GW0aBNYivNO2HoJMI2KXSAt5gzgBma7XDdPoeCDhfKNh1O559kotdMIWNVZNtQm3275HfdLuBiG5R4kV",
+	"kha1gpDk3kf5WhOCffXOqqm0ADRFg5DYHg2I64WkbYXNJRIuUXL15iwJOrTpLMj5w2Kd5laAuAHGZbus",
+	"xz9hEYuQY2Qj0Me1lufSG04Q3hLDhGsd3+tQP3QEJTtus9W16d3QCu4HgsUWrG4rjHkk1/1/sCGy2los",
+	"7PhnhL1iPRzCUxZdIQtWK6Dkf9eeg8DZRGEzZHupJh+yfcLXCYrJQ8JexuT9CmeDig6uR0T2tJ5eTRnz",
+	"nue1qIULep/Szt2WdY+2Tjr8Q1Tn+yxSZlLeleeDqB7b0zdsyJ+g3gQRi/28BknKt9kurkHaF4FZ8ico",
+	"xmLBmjN8imPxaUDDu1LaOp6bGRHugWZIR3xbDAWsGGXaQ2FXoCZjR9LeiAjfFGbvaxaxvXGLI9irqDm/",
+	"yu5t2mJ0hZRpyDxBjFshIKi//M/5ptdZmfrLgWEWVGXbcZPvOsUpr3j3fkWbIcxGYRwh9ouc03KCcBwf",
+	"Qwu4OMBdd5vQqK019PalEbHPhspCE74eL1dMsqgAtDZcqt1+KcaW6/aObpo+tcLRAiIz1IKCVvb9uGuu",
+	"jlc0Uj7COSu4Xz7CLr1rhRk1blshrYROmxZ1uWmE9GGYG/ClYw8Y2tCNN1GBk+m4VJHgEiSaaLpana7X",
+	"SXPJ8gNoNjtj0aBmMxSVadm2A5tmtW4q72pl1e+BsZDAdoC8UGaDVEhUTVQl7N/4ujASgTrBlBzydWDU",
+	"AUGhnvhFwjnmm/CZ8N+o4n/AP1EMS1RY5rwrdLtoZRetipcgMVEI8DW+yT+N7dQGkToZ1cs/fPD+e+Sm",
+	"vMD67IhvooWaHSLrzbvna2AM1h6hb7e9ahKp0JULwgJXr6DNUK1WY1db2PWsN2XOu3LXcBh5NVWddw0N",
+	"XSQb/WgMZeHeps/raEy460VGaCKv2MdiBseewP6NzWgNxXUsn7rhXUcn1v6AOxpJj+gzIav5dhpP6F0h",
+	"brfVktZCfJEI45I/QVWEsn5Tir8jvm2YhdFCG9a9Fo3JuzDIbsc+5sLkNgW7Sacq18NUVzzTS/muvSdZ",
+	"12q13l9Ag3aU/hDvoNGal8qdcCnj2E8XlQKsVCuOczkhbQdGY5L+cIypVrR831qB76BVJm8LtEpZS16H",
+	"uqrJmfd4CubIEd/iTzI2U19YLeCNPMkYA/xJSksRGpJ9jBq8VBsSDwzZ/nhdKpY6M+Z0YeNFKW74HYw1",
+	"BWFeFyg8alyoX7hUqU9X6tNz0xca9XqjXv8nQ/CkcelSnb4zU69X6IUf3qvMTNszFevvpy9XZmYuX750",
+	"aWamXkcvR6pfgz1nQ3ZYVBWW31xylhPa19jDL2BFs/Y5SvM46nMgAo59guL4CYtY/wqahhAv24CvepsM",
+	"GuHrfDOrElT2VfnPp5b9vttaKeXhrHDLzeMLDG0cgpiGGbxiu0IcQxjm1rvXLl68+MOpE/ecs4AKIeKM",
+	"+M+sgZkPGaK24JvirtSW2yZhg1i5pi7TQCeGBdnppe3vE/mpDgG3KrsvfdTaKQuU7FSVsC8xGrQdD5Rg",
+	"8HsNA9yZyDPyQO2RY6/W2t7yRIvqHC/KlpnBeQj8TGlNuwmdkJx7dSzT1TSWqR/oieELVFzrQHgmODZ9",
+	"NpBJgwh7jxcSmSsCX6jHXqJDCC4veyXCzbEDplKs44aXZ8oXtkx4CW2Oq5JhIZ2CSgT2xOpJujd55XQa",
+	"0l3dosF4Ia10qRfFP+86zftXbfum5Qd0lA+Rd83Q8tjHET1lPRQvfbFf4M9vgwIqjQGXiZwiN7idbqi1",
+	"r9LIRqJ0i1Sf064dyw8DrT3Ww1UFAXmEvHDEt4AbHqOofxEnO/gWTFeJV+wLFxz1LKiD3cQXxehxXxjH",
+	"+yg9MCkxiX2g7EionYXveL4TroxrB3zDm/GzSBbNru9Tt0knefNW+vQI+fHHeHpJfm9Nt5b8SWEtMXGE",
+	"9m0hpqGEzcYboEggKStLsoj3WsfOmfUtEDx17UkyThB9OOKfsF5xYj3JpRE7RI44QNPrvFAufbbPDvgz",
+	"/jQVelOGzkK974iBUBeM2F8im+A828k0DYUWMtt7J79qpvGwAg1Vli0fLHSIGCfrcF20HH+dc9rq1xuy",
+	"p2TV0h7jSwqpgEQJQrmw49bwCPcYIrWTrqJ2peKwRa67/8o2qA2VpdzZ00mfZavVpZPJCzYUlCvmJKfY",
+	"IEjHOJl/Ff8q7Ev2ZYU9Z8/BBkqsM/Zn9ucG3DLTsGvPlOoQs5roLeI0YoMozT0OhaDtC5sgjgqM5R0k",
+	"Mbl+8baZyADx1Eexz8jQU7wniYFv/Ki7QtpO6z4Jvbbn+94DMv1Oo14nP1jy2pScW3IWlwhdpv4KeUDp",
+	"/YLtcVphqOzoy0KYHVCG9jEEdUDjaOYksrU4XLhoxt3qxj0nm845MUHgLLpUR6O/4+uJKZFEGsj5UanM",
+	"EmU9ZQIfHlbJzdupZYsmRe0R/Jm9vlpLBjKJwxJnQhWdrcTLk9vjPDNFT/QI2+FbKEiSEL7gwl4xgg+8",
+	"undK7tZkVswERlQ+y3AcG+mYeXlFvY7wGNTMUQ4PUswKschMvz2LbfkdviWS43wLzMJdjKMqCXaZuiq3",
+	"30o2IxN3uftmHKa3YmRJoXkKYbrvni8W72Wij1IxcbxwYmZHNNGAokbVk/HnxyTj2FBreQ8wTG073bZh",
+	"GqDYjDulazLKQFNncgNbVa/8LO5BvfhT7E2uwq0MLWrWYbT9cNoLY1tOC8xG0O/4oe254RJ+WqGW31o5",
+	"4TKl07wue0ivfBT3lV76WdJreu0fZf+AdELqOk7mr+DHC0d4UMiAXFHiWTLSJKJGIig3NAtvgDZ7yiL+",
+	"65QtR0Z33kyqUSzJSHtvjE4/q0xkbuirGEFY8Iq7BBCUOH+1mY3oIumrWoIdynC5Ym/gtfPXbt2+bhLH",
+	"rbRp2/NXppSwMxBQQCzXJrBuAWBeDEUoG9PVerUeR/ytjmM0jIvVevUiWoHhEi5qbGtZdttxa7/y7sHV",
+	"VVNzHUOMI2/WfBr6K9lH6MOO54fZa2lipHixFvqU6u/I/jPw2ZIYWfpILYHXrt4pabMWp1bK7mNYNXPb",
+	"aRdnFbdiLAo8KZAuIo9mbblXN/AJ85gTEJjZVXPsgxLKunonh5e7UK8fC6Y2cYKpGDjSYaYwzaNgvn9R",
+	"QdR3JYF967qRz9cUgDg2fqleL3slmXNNxefBS0G33bb8FRGnB/djEwUegHEUHsQ4kLWI4l7sFBBMxws0",
+	"u5lCNySykQbhjzx7ZXL4XZBLTSVhgHxOaXVi3FoRT7Kalb+g7VYLxDF9ahjGG07cZxGPlaSFhnlqAMx9",
+	"JQHdjyKGFJ2PncxMQgt5fOQp0FAymywFyXxmjoLyAqIWUMgKlsqJD/B2iaTQQkdiaDCmlzJxTsxuFUoi",
+	"0Bp4wSL5Yq8Exx4HO8txu6MV5bdPCr1FkoEU2B4Ce1SBIyB47HVuG0WtQWYnWTQJacUK0qZgJhVp6zpe",
+	"l1LreEoI9WdhR2c0pumfkM8P2OCUeX1m/AYkwPBT2LF4GtEkTG7qefknNDzFxa6/eTmdcMkZL3apVsa4",
+	"FTCJY2uXvQN47+LCp/7Vydf+zel01uOfxq7XrsC1HUfNF53HidT8GZHP21buZ067XwvcJutL2hVQ0jyE",
+	"fBLRXbOaobMsIkolDwg80SgZf9uVD333JM93VldgsFNCYPPCK2KvFOwW6410MnKL8lsliK60UQLCT2se",
+	"+HYcH79SFKUVWYr2EsOrh4Al2xWwYwhSCLiZkLhVw8wR19W/kdZZSxcFC6jTjSowcGI507Ra1LUtvxLK",
+	"JRj9WNVpBuUPQX0DTLGEhpOiHwlKzyMxdPHIbNYnD2yUufU98SIWwiGcfQCXsAYcOhg05l0hlxFl49iE",
+	"vRApBb6mDEctqDNLa25KuEvJ7UP6UprvAKvH4RYyoyW5dk1gnA2qhP07Iq9hvQ5MhGWzHf6Eb2IXubpg",
+	"wqJ5F5rhG4Iu8imUvTjzuolAqmdi6gpYM4fLI9mkzF66LoWUHDy1D71jJifXauzjJLtWJQlJYKnRQGSD",
+	"IdQvk6pJzZYCeITqegHkGfDPIZqKxrnYiXlXDAE6gWzBUwRWZYveRF1ALoYTVxmdqY0IH4vlYzIPEZuJ",
+	"30g6AUjJzMXJKxcLFYdnHAAqFm6NjwZ9l2y9bOQnqQ57djJzz3YWRZ5Df5suywMzSm4/LAlBl8WslZtB",
+	"aJW0DH8Aa5EASMscW8w4jItQ5Q6RiLMhiigqpgH1JeRiXMerXv+ORtYFeGeCmNY3qiGgrt9pBdvfehxA",
+	"lJhkrQENOllymyDJcUH7uQQGNTHdgpJDbpcqepiYzXEuWoHpqxm8U6PlY2ub42QM1KSrjs5+x474Y5z4",
+	"oTC1UFkT6fUOM5YSbsjZKRwJdFs1y12nHsGSD7AUIeoyzFoove9fwCKnxJKt45sT8NYYjVGLc/QjPII/",
+	"iWMHNGUIBVbHMtK0ZidFfUXZ2h4JdegL6C3U2VcJ+0I8mnES+Pa8m9qZKtcKAzWCs4c24oOeEjSSGNYL",
+	"HO0Q4dU9opKK1ryUK1GiKN+6XMidRGGPKXNoWw9nxc0Y9BF/LWiqPPBKh5A//Yjl6erW3+ZcHCn6BY0K",
+	"GGiUg4HyLT1RH2Ly5vsWFU3OuEg0uXZlkHPlQQv6Ixh0dvWE0qjxMYCnR4iiP2CoYAcPeOiJeMBGWmyC",
+	"wF5QEi9gt9meHOZhDI/H+vh15aqiWiDXmpGtjXmXkIp63gm4swhMN8kPBPLxinymGCSAZxG+bpJzAvVn",
+	"knMt74FJzk1Xq+cumuQcrLAwBmGsJjnH1yXu60hewA+vxP2kq2LkATtDHJ2pgOXjz23PxUOn4uAAutoS",
+	"CPuZCa2S9N4m+PivQR5D0/wTvgnlliD/cR3W+TZ462wYjyYuZIARyMOtYiS/SWxrhVgLIfWVawu+g0+5",
+	"9GEYfxFjcFxyEV4JADtFLDkHMCdRju+C9Jd4w77Ig5u4GrLIKnu9n5+F6EMJUFxAQpbpnMgkF+oXLlem",
+	"65ULdfhcnY7/VOEGfCZeMzThP3KhLpu7UAc1tM83+DbbgeKveFnSEg5YGCxpMOG4pMudtkkuNy7WCXxg",
+	"ffLDxsV6dd6dd9nX8qAx4CkRLVMoW95LsxaoATXnACnKuErYc7E7ak2JLqIF0S89YQEWAZlG9iiz3yIA",
+	"J4xqjEFDjiySRS8Rf2YmlW9AUPzXMWgRZxHbBPOuJmleJSiI8FiNNAwXmxrCBNqC5UCHQzUwRhYcRuzV",
+	"vBsLnjatuV6YHDMVlB89d3vu2lS6jDEyI15MsUqww/NuIimH/CmGDVMKiN+KBw8UgSfzRewlggw+xRMz",
+	"cYP3BUJU3S3JqBlqlX1mG4nYXtrInvJiwiM5ZkLrGr9XSSJYsRZgV5Rf8zW5rcpxOhCkhfcjyDpEaHqB",
+	"3HzJBiq9oq2nfwoXOam+SgSvGNOmeJpvymcVcSy45AsiqmGqWMiXYGWRHATJgVbXlxvqSzf5uqjVlDsY",
+	"H3yA/IQkqhZrmoQ/hcmAQUFEuA8Z5DHEsvm6mNwLpcFdvi6L2VGr6sxOtfboW2h1TlLqVPBHz86f1FZu",
+	"jfUvv+cO5ecxRB/EeRoUiTT+JfhS+YLIcpsuL1qzEUufth1XBQaX3qvZTtB2gpHv1wLX8/45FzL1uwLU",
+	"kb90QiiyPOh1goDj7aAIWU57rtGHtNnVLUn6SFgILSfAw8K1WtBdLIaiNUHiYMVtZq9kS/TGg88mjL5l",
+	"wwAJhj5FlonaNY04EyM5vjgbi2sbFVFSR9b7/4J5UzlXG2UdlRo45h6/wb2sv9VgY+bw2N63AlyXlchg",
+	"q85e1+7vSHDdX8HI8VF3ajHM6RLA6dsmxfIkHSE8V4zDEZPNk/7ZQfcmIuHCkHt/Q/WNEYalGrGmVs/L",
+	"Y2YmKqJHvy46wcHnyfHlU1fSE616WG6WFgrMu3xdhLtl3Et6zpmTJapE1BmKEI8W0ZJBlWBDstYX41lp",
+	"jWZ6yta8m+kqYgcyeD9kRwRBQIfqeZclvR5JNzx2m9mBPMsrU65/JBzETKRfRCey4XxIoKpEnTk8Wedk",
+	"XcVNndjFOgsxNg6CnNKh4bUWrTzguPzIh/EH36vR/uTNswj5//XKep0kGLFDLbHJ8uLvmfT7SuVQIf90",
+	"jFhQZJOLxSUnCL18rWjumcRB03hap8NlJ3PEQvlrGTnnKLl8Qu9Q+RGOv3ZgwgV03CC03NCxwpxbm/mt",
+	"msydZYc+CDSXTjgl+ZMYJ5xO2nMCR1LuPhA/TVFy9YQDTn/v4oRjzvRfs2nLWaa+Q0eNUnmq9kh+XhHO",
+	"9HFfqflUfsu9HPe/6FudpY9b8A04HiWAWJiu3zIaxlIYdhq1WstrWq0lLwgb79TfqSPuSbK29gTOFIMr",
+	"UmCFyvaUSQUibtWcoJ1CLXzaipAvq6b2wImh/HmMBBWPipxvp1X3IuEA4+ulbf6UWq1wSdsoG4rJyCPz",
+	"M5MrFu2nTYpaTl2Tyg8U8M+VeDJaMp+J4y6UZsl5TCfIp9L+o6m0sw+RdXV9of23IQo+hRGpPQsoiyGL",
+	"p4BBIE2rCjhZgzZOG7jatZ1Q18DXCdq3N7IB0KEluzIC7NwXZa5pAuwwl2hJO/ixwFnqd4n1ZaZyA9Xh",
+	"NsFN28G+e3piF1TyEb33gde8T8O0o2senJTt+fHPkeg8gQTBIrNcGdQ3MoXyizv8M8iv5pJtSWL9NUJn",
+	"tsQxb1n++SiWnZpBPJfnhW+wXgWPxjtCSh3K7HECAIvdW/4YAOhsjx1V4NhBFkmPZcj6aYcfQOCw2NlP",
+	"QBz9/IacgDh3PMugwywkCb7mfypEdiHb0k3pC77FXiDk/1X+iN5Cg0g6j3FCB3xLoRIBt9XtGjuUp3Vu",
+	"jGkZo9+ybXF/F0f1QmZ4MJOqJo0UNphtl/X/n8lhhs41WbEBHV+zWtevfpjs034SoITBrCVHcY/qM25O",
+	"1+ufim6ektIdYmJLlHKIfP8ucmc/5qJ1dLt2Ja5D9vdeJtiv6fQreWqymh4WWERFAvMnaYu3EhOyRFko",
+	"FRQ9wUIIctxIaT1bgJKh6luYHtC0/N/pr6XxrdLX5xI7Uje49BB1uYOCgl8rv0eyP2Js78ozVjRN/wvb",
+	"TTKxIiag/sCJOFJymGAbdqES4zFMRThKmbOCFTkPR8GALfR/AwCgzR+77nEAAA==",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,7 @@
+// Package api содержит модели и строгий интерфейс chi-сервера, сгенерированные
+// по docs/openapi.yaml. Спецификация — единственный источник правды: после её
+// изменения нужно выполнить go generate ./internal/api, а несоответствие
+// реализации интерфейсу ломает сборку.
+package api
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.1 -config oapi-codegen.yaml ../../docs/openapi.yaml
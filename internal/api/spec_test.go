@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// generatedTags читает include-tags из oapi-codegen.yaml. Операции остальных
+// тегов обслуживаются обычными хендлерами; их маршруты с docs/openapi.yaml
+// сверяет TestRoutesMatchSpec в internal/http.
+func generatedTags(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile("oapi-codegen.yaml")
+	if err != nil {
+		t.Fatalf("read oapi-codegen.yaml: %v", err)
+	}
+	var cfg struct {
+		OutputOptions struct {
+			IncludeTags []string `yaml:"include-tags"`
+		} `yaml:"output-options"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("parse oapi-codegen.yaml: %v", err)
+	}
+	if len(cfg.OutputOptions.IncludeTags) == 0 {
+		t.Fatal("oapi-codegen.yaml: include-tags is empty")
+	}
+	return cfg.OutputOptions.IncludeTags
+}
+
+func loadSpecFile(t *testing.T) *openapi3.T {
+	t.Helper()
+	spec, err := openapi3.NewLoader().LoadFromFile("../../docs/openapi.yaml")
+	if err != nil {
+		t.Fatalf("load docs/openapi.yaml: %v", err)
+	}
+	if err := spec.Validate(openapi3.NewLoader().Context); err != nil {
+		t.Fatalf("docs/openapi.yaml is invalid: %v", err)
+	}
+	return spec
+}
+
+func generated(op *openapi3.Operation, tags []string) bool {
+	for _, tag := range op.Tags {
+		if slices.Contains(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// TestGeneratedCodeMatchesSpec падает, если docs/openapi.yaml изменили, а
+// код не перегенерировали: go generate ./internal/api.
+func TestGeneratedCodeMatchesSpec(t *testing.T) {
+	spec := loadSpecFile(t)
+	embedded, err := GetSwagger()
+	if err != nil {
+		t.Fatalf("decode embedded spec: %v", err)
+	}
+
+	tags := generatedTags(t)
+	want := map[string]*openapi3.Operation{}
+	for path, item := range spec.Paths.Map() {
+		for method, op := range item.Operations() {
+			if generated(op, tags) {
+				want[method+" "+path] = op
+			}
+		}
+	}
+	got := map[string]*openapi3.Operation{}
+	for path, item := range embedded.Paths.Map() {
+		for method, op := range item.Operations() {
+			got[method+" "+path] = op
+		}
+	}
+
+	for key, op := range want {
+		gen, ok := got[key]
+		if !ok {
+			t.Errorf("%s: operation is missing from generated code", key)
+			continue
+		}
+		// Генератор пишет operationId с заглавной буквы.
+		src, dst := *op, *gen
+		if !strings.EqualFold(src.OperationID, dst.OperationID) {
+			t.Errorf("%s: operationId %q, generated %q", key, src.OperationID, dst.OperationID)
+		}
+		src.OperationID, dst.OperationID = "", ""
+		if mustJSON(t, src) != mustJSON(t, dst) {
+			t.Errorf("%s: generated code is out of date", key)
+		}
+	}
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			t.Errorf("%s: generated operation is not in docs/openapi.yaml", key)
+		}
+	}
+
+	for name, schema := range embedded.Components.Schemas {
+		src, ok := spec.Components.Schemas[name]
+		if !ok {
+			t.Errorf("schema %s is not in docs/openapi.yaml", name)
+			continue
+		}
+		if mustJSON(t, schema) != mustJSON(t, src) {
+			t.Errorf("schema %s: generated code is out of date", name)
+		}
+	}
+}
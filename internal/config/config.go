@@ -3,25 +3,95 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	Port       string
+	GRPCPort   string
 	DBHost     string
 	DBPort     string
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	SearchSimilarityThreshold float64
+	UndoWindow                time.Duration
+	SSEHeartbeat              time.Duration
+	SSEReplaySize             int
+	WebhookMaxAttempts        int
+	WebhookPollInterval       time.Duration
+	WebhookTimeout            time.Duration
+	GraphQLMaxDepth           int
+	GraphQLMaxComplexity      int
+
+	// SMTPHost — SMTP-релей для писем; пустой — уведомления не отправляются.
+	SMTPHost           string
+	SMTPPort           string
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPFrom           string
+	SMTPTimeout        time.Duration
+	NotifyPollInterval time.Duration
+	NotifyMaxAttempts  int
+	NotifyDueSoon      time.Duration
+	NotifyDigestHour   int
+
+	ReminderPollInterval time.Duration
+
+	JobWorkers      int
+	JobPollInterval time.Duration
+	JobMaxAttempts  int
+	JobTimeout      time.Duration
+	JobRetention    time.Duration
+	// JobDrainTimeout — сколько при остановке ждать начатых задач.
+	JobDrainTimeout time.Duration
+	// AdminUsers — X-User-Id пользователей, которым доступен /api/v1/admin.
+	AdminUsers []string
 }
 
 func Load() Config {
 	return Config{
 		Port:       getEnv("PORT", "8080"),
+		GRPCPort:   getEnv("GRPC_PORT", "9090"),
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUser:     getEnv("DB_USER", "todo_user"),
 		DBPassword: getEnv("DB_PASSWORD", "todo_password"),
 		DBName:     getEnv("DB_NAME", "todo_db"),
+
+		SearchSimilarityThreshold: getEnvFloat("SEARCH_SIMILARITY_THRESHOLD", 0.3),
+		UndoWindow:                getEnvDuration("UNDO_WINDOW", time.Minute),
+		SSEHeartbeat:              getEnvDuration("SSE_HEARTBEAT", 15*time.Second),
+		SSEReplaySize:             getEnvInt("SSE_REPLAY_SIZE", 1000),
+		WebhookMaxAttempts:        getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookPollInterval:       getEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+		WebhookTimeout:            getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		GraphQLMaxDepth:           getEnvInt("GRAPHQL_MAX_DEPTH", 8),
+		GraphQLMaxComplexity:      getEnvInt("GRAPHQL_MAX_COMPLEXITY", 5000),
+
+		SMTPHost:           os.Getenv("SMTP_HOST"),
+		SMTPPort:           getEnv("SMTP_PORT", "587"),
+		SMTPUsername:       os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:       os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:           getEnv("SMTP_FROM", "todo-api <noreply@localhost>"),
+		SMTPTimeout:        getEnvDuration("SMTP_TIMEOUT", 30*time.Second),
+		NotifyPollInterval: getEnvDuration("NOTIFY_POLL_INTERVAL", 5*time.Second),
+		NotifyMaxAttempts:  getEnvInt("NOTIFY_MAX_ATTEMPTS", 8),
+		NotifyDueSoon:      getEnvDuration("NOTIFY_DUE_SOON", 24*time.Hour),
+		NotifyDigestHour:   getEnvInt("NOTIFY_DIGEST_HOUR", 8),
+
+		ReminderPollInterval: getEnvDuration("REMINDER_POLL_INTERVAL", 10*time.Second),
+
+		JobWorkers:      getEnvInt("JOB_WORKERS", 4),
+		JobPollInterval: getEnvDuration("JOB_POLL_INTERVAL", time.Second),
+		JobMaxAttempts:  getEnvInt("JOB_MAX_ATTEMPTS", 10),
+		JobTimeout:      getEnvDuration("JOB_TIMEOUT", 5*time.Minute),
+		JobRetention:    getEnvDuration("JOB_RETENTION", 7*24*time.Hour),
+		JobDrainTimeout: getEnvDuration("JOB_DRAIN_TIMEOUT", 30*time.Second),
+		AdminUsers:      getEnvList("ADMIN_USERS"),
 	}
 }
 
@@ -38,3 +108,35 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList разбирает список через запятую, пропуская пустые элементы.
+func getEnvList(key string) []string {
+	var res []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EntityList = "list"
+	EntityTask = "task"
+
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEvent — запись журнала об одном изменении списка или задачи.
+// Before пуст для создания, After — для удаления.
+type AuditEvent struct {
+	ID         int64                  `json:"id"`
+	Actor      string                 `json:"actor"`
+	RequestID  string                 `json:"request_id"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	ListID     string                 `json:"list_id"`
+	Action     string                 `json:"action"`
+	Before     json.RawMessage        `json:"before,omitempty"`
+	After      json.RawMessage        `json:"after,omitempty"`
+	Diff       map[string]FieldChange `json:"diff"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
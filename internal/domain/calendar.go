@@ -0,0 +1,16 @@
+package domain
+
+import "strconv"
+
+// CalendarObject — задача как ресурс календаря CalDAV. Name — имя ресурса
+// в коллекции списка, UID — UID компонента VTODO.
+type CalendarObject struct {
+	Task *Task
+	Name string
+	UID  string
+}
+
+// ETag ресурса — версия задачи: она растёт при каждом изменении.
+func (o *CalendarObject) ETag() string {
+	return `"` + strconv.FormatInt(o.Task.Version, 10) + `"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDeleted = "task.deleted"
+	EventListCreated = "list.created"
+	EventListUpdated = "list.updated"
+	EventListDeleted = "list.deleted"
+)
+
+// ChangeEvent — изменение списка или его задачи, рассылаемое подписчикам.
+// ID растёт от события к событию и служит Last-Event-ID в SSE. Reminder
+// заполняется только для task.reminder.
+type ChangeEvent struct {
+	ID         int64     `json:"id"`
+	Type       string    `json:"type"`
+	ListID     string    `json:"list_id"`
+	TaskID     string    `json:"task_id,omitempty"`
+	List       *List     `json:"list,omitempty"`
+	Task       *Task     `json:"task,omitempty"`
+	Reminder   *Reminder `json:"reminder,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
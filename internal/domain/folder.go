@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// MaxFolderDepth — наибольшая вложенность папок: папка верхнего уровня
+// имеет глубину 1.
+const MaxFolderDepth = 5
+
+// Folder — папка для группировки списков. ParentID nil — папка верхнего
+// уровня.
+type Folder struct {
+	ID        string    `json:"id"`
+	ParentID  *string   `json:"parent_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ListNode — список в дереве папок с числом невыполненных задач.
+type ListNode struct {
+	List
+	OpenTasks int `json:"open_tasks"`
+}
+
+// FolderNode — папка в дереве. OpenTasks считает невыполненные задачи
+// списков папки и всех вложенных папок.
+type FolderNode struct {
+	Folder
+	Depth     int           `json:"depth"`
+	OpenTasks int           `json:"open_tasks"`
+	Folders   []*FolderNode `json:"folders"`
+	Lists     []*ListNode   `json:"lists"`
+}
+
+// FolderTree — вся иерархия: папки верхнего уровня и списки вне папок.
+type FolderTree struct {
+	Folders   []*FolderNode `json:"folders"`
+	Lists     []*ListNode   `json:"lists"`
+	OpenTasks int           `json:"open_tasks"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	// JobFailed — попытки исчерпаны; задачу можно перезапустить вручную.
+	JobFailed = "failed"
+)
+
+// JobStates — состояния фоновой задачи.
+var JobStates = []string{JobPending, JobRunning, JobCompleted, JobFailed}
+
+// Job — фоновая задача в очереди. Kind выбирает обработчик, Args — его
+// аргументы в JSON.
+type Job struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Args        json.RawMessage `json:"args"`
+	State       string          `json:"state"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedUntil *time.Time      `json:"locked_until,omitempty"`
+	UniqueKey   string          `json:"unique_key,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
@@ -6,11 +6,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// List — список задач. ArchivedAt заполнен у списка в архиве, FolderID —
+// у списка в папке.
 type List struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Version     int64      `json:"version"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	FolderID    *string    `json:"folder_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
 
 func NewList(title, description string) *List {
@@ -21,3 +26,15 @@ func NewList(title, description string) *List {
 		CreatedAt:   time.Now(),
 	}
 }
+
+// CloneOptions — что переносится в копию списка.
+type CloneOptions struct {
+	// IncludeTasks копирует задачи; без него создаётся пустой список.
+	IncludeTasks bool
+	// ResetCompletion снимает отметки о выполнении у копий задач.
+	ResetCompletion bool
+	// KeepLabels копирует метки задач.
+	KeepLabels bool
+	// KeepOrder сохраняет порядок задач: копии создаются в порядке исходных.
+	KeepOrder bool
+}
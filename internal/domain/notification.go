@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// События, о которых сервис уведомляет пользователей.
+const (
+	NotifyAssigned  = "task.assigned"
+	NotifyMentioned = "task.mentioned"
+	NotifyDueSoon   = "task.due_soon"
+	NotifyDigest    = "list.digest"
+	NotifyReminder  = EventTaskReminder
+)
+
+// NotificationEvents — события, для которых настраиваются каналы.
+var NotificationEvents = []string{NotifyAssigned, NotifyMentioned, NotifyDueSoon, NotifyDigest, NotifyReminder}
+
+// ChannelEmail — уведомление письмом через SMTP-релей.
+const ChannelEmail = "email"
+
+// NotificationChannels — каналы доставки уведомлений.
+var NotificationChannels = []string{ChannelEmail}
+
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	// NotificationSkipped — канал отключили или удалили адрес после постановки
+	// уведомления в очередь.
+	NotificationSkipped = "skipped"
+	NotificationDead    = "dead"
+)
+
+// NotificationSettings — настройки уведомлений пользователя.
+type NotificationSettings struct {
+	UserID   string `json:"-"`
+	Email    string `json:"email"`
+	Locale   string `json:"locale"`
+	TimeZone string `json:"timezone"`
+	// Preferences — включён ли канал для события: Preferences[событие][канал].
+	Preferences map[string]map[string]bool `json:"preferences"`
+	// DigestLists — списки, по которым пользователь получает ежедневную сводку.
+	DigestLists []string `json:"digest_lists"`
+}
+
+// Notification — уведомление одного пользователя по одному каналу.
+type Notification struct {
+	ID            int64           `json:"id"`
+	UserID        string          `json:"user_id"`
+	Event         string          `json:"event"`
+	Channel       string          `json:"channel"`
+	Payload       json.RawMessage `json:"payload"`
+	DedupKey      string          `json:"dedup_key,omitempty"`
+	State         string          `json:"state"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	SentAt        *time.Time      `json:"sent_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+const (
+	PresenceViewing = "viewing"
+	PresenceEditing = "editing"
+	PresenceTyping  = "typing"
+	PresenceLeft    = "left"
+)
+
+// Presence — что пользователь сейчас делает в списке: смотрит его,
+// редактирует или набирает текст задачи TaskID.
+type Presence struct {
+	ListID string    `json:"list_id"`
+	TaskID string    `json:"task_id,omitempty"`
+	UserID string    `json:"user_id"`
+	State  string    `json:"state"`
+	At     time.Time `json:"at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Виды распознанных фрагментов текста быстрого добавления.
+const (
+	QuickAddDate       = "date"
+	QuickAddTime       = "time"
+	QuickAddLabel      = "label"
+	QuickAddPriority   = "priority"
+	QuickAddRecurrence = "recurrence"
+)
+
+// QuickAddPart — распознанный фрагмент исходного текста. Start и End —
+// смещения в символах (End не включается); Value — нормализованное
+// значение: дата ГГГГ-ММ-ДД, время ЧЧ:ММ, метка, приоритет или правило
+// повторения.
+type QuickAddPart struct {
+	Kind  string `json:"kind"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Value string `json:"value"`
+}
+
+// QuickAdd — результат разбора текста быстрого добавления. Title — текст
+// без распознанных фрагментов.
+type QuickAdd struct {
+	Input      string         `json:"input"`
+	Title      string         `json:"title"`
+	DueAt      *time.Time     `json:"due_at,omitempty"`
+	Labels     []string       `json:"labels"`
+	Priority   string         `json:"priority,omitempty"`
+	Recurrence string         `json:"recurrence,omitempty"`
+	Parts      []QuickAddPart `json:"parts"`
+}
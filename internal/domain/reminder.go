@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// EventTaskReminder — сработало напоминание о задаче. Приходит вебхукам
+// и в поток изменений списка.
+const EventTaskReminder = "task.reminder"
+
+const (
+	ReminderPending   = "pending"
+	ReminderFired     = "fired"
+	ReminderDismissed = "dismissed"
+)
+
+// Каналы, по которым доставляется сработавшее напоминание.
+const (
+	ReminderChannelEmail   = "email"
+	ReminderChannelWebhook = "webhook"
+	ReminderChannelSSE     = "sse"
+)
+
+// ReminderChannels — каналы напоминаний; по умолчанию используются все.
+var ReminderChannels = []string{ReminderChannelEmail, ReminderChannelWebhook, ReminderChannelSSE}
+
+// Reminder — напоминание пользователя о задаче. Задаётся либо временем
+// RemindAt, либо смещением DueOffsetMinutes от срока задачи; относительное
+// напоминание следует за переносом срока.
+type Reminder struct {
+	ID     string `json:"id"`
+	TaskID string `json:"task_id"`
+	ListID string `json:"list_id"`
+	UserID string `json:"user_id"`
+
+	RemindAt *time.Time `json:"remind_at,omitempty"`
+	// DueOffsetMinutes — смещение от срока задачи, отрицательное — до срока.
+	DueOffsetMinutes *int     `json:"due_offset_minutes,omitempty"`
+	Channels         []string `json:"channels"`
+
+	State        string     `json:"state"`
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	// FireAt — когда напоминание сработает; nil, если оно относительное,
+	// а у задачи нет срока.
+	FireAt    *time.Time `json:"fire_at,omitempty"`
+	FiredAt   *time.Time `json:"fired_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Task — задача на момент срабатывания; заполняется только для
+	// сработавших напоминаний, которые передаются в каналы.
+	Task *Task `json:"task,omitempty"`
+}
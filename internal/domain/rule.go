@@ -0,0 +1,110 @@
+package domain
+
+import "time"
+
+// Триггеры правил автоматизации. Задачные триггеры совпадают с событиями
+// вебхуков; list.completed срабатывает, когда в списке не осталось
+// невыполненных задач.
+const (
+	RuleTriggerTaskCreated   = EventTaskCreated
+	RuleTriggerTaskUpdated   = EventTaskUpdated
+	RuleTriggerTaskCompleted = EventTaskCompleted
+	RuleTriggerListCompleted = "list.completed"
+)
+
+var RuleTriggers = []string{
+	RuleTriggerTaskCreated, RuleTriggerTaskUpdated, RuleTriggerTaskCompleted, RuleTriggerListCompleted,
+}
+
+// Поля задачи, которые проверяют условия правил.
+const (
+	RuleFieldText      = "text"
+	RuleFieldAssignee  = "assignee"
+	RuleFieldCompleted = "completed"
+	RuleFieldDueAt     = "due_at"
+)
+
+// Операторы условий. contains и equals для текста не учитывают регистр;
+// is_set и not_set не используют значение.
+const (
+	RuleOpContains  = "contains"
+	RuleOpEquals    = "equals"
+	RuleOpNotEquals = "not_equals"
+	RuleOpIsSet     = "is_set"
+	RuleOpNotSet    = "not_set"
+)
+
+// Действия правил. Задачные действия применяются к задаче события,
+// archive_list — к списку правила.
+const (
+	RuleActionMoveTask     = "move_task"
+	RuleActionAssign       = "assign"
+	RuleActionCompleteTask = "complete_task"
+	RuleActionArchiveList  = "archive_list"
+)
+
+// Результаты выполнения правила.
+const (
+	RuleExecutionApplied = "applied"
+	RuleExecutionFailed  = "failed"
+	// RuleExecutionLoop — правило не запущено: оно уже есть в цепочке
+	// правил, вызвавшей событие, или цепочка слишком длинная.
+	RuleExecutionLoop = "loop_prevented"
+)
+
+type RuleCondition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value string `json:"value,omitempty"`
+}
+
+type RuleAction struct {
+	Type     string `json:"type"`
+	ListID   string `json:"list_id,omitempty"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// Rule — правило автоматизации списка: при событии Trigger в списке ListID,
+// если выполнены все Conditions, по порядку выполняются Actions.
+type Rule struct {
+	ID         string          `json:"id"`
+	OwnerID    string          `json:"-"`
+	ListID     string          `json:"list_id"`
+	Name       string          `json:"name"`
+	Trigger    string          `json:"trigger"`
+	Conditions []RuleCondition `json:"conditions"`
+	Actions    []RuleAction    `json:"actions"`
+	Enabled    bool            `json:"enabled"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// RuleExecution — запись журнала выполнения правила.
+type RuleExecution struct {
+	ID      int64  `json:"id"`
+	RuleID  string `json:"rule_id"`
+	Trigger string `json:"trigger"`
+	ListID  string `json:"list_id"`
+	TaskID  string `json:"task_id,omitempty"`
+	Status  string `json:"status"`
+	// Actions — выполненные действия; при ошибке — те, что успели
+	// выполниться до неё и были откачены.
+	Actions   []RuleAction `json:"actions"`
+	Error     string       `json:"error,omitempty"`
+	Depth     int          `json:"depth"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// RuleTestResult — результат проверки правила без выполнения действий.
+type RuleTestResult struct {
+	Matched    bool                  `json:"matched"`
+	Conditions []RuleConditionResult `json:"conditions"`
+	// Actions — действия, которые выполнило бы правило; пусто, если
+	// условия не выполнены.
+	Actions []RuleAction `json:"actions"`
+}
+
+type RuleConditionResult struct {
+	RuleCondition
+	Matched bool `json:"matched"`
+}
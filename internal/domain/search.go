@@ -0,0 +1,23 @@
+package domain
+
+const (
+	SearchHitList = "list"
+	SearchHitTask = "task"
+)
+
+type SearchHit struct {
+	Type    string  `json:"type"`
+	ID      string  `json:"id"`
+	ListID  string  `json:"list_id"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	Rank    float32 `json:"rank"`
+}
+
+type Suggestion struct {
+	Type   string  `json:"type"`
+	ID     string  `json:"id"`
+	ListID string  `json:"list_id"`
+	Text   string  `json:"text"`
+	Score  float32 `json:"score"`
+}
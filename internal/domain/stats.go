@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+const (
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+)
+
+// StatsRange — интервал [From, To), за который считается скорость выполнения.
+type StatsRange struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+}
+
+type VelocityPoint struct {
+	PeriodStart time.Time `json:"period_start"`
+	Completed   int       `json:"completed"`
+}
+
+type TaskStats struct {
+	ListID         string    `json:"list_id,omitempty"`
+	Total          int       `json:"total"`
+	Completed      int       `json:"completed"`
+	Open           int       `json:"open"`
+	CompletionRate float64   `json:"completion_rate"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	Interval       string    `json:"interval"`
+	// AvgTimeToComplete — среднее время от создания до выполнения (в секундах)
+	// для задач, выполненных в интервале; nil, если таких задач нет.
+	AvgTimeToComplete *float64        `json:"avg_time_to_complete_seconds"`
+	Velocity          []VelocityPoint `json:"velocity"`
+}
+
+// TaskCounts — количество задач списка без учёта интервала.
+type TaskCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Open      int `json:"open"`
+}
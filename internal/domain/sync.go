@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	SyncOpUpsert = "upsert"
+	SyncOpDelete = "delete"
+
+	// Причины конфликтов при отправке изменений.
+	SyncConflictStale    = "stale"    // поле на сервере изменено позже
+	SyncConflictDeleted  = "deleted"  // сущность удалена на сервере позже
+	SyncConflictModified = "modified" // удаление проиграло более позднему изменению
+	SyncConflictInvalid  = "invalid"  // изменение не прошло проверку
+)
+
+// FieldClock — время последнего изменения каждого синхронизируемого поля.
+type FieldClock map[string]time.Time
+
+// SyncCursor — позиция в потоке изменений. Окно включает изменения
+// транзакций с номерами из [Since, Until); AfterSeq продолжает страницу
+// внутри окна. Until = 0 — окно ещё не открыто.
+type SyncCursor struct {
+	Since    uint64
+	Until    uint64
+	AfterSeq int64
+}
+
+// SyncTombstone — удалённая сущность.
+type SyncTombstone struct {
+	EntityType string    `json:"entity_type"`
+	ID         string    `json:"id"`
+	ListID     string    `json:"list_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+// SyncPage — изменения с момента курсора. Next — курсор следующего запроса.
+type SyncPage struct {
+	Lists   []*List         `json:"lists"`
+	Tasks   []*Task         `json:"tasks"`
+	Deleted []SyncTombstone `json:"deleted"`
+	HasMore bool            `json:"has_more"`
+	Next    SyncCursor      `json:"-"`
+}
+
+// SyncChange — изменение, сделанное клиентом офлайн. ModifiedAt — время
+// изменения на клиенте; по нему разрешаются конфликты по каждому полю.
+type SyncChange struct {
+	EntityType string                     `json:"entity_type"`
+	Op         string                     `json:"op"`
+	ID         string                     `json:"id"`
+	ListID     string                     `json:"list_id,omitempty"`
+	Fields     map[string]json.RawMessage `json:"fields,omitempty"`
+	ModifiedAt time.Time                  `json:"modified_at"`
+}
+
+type SyncApplied struct {
+	EntityType string   `json:"entity_type"`
+	ID         string   `json:"id"`
+	Op         string   `json:"op"`
+	Fields     []string `json:"fields,omitempty"`
+}
+
+// SyncConflict — изменение клиента или его поле, которое не применено.
+type SyncConflict struct {
+	EntityType  string          `json:"entity_type"`
+	ID          string          `json:"id"`
+	Field       string          `json:"field,omitempty"`
+	Reason      string          `json:"reason"`
+	Message     string          `json:"message,omitempty"`
+	ServerValue any             `json:"server_value,omitempty"`
+	ClientValue json.RawMessage `json:"client_value,omitempty"`
+}
+
+type SyncPushResult struct {
+	Applied   []SyncApplied  `json:"applied"`
+	Conflicts []SyncConflict `json:"conflicts"`
+}
@@ -2,11 +2,33 @@ package domain
 
 import "time"
 
+// Приоритеты задачи; пустая строка — приоритет не задан.
+const (
+	TaskPriorityLow    = "low"
+	TaskPriorityMedium = "medium"
+	TaskPriorityHigh   = "high"
+)
+
+// Правила повторения задачи; пустая строка — задача не повторяется.
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+	RecurrenceYearly  = "yearly"
+)
+
 type Task struct {
-	ID        string    `json:"id"`
-	ListID    string    `json:"list_id"`
-	Text      string    `json:"text"`
-	Completed bool      `json:"completed"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string     `json:"id"`
+	ListID      string     `json:"list_id"`
+	Text        string     `json:"text"`
+	Completed   bool       `json:"completed"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Assignee    string     `json:"assignee,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	Version     int64      `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
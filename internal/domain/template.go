@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// TemplateStartDate — встроенная переменная шаблона: дата начала, от
+// которой отсчитываются сроки задач. По умолчанию — сегодня.
+const TemplateStartDate = "start_date"
+
+// TemplateTask — задача шаблона. Срок задаётся относительно даты начала:
+// DueOffsetDays дней после неё, в DueTime (ЧЧ:ММ, пусто — полночь)
+// в часовом поясе пользователя; nil — без срока.
+type TemplateTask struct {
+	Text          string   `json:"text"`
+	DueOffsetDays *int     `json:"due_offset_days,omitempty"`
+	DueTime       string   `json:"due_time,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	Priority      string   `json:"priority,omitempty"`
+	Recurrence    string   `json:"recurrence,omitempty"`
+}
+
+// Template — шаблон списка. В Title, Description и текстах задач можно
+// использовать переменные {{имя}}: они подставляются при создании списка
+// по шаблону. Variables — имена переменных, встречающихся в шаблоне.
+// Общий (Shared) шаблон видят и используют все пользователи, меняет
+// только владелец.
+type Template struct {
+	ID          string         `json:"id"`
+	OwnerID     string         `json:"-"`
+	Name        string         `json:"name"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Tasks       []TemplateTask `json:"tasks"`
+	Variables   []string       `json:"variables"`
+	Shared      bool           `json:"shared"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
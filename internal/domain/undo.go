@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+const (
+	UndoCreateList    = "list.create"
+	UndoUpdateList    = "list.update"
+	UndoDeleteList    = "list.delete"
+	UndoCreateTask    = "task.create"
+	UndoUpdateTask    = "task.update"
+	UndoDeleteTask    = "task.delete"
+	UndoCompleteTasks = "task.complete_bulk"
+)
+
+// Snapshot — состояние сущностей, затронутых одной операцией.
+type Snapshot struct {
+	Lists []*List `json:"lists"`
+	Tasks []*Task `json:"tasks"`
+}
+
+// UndoEntry хранит состояние до и после операции. Сущность, которой нет
+// в Before, была создана операцией; которой нет в After — удалена.
+type UndoEntry struct {
+	Token      string     `json:"token"`
+	Actor      string     `json:"actor"`
+	Action     string     `json:"action"`
+	Before     Snapshot   `json:"before"`
+	After      Snapshot   `json:"after"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RedeemedAt *time.Time `json:"redeemed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// UndoResult — итог отмены: восстановленные сущности и удалённые
+// (созданные отменённой операцией).
+type UndoResult struct {
+	Action   string   `json:"action"`
+	Restored Snapshot `json:"restored"`
+	Removed  Snapshot `json:"removed"`
+}
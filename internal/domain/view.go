@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Period — относительный интервал времени, который вычисляется
+// в момент применения фильтра.
+type Period string
+
+const (
+	PeriodToday     Period = "today"
+	PeriodThisWeek  Period = "this_week"
+	PeriodThisMonth Period = "this_month"
+	PeriodLast7Days Period = "last_7_days"
+	PeriodNext7Days Period = "next_7_days"
+	PeriodOverdue   Period = "overdue"
+	PeriodUpcoming  Period = "upcoming"
+	PeriodNoDueDate Period = "none"
+)
+
+// TaskFilter — определение сохранённого фильтра задач.
+// Пустые поля не ограничивают выборку.
+type TaskFilter struct {
+	ListIDs     []string   `json:"list_ids,omitempty"`
+	Completed   *bool      `json:"completed,omitempty"`
+	Text        string     `json:"text,omitempty"`
+	CreatedIn   Period     `json:"created_in,omitempty"`
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+	DueIn       Period     `json:"due_in,omitempty"`
+	DueFrom     *time.Time `json:"due_from,omitempty"`
+	DueTo       *time.Time `json:"due_to,omitempty"`
+}
+
+// TaskQuery — фильтр с вычисленными абсолютными границами интервалов.
+type TaskQuery struct {
+	ListIDs     []string
+	Completed   *bool
+	Text        string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	NoDueDate   bool
+}
+
+type View struct {
+	ID        string     `json:"id"`
+	OwnerID   string     `json:"owner_id,omitempty"`
+	Name      string     `json:"name"`
+	Filter    TaskFilter `json:"filter"`
+	BuiltIn   bool       `json:"built_in"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
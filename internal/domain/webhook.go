@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventTaskCompleted — задача отмечена выполненной. Отправляется вебхукам
+// вместе с task.updated.
+const EventTaskCompleted = "task.completed"
+
+// WebhookEventTypes — события, на которые можно подписать вебхук.
+var WebhookEventTypes = []string{
+	EventListCreated, EventListUpdated, EventListDeleted,
+	EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted,
+	EventTaskReminder,
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+type WebhookSubscription struct {
+	ID         string    `json:"id"`
+	OwnerID    string    `json:"-"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	ListID     string    `json:"list_id,omitempty"`
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery — доставка одного события одной подписке.
+type WebhookDelivery struct {
+	ID             int64            `json:"id"`
+	SubscriptionID string           `json:"subscription_id"`
+	EventID        int64            `json:"event_id"`
+	EventType      string           `json:"event_type"`
+	State          string           `json:"state"`
+	Attempts       int              `json:"attempts"`
+	NextAttemptAt  time.Time        `json:"next_attempt_at"`
+	LastStatusCode *int             `json:"last_status_code,omitempty"`
+	LastError      string           `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Payload        json.RawMessage  `json:"payload,omitempty"`
+	Log            []WebhookAttempt `json:"log,omitempty"`
+
+	// URL и Secret подписки нужны только для отправки.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt — одна попытка доставки.
+type WebhookAttempt struct {
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int       `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
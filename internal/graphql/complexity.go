@@ -0,0 +1,504 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// listFields — поля-списки схемы: стоимость вложенного выбора умножается
+// на число элементов из аргумента или на его значение по умолчанию.
+var listFields = map[string]struct {
+	arg string
+	def int
+}{
+	"lists": {arg: "limit", def: 20},
+	"tasks": {arg: "first", def: 50},
+}
+
+var errMalformedQuery = errors.New("malformed query")
+
+// operation — разобранная операция документа в объёме, нужном для оценки стоимости.
+type operation struct {
+	kind      string // query, mutation, subscription
+	name      string
+	selection []*selection
+	varDefs   map[string]any
+}
+
+// selection — поле, фрагмент-спред (spread) или inline-фрагмент (только children).
+type selection struct {
+	name     string
+	spread   string
+	args     map[string]any
+	children []*selection
+}
+
+type document struct {
+	operations []*operation
+	fragments  map[string][]*selection
+}
+
+// pickOperation выбирает операцию так же, как это делает исполнитель.
+func (d *document) pickOperation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) != 1 {
+			return nil, errors.New("operationName is required for documents with several operations")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("operation %q not found", name)
+}
+
+// complexity оценивает стоимость операции до выполнения: каждое поле стоит 1,
+// а поля-списки умножают стоимость вложенных полей на число элементов.
+func (d *document) complexity(op *operation, variables map[string]any) int {
+	c := &costCounter{doc: d, op: op, vars: variables, visiting: make(map[string]bool)}
+	return c.cost(op.selection)
+}
+
+type costCounter struct {
+	doc      *document
+	op       *operation
+	vars     map[string]any
+	visiting map[string]bool
+}
+
+func (c *costCounter) cost(sels []*selection) int {
+	total := 0
+	for _, s := range sels {
+		switch {
+		case s.spread != "":
+			// Циклы фрагментов отсекает валидация; защита на случай, если её не было.
+			if c.visiting[s.spread] {
+				continue
+			}
+			c.visiting[s.spread] = true
+			total = saturatingAdd(total, c.cost(c.doc.fragments[s.spread]))
+			delete(c.visiting, s.spread)
+		case s.name == "":
+			total = saturatingAdd(total, c.cost(s.children))
+		default:
+			inner := c.cost(s.children)
+			if lf, ok := listFields[s.name]; ok && len(s.children) > 0 {
+				inner = saturatingMul(inner, c.intArg(s.args, lf.arg, lf.def))
+			}
+			total = saturatingAdd(total, saturatingAdd(1, inner))
+		}
+	}
+	return total
+}
+
+func (c *costCounter) intArg(args map[string]any, name string, def int) int {
+	v, ok := args[name]
+	if !ok {
+		return def
+	}
+	if ref, ok := v.(varRef); ok {
+		if v, ok = c.vars[string(ref)]; !ok {
+			if v, ok = c.op.varDefs[string(ref)]; !ok {
+				return def
+			}
+		}
+	}
+	switch n := v.(type) {
+	case int:
+		return max(n, 0)
+	case float64:
+		return max(int(n), 0)
+	default:
+		return def
+	}
+}
+
+const maxCost = 1 << 30
+
+func saturatingAdd(a, b int) int { return min(a+b, maxCost) }
+
+func saturatingMul(a, b int) int {
+	if a != 0 && b > maxCost/a {
+		return maxCost
+	}
+	return min(a*b, maxCost)
+}
+
+// varRef — ссылка на переменную ($name) в аргументе.
+type varRef string
+
+// parseDocument разбирает документ GraphQL. Это не полноценный парсер:
+// значения, кроме чисел и переменных, пропускаются, типы не проверяются —
+// документ до этого уже проверен валидатором схемы.
+func parseDocument(src string) (*document, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	doc := &document{fragments: make(map[string][]*selection)}
+
+	for !p.eof() {
+		switch t := p.peek(); {
+		case t == "{":
+			sel, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selection: sel})
+		case t == "query" || t == "mutation" || t == "subscription":
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case t == "fragment":
+			p.next()
+			name := p.next()
+			if p.next() != "on" {
+				return nil, errMalformedQuery
+			}
+			p.next()
+			if err := p.skipDirectives(); err != nil {
+				return nil, err
+			}
+			sel, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[name] = sel
+		default:
+			return nil, errMalformedQuery
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, errMalformedQuery
+	}
+	return doc, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+type token struct {
+	text string
+	str  bool // строковый литерал
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.toks) }
+
+func (p *parser) peek() string {
+	if p.eof() {
+		return ""
+	}
+	if p.toks[p.pos].str {
+		return `"`
+	}
+	return p.toks[p.pos].text
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) operation() (*operation, error) {
+	op := &operation{kind: p.next(), varDefs: make(map[string]any)}
+	if t := p.peek(); t != "(" && t != "{" && t != "@" {
+		op.name = p.next()
+	}
+	if p.peek() == "(" {
+		p.next()
+		for p.peek() != ")" {
+			if p.eof() || p.next() != "$" {
+				return nil, errMalformedQuery
+			}
+			name := p.next()
+			if p.next() != ":" {
+				return nil, errMalformedQuery
+			}
+			if err := p.skipType(); err != nil {
+				return nil, err
+			}
+			if p.peek() == "=" {
+				p.next()
+				v, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				op.varDefs[name] = v
+			}
+			if err := p.skipDirectives(); err != nil {
+				return nil, err
+			}
+		}
+		p.next()
+	}
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	sel, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selection = sel
+	return op, nil
+}
+
+func (p *parser) skipType() error {
+	if p.peek() == "[" {
+		p.next()
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if p.next() != "]" {
+			return errMalformedQuery
+		}
+	} else if !isName(p.next()) {
+		return errMalformedQuery
+	}
+	if p.peek() == "!" {
+		p.next()
+	}
+	return nil
+}
+
+func (p *parser) selectionSet() ([]*selection, error) {
+	if p.next() != "{" {
+		return nil, errMalformedQuery
+	}
+	var sels []*selection
+	for p.peek() != "}" {
+		if p.eof() {
+			return nil, errMalformedQuery
+		}
+		s, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, s)
+	}
+	p.next()
+	return sels, nil
+}
+
+func (p *parser) selection() (*selection, error) {
+	if p.peek() == "..." {
+		p.next()
+		if t := p.peek(); t != "on" && t != "{" && t != "@" {
+			s := &selection{spread: p.next()}
+			return s, p.skipDirectives()
+		}
+		if p.peek() == "on" {
+			p.next()
+			p.next()
+		}
+		if err := p.skipDirectives(); err != nil {
+			return nil, err
+		}
+		children, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		return &selection{children: children}, nil
+	}
+
+	name := p.next()
+	if !isName(name) {
+		return nil, errMalformedQuery
+	}
+	if p.peek() == ":" {
+		p.next()
+		if name = p.next(); !isName(name) {
+			return nil, errMalformedQuery
+		}
+	}
+	s := &selection{name: name}
+	if p.peek() == "(" {
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		s.args = args
+	}
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek() == "{" {
+		children, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		s.children = children
+	}
+	return s, nil
+}
+
+func (p *parser) arguments() (map[string]any, error) {
+	p.next()
+	args := make(map[string]any)
+	for p.peek() != ")" {
+		name := p.next()
+		if !isName(name) || p.next() != ":" {
+			return nil, errMalformedQuery
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = v
+	}
+	p.next()
+	return args, nil
+}
+
+func (p *parser) skipDirectives() error {
+	for p.peek() == "@" {
+		p.next()
+		p.next()
+		if p.peek() == "(" {
+			if _, err := p.arguments(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// value возвращает int/float64 для чисел, varRef для переменных и nil для
+// остальных значений, которые для оценки стоимости не нужны.
+func (p *parser) value() (any, error) {
+	switch t := p.next(); {
+	case t == "":
+		return nil, errMalformedQuery
+	case t == "$":
+		return varRef(p.next()), nil
+	case t == "[" || t == "{":
+		closing := map[string]string{"[": "]", "{": "}"}[t]
+		for p.peek() != closing {
+			if p.eof() {
+				return nil, errMalformedQuery
+			}
+			if t == "{" {
+				p.next()
+				if p.next() != ":" {
+					return nil, errMalformedQuery
+				}
+			}
+			if _, err := p.value(); err != nil {
+				return nil, err
+			}
+		}
+		p.next()
+		return nil, nil
+	case t[0] == '-' || (t[0] >= '0' && t[0] <= '9'):
+		if n, err := strconv.Atoi(t); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(t, 64); err == nil {
+			return f, nil
+		}
+		return nil, errMalformedQuery
+	default:
+		return nil, nil
+	}
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize разбивает документ на лексемы, пропуская пробелы, запятые
+// и комментарии. Строки возвращаются одной лексемой.
+func tokenize(src string) ([]token, error) {
+	src = strings.TrimPrefix(src, "\uFEFF")
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			for end >= 0 && src[i+3+end-1] == '\\' {
+				next := strings.Index(src[i+3+end+3:], `"""`)
+				if next < 0 {
+					end = -1
+					break
+				}
+				end += 3 + next
+			}
+			if end < 0 {
+				return nil, errMalformedQuery
+			}
+			toks = append(toks, token{text: src[i+3 : i+3+end], str: true})
+			i += 3 + end + 3
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				if j < len(src) && src[j] == '\n' {
+					return nil, errMalformedQuery
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, errMalformedQuery
+			}
+			toks = append(toks, token{text: src[i+1 : j], str: true})
+			i = j + 1
+		case strings.HasPrefix(src[i:], "..."):
+			toks = append(toks, token{text: "..."})
+			i += 3
+		case strings.ContainsRune("{}()[]:=!$@|&", rune(c)):
+			toks = append(toks, token{text: string(c)})
+			i++
+		case c == '-' || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			j := i + 1
+			for j < len(src) {
+				d := src[j]
+				if d == '_' || d == '.' || d == '+' || d == '-' || (d >= '0' && d <= '9') || (d >= 'a' && d <= 'z') || (d >= 'A' && d <= 'Z') {
+					// Знаки и точка допустимы только внутри чисел.
+					if (d == '.' || d == '+' || d == '-') && !isNumberStart(src[i]) {
+						break
+					}
+					j++
+					continue
+				}
+				break
+			}
+			toks = append(toks, token{text: src[i:j]})
+			i = j
+		default:
+			return nil, errMalformedQuery
+		}
+	}
+	return toks, nil
+}
+
+func isNumberStart(c byte) bool {
+	return c == '-' || (c >= '0' && c <= '9')
+}
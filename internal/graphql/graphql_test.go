@@ -0,0 +1,451 @@
+package graphql
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+)
+
+var errDBDown = errors.New("connection refused")
+
+// err у фейков, если задан, возвращается из GetByID вместо результата.
+type fakeListRepo struct {
+	lists []*domain.List
+	err   error
+}
+
+func (r *fakeListRepo) Create(ctx context.Context, l *domain.List) (*domain.List, error) {
+	r.lists = append(r.lists, l)
+	return l, nil
+}
+
+func (r *fakeListRepo) GetByID(ctx context.Context, id string) (*domain.List, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, l := range r.lists {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *fakeListRepo) Update(ctx context.Context, l *domain.List) error { return nil }
+func (r *fakeListRepo) Delete(ctx context.Context, id string) error      { return nil }
+
+func (r *fakeListRepo) GetAll(ctx context.Context) ([]*domain.List, int) {
+	return r.lists, len(r.lists)
+}
+
+func (r *fakeListRepo) FindWithPagination(ctx context.Context, limit, offset int) ([]*domain.List, int) {
+	end := min(offset+limit, len(r.lists))
+	if offset > end {
+		return nil, len(r.lists)
+	}
+	return r.lists[offset:end], len(r.lists)
+}
+
+func (r *fakeListRepo) SearchByTitle(ctx context.Context, query string) ([]domain.List, error) {
+	return nil, nil
+}
+
+func (r *fakeListRepo) Restore(ctx context.Context, l *domain.List, expectedVersion int64) error {
+	return nil
+}
+
+func (r *fakeListRepo) DeleteVersion(ctx context.Context, id string, version int64) error {
+	return nil
+}
+
+func (r *fakeListRepo) Clone(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error) {
+	return nil, nil
+}
+
+type fakeTaskRepo struct {
+	tasks []*domain.Task
+	err   error
+
+	listByListIDCalls  int
+	listByListIDsCalls int
+	countCalls         int
+}
+
+func (r *fakeTaskRepo) Create(ctx context.Context, t *domain.Task) error {
+	r.tasks = append(r.tasks, t)
+	return nil
+}
+
+func (r *fakeTaskRepo) CreateMany(ctx context.Context, tasks []*domain.Task) error {
+	r.tasks = append(r.tasks, tasks...)
+	return nil
+}
+
+func (r *fakeTaskRepo) GetByID(ctx context.Context, id string) (*domain.Task, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, t := range r.tasks {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *fakeTaskRepo) ListByListID(ctx context.Context, listID string, limit, offset int) ([]*domain.Task, int, error) {
+	r.listByListIDCalls++
+	return nil, 0, nil
+}
+
+func (r *fakeTaskRepo) ListByListIDs(ctx context.Context, listIDs []string, perList int) ([]*domain.Task, error) {
+	r.listByListIDsCalls++
+	var res []*domain.Task
+	for _, t := range r.tasks {
+		for _, id := range listIDs {
+			if t.ListID == id {
+				res = append(res, t)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeTaskRepo) CountByListIDs(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error) {
+	r.countCalls++
+	res := make(map[string]domain.TaskCounts)
+	for _, t := range r.tasks {
+		c := res[t.ListID]
+		c.Total++
+		if t.Completed {
+			c.Completed++
+		} else {
+			c.Open++
+		}
+		res[t.ListID] = c
+	}
+	return res, nil
+}
+
+func (r *fakeTaskRepo) FindByQuery(ctx context.Context, q domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeTaskRepo) Update(ctx context.Context, t *domain.Task) error { return nil }
+func (r *fakeTaskRepo) Delete(ctx context.Context, id string) error      { return nil }
+
+func (r *fakeTaskRepo) ListAllByListID(ctx context.Context, listID string) ([]*domain.Task, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) Restore(ctx context.Context, t *domain.Task, expectedVersion int64) error {
+	return nil
+}
+
+func (r *fakeTaskRepo) DeleteVersion(ctx context.Context, id string, version int64) error {
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeAuditRepo struct{}
+
+func (fakeAuditRepo) Append(ctx context.Context, e *domain.AuditEvent) error { return nil }
+
+func (fakeAuditRepo) ListByList(ctx context.Context, listID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
+func (fakeAuditRepo) ListByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
+func newTestHandler(t *testing.T, cfg Config) (*Handler, *fakeListRepo, *fakeTaskRepo) {
+	t.Helper()
+	now := time.Now()
+	lists := &fakeListRepo{}
+	tasks := &fakeTaskRepo{}
+	for _, id := range []string{"l1", "l2", "l3"} {
+		lists.lists = append(lists.lists, &domain.List{ID: id, Title: "List " + id, CreatedAt: now})
+		tasks.tasks = append(tasks.tasks,
+			&domain.Task{ID: id + "-a", ListID: id, Text: "a", CreatedAt: now, UpdatedAt: now},
+			&domain.Task{ID: id + "-b", ListID: id, Text: "b", Completed: true, CreatedAt: now, UpdatedAt: now},
+		)
+	}
+
+	ls := service.NewListService(lists, tasks, fakeTransactor{}, fakeAuditRepo{}, nil, nil)
+	ts := service.NewTaskService(tasks, lists, fakeTransactor{}, fakeAuditRepo{}, nil, nil)
+	h, err := NewHandler(ls, ts, service.NewEventBroker(16), cfg)
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h, lists, tasks
+}
+
+var defaultTestConfig = Config{MaxDepth: 8, MaxComplexity: 5000, Heartbeat: time.Second}
+
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, h http.Handler, query string, variables map[string]any) gqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var resp gqlResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func TestListsWithTasksAreBatched(t *testing.T) {
+	h, _, tasks := newTestHandler(t, defaultTestConfig)
+
+	resp := post(t, h, `{
+		lists(limit: 10) {
+			totalCount
+			nodes { id openCount tasks(first: 5) { id text list { id } } }
+		}
+	}`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+
+	var data struct {
+		Lists struct {
+			TotalCount int
+			Nodes      []struct {
+				ID        string
+				OpenCount int
+				Tasks     []struct {
+					ID   string
+					List struct{ ID string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Lists.TotalCount != 3 || len(data.Lists.Nodes) != 3 {
+		t.Fatalf("lists = %+v", data.Lists)
+	}
+	for _, n := range data.Lists.Nodes {
+		if len(n.Tasks) != 2 || n.OpenCount != 1 {
+			t.Errorf("list %s: tasks = %d, open = %d", n.ID, len(n.Tasks), n.OpenCount)
+		}
+		for _, task := range n.Tasks {
+			if task.List.ID != n.ID {
+				t.Errorf("task %s: list = %q, want %q", task.ID, task.List.ID, n.ID)
+			}
+		}
+	}
+
+	if tasks.listByListIDsCalls != 1 || tasks.countCalls != 1 {
+		t.Errorf("ListByListIDs calls = %d, CountByListIDs calls = %d, want 1 and 1",
+			tasks.listByListIDsCalls, tasks.countCalls)
+	}
+	if tasks.listByListIDCalls != 0 {
+		t.Errorf("ListByListID calls = %d, want 0", tasks.listByListIDCalls)
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	h, _, tasks := newTestHandler(t, Config{MaxDepth: 4, MaxComplexity: 1000, Heartbeat: time.Second})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		code      string
+	}{
+		{
+			name:  "too deep",
+			query: `{ task(id: "l1-a") { list { tasks { list { tasks { id } } } } } }`,
+		},
+		{
+			name:  "too complex",
+			query: `{ lists(limit: 100) { nodes { tasks(first: 500) { id text } } } }`,
+			code:  "QUERY_TOO_COMPLEX",
+		},
+		{
+			name:      "too complex via variables and fragments",
+			query:     `query Q($n: Int) { lists { nodes { ...F } } } fragment F on List { tasks(first: $n) { id } }`,
+			variables: map[string]any{"n": 100},
+			code:      "QUERY_TOO_COMPLEX",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(t, h, tt.query, tt.variables)
+			if len(resp.Errors) == 0 {
+				t.Fatal("expected an error")
+			}
+			if resp.Data != nil && string(resp.Data) != "null" {
+				t.Errorf("data = %s, want none", resp.Data)
+			}
+			if tt.code != "" && resp.Errors[0].Extensions["code"] != tt.code {
+				t.Errorf("code = %v, want %s", resp.Errors[0].Extensions["code"], tt.code)
+			}
+		})
+	}
+	if tasks.listByListIDsCalls != 0 {
+		t.Errorf("rejected queries must not reach the repository")
+	}
+}
+
+func TestMutationErrorsCarryCode(t *testing.T) {
+	h, _, _ := newTestHandler(t, defaultTestConfig)
+
+	resp := post(t, h, `mutation { createList(input: {title: ""}) { list { id } undoToken } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "VALIDATION_FAILED" {
+		t.Fatalf("errors = %+v, want VALIDATION_FAILED", resp.Errors)
+	}
+
+	resp = post(t, h, `mutation { createTask(listId: "missing", input: {text: "x"}) { task { id } } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "NOT_FOUND" {
+		t.Fatalf("errors = %+v, want NOT_FOUND", resp.Errors)
+	}
+}
+
+func TestStorageErrorsAreNotReportedAsNotFound(t *testing.T) {
+	h, lists, tasks := newTestHandler(t, defaultTestConfig)
+
+	resp := post(t, h, `{ list(id: "missing") { id } task(id: "missing") { id } }`, nil)
+	if len(resp.Errors) != 0 || string(resp.Data) != `{"list":null,"task":null}` {
+		t.Fatalf("missing: data = %s, errors = %+v", resp.Data, resp.Errors)
+	}
+
+	lists.err, tasks.err = errDBDown, errDBDown
+	for _, query := range []string{
+		`{ list(id: "l1") { id } }`,
+		`{ task(id: "l1-a") { id } }`,
+		`mutation { createTask(listId: "l1", input: {text: "x"}) { task { id } } }`,
+	} {
+		resp := post(t, h, query, nil)
+		if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "INTERNAL_ERROR" {
+			t.Errorf("%s: errors = %+v, want INTERNAL_ERROR", query, resp.Errors)
+			continue
+		}
+		if strings.Contains(resp.Errors[0].Message, errDBDown.Error()) {
+			t.Errorf("%s: storage error leaked: %q", query, resp.Errors[0].Message)
+		}
+	}
+}
+
+func TestBatchLoaderFetchesWithoutLock(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := make(chan []string, 4)
+	l := newBatchLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		calls <- keys
+		res := map[string]int{}
+		for _, k := range keys {
+			if k == "slow" {
+				close(started)
+				<-release
+			}
+			res[k] = len(k)
+		}
+		return res, nil
+	})
+
+	slow := make(chan int)
+	go func() {
+		v, _ := l.load(context.Background(), "slow")
+		slow <- v
+	}()
+	<-started
+
+	// Пока идёт загрузка slow, другие ключи загружаются, а не ждут её.
+	if v, err := l.load(context.Background(), "fast"); err != nil || v != 4 {
+		t.Fatalf("fast = %d, %v", v, err)
+	}
+	// Повторное обращение к slow ждёт идущую загрузку, а не начинает новую.
+	again := make(chan int)
+	go func() {
+		v, _ := l.load(context.Background(), "slow")
+		again <- v
+	}()
+	close(release)
+	if v := <-slow; v != 4 {
+		t.Errorf("slow = %d", v)
+	}
+	if v := <-again; v != 4 {
+		t.Errorf("slow again = %d", v)
+	}
+	if len(calls) != 2 {
+		t.Errorf("fetch calls = %d, want 2", len(calls))
+	}
+}
+
+func TestSubscriptionStreamsChangeEvents(t *testing.T) {
+	h, _, _ := newTestHandler(t, defaultTestConfig)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	body := `{"query":"subscription { listEvents(listId: \"l1\") { type taskId task { text } } }"}`
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// Подписка регистрируется асинхронно: публикуем, пока не придёт событие.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		task := &domain.Task{ID: "l1-c", ListID: "l1", Text: "new"}
+		for {
+			h.resolver.broker.Publish(domain.ChangeEvent{Type: domain.EventTaskCreated, ListID: "l1", TaskID: task.ID, Task: task})
+			select {
+			case <-stop:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "event: next" {
+			sc.Scan()
+			want := `data: {"data":{"listEvents":{"type":"task.created","taskId":"l1-c","task":{"text":"new"}}}}`
+			if sc.Text() != want {
+				t.Fatalf("got %s, want %s", sc.Text(), want)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended without events: %v", sc.Err())
+}
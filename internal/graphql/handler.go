@@ -0,0 +1,191 @@
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"todo-api/internal/service"
+
+	gographql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Config — ограничения запросов и параметры потока подписок.
+type Config struct {
+	MaxDepth      int
+	MaxComplexity int
+	Heartbeat     time.Duration
+}
+
+// Handler обслуживает /graphql: запросы и мутации через GET и POST,
+// подписки — потоком Server-Sent Events (Accept: text/event-stream).
+type Handler struct {
+	schema   *gographql.Schema
+	resolver *Resolver
+	cfg      Config
+}
+
+func NewHandler(lists service.ListService, tasks *service.TaskService, broker *service.EventBroker, cfg Config) (*Handler, error) {
+	resolver := &Resolver{lists: lists, tasks: tasks, broker: broker}
+	schema, err := gographql.ParseSchema(schemaSDL, resolver,
+		gographql.UseStringDescriptions(),
+		gographql.MaxDepth(cfg.MaxDepth),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse graphql schema: %w", err)
+	}
+	return &Handler{schema: schema, resolver: resolver, cfg: cfg}, nil
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type errorResponse struct {
+	Errors []responseError `json:"errors"`
+}
+
+type responseError struct {
+	Message    string         `json:"message"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, "VALIDATION_FAILED", "variables must be a JSON object")
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "VALIDATION_FAILED", "invalid JSON")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "VALIDATION_FAILED", "method not allowed")
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION_FAILED", "query is required")
+		return
+	}
+
+	// Ошибки синтаксиса, типов и глубины возвращает валидатор схемы.
+	if errs := h.schema.ValidateWithVariables(req.Query, req.Variables); len(errs) > 0 {
+		writeJSON(w, http.StatusOK, &gographql.Response{Errors: errs})
+		return
+	}
+
+	doc, err := parseDocument(req.Query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "VALIDATION_FAILED", err.Error())
+		return
+	}
+	op, err := doc.pickOperation(req.OperationName)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "VALIDATION_FAILED", err.Error())
+		return
+	}
+	if cost := doc.complexity(op, req.Variables); cost > h.cfg.MaxComplexity {
+		writeError(w, http.StatusOK, "QUERY_TOO_COMPLEX",
+			fmt.Sprintf("query complexity %d exceeds the limit of %d", cost, h.cfg.MaxComplexity))
+		return
+	}
+
+	if r.Method == http.MethodGet && op.kind == "mutation" {
+		w.Header().Set("Allow", "POST")
+		writeError(w, http.StatusMethodNotAllowed, "VALIDATION_FAILED", "mutations require POST")
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.resolver.lists, h.resolver.tasks))
+	if op.kind == "subscription" {
+		if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			writeError(w, http.StatusNotAcceptable, "VALIDATION_FAILED", "subscriptions require Accept: text/event-stream")
+			return
+		}
+		h.subscribe(w, r.WithContext(ctx), req)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+// subscribe отдаёт ответы подписки событиями next и завершает поток событием
+// complete (протокол GraphQL over SSE, режим отдельных соединений).
+func (h *Handler) subscribe(w http.ResponseWriter, r *http.Request, req request) {
+	ctx := r.Context()
+	responses, err := h.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal error")
+		return
+	}
+
+	// Поток живёт дольше WriteTimeout сервера.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.cfg.Heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case resp, ok := <-responses:
+			if !ok {
+				fmt.Fprint(w, "event: complete\ndata:\n\n")
+				_ = rc.Flush()
+				return
+			}
+			data, _ := json.Marshal(resp)
+			fmt.Fprintf(w, "event: next\ndata: %s\n\n", data)
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Errors: []responseError{{
+		Message:    message,
+		Extensions: map[string]any{"code": code},
+	}}})
+}
@@ -0,0 +1,175 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+)
+
+// batchLoader загружает значения по ключам пачками. Резолвер, вернувший
+// несколько сущностей, регистрирует их ключи через prime; первое обращение
+// к любому из них загружает сразу все зарегистрированные ключи.
+// Обращения к ключам уже идущей загрузки ждут её, не держа блокировку:
+// остальные ключи тем временем загружаются своими пачками.
+type batchLoader[V any] struct {
+	fetch func(ctx context.Context, keys []string) (map[string]V, error)
+
+	mu       sync.Mutex
+	pending  map[string]struct{}
+	loaded   map[string]V
+	inflight map[string]*batch[V]
+}
+
+// batch — идущая загрузка; done закрывается, когда res и err заполнены.
+type batch[V any] struct {
+	done chan struct{}
+	res  map[string]V
+	err  error
+}
+
+func newBatchLoader[V any](fetch func(ctx context.Context, keys []string) (map[string]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{
+		fetch:    fetch,
+		pending:  make(map[string]struct{}),
+		loaded:   make(map[string]V),
+		inflight: make(map[string]*batch[V]),
+	}
+}
+
+func (l *batchLoader[V]) prime(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, k := range keys {
+		_, loaded := l.loaded[k]
+		_, loading := l.inflight[k]
+		if !loaded && !loading {
+			l.pending[k] = struct{}{}
+		}
+	}
+}
+
+// set кладёт уже известное значение, чтобы его не загружать.
+func (l *batchLoader[V]) set(key string, v V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loaded[key] = v
+	delete(l.pending, key)
+}
+
+func (l *batchLoader[V]) load(ctx context.Context, key string) (V, error) {
+	var zero V
+	l.mu.Lock()
+	if v, ok := l.loaded[key]; ok {
+		l.mu.Unlock()
+		return v, nil
+	}
+	if b, ok := l.inflight[key]; ok {
+		l.mu.Unlock()
+		select {
+		case <-b.done:
+			return b.res[key], b.err
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	l.pending[key] = struct{}{}
+	keys := make([]string, 0, len(l.pending))
+	b := &batch[V]{done: make(chan struct{})}
+	for k := range l.pending {
+		keys = append(keys, k)
+		l.inflight[k] = b
+	}
+	clear(l.pending)
+	l.mu.Unlock()
+
+	b.res, b.err = l.fetch(ctx, keys)
+
+	l.mu.Lock()
+	for _, k := range keys {
+		// После ошибки ключи не кешируются: следующее обращение повторит
+		// загрузку.
+		if b.err == nil {
+			l.loaded[k] = b.res[k]
+		}
+		delete(l.inflight, k)
+	}
+	l.mu.Unlock()
+	close(b.done)
+
+	if b.err != nil {
+		return zero, b.err
+	}
+	return b.res[key], nil
+}
+
+// loaders — загрузчики одного запроса.
+type loaders struct {
+	lists  *batchLoader[*domain.List]
+	counts *batchLoader[domain.TaskCounts]
+
+	mu     sync.Mutex
+	tasks  map[int]*batchLoader[[]*domain.Task] // по значению аргумента first
+	primed []string
+	svc    *service.TaskService
+}
+
+func newLoaders(lists service.ListService, tasks *service.TaskService) *loaders {
+	return &loaders{
+		lists: newBatchLoader(func(ctx context.Context, ids []string) (map[string]*domain.List, error) {
+			// Пакетного чтения списков нет; сюда попадают только списки задач,
+			// запрошенных без своих списков, и они кешируются на запрос.
+			res := make(map[string]*domain.List, len(ids))
+			for _, id := range ids {
+				l, err := lists.GetByID(ctx, id)
+				if errors.Is(err, storage.ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				res[id] = l
+			}
+			return res, nil
+		}),
+		counts: newBatchLoader(tasks.CountTasksByLists),
+		tasks:  make(map[int]*batchLoader[[]*domain.Task]),
+		svc:    tasks,
+	}
+}
+
+func (l *loaders) tasksFor(first int) *batchLoader[[]*domain.Task] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if tl, ok := l.tasks[first]; ok {
+		return tl
+	}
+	tl := newBatchLoader(func(ctx context.Context, listIDs []string) (map[string][]*domain.Task, error) {
+		return l.svc.TasksByLists(ctx, listIDs, first)
+	})
+	tl.prime(l.primed...)
+	l.tasks[first] = tl
+	return tl
+}
+
+// primeLists регистрирует списки ответа во всех загрузчиках: их задачи
+// и счётчики загрузятся одной пачкой.
+func (l *loaders) primeLists(lists []*domain.List) {
+	ids := make([]string, len(lists))
+	for i, list := range lists {
+		ids[i] = list.ID
+		l.lists.set(list.ID, list)
+	}
+	l.counts.prime(ids...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.primed = append(l.primed, ids...)
+	for _, tl := range l.tasks {
+		tl.prime(ids...)
+	}
+}
@@ -0,0 +1,427 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	gographql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	maxListsLimit = 100
+	maxTasksFirst = 500
+)
+
+// gqlError — ошибка с кодом из REST-модели ошибок в extensions.code.
+type gqlError struct {
+	code    string
+	message string
+}
+
+func (e *gqlError) Error() string { return e.message }
+
+func (e *gqlError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+func validationError(msg string) error {
+	return &gqlError{code: "VALIDATION_FAILED", message: msg}
+}
+
+// toGQLError переводит ошибку сервиса так же, как это делают REST-обработчики.
+// Текст прочих ошибок клиенту не показывается, только пишется в лог.
+func toGQLError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidListTitle),
+		errors.Is(err, service.ErrInvalidTaskText),
+		errors.Is(err, service.ErrNoTaskIDs),
+		errors.Is(err, service.ErrTaskNotInList):
+		return validationError(err.Error())
+	case errors.Is(err, storage.ErrNotFound):
+		return &gqlError{code: "NOT_FOUND", message: "list or task not found"}
+	default:
+		logger.Info("graphql: " + err.Error())
+		return &gqlError{code: "INTERNAL_ERROR", message: "internal error"}
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, ld *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, ld)
+}
+
+// Resolver — корневой резолвер запросов, мутаций и подписок.
+type Resolver struct {
+	lists  service.ListService
+	tasks  *service.TaskService
+	broker *service.EventBroker
+}
+
+func (r *Resolver) loaders(ctx context.Context) *loaders {
+	if ld, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return ld
+	}
+	return newLoaders(r.lists, r.tasks)
+}
+
+func (r *Resolver) Lists(ctx context.Context, args struct {
+	Limit  int32
+	Offset int32
+}) (*listConnectionResolver, error) {
+	if args.Limit < 1 || args.Limit > maxListsLimit {
+		return nil, validationError("limit must be 1..100")
+	}
+	if args.Offset < 0 {
+		return nil, validationError("offset must be non-negative")
+	}
+
+	lists, total := r.lists.GetAllListsWithPagination(ctx, int(args.Limit), int(args.Offset))
+	ld := r.loaders(ctx)
+	ld.primeLists(lists)
+
+	nodes := make([]*listResolver, len(lists))
+	for i, l := range lists {
+		nodes[i] = &listResolver{l: l, ld: ld}
+	}
+	return &listConnectionResolver{total: total, nodes: nodes}, nil
+}
+
+func (r *Resolver) List(ctx context.Context, args struct{ ID gographql.ID }) (*listResolver, error) {
+	l, err := r.lists.GetByID(ctx, string(args.ID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	ld := r.loaders(ctx)
+	ld.primeLists([]*domain.List{l})
+	return &listResolver{l: l, ld: ld}, nil
+}
+
+func (r *Resolver) Task(ctx context.Context, args struct{ ID gographql.ID }) (*taskResolver, error) {
+	t, err := r.tasks.GetTask(ctx, string(args.ID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	return &taskResolver{t: t, ld: r.loaders(ctx)}, nil
+}
+
+type listInput struct {
+	Title       string
+	Description *string
+}
+
+func (in listInput) description() string {
+	if in.Description == nil {
+		return ""
+	}
+	return *in.Description
+}
+
+func (r *Resolver) CreateList(ctx context.Context, args struct{ Input listInput }) (*listPayloadResolver, error) {
+	l, token, err := r.lists.CreateList(ctx, args.Input.Title, args.Input.description())
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	return &listPayloadResolver{list: &listResolver{l: l, ld: r.loaders(ctx)}, token: token}, nil
+}
+
+func (r *Resolver) UpdateList(ctx context.Context, args struct {
+	ID    gographql.ID
+	Input listInput
+}) (*listPayloadResolver, error) {
+	l, token, err := r.lists.UpdateList(ctx, string(args.ID), args.Input.Title, args.Input.description())
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	return &listPayloadResolver{list: &listResolver{l: l, ld: r.loaders(ctx)}, token: token}, nil
+}
+
+func (r *Resolver) DeleteList(ctx context.Context, args struct{ ID gographql.ID }) (*deletePayloadResolver, error) {
+	token, err := r.lists.Delete(ctx, string(args.ID))
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	return &deletePayloadResolver{id: args.ID, token: token}, nil
+}
+
+func (r *Resolver) CreateTask(ctx context.Context, args struct {
+	ListID gographql.ID
+	Input  struct {
+		Text  string
+		DueAt *gographql.Time
+	}
+}) (*taskPayloadResolver, error) {
+	t, token, err := r.tasks.CreateTask(ctx, string(args.ListID), args.Input.Text, fromGQLTime(args.Input.DueAt))
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	return &taskPayloadResolver{task: &taskResolver{t: t, ld: r.loaders(ctx)}, token: token}, nil
+}
+
+func (r *Resolver) UpdateTask(ctx context.Context, args struct {
+	ID    gographql.ID
+	Input struct {
+		Text      *string
+		Completed *bool
+		DueAt     *gographql.Time
+	}
+}) (*taskPayloadResolver, error) {
+	var text string
+	if args.Input.Text != nil {
+		if *args.Input.Text == "" {
+			return nil, toGQLError(service.ErrInvalidTaskText)
+		}
+		text = *args.Input.Text
+	}
+	t, token, err := r.tasks.UpdateTask(ctx, string(args.ID), text, args.Input.Completed, fromGQLTime(args.Input.DueAt))
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	return &taskPayloadResolver{task: &taskResolver{t: t, ld: r.loaders(ctx)}, token: token}, nil
+}
+
+func (r *Resolver) DeleteTask(ctx context.Context, args struct{ ID gographql.ID }) (*deletePayloadResolver, error) {
+	token, err := r.tasks.DeleteTask(ctx, string(args.ID))
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	return &deletePayloadResolver{id: args.ID, token: token}, nil
+}
+
+func (r *Resolver) CompleteTasks(ctx context.Context, args struct {
+	ListID gographql.ID
+	IDs    []gographql.ID
+}) (*completeTasksPayloadResolver, error) {
+	ids := make([]string, len(args.IDs))
+	for i, id := range args.IDs {
+		ids[i] = string(id)
+	}
+	tasks, token, err := r.tasks.CompleteTasks(ctx, string(args.ListID), ids)
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	ld := r.loaders(ctx)
+	res := make([]*taskResolver, len(tasks))
+	for i, t := range tasks {
+		res[i] = &taskResolver{t: t, ld: ld}
+	}
+	return &completeTasksPayloadResolver{tasks: res, token: token}, nil
+}
+
+// ListEvents подписывает на тот же поток изменений, что и SSE-эндпоинт списка.
+// Подписка завершается вместе с удалением списка.
+func (r *Resolver) ListEvents(ctx context.Context, args struct{ ListID gographql.ID }) (<-chan *changeEventResolver, error) {
+	listID := string(args.ListID)
+	if _, err := r.lists.GetByID(ctx, listID); err != nil {
+		return nil, toGQLError(err)
+	}
+
+	sub := r.broker.Subscribe(listID, nil)
+	out := make(chan *changeEventResolver)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				// Загрузчики на каждое событие: иначе вложенные поля
+				// отдавали бы закешированные при первом событии данные.
+				res := &changeEventResolver{ev: ev, ld: newLoaders(r.lists, r.tasks)}
+				select {
+				case out <- res:
+				case <-ctx.Done():
+					return
+				}
+				if ev.Type == domain.EventListDeleted {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+type listConnectionResolver struct {
+	total int
+	nodes []*listResolver
+}
+
+func (c *listConnectionResolver) TotalCount() int32      { return int32(c.total) }
+func (c *listConnectionResolver) Nodes() []*listResolver { return c.nodes }
+
+type listResolver struct {
+	l  *domain.List
+	ld *loaders
+}
+
+func (lr *listResolver) ID() gographql.ID          { return gographql.ID(lr.l.ID) }
+func (lr *listResolver) Title() string             { return lr.l.Title }
+func (lr *listResolver) Description() string       { return lr.l.Description }
+func (lr *listResolver) Version() int32            { return int32(lr.l.Version) }
+func (lr *listResolver) CreatedAt() gographql.Time { return gographql.Time{Time: lr.l.CreatedAt} }
+
+func (lr *listResolver) Tasks(ctx context.Context, args struct{ First int32 }) ([]*taskResolver, error) {
+	if args.First < 1 || args.First > maxTasksFirst {
+		return nil, validationError("first must be 1..500")
+	}
+	tasks, err := lr.ld.tasksFor(int(args.First)).load(ctx, lr.l.ID)
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	res := make([]*taskResolver, len(tasks))
+	for i, t := range tasks {
+		res[i] = &taskResolver{t: t, ld: lr.ld}
+	}
+	return res, nil
+}
+
+func (lr *listResolver) counts(ctx context.Context) (domain.TaskCounts, error) {
+	c, err := lr.ld.counts.load(ctx, lr.l.ID)
+	if err != nil {
+		return c, toGQLError(err)
+	}
+	return c, nil
+}
+
+func (lr *listResolver) TaskCount(ctx context.Context) (int32, error) {
+	c, err := lr.counts(ctx)
+	return int32(c.Total), err
+}
+
+func (lr *listResolver) CompletedCount(ctx context.Context) (int32, error) {
+	c, err := lr.counts(ctx)
+	return int32(c.Completed), err
+}
+
+func (lr *listResolver) OpenCount(ctx context.Context) (int32, error) {
+	c, err := lr.counts(ctx)
+	return int32(c.Open), err
+}
+
+type taskResolver struct {
+	t  *domain.Task
+	ld *loaders
+}
+
+func (tr *taskResolver) ID() gographql.ID             { return gographql.ID(tr.t.ID) }
+func (tr *taskResolver) ListID() gographql.ID         { return gographql.ID(tr.t.ListID) }
+func (tr *taskResolver) Text() string                 { return tr.t.Text }
+func (tr *taskResolver) Completed() bool              { return tr.t.Completed }
+func (tr *taskResolver) DueAt() *gographql.Time       { return toGQLTime(tr.t.DueAt) }
+func (tr *taskResolver) CompletedAt() *gographql.Time { return toGQLTime(tr.t.CompletedAt) }
+func (tr *taskResolver) Version() int32               { return int32(tr.t.Version) }
+func (tr *taskResolver) CreatedAt() gographql.Time    { return gographql.Time{Time: tr.t.CreatedAt} }
+func (tr *taskResolver) UpdatedAt() gographql.Time    { return gographql.Time{Time: tr.t.UpdatedAt} }
+
+func (tr *taskResolver) List(ctx context.Context) (*listResolver, error) {
+	l, err := tr.ld.lists.load(ctx, tr.t.ListID)
+	if err != nil {
+		return nil, toGQLError(err)
+	}
+	if l == nil {
+		return nil, nil
+	}
+	return &listResolver{l: l, ld: tr.ld}, nil
+}
+
+type listPayloadResolver struct {
+	list  *listResolver
+	token string
+}
+
+func (p *listPayloadResolver) List() *listResolver { return p.list }
+func (p *listPayloadResolver) UndoToken() string   { return p.token }
+
+type taskPayloadResolver struct {
+	task  *taskResolver
+	token string
+}
+
+func (p *taskPayloadResolver) Task() *taskResolver { return p.task }
+func (p *taskPayloadResolver) UndoToken() string   { return p.token }
+
+type completeTasksPayloadResolver struct {
+	tasks []*taskResolver
+	token string
+}
+
+func (p *completeTasksPayloadResolver) Tasks() []*taskResolver { return p.tasks }
+func (p *completeTasksPayloadResolver) UndoToken() string      { return p.token }
+
+type deletePayloadResolver struct {
+	id    gographql.ID
+	token string
+}
+
+func (p *deletePayloadResolver) ID() gographql.ID  { return p.id }
+func (p *deletePayloadResolver) UndoToken() string { return p.token }
+
+type changeEventResolver struct {
+	ev domain.ChangeEvent
+	ld *loaders
+}
+
+func (e *changeEventResolver) ID() gographql.ID {
+	return gographql.ID(strconv.FormatInt(e.ev.ID, 10))
+}
+func (e *changeEventResolver) Type() string         { return e.ev.Type }
+func (e *changeEventResolver) ListID() gographql.ID { return gographql.ID(e.ev.ListID) }
+func (e *changeEventResolver) OccurredAt() gographql.Time {
+	return gographql.Time{Time: e.ev.OccurredAt}
+}
+
+func (e *changeEventResolver) TaskID() *gographql.ID {
+	if e.ev.TaskID == "" {
+		return nil
+	}
+	id := gographql.ID(e.ev.TaskID)
+	return &id
+}
+
+func (e *changeEventResolver) List() *listResolver {
+	if e.ev.List == nil {
+		return nil
+	}
+	e.ld.primeLists([]*domain.List{e.ev.List})
+	return &listResolver{l: e.ev.List, ld: e.ld}
+}
+
+func (e *changeEventResolver) Task() *taskResolver {
+	if e.ev.Task == nil {
+		return nil
+	}
+	return &taskResolver{t: e.ev.Task, ld: e.ld}
+}
+
+func toGQLTime(t *time.Time) *gographql.Time {
+	if t == nil {
+		return nil
+	}
+	return &gographql.Time{Time: *t}
+}
+
+func fromGQLTime(t *gographql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/grpc/todov1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoList(l *domain.List) *todov1.List {
+	if l == nil {
+		return nil
+	}
+	return &todov1.List{
+		Id:          l.ID,
+		Title:       l.Title,
+		Description: l.Description,
+		Version:     l.Version,
+		CreatedAt:   timestamppb.New(l.CreatedAt),
+	}
+}
+
+func toProtoTask(t *domain.Task) *todov1.Task {
+	if t == nil {
+		return nil
+	}
+	return &todov1.Task{
+		Id:          t.ID,
+		ListId:      t.ListID,
+		Text:        t.Text,
+		Completed:   t.Completed,
+		DueAt:       toProtoTime(t.DueAt),
+		CompletedAt: toProtoTime(t.CompletedAt),
+		Version:     t.Version,
+		CreatedAt:   timestamppb.New(t.CreatedAt),
+		UpdatedAt:   timestamppb.New(t.UpdatedAt),
+	}
+}
+
+func toProtoTasks(tasks []*domain.Task) []*todov1.Task {
+	res := make([]*todov1.Task, len(tasks))
+	for i, t := range tasks {
+		res[i] = toProtoTask(t)
+	}
+	return res
+}
+
+func toProtoEvent(ev domain.ChangeEvent) *todov1.ChangeEvent {
+	return &todov1.ChangeEvent{
+		Id:         ev.ID,
+		Type:       ev.Type,
+		ListId:     ev.ListID,
+		TaskId:     ev.TaskID,
+		List:       toProtoList(ev.List),
+		Task:       toProtoTask(ev.Task),
+		OccurredAt: timestamppb.New(ev.OccurredAt),
+	}
+}
+
+func toProtoTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// fromProtoTime возвращает nil для незаданного времени; некорректное
+// значение (например, секунды вне диапазона) — ошибка валидации.
+func fromProtoTime(ts *timestamppb.Timestamp) (*time.Time, error) {
+	if ts == nil {
+		return nil, nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return nil, invalidArgument("invalid timestamp")
+	}
+	t := ts.AsTime()
+	return &t, nil
+}
+
+// pagination применяет те же значения по умолчанию, что и REST.
+func pagination(limit, offset int32) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return int(limit), int(max(offset, 0))
+}
@@ -0,0 +1,278 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/grpc/todov1"
+	"todo-api/internal/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errNotFound = errors.New("not found")
+
+type fakeListRepo struct {
+	lists map[string]*domain.List
+}
+
+func (r *fakeListRepo) Create(ctx context.Context, l *domain.List) (*domain.List, error) {
+	r.lists[l.ID] = l
+	return l, nil
+}
+
+func (r *fakeListRepo) GetByID(ctx context.Context, id string) (*domain.List, error) {
+	if l, ok := r.lists[id]; ok {
+		return l, nil
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeListRepo) Update(ctx context.Context, l *domain.List) error { return nil }
+func (r *fakeListRepo) Delete(ctx context.Context, id string) error      { return nil }
+
+func (r *fakeListRepo) GetAll(ctx context.Context) ([]*domain.List, int) { return nil, 0 }
+
+func (r *fakeListRepo) FindWithPagination(ctx context.Context, limit, offset int) ([]*domain.List, int) {
+	return nil, 0
+}
+
+func (r *fakeListRepo) SearchByTitle(ctx context.Context, query string) ([]domain.List, error) {
+	return nil, nil
+}
+
+func (r *fakeListRepo) Restore(ctx context.Context, l *domain.List, expectedVersion int64) error {
+	return nil
+}
+
+func (r *fakeListRepo) DeleteVersion(ctx context.Context, id string, version int64) error {
+	return nil
+}
+
+func (r *fakeListRepo) Clone(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error) {
+	return nil, nil
+}
+
+type fakeTaskRepo struct {
+	tasks map[string]*domain.Task
+}
+
+func (r *fakeTaskRepo) Create(ctx context.Context, t *domain.Task) error {
+	r.tasks[t.ID] = t
+	return nil
+}
+
+func (r *fakeTaskRepo) CreateMany(ctx context.Context, tasks []*domain.Task) error {
+	for _, t := range tasks {
+		r.tasks[t.ID] = t
+	}
+	return nil
+}
+
+func (r *fakeTaskRepo) GetByID(ctx context.Context, id string) (*domain.Task, error) {
+	if t, ok := r.tasks[id]; ok {
+		return t, nil
+	}
+	return nil, errNotFound
+}
+
+func (r *fakeTaskRepo) ListByListID(ctx context.Context, listID string, limit, offset int) ([]*domain.Task, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeTaskRepo) ListByListIDs(ctx context.Context, listIDs []string, perList int) ([]*domain.Task, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) CountByListIDs(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) FindByQuery(ctx context.Context, q domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeTaskRepo) Update(ctx context.Context, t *domain.Task) error { return nil }
+func (r *fakeTaskRepo) Delete(ctx context.Context, id string) error      { return nil }
+
+func (r *fakeTaskRepo) ListAllByListID(ctx context.Context, listID string) ([]*domain.Task, error) {
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) Restore(ctx context.Context, t *domain.Task, expectedVersion int64) error {
+	return nil
+}
+
+func (r *fakeTaskRepo) DeleteVersion(ctx context.Context, id string, version int64) error {
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeAuditRepo struct{}
+
+func (fakeAuditRepo) Append(ctx context.Context, e *domain.AuditEvent) error { return nil }
+
+func (fakeAuditRepo) ListByList(ctx context.Context, listID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
+func (fakeAuditRepo) ListByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
+// startServer поднимает сервер на bufconn и возвращает клиентское соединение.
+func startServer(t *testing.T) (*gogrpc.ClientConn, *service.EventBroker) {
+	t.Helper()
+	lists := &fakeListRepo{lists: map[string]*domain.List{
+		"l1": {ID: "l1", Title: "Inbox", CreatedAt: time.Now()},
+	}}
+	tasks := &fakeTaskRepo{tasks: make(map[string]*domain.Task)}
+	broker := service.NewEventBroker(16)
+	srv := NewServer(
+		service.NewListService(lists, tasks, fakeTransactor{}, fakeAuditRepo{}, nil, nil),
+		service.NewTaskService(tasks, lists, fakeTransactor{}, fakeAuditRepo{}, nil, nil),
+		broker,
+	)
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(func() {
+		broker.Close()
+		srv.Shutdown(context.Background())
+	})
+
+	conn, err := gogrpc.NewClient("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, broker
+}
+
+func assertStatus(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("code = %s (%s), want %s", st.Code(), st.Message(), code)
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			if info.Reason != reason {
+				t.Errorf("reason = %s, want %s", info.Reason, reason)
+			}
+			return
+		}
+	}
+	t.Errorf("no ErrorInfo in status details")
+}
+
+func TestErrorsMatchRESTModel(t *testing.T) {
+	conn, _ := startServer(t)
+	ctx := context.Background()
+	lists := todov1.NewListServiceClient(conn)
+	tasks := todov1.NewTaskServiceClient(conn)
+
+	_, err := lists.CreateList(ctx, &todov1.CreateListRequest{})
+	assertStatus(t, err, codes.InvalidArgument, "VALIDATION_FAILED")
+
+	_, err = lists.GetList(ctx, &todov1.GetListRequest{Id: "missing"})
+	assertStatus(t, err, codes.NotFound, "NOT_FOUND")
+
+	_, err = tasks.CreateTask(ctx, &todov1.CreateTaskRequest{ListId: "missing", Text: "x"})
+	assertStatus(t, err, codes.NotFound, "NOT_FOUND")
+
+	_, err = tasks.CompleteTasks(ctx, &todov1.CompleteTasksRequest{ListId: "l1"})
+	assertStatus(t, err, codes.InvalidArgument, "VALIDATION_FAILED")
+
+	_, err = tasks.UpdateTask(ctx, &todov1.UpdateTaskRequest{Id: "any", Text: new(string)})
+	assertStatus(t, err, codes.InvalidArgument, "VALIDATION_FAILED")
+
+	longUser := metadata.AppendToOutgoingContext(ctx, "x-user-id", strings.Repeat("a", 101))
+	_, err = lists.GetList(longUser, &todov1.GetListRequest{Id: "l1"})
+	assertStatus(t, err, codes.InvalidArgument, "VALIDATION_FAILED")
+}
+
+func TestCreateAndGetTask(t *testing.T) {
+	conn, _ := startServer(t)
+	ctx := context.Background()
+	tasks := todov1.NewTaskServiceClient(conn)
+
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	created, err := tasks.CreateTask(ctx, &todov1.CreateTaskRequest{ListId: "l1", Text: "Buy milk", DueAt: timestamppb.New(due)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tasks.GetTask(ctx, &todov1.GetTaskRequest{Id: created.GetTask().GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetText() != "Buy milk" || got.GetListId() != "l1" || !got.GetDueAt().AsTime().Equal(due) {
+		t.Errorf("task = %+v", got)
+	}
+}
+
+func TestWatchListStreamsEventsUntilDeleted(t *testing.T) {
+	conn, broker := startServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	broker.Publish(domain.ChangeEvent{ID: 1, Type: domain.EventTaskCreated, ListID: "l1", TaskID: "t0"})
+	broker.Publish(domain.ChangeEvent{ID: 2, Type: domain.EventTaskCreated, ListID: "l1", TaskID: "t1"})
+
+	last := int64(1)
+	stream, err := todov1.NewListServiceClient(conn).WatchList(ctx, &todov1.WatchListRequest{ListId: "l1", LastEventId: &last})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.GetId() != 2 || ev.GetType() != domain.EventTaskCreated || ev.GetTaskId() != "t1" {
+		t.Fatalf("replayed event = %+v", ev)
+	}
+
+	// Подписка уже зарегистрирована: повтор пришёл из неё.
+	broker.Publish(domain.ChangeEvent{ID: 3, Type: domain.EventListDeleted, ListID: "l1"})
+	if ev, err = stream.Recv(); err != nil || ev.GetType() != domain.EventListDeleted {
+		t.Fatalf("event = %+v, err = %v", ev, err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("stream should end after list deletion, got %v", err)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	conn, _ := startServer(t)
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+		&healthpb.HealthCheckRequest{Service: todov1.ListService_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %s", resp.GetStatus())
+	}
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/grpc/todov1"
+	"todo-api/internal/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// eventReset — тип события, которым WatchList сообщает о пропущенных изменениях.
+const eventReset = "reset"
+
+type listServer struct {
+	todov1.UnimplementedListServiceServer
+
+	lists  service.ListService
+	broker *service.EventBroker
+}
+
+func (s *listServer) CreateList(ctx context.Context, req *todov1.CreateListRequest) (*todov1.CreateListResponse, error) {
+	list, undoToken, err := s.lists.CreateList(ctx, req.GetTitle(), req.GetDescription())
+	if err != nil {
+		return nil, serviceError(err, "INTERNAL_ERROR", "failed to create list")
+	}
+	return &todov1.CreateListResponse{List: toProtoList(list), UndoToken: undoToken}, nil
+}
+
+func (s *listServer) ListLists(ctx context.Context, req *todov1.ListListsRequest) (*todov1.ListListsResponse, error) {
+	limit, offset := pagination(req.GetLimit(), req.GetOffset())
+	lists, total := s.lists.GetAllListsWithPagination(ctx, limit, offset)
+
+	res := &todov1.ListListsResponse{Lists: make([]*todov1.List, len(lists)), TotalCount: int32(total)}
+	for i, l := range lists {
+		res.Lists[i] = toProtoList(l)
+	}
+	return res, nil
+}
+
+func (s *listServer) SearchLists(ctx context.Context, req *todov1.SearchListsRequest) (*todov1.SearchListsResponse, error) {
+	if req.GetTitle() == "" {
+		return nil, invalidArgument("missing title parameter")
+	}
+	lists, err := s.lists.SearchByTitle(ctx, req.GetTitle())
+	if err != nil {
+		return nil, statusError("INTERNAL_ERROR", "failed to search lists")
+	}
+
+	res := &todov1.SearchListsResponse{Lists: make([]*todov1.List, len(lists))}
+	for i := range lists {
+		res.Lists[i] = toProtoList(&lists[i])
+	}
+	return res, nil
+}
+
+func (s *listServer) GetList(ctx context.Context, req *todov1.GetListRequest) (*todov1.List, error) {
+	list, err := s.lists.GetByID(ctx, req.GetId())
+	if err != nil {
+		return nil, notFound("list not found")
+	}
+	return toProtoList(list), nil
+}
+
+func (s *listServer) UpdateList(ctx context.Context, req *todov1.UpdateListRequest) (*todov1.UpdateListResponse, error) {
+	list, undoToken, err := s.lists.UpdateList(ctx, req.GetId(), req.GetTitle(), req.GetDescription())
+	if err != nil {
+		return nil, serviceError(err, "NOT_FOUND", "list not found")
+	}
+	return &todov1.UpdateListResponse{List: toProtoList(list), UndoToken: undoToken}, nil
+}
+
+func (s *listServer) DeleteList(ctx context.Context, req *todov1.DeleteListRequest) (*todov1.DeleteListResponse, error) {
+	undoToken, err := s.lists.Delete(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err, "NOT_FOUND", "list not found")
+	}
+	return &todov1.DeleteListResponse{UndoToken: undoToken}, nil
+}
+
+// WatchList передаёт тот же поток изменений, что и SSE-эндпоинт списка.
+// Поток завершается после удаления списка; если сервер отключил
+// подписчика, вызов завершается с Unavailable и его можно возобновить
+// с last_event_id.
+func (s *listServer) WatchList(req *todov1.WatchListRequest, stream todov1.ListService_WatchListServer) error {
+	ctx := stream.Context()
+	listID := req.GetListId()
+	if _, err := s.lists.GetByID(ctx, listID); err != nil {
+		return notFound("list not found")
+	}
+
+	var lastEventID *int64
+	if req.LastEventId != nil {
+		id := req.GetLastEventId()
+		lastEventID = &id
+	}
+	sub := s.broker.Subscribe(listID, lastEventID)
+	defer sub.Close()
+
+	if sub.Missed {
+		if err := stream.Send(&todov1.ChangeEvent{Type: eventReset, ListId: listID}); err != nil {
+			return err
+		}
+	}
+	for _, ev := range sub.Replay {
+		if err := stream.Send(toProtoEvent(ev)); err != nil {
+			return err
+		}
+		if ev.Type == domain.EventListDeleted {
+			return nil
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case ev, ok := <-sub.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "event stream interrupted, resume with last_event_id")
+			}
+			if err := stream.Send(toProtoEvent(ev)); err != nil {
+				return err
+			}
+			if ev.Type == domain.EventListDeleted {
+				return nil
+			}
+		}
+	}
+}
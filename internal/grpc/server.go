@@ -0,0 +1,129 @@
+// Package grpc — gRPC API списков и задач поверх тех же сервисов, что и REST.
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"todo-api/internal/grpc/todov1"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/pkg/logger"
+
+	"github.com/google/uuid"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Ключи метаданных — те же заголовки, что и в REST (X-User-Id, X-Request-Id).
+const (
+	userIDKey    = "x-user-id"
+	requestIDKey = "x-request-id"
+)
+
+// Server — gRPC-сервер со службами списков и задач, health и reflection.
+type Server struct {
+	srv    *gogrpc.Server
+	health *health.Server
+}
+
+func NewServer(lists service.ListService, tasks *service.TaskService, broker *service.EventBroker) *Server {
+	srv := gogrpc.NewServer(
+		gogrpc.ChainUnaryInterceptor(unaryInterceptor),
+		gogrpc.ChainStreamInterceptor(streamInterceptor),
+	)
+	todov1.RegisterListServiceServer(srv, &listServer{lists: lists, broker: broker})
+	todov1.RegisterTaskServiceServer(srv, &taskServer{tasks: tasks})
+
+	hs := health.NewServer()
+	for _, name := range []string{"", todov1.ListService_ServiceDesc.ServiceName, todov1.TaskService_ServiceDesc.ServiceName} {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(srv, hs)
+	reflection.Register(srv)
+
+	return &Server{srv: srv, health: hs}
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// Shutdown переводит health в NOT_SERVING и ждёт завершения вызовов;
+// если ctx истекает раньше, оставшиеся вызовы обрываются.
+// Потоки WatchList завершаются закрытием EventBroker.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.srv.Stop()
+		<-done
+	}
+}
+
+// withRequestContext кладёт в контекст пользователя и идентификатор запроса
+// из метаданных, как middleware REST, и так же отклоняет слишком длинные.
+func withRequestContext(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(userIDKey); len(v) > 0 && v[0] != "" {
+		if len(v[0]) > reqctx.MaxIDLength {
+			return ctx, invalidArgument(fmt.Sprintf("%s must be at most %d chars", userIDKey, reqctx.MaxIDLength))
+		}
+		ctx = reqctx.WithUserID(ctx, v[0])
+	}
+	requestID := uuid.New().String()
+	if v := md.Get(requestIDKey); len(v) > 0 && v[0] != "" {
+		if len(v[0]) > reqctx.MaxIDLength {
+			return ctx, invalidArgument(fmt.Sprintf("%s must be at most %d chars", requestIDKey, reqctx.MaxIDLength))
+		}
+		requestID = v[0]
+	}
+	_ = gogrpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+	return reqctx.WithRequestID(ctx, requestID), nil
+}
+
+func unaryInterceptor(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx, err := withRequestContext(ctx)
+	if err != nil {
+		logCall(info.FullMethod, err, start)
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	logCall(info.FullMethod, err, start)
+	return resp, err
+}
+
+func streamInterceptor(srv any, ss gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+	start := time.Now()
+	ctx, err := withRequestContext(ss.Context())
+	if err == nil {
+		err = handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+	logCall(info.FullMethod, err, start)
+	return err
+}
+
+func logCall(method string, err error, start time.Time) {
+	logger.Info("gRPC: " + method + " " + status.Code(err).String() + " Duration: " + time.Since(start).String())
+}
+
+type serverStream struct {
+	gogrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"errors"
+
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain — домен ErrorInfo в деталях ошибок.
+const errorDomain = "todo-api"
+
+// restCodes — коды модели ошибок REST и соответствующие им коды gRPC.
+// Код REST передаётся в ErrorInfo.Reason, чтобы клиенты обоих API
+// разбирали ошибки одинаково.
+var restCodes = map[string]codes.Code{
+	"VALIDATION_FAILED": codes.InvalidArgument,
+	"NOT_FOUND":         codes.NotFound,
+	"CONFLICT":          codes.Aborted,
+	"UNAUTHORIZED":      codes.Unauthenticated,
+	"FORBIDDEN":         codes.PermissionDenied,
+	"INTERNAL_ERROR":    codes.Internal,
+}
+
+func statusError(restCode, message string) error {
+	st := status.New(restCodes[restCode], message)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: restCode, Domain: errorDomain}); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
+
+func invalidArgument(message string) error { return statusError("VALIDATION_FAILED", message) }
+func notFound(message string) error        { return statusError("NOT_FOUND", message) }
+
+// serviceError переводит ошибку сервиса так же, как REST-обработчики:
+// ошибки валидации — VALIDATION_FAILED, конфликт версий — CONFLICT,
+// остальное — fallback (обычно NOT_FOUND с сообщением REST).
+func serviceError(err error, fallbackCode, fallbackMessage string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidListTitle),
+		errors.Is(err, service.ErrInvalidTaskText),
+		errors.Is(err, service.ErrNoTaskIDs),
+		errors.Is(err, service.ErrTaskNotInList):
+		return invalidArgument(err.Error())
+	case errors.Is(err, storage.ErrConflict):
+		return statusError("CONFLICT", "resource was modified concurrently")
+	default:
+		return statusError(fallbackCode, fallbackMessage)
+	}
+}
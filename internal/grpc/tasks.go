@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+
+	"todo-api/internal/grpc/todov1"
+	"todo-api/internal/service"
+)
+
+type taskServer struct {
+	todov1.UnimplementedTaskServiceServer
+
+	tasks *service.TaskService
+}
+
+func (s *taskServer) CreateTask(ctx context.Context, req *todov1.CreateTaskRequest) (*todov1.CreateTaskResponse, error) {
+	dueAt, err := fromProtoTime(req.GetDueAt())
+	if err != nil {
+		return nil, err
+	}
+	task, undoToken, err := s.tasks.CreateTask(ctx, req.GetListId(), req.GetText(), dueAt)
+	if err != nil {
+		return nil, serviceError(err, "NOT_FOUND", "list not found")
+	}
+	return &todov1.CreateTaskResponse{Task: toProtoTask(task), UndoToken: undoToken}, nil
+}
+
+func (s *taskServer) ListTasks(ctx context.Context, req *todov1.ListTasksRequest) (*todov1.ListTasksResponse, error) {
+	limit, offset := pagination(req.GetLimit(), req.GetOffset())
+	tasks, total, err := s.tasks.ListTasks(ctx, req.GetListId(), limit, offset)
+	if err != nil {
+		return nil, serviceError(err, "NOT_FOUND", "list not found")
+	}
+	return &todov1.ListTasksResponse{Tasks: toProtoTasks(tasks), TotalCount: int32(total)}, nil
+}
+
+// CompleteTasks отмечает выполненными несколько задач списка; отменяется
+// одним токеном.
+func (s *taskServer) CompleteTasks(ctx context.Context, req *todov1.CompleteTasksRequest) (*todov1.CompleteTasksResponse, error) {
+	tasks, undoToken, err := s.tasks.CompleteTasks(ctx, req.GetListId(), req.GetIds())
+	if err != nil {
+		return nil, serviceError(err, "NOT_FOUND", "list or task not found")
+	}
+	return &todov1.CompleteTasksResponse{Tasks: toProtoTasks(tasks), UndoToken: undoToken}, nil
+}
+
+func (s *taskServer) GetTask(ctx context.Context, req *todov1.GetTaskRequest) (*todov1.Task, error) {
+	task, err := s.tasks.GetTask(ctx, req.GetId())
+	if err != nil {
+		return nil, notFound("task not found")
+	}
+	return toProtoTask(task), nil
+}
+
+func (s *taskServer) UpdateTask(ctx context.Context, req *todov1.UpdateTaskRequest) (*todov1.UpdateTaskResponse, error) {
+	// Пустой text в REST означает «не менять»; здесь для этого поле не задают.
+	if req.Text != nil && req.GetText() == "" {
+		return nil, invalidArgument(service.ErrInvalidTaskText.Error())
+	}
+	dueAt, err := fromProtoTime(req.GetDueAt())
+	if err != nil {
+		return nil, err
+	}
+	task, undoToken, err := s.tasks.UpdateTask(ctx, req.GetId(), req.GetText(), req.Completed, dueAt)
+	if err != nil {
+		return nil, serviceError(err, "NOT_FOUND", "task not found")
+	}
+	return &todov1.UpdateTaskResponse{Task: toProtoTask(task), UndoToken: undoToken}, nil
+}
+
+func (s *taskServer) DeleteTask(ctx context.Context, req *todov1.DeleteTaskRequest) (*todov1.DeleteTaskResponse, error) {
+	undoToken, err := s.tasks.DeleteTask(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err, "NOT_FOUND", "task not found")
+	}
+	return &todov1.DeleteTaskResponse{UndoToken: undoToken}, nil
+}
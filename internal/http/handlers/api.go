@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"todo-api/internal/api"
+	"todo-api/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// API реализует строгий интерфейс сервера, сгенерированный по
+// docs/openapi.yaml, для операций со списками и задачами.
+type API struct {
+	*ListHandler
+	*TaskHandler
+}
+
+// Расхождение обработчиков со спецификацией ломает сборку.
+var _ api.StrictServerInterface = API{}
+
+// Register регистрирует операции спецификации в роутере r. Ошибки разбора
+// параметров и тела запроса возвращаются в формате Error спецификации.
+func (a API) Register(r chi.Router) {
+	strict := api.NewStrictHandlerWithOptions(a, nil, api.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  writeRequestError,
+		ResponseErrorHandlerFunc: writeResponseError,
+	})
+	api.HandlerWithOptions(strict, api.ChiServerOptions{
+		BaseRouter:       r,
+		ErrorHandlerFunc: writeRequestError,
+	})
+}
+
+func writeRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	writeAPIError(w, http.StatusBadRequest, api.Error(validationFailed(err.Error())))
+}
+
+func writeResponseError(w http.ResponseWriter, r *http.Request, err error) {
+	writeAPIError(w, http.StatusInternalServerError, api.Error(internalError("internal error")))
+}
+
+func writeAPIError(w http.ResponseWriter, status int, body api.Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func apiError(code, message string) api.Error {
+	return api.Error{Code: code, Message: message, Details: &map[string]interface{}{}}
+}
+
+func validationFailed(message string) api.ValidationErrorJSONResponse {
+	return api.ValidationErrorJSONResponse(apiError("VALIDATION_FAILED", message))
+}
+
+func notFound(message string) api.NotFoundJSONResponse {
+	return api.NotFoundJSONResponse(apiError("NOT_FOUND", message))
+}
+
+func internalError(message string) api.ServerErrorJSONResponse {
+	return api.ServerErrorJSONResponse(apiError("INTERNAL_ERROR", message))
+}
+
+// deref возвращает значение необязательного поля запроса или нулевое значение.
+func deref[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
+
+// derefOr возвращает значение необязательного поля запроса или def.
+func derefOr[T any](p *T, def T) T {
+	if p != nil {
+		return *p
+	}
+	return def
+}
+
+func toAPIList(l *domain.List) api.List {
+	res := api.List{
+		Id:         l.ID,
+		Title:      l.Title,
+		Version:    &l.Version,
+		ArchivedAt: l.ArchivedAt,
+		FolderId:   l.FolderID,
+		CreatedAt:  &l.CreatedAt,
+	}
+	if l.Description != "" {
+		res.Description = &l.Description
+	}
+	return res
+}
+
+func toAPILists(lists []*domain.List) []api.List {
+	res := make([]api.List, 0, len(lists))
+	for _, l := range lists {
+		res = append(res, toAPIList(l))
+	}
+	return res
+}
+
+func toAPITask(t *domain.Task) api.Task {
+	res := api.Task{
+		Id:          t.ID,
+		ListId:      t.ListID,
+		Text:        t.Text,
+		Completed:   t.Completed,
+		DueAt:       t.DueAt,
+		CompletedAt: t.CompletedAt,
+		Version:     &t.Version,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+	if t.Assignee != "" {
+		res.Assignee = &t.Assignee
+	}
+	if len(t.Labels) > 0 {
+		res.Labels = &t.Labels
+	}
+	if t.Priority != "" {
+		res.Priority = (*api.TaskPriority)(&t.Priority)
+	}
+	if t.Recurrence != "" {
+		res.Recurrence = (*api.TaskRecurrence)(&t.Recurrence)
+	}
+	return res
+}
+
+func toAPITasks(tasks []*domain.Task) []api.Task {
+	res := make([]api.Task, 0, len(tasks))
+	for _, t := range tasks {
+		res = append(res, toAPITask(t))
+	}
+	return res
+}
+
+func toAPIQuickAdd(q *domain.QuickAdd) api.QuickAddParse {
+	res := api.QuickAddParse{
+		Input:  q.Input,
+		Title:  q.Title,
+		DueAt:  q.DueAt,
+		Labels: q.Labels,
+		Parts:  make([]api.QuickAddPart, 0, len(q.Parts)),
+	}
+	if q.Priority != "" {
+		res.Priority = (*api.TaskPriority)(&q.Priority)
+	}
+	if q.Recurrence != "" {
+		res.Recurrence = (*api.TaskRecurrence)(&q.Recurrence)
+	}
+	for _, p := range q.Parts {
+		res.Parts = append(res.Parts, api.QuickAddPart{
+			Kind:  api.QuickAddPartKind(p.Kind),
+			Text:  p.Text,
+			Start: p.Start,
+			End:   p.End,
+			Value: p.Value,
+		})
+	}
+	return res
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuditHandler struct {
+	svc *service.AuditService
+}
+
+func NewAuditHandler(svc *service.AuditService) *AuditHandler {
+	return &AuditHandler{svc: svc}
+}
+
+func (h *AuditHandler) ListActivity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	limit, offset := parsePagination(r)
+
+	events, total, err := h.svc.ListActivity(ctx, id, limit, offset)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to load activity","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(events)
+}
+
+func (h *AuditHandler) TaskHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	taskID := chi.URLParam(r, "taskID")
+	limit, offset := parsePagination(r)
+
+	events, total, err := h.svc.TaskHistory(ctx, taskID, limit, offset)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"code":"NOT_FOUND","message":"task not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to load history","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(events)
+}
+
+// parsePagination читает limit (по умолчанию 20) и offset, игнорируя некорректные значения.
+func parsePagination(r *http.Request) (int, int) {
+	limit := 20
+	offset := 0
+
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l >= 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+	return limit, offset
+}
@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func init() {
+	// Методы WebDAV, которых нет среди стандартных методов chi.
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+}
+
+// maxCalendarObjectSize ограничивает тело PUT ресурса CalDAV.
+const maxCalendarObjectSize = 1 << 20
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+// CalDAV возвращает обработчик минимального сервера CalDAV (RFC 4791).
+// Каждый список — коллекция /lists/{listID}/ с задачами-ресурсами VTODO.
+// Клиент авторизуется токеном календаря списка: паролем Basic-авторизации
+// (имя пользователя любое) или параметром token.
+func (h *CalendarHandler) CalDAV() http.Handler {
+	r := chi.NewRouter()
+	r.Route("/lists/{listID}", func(r chi.Router) {
+		r.Use(h.authorizeDAV)
+		r.Options("/", davOptions)
+		r.MethodFunc("PROPFIND", "/", h.propfindCollection)
+		r.MethodFunc("REPORT", "/", h.report)
+		r.Options("/{name}", davOptions)
+		r.MethodFunc("PROPFIND", "/{name}", h.propfindObject)
+		r.Get("/{name}", h.getObject)
+		r.Put("/{name}", h.putObject)
+		r.Delete("/{name}", h.deleteObject)
+	})
+	return r
+}
+
+type davListKey struct{}
+
+func (h *CalendarHandler) authorizeDAV(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if _, password, ok := r.BasicAuth(); ok {
+			token = password
+		}
+		list, err := h.svc.Authorize(r.Context(), chi.URLParam(r, "listID"), token)
+		if err != nil {
+			if errors.Is(err, service.ErrCalendarUnauthorized) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="todo-api CalDAV", charset="UTF-8"`)
+			}
+			writeCalendarAuthError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), davListKey{}, list)))
+	})
+}
+
+func davList(r *http.Request) *domain.List {
+	return r.Context().Value(davListKey{}).(*domain.List)
+}
+
+func caldavCollection(listID string) string {
+	return "/caldav/lists/" + listID + "/"
+}
+
+func caldavObject(listID, name string) string {
+	return caldavCollection(listID) + url.PathEscape(name)
+}
+
+func davOptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	w.Header().Set("Allow", "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+	w.WriteHeader(http.StatusOK)
+}
+
+// davProps — имена свойств из элемента DAV:prop запроса.
+type davProps struct {
+	names []xml.Name
+}
+
+func (p *davProps) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			p.names = append(p.names, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// davRequest — тело PROPFIND и REPORT. Пустое тело PROPFIND — allprop.
+type davRequest struct {
+	XMLName xml.Name
+	AllProp *struct{} `xml:"DAV: allprop"`
+	Prop    *davProps `xml:"DAV: prop"`
+	Hrefs   []string  `xml:"DAV: href"`
+}
+
+func parseDAVRequest(r *http.Request) (*davRequest, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	var req davRequest
+	if strings.TrimSpace(string(body)) == "" {
+		return &req, nil
+	}
+	if err := xml.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// propNames — запрошенные свойства; nil — все свойства по умолчанию.
+func (req *davRequest) propNames() []xml.Name {
+	if req.Prop == nil || req.AllProp != nil {
+		return nil
+	}
+	return req.Prop.names
+}
+
+var (
+	collectionProps = []xml.Name{
+		{Space: nsDAV, Local: "resourcetype"},
+		{Space: nsDAV, Local: "displayname"},
+		{Space: nsCS, Local: "getctag"},
+		{Space: nsCalDAV, Local: "supported-calendar-component-set"},
+	}
+	objectProps = []xml.Name{
+		{Space: nsDAV, Local: "resourcetype"},
+		{Space: nsDAV, Local: "getetag"},
+		{Space: nsDAV, Local: "getcontenttype"},
+	}
+)
+
+// collectionProp возвращает свойство коллекции списка в виде XML.
+func collectionProp(name xml.Name, list *domain.List, objects []*domain.CalendarObject) (string, bool) {
+	href := "<D:href>" + xmlText(caldavCollection(list.ID)) + "</D:href>"
+	switch name {
+	case xml.Name{Space: nsDAV, Local: "resourcetype"}:
+		return "<D:resourcetype><D:collection/><C:calendar/></D:resourcetype>", true
+	case xml.Name{Space: nsDAV, Local: "displayname"}:
+		return "<D:displayname>" + xmlText(list.Title) + "</D:displayname>", true
+	case xml.Name{Space: nsDAV, Local: "current-user-principal"}:
+		return "<D:current-user-principal>" + href + "</D:current-user-principal>", true
+	case xml.Name{Space: nsDAV, Local: "owner"}:
+		return "<D:owner>" + href + "</D:owner>", true
+	case xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}:
+		return "<D:current-user-privilege-set><D:privilege><D:read/></D:privilege><D:privilege><D:write/></D:privilege></D:current-user-privilege-set>", true
+	case xml.Name{Space: nsDAV, Local: "supported-report-set"}:
+		return "<D:supported-report-set>" +
+			"<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>" +
+			"<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>" +
+			"</D:supported-report-set>", true
+	case xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}:
+		return `<C:supported-calendar-component-set><C:comp name="VTODO"/></C:supported-calendar-component-set>`, true
+	case xml.Name{Space: nsCS, Local: "getctag"}:
+		return "<CS:getctag>" + xmlText(collectionTag(objects)) + "</CS:getctag>", true
+	}
+	return "", false
+}
+
+// collectionTag меняется при любом изменении задач списка: это хеш имён
+// и ETag всех ресурсов коллекции.
+func collectionTag(objects []*domain.CalendarObject) string {
+	h := fnv.New64a()
+	for _, o := range objects {
+		io.WriteString(h, o.Name+" "+o.ETag()+"\n")
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// objectProp возвращает свойство ресурса задачи в виде XML.
+func (h *CalendarHandler) objectProp(name xml.Name, o *domain.CalendarObject) (string, bool) {
+	switch name {
+	case xml.Name{Space: nsDAV, Local: "resourcetype"}:
+		return "<D:resourcetype/>", true
+	case xml.Name{Space: nsDAV, Local: "getetag"}:
+		return "<D:getetag>" + xmlText(o.ETag()) + "</D:getetag>", true
+	case xml.Name{Space: nsDAV, Local: "getcontenttype"}:
+		return "<D:getcontenttype>text/calendar; charset=utf-8; component=VTODO</D:getcontenttype>", true
+	case xml.Name{Space: nsCalDAV, Local: "calendar-data"}:
+		return "<C:calendar-data>" + xmlText(h.svc.CalendarData(o)) + "</C:calendar-data>", true
+	}
+	return "", false
+}
+
+// multistatus собирает ответ 207 Multi-Status.
+type multistatus struct {
+	b strings.Builder
+}
+
+func newMultistatus() *multistatus {
+	m := &multistatus{}
+	m.b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	m.b.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="` + nsCalDAV + `" xmlns:CS="` + nsCS + `">`)
+	return m
+}
+
+// response добавляет ресурс href: найденные свойства со статусом 200,
+// неизвестные — с 404.
+func (m *multistatus) response(href string, names []xml.Name, prop func(xml.Name) (string, bool)) {
+	var found, missing strings.Builder
+	for _, name := range names {
+		if v, ok := prop(name); ok {
+			found.WriteString(v)
+		} else {
+			missing.WriteString(`<x:` + name.Local + ` xmlns:x="` + xmlText(name.Space) + `"/>`)
+		}
+	}
+	m.b.WriteString("<D:response><D:href>" + xmlText(href) + "</D:href>")
+	if found.Len() > 0 {
+		m.b.WriteString("<D:propstat><D:prop>" + found.String() + "</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>")
+	}
+	if missing.Len() > 0 {
+		m.b.WriteString("<D:propstat><D:prop>" + missing.String() + "</D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat>")
+	}
+	m.b.WriteString("</D:response>")
+}
+
+func (m *multistatus) notFound(href string) {
+	m.b.WriteString("<D:response><D:href>" + xmlText(href) + "</D:href><D:status>HTTP/1.1 404 Not Found</D:status></D:response>")
+}
+
+func (m *multistatus) write(w http.ResponseWriter) {
+	m.b.WriteString("</D:multistatus>")
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, m.b.String())
+}
+
+// propfindCollection отдаёт свойства коллекции, а с Depth: 1 — и ресурсов.
+func (h *CalendarHandler) propfindCollection(w http.ResponseWriter, r *http.Request) {
+	req, err := parseDAVRequest(r)
+	if err != nil {
+		writeDAVError(w, http.StatusBadRequest, "invalid PROPFIND body")
+		return
+	}
+	list := davList(r)
+	objects, err := h.svc.Objects(r.Context(), list.ID)
+	if err != nil {
+		writeDAVError(w, http.StatusInternalServerError, "failed to load tasks")
+		return
+	}
+
+	names := req.propNames()
+	if names == nil {
+		names = collectionProps
+	}
+	ms := newMultistatus()
+	ms.response(caldavCollection(list.ID), names, func(n xml.Name) (string, bool) {
+		return collectionProp(n, list, objects)
+	})
+	if r.Header.Get("Depth") != "0" {
+		if req.propNames() == nil {
+			names = objectProps
+		}
+		for _, o := range objects {
+			ms.response(caldavObject(list.ID, o.Name), names, func(n xml.Name) (string, bool) {
+				return h.objectProp(n, o)
+			})
+		}
+	}
+	ms.write(w)
+}
+
+func (h *CalendarHandler) propfindObject(w http.ResponseWriter, r *http.Request) {
+	req, err := parseDAVRequest(r)
+	if err != nil {
+		writeDAVError(w, http.StatusBadRequest, "invalid PROPFIND body")
+		return
+	}
+	list := davList(r)
+	o, ok := h.object(w, r, list)
+	if !ok {
+		return
+	}
+	names := req.propNames()
+	if names == nil {
+		names = objectProps
+	}
+	ms := newMultistatus()
+	ms.response(caldavObject(list.ID, o.Name), names, func(n xml.Name) (string, bool) {
+		return h.objectProp(n, o)
+	})
+	ms.write(w)
+}
+
+// report обрабатывает calendar-query (фильтры не применяются: в коллекции
+// только VTODO) и calendar-multiget.
+func (h *CalendarHandler) report(w http.ResponseWriter, r *http.Request) {
+	req, err := parseDAVRequest(r)
+	if err != nil || req.XMLName.Space != nsCalDAV {
+		writeDAVError(w, http.StatusBadRequest, "invalid REPORT body")
+		return
+	}
+	list := davList(r)
+	names := req.propNames()
+	if names == nil {
+		names = objectProps
+	}
+	prop := func(o *domain.CalendarObject) func(xml.Name) (string, bool) {
+		return func(n xml.Name) (string, bool) { return h.objectProp(n, o) }
+	}
+
+	ms := newMultistatus()
+	switch req.XMLName.Local {
+	case "calendar-query":
+		objects, err := h.svc.Objects(r.Context(), list.ID)
+		if err != nil {
+			writeDAVError(w, http.StatusInternalServerError, "failed to load tasks")
+			return
+		}
+		for _, o := range objects {
+			ms.response(caldavObject(list.ID, o.Name), names, prop(o))
+		}
+	case "calendar-multiget":
+		for _, href := range req.Hrefs {
+			name, ok := objectName(list.ID, href)
+			var o *domain.CalendarObject
+			if ok {
+				if o, err = h.svc.Object(r.Context(), list.ID, name); err != nil {
+					writeDAVError(w, http.StatusInternalServerError, "failed to load task")
+					return
+				}
+			}
+			if o == nil {
+				ms.notFound(href)
+				continue
+			}
+			ms.response(href, names, prop(o))
+		}
+	default:
+		writeDAVError(w, http.StatusForbidden, "supported reports: calendar-query, calendar-multiget")
+		return
+	}
+	ms.write(w)
+}
+
+// objectName извлекает имя ресурса из href коллекции списка listID.
+func objectName(listID, href string) (string, bool) {
+	if u, err := url.Parse(href); err == nil {
+		href = u.Path
+	}
+	dir, name := path.Split(href)
+	if dir != caldavCollection(listID) || name == "" {
+		return "", false
+	}
+	name, err := url.PathUnescape(name)
+	return name, err == nil
+}
+
+// object загружает ресурс из пути запроса; если его нет, отвечает 404.
+func (h *CalendarHandler) object(w http.ResponseWriter, r *http.Request, list *domain.List) (*domain.CalendarObject, bool) {
+	o, err := h.svc.Object(r.Context(), list.ID, chi.URLParam(r, "name"))
+	if err != nil {
+		writeDAVError(w, http.StatusInternalServerError, "failed to load task")
+		return nil, false
+	}
+	if o == nil {
+		writeDAVError(w, http.StatusNotFound, "calendar object not found")
+		return nil, false
+	}
+	return o, true
+}
+
+func (h *CalendarHandler) getObject(w http.ResponseWriter, r *http.Request) {
+	o, ok := h.object(w, r, davList(r))
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", o.ETag())
+	_, _ = io.WriteString(w, h.svc.CalendarData(o))
+}
+
+func (h *CalendarHandler) putObject(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCalendarObjectSize))
+	if err != nil {
+		writeDAVError(w, http.StatusRequestEntityTooLarge, "calendar object must be at most 1 MiB")
+		return
+	}
+	o, created, err := h.svc.Put(r.Context(), davList(r).ID, chi.URLParam(r, "name"), body,
+		r.Header.Get("If-Match"), r.Header.Get("If-None-Match"))
+	if err != nil {
+		writeCalendarObjectError(w, err)
+		return
+	}
+	w.Header().Set("ETag", o.ETag())
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CalendarHandler) deleteObject(w http.ResponseWriter, r *http.Request) {
+	err := h.svc.Delete(r.Context(), davList(r).ID, chi.URLParam(r, "name"), r.Header.Get("If-Match"))
+	if err != nil {
+		writeCalendarObjectError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCalendarObjectError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrCalendarPrecondition):
+		writeDAVError(w, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, service.ErrCalendarObjectNotFound):
+		writeDAVError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidCalendarData), errors.Is(err, service.ErrInvalidCalendarName),
+		errors.Is(err, service.ErrInvalidTaskText):
+		writeDAVError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeDAVError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+// writeDAVError отвечает ошибкой в формате остального API.
+func writeDAVError(w http.ResponseWriter, status int, message string) {
+	code := "INTERNAL_ERROR"
+	switch status {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		code = "VALIDATION_FAILED"
+	case http.StatusNotFound:
+		code = "NOT_FOUND"
+	case http.StatusPreconditionFailed:
+		code = "CONFLICT"
+	case http.StatusForbidden:
+		code = "FORBIDDEN"
+	}
+	body, _ := json.Marshal(map[string]any{"code": code, "message": message, "details": map[string]any{}})
+	http.Error(w, string(body), status)
+}
+
+func xmlText(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
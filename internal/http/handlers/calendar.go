@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CalendarHandler struct {
+	svc *service.CalendarService
+}
+
+func NewCalendarHandler(svc *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{svc: svc}
+}
+
+// IssueToken выпускает токен календаря списка и возвращает адреса ленты
+// и коллекции CalDAV. Прежний токен перестаёт действовать.
+func (h *CalendarHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	token, err := h.svc.IssueToken(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Info("issue calendar token for list " + id + " failed: " + err.Error())
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	base := baseURL(r)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"token":      token,
+		"feed_url":   base + "/api/v1/lists/" + id + "/calendar.ics?token=" + url.QueryEscape(token),
+		"caldav_url": base + caldavCollection(id),
+	})
+}
+
+// Feed отдаёт задачи списка лентой iCalendar (VTODO) для подписки
+// в календаре.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.Authorize(r.Context(), chi.URLParam(r, "id"), r.URL.Query().Get("token"))
+	if err != nil {
+		writeCalendarAuthError(w, err)
+		return
+	}
+
+	objects, err := h.svc.Objects(r.Context(), list.ID)
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to load tasks","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="list-`+list.ID+`.ics"`)
+	if err := h.svc.WriteFeed(w, list, objects); err != nil {
+		logger.Info("calendar feed for list " + list.ID + " failed: " + err.Error())
+	}
+}
+
+func writeCalendarAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrCalendarUnauthorized):
+		http.Error(w, `{"code":"UNAUTHORIZED","message":"invalid calendar token","details":{}}`, http.StatusUnauthorized)
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
+	default:
+		logger.Info("calendar authorization failed: " + err.Error())
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+	}
+}
+
+// baseURL — схема и хост, по которым клиент обратился к сервису.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakeCalendarRepo struct {
+	storage.CalendarRepository
+	fail *failure
+}
+
+func (r *fakeCalendarRepo) SetToken(ctx context.Context, listID, tokenHash string) error {
+	return r.fail.get()
+}
+
+func TestCalendar_IssueTokenErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	h := NewCalendarHandler(service.NewCalendarService(&fakeCalendarRepo{fail: fail}, lists, nil, fakeTransactor{}))
+	router := chi.NewRouter()
+	router.Post("/api/v1/lists/{id}/calendar-token", h.IssueToken)
+
+	for _, tc := range []struct {
+		name   string
+		listID string
+		dbErr  error
+		status int
+	}{
+		{"issued", "l1", nil, http.StatusCreated},
+		{"unknown list", "missing", nil, http.StatusNotFound},
+		{"storage failure", "l1", errDBDown, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/lists/"+tc.listID+"/calendar-token", nil))
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %s", rec.Body)
+			}
+		})
+	}
+}
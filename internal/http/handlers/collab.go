@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	collabMaxMessageSize = 64 << 10
+	collabMaxLists       = 50
+	// collabSendBuffer — сколько исходящих сообщений может ждать отправки.
+	// Клиент, который не успевает их читать, отключается.
+	collabSendBuffer = 256
+	collabWriteWait  = 10 * time.Second
+	collabPongWait   = 60 * time.Second
+	collabPingPeriod = collabPongWait * 9 / 10
+	collabOpTimeout  = 10 * time.Second
+)
+
+// collabMessage — сообщение клиента. Поля используются в зависимости от Type:
+// subscribe/unsubscribe — ListIDs; presence — ListID, TaskID, State;
+// mutate — OpID, Action (create, update, delete) и поля задачи.
+type collabMessage struct {
+	Type      string     `json:"type"`
+	OpID      string     `json:"op_id,omitempty"`
+	ListIDs   []string   `json:"list_ids,omitempty"`
+	ListID    string     `json:"list_id,omitempty"`
+	TaskID    string     `json:"task_id,omitempty"`
+	State     string     `json:"state,omitempty"`
+	Action    string     `json:"action,omitempty"`
+	Text      string     `json:"text,omitempty"`
+	Completed *bool      `json:"completed,omitempty"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+}
+
+// collabReply — сообщение сервера: event, presence, subscribed, ack или error.
+type collabReply struct {
+	Type      string              `json:"type"`
+	OpID      string              `json:"op_id,omitempty"`
+	ListIDs   []string            `json:"list_ids,omitempty"`
+	Event     *domain.ChangeEvent `json:"event,omitempty"`
+	Presence  *domain.Presence    `json:"presence,omitempty"`
+	Task      *domain.Task        `json:"task,omitempty"`
+	UndoToken string              `json:"undo_token,omitempty"`
+	Code      string              `json:"code,omitempty"`
+	Message   string              `json:"message,omitempty"`
+}
+
+type CollabHandler struct {
+	tasks    *service.TaskService
+	lists    service.ListService
+	broker   *service.EventBroker
+	presence *service.PresenceHub
+	upgrader websocket.Upgrader
+
+	mu    sync.Mutex
+	conns map[*collabConn]struct{}
+}
+
+func NewCollabHandler(tasks *service.TaskService, lists service.ListService, broker *service.EventBroker, presence *service.PresenceHub) *CollabHandler {
+	return &CollabHandler{
+		tasks:    tasks,
+		lists:    lists,
+		broker:   broker,
+		presence: presence,
+		conns:    make(map[*collabConn]struct{}),
+	}
+}
+
+// Collab открывает WebSocket для совместной работы со списками. Upgrader
+// по умолчанию пропускает только запросы с того же Origin.
+func (h *CollabHandler) Collab(w http.ResponseWriter, r *http.Request) {
+	userID := reqctx.UserID(r.Context())
+	if userID == reqctx.AnonymousUser {
+		http.Error(w, `{"code":"UNAUTHORIZED","message":"X-User-Id is required","details":{}}`, http.StatusUnauthorized)
+		return
+	}
+
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrader уже ответил клиенту.
+		return
+	}
+
+	c := &collabConn{
+		h:      h,
+		ws:     ws,
+		userID: userID,
+		send:   make(chan collabReply, collabSendBuffer),
+		done:   make(chan struct{}),
+		lists:  make(map[string]func()),
+	}
+	h.mu.Lock()
+	h.conns[c] = struct{}{}
+	h.mu.Unlock()
+
+	go c.writeLoop()
+	c.readLoop(r.Context())
+	c.close(websocket.CloseNormalClosure, "")
+
+	h.mu.Lock()
+	delete(h.conns, c)
+	h.mu.Unlock()
+}
+
+// Close закрывает все соединения; вызывается при остановке сервера,
+// который сам не ждёт перехваченные соединения.
+func (h *CollabHandler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.conns {
+		c.close(websocket.CloseGoingAway, "server shutting down")
+	}
+}
+
+type collabConn struct {
+	h      *CollabHandler
+	ws     *websocket.Conn
+	userID string
+	send   chan collabReply
+	done   chan struct{}
+
+	closeOnce   sync.Once
+	closeCode   int
+	closeReason string
+
+	mu    sync.Mutex
+	lists map[string]func()
+}
+
+func (c *collabConn) readLoop(ctx context.Context) {
+	c.ws.SetReadLimit(collabMaxMessageSize)
+	_ = c.ws.SetReadDeadline(time.Now().Add(collabPongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(collabPongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = c.ws.SetReadDeadline(time.Now().Add(collabPongWait))
+
+		var msg collabMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.enqueue(collabReply{Type: "error", Code: "VALIDATION_FAILED", Message: "invalid json"})
+			continue
+		}
+
+		// Сообщения обрабатываются по одному: клиент, присылающий мутации
+		// быстрее, чем они выполняются, упирается в TCP-буфер.
+		switch msg.Type {
+		case "subscribe":
+			c.subscribe(ctx, msg.ListIDs)
+		case "unsubscribe":
+			c.unsubscribe(msg.ListIDs)
+		case "presence":
+			c.publishPresence(msg)
+		case "mutate":
+			c.mutate(ctx, msg)
+		default:
+			c.enqueue(collabReply{Type: "error", OpID: msg.OpID, Code: "VALIDATION_FAILED", Message: "unknown message type"})
+		}
+	}
+}
+
+func (c *collabConn) writeLoop() {
+	ticker := time.NewTicker(collabPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+	}()
+
+	for {
+		select {
+		case m := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if err := c.ws.WriteJSON(m); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(collabWriteWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			msg := websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+			_ = c.ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(collabWriteWait))
+			return
+		}
+	}
+}
+
+// enqueue ставит сообщение в очередь на отправку, не блокируясь. Переполненная
+// очередь означает, что клиент не успевает читать, и соединение закрывается.
+func (c *collabConn) enqueue(m collabReply) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	select {
+	case c.send <- m:
+	default:
+		c.close(websocket.ClosePolicyViolation, "slow consumer")
+	}
+}
+
+func (c *collabConn) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode, c.closeReason = code, reason
+		close(c.done)
+
+		c.mu.Lock()
+		lists := c.lists
+		c.lists = map[string]func(){}
+		c.mu.Unlock()
+		for listID, stop := range lists {
+			stop()
+			c.h.presence.Publish(domain.Presence{ListID: listID, UserID: c.userID, State: domain.PresenceLeft, At: time.Now().UTC()})
+		}
+	})
+}
+
+func (c *collabConn) subscribe(ctx context.Context, listIDs []string) {
+	subscribed := []string{}
+	for _, listID := range listIDs {
+		c.mu.Lock()
+		_, ok := c.lists[listID]
+		n := len(c.lists)
+		c.mu.Unlock()
+		if ok {
+			subscribed = append(subscribed, listID)
+			continue
+		}
+		if n >= collabMaxLists {
+			c.enqueue(collabReply{Type: "error", ListIDs: []string{listID}, Code: "VALIDATION_FAILED", Message: "too many subscriptions"})
+			continue
+		}
+		_, err := c.h.lists.GetByID(ctx, listID)
+		if errors.Is(err, storage.ErrNotFound) {
+			c.enqueue(collabReply{Type: "error", ListIDs: []string{listID}, Code: "NOT_FOUND", Message: "list not found"})
+			continue
+		}
+		if err != nil {
+			logger.Info("collab: subscribe to list " + listID + " failed: " + err.Error())
+			c.enqueue(collabReply{Type: "error", ListIDs: []string{listID}, Code: "INTERNAL_ERROR", Message: "internal error"})
+			continue
+		}
+
+		sub := c.h.broker.Subscribe(listID, nil)
+		presence, cancelPresence := c.h.presence.Subscribe(listID)
+		stopped := make(chan struct{})
+		stop := func() {
+			close(stopped)
+			sub.Close()
+			cancelPresence()
+		}
+
+		c.mu.Lock()
+		select {
+		case <-c.done:
+			c.mu.Unlock()
+			stop()
+			return
+		default:
+		}
+		c.lists[listID] = stop
+		c.mu.Unlock()
+
+		go c.forward(sub, presence, stopped)
+		c.h.presence.Publish(domain.Presence{ListID: listID, UserID: c.userID, State: domain.PresenceViewing, At: time.Now().UTC()})
+		subscribed = append(subscribed, listID)
+	}
+	c.enqueue(collabReply{Type: "subscribed", ListIDs: subscribed})
+}
+
+// forward пересылает клиенту события и сигналы присутствия одного списка.
+func (c *collabConn) forward(sub *service.Subscription, presence <-chan domain.Presence, stopped <-chan struct{}) {
+	for {
+		select {
+		case ev, ok := <-sub.Events:
+			if !ok {
+				select {
+				case <-stopped:
+				default:
+					// Брокер отключил подписку: клиент пропустил события
+					// и должен переподключиться и перечитать списки.
+					c.close(websocket.CloseTryAgainLater, "event stream interrupted")
+				}
+				return
+			}
+			c.enqueue(collabReply{Type: "event", Event: &ev})
+		case p, ok := <-presence:
+			if !ok {
+				return
+			}
+			if p.UserID != c.userID {
+				c.enqueue(collabReply{Type: "presence", Presence: &p})
+			}
+		case <-stopped:
+			return
+		}
+	}
+}
+
+func (c *collabConn) unsubscribe(listIDs []string) {
+	for _, listID := range listIDs {
+		c.mu.Lock()
+		stop, ok := c.lists[listID]
+		delete(c.lists, listID)
+		c.mu.Unlock()
+		if ok {
+			stop()
+			c.h.presence.Publish(domain.Presence{ListID: listID, UserID: c.userID, State: domain.PresenceLeft, At: time.Now().UTC()})
+		}
+	}
+}
+
+func (c *collabConn) publishPresence(msg collabMessage) {
+	c.mu.Lock()
+	_, ok := c.lists[msg.ListID]
+	c.mu.Unlock()
+	if !ok {
+		c.enqueue(collabReply{Type: "error", OpID: msg.OpID, Code: "VALIDATION_FAILED", Message: "not subscribed to list"})
+		return
+	}
+	switch msg.State {
+	case domain.PresenceViewing, domain.PresenceEditing, domain.PresenceTyping:
+	default:
+		c.enqueue(collabReply{Type: "error", OpID: msg.OpID, Code: "VALIDATION_FAILED", Message: "state must be viewing, editing or typing"})
+		return
+	}
+	c.h.presence.Publish(domain.Presence{
+		ListID: msg.ListID,
+		TaskID: msg.TaskID,
+		UserID: c.userID,
+		State:  msg.State,
+		At:     time.Now().UTC(),
+	})
+}
+
+// mutate выполняет изменение задачи через TaskService и подтверждает его
+// сообщением ack с op_id клиента (или error с тем же op_id).
+func (c *collabConn) mutate(ctx context.Context, msg collabMessage) {
+	if msg.OpID == "" {
+		c.enqueue(collabReply{Type: "error", Code: "VALIDATION_FAILED", Message: "op_id is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, collabOpTimeout)
+	defer cancel()
+
+	var (
+		task  *domain.Task
+		token string
+		err   error
+	)
+	switch msg.Action {
+	case "create":
+		task, token, err = c.h.tasks.CreateTask(ctx, msg.ListID, msg.Text, msg.DueAt)
+	case "update":
+		task, token, err = c.h.tasks.UpdateTask(ctx, msg.TaskID, msg.Text, msg.Completed, msg.DueAt)
+	case "delete":
+		token, err = c.h.tasks.DeleteTask(ctx, msg.TaskID)
+	default:
+		c.enqueue(collabReply{Type: "error", OpID: msg.OpID, Code: "VALIDATION_FAILED", Message: "action must be create, update or delete"})
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrInvalidTaskText):
+		c.enqueue(collabReply{Type: "error", OpID: msg.OpID, Code: "VALIDATION_FAILED", Message: err.Error()})
+	case errors.Is(err, storage.ErrNotFound):
+		c.enqueue(collabReply{Type: "error", OpID: msg.OpID, Code: "NOT_FOUND", Message: "list or task not found"})
+	case err != nil:
+		logger.Info("collab: op " + msg.OpID + " failed: " + err.Error())
+		c.enqueue(collabReply{Type: "error", OpID: msg.OpID, Code: "INTERNAL_ERROR", Message: "internal error"})
+	default:
+		c.enqueue(collabReply{Type: "ack", OpID: msg.OpID, Task: task, UndoToken: token})
+	}
+}
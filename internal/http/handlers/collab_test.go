@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/gorilla/websocket"
+)
+
+var errDBDown = errors.New("connection refused")
+
+// failure — ошибка хранилища, которую тест включает между сообщениями,
+// пока соединение обрабатывается в другой горутине.
+type failure struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (f *failure) set(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *failure) get() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// fakeListRepo и fakeTaskRepo реализуют только то, что нужно TaskService
+// и ListService в этих тестах; ошибка из failure возвращается из всех чтений.
+type fakeListRepo struct {
+	storage.ListRepository
+	lists map[string]*domain.List
+	fail  *failure
+}
+
+func (r *fakeListRepo) GetByID(ctx context.Context, id string) (*domain.List, error) {
+	if err := r.fail.get(); err != nil {
+		return nil, err
+	}
+	if l, ok := r.lists[id]; ok {
+		return l, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+type fakeTaskRepo struct {
+	storage.TaskRepository
+	tasks map[string]*domain.Task
+	fail  *failure
+}
+
+func (r *fakeTaskRepo) Create(ctx context.Context, t *domain.Task) error {
+	r.tasks[t.ID] = t
+	return nil
+}
+
+func (r *fakeTaskRepo) GetByID(ctx context.Context, id string) (*domain.Task, error) {
+	if err := r.fail.get(); err != nil {
+		return nil, err
+	}
+	if t, ok := r.tasks[id]; ok {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *fakeTaskRepo) Update(ctx context.Context, t *domain.Task) error {
+	r.tasks[t.ID] = t
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeAuditRepo struct {
+	storage.AuditRepository
+}
+
+func (fakeAuditRepo) Append(ctx context.Context, e *domain.AuditEvent) error { return nil }
+
+// dialCollab поднимает CollabHandler над списками и задачами на
+// httptest-сервере и подключается к нему от имени olga.
+func dialCollab(t *testing.T, fail *failure, listMap map[string]*domain.List, taskMap map[string]*domain.Task) *websocket.Conn {
+	t.Helper()
+	lists := &fakeListRepo{lists: listMap, fail: fail}
+	tasks := &fakeTaskRepo{tasks: taskMap, fail: fail}
+	broker := service.NewEventBroker(16)
+	h := NewCollabHandler(
+		service.NewTaskService(tasks, lists, fakeTransactor{}, fakeAuditRepo{}, nil, nil),
+		service.NewListService(lists, tasks, fakeTransactor{}, fakeAuditRepo{}, nil, nil),
+		broker,
+		service.NewPresenceHub(),
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Collab(w, r.WithContext(reqctx.WithUserID(r.Context(), "olga")))
+	}))
+	t.Cleanup(func() {
+		h.Close()
+		srv.Close()
+		broker.Close()
+	})
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// roundTrip отправляет сообщение и возвращает ответ на него.
+func roundTrip(t *testing.T, ws *websocket.Conn, msg collabMessage) collabReply {
+	t.Helper()
+	if err := ws.WriteJSON(msg); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return nextReply(t, ws)
+}
+
+// nextReply возвращает следующий ответ, который не является событием или
+// сигналом присутствия.
+func nextReply(t *testing.T, ws *websocket.Conn) collabReply {
+	t.Helper()
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var reply collabReply
+		if err := ws.ReadJSON(&reply); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if reply.Type != "event" && reply.Type != "presence" {
+			return reply
+		}
+	}
+}
+
+func TestCollab_MutateErrors(t *testing.T) {
+	fail := &failure{}
+	ws := dialCollab(t, fail,
+		map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}},
+		map[string]*domain.Task{"t1": {ID: "t1", ListID: "l1", Text: "Купить хлеб"}})
+
+	done := true
+	cases := []struct {
+		name  string
+		msg   collabMessage
+		dbErr error
+		code  string
+	}{
+		{"ack", collabMessage{OpID: "op-1", Action: "update", TaskID: "t1", Completed: &done}, nil, ""},
+		{"invalid text", collabMessage{OpID: "op-2", Action: "create", ListID: "l1", Text: strings.Repeat("a", 501)}, nil, "VALIDATION_FAILED"},
+		{"unknown list", collabMessage{OpID: "op-3", Action: "create", ListID: "missing", Text: "Молоко"}, nil, "NOT_FOUND"},
+		{"unknown task", collabMessage{OpID: "op-4", Action: "delete", TaskID: "missing"}, nil, "NOT_FOUND"},
+		{"storage failure", collabMessage{OpID: "op-5", Action: "update", TaskID: "t1", Completed: &done}, errDBDown, "INTERNAL_ERROR"},
+		{"storage failure on create", collabMessage{OpID: "op-6", Action: "create", ListID: "l1", Text: "Молоко"}, errDBDown, "INTERNAL_ERROR"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			tc.msg.Type = "mutate"
+			reply := roundTrip(t, ws, tc.msg)
+
+			if reply.OpID != tc.msg.OpID {
+				t.Errorf("op_id = %q, want %q", reply.OpID, tc.msg.OpID)
+			}
+			if tc.code == "" {
+				if reply.Type != "ack" || reply.Task == nil || !reply.Task.Completed {
+					t.Errorf("reply = %+v, want ack with completed task", reply)
+				}
+				return
+			}
+			if reply.Type != "error" || reply.Code != tc.code {
+				t.Errorf("reply = %s %s (%s), want error %s", reply.Type, reply.Code, reply.Message, tc.code)
+			}
+			if tc.dbErr != nil && strings.Contains(reply.Message, tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %q", reply.Message)
+			}
+		})
+	}
+}
+
+func TestCollab_PresenceErrorsCarryOpID(t *testing.T) {
+	ws := dialCollab(t, &failure{}, map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, map[string]*domain.Task{})
+
+	reply := roundTrip(t, ws, collabMessage{Type: "presence", OpID: "p-1", ListID: "l1", State: domain.PresenceEditing})
+	if reply.Type != "error" || reply.OpID != "p-1" || reply.Code != "VALIDATION_FAILED" {
+		t.Fatalf("not subscribed: reply = %+v", reply)
+	}
+
+	if reply := roundTrip(t, ws, collabMessage{Type: "subscribe", ListIDs: []string{"l1"}}); reply.Type != "subscribed" {
+		t.Fatalf("subscribe: reply = %+v", reply)
+	}
+	reply = roundTrip(t, ws, collabMessage{Type: "presence", OpID: "p-2", ListID: "l1", State: "sleeping"})
+	if reply.Type != "error" || reply.OpID != "p-2" || reply.Code != "VALIDATION_FAILED" {
+		t.Fatalf("bad state: reply = %+v", reply)
+	}
+}
+
+func TestCollab_SubscribeErrors(t *testing.T) {
+	fail := &failure{}
+	ws := dialCollab(t, fail, map[string]*domain.List{}, map[string]*domain.Task{})
+
+	for _, tc := range []struct {
+		name  string
+		dbErr error
+		code  string
+	}{
+		{"unknown list", nil, "NOT_FOUND"},
+		{"storage failure", errDBDown, "INTERNAL_ERROR"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			reply := roundTrip(t, ws, collabMessage{Type: "subscribe", ListIDs: []string{"l1"}})
+			if reply.Type != "error" || reply.Code != tc.code || len(reply.ListIDs) != 1 {
+				t.Fatalf("reply = %+v, want error %s", reply, tc.code)
+			}
+			// Ошибка по списку не отменяет ответ subscribed на всё сообщение.
+			if reply := nextReply(t, ws); reply.Type != "subscribed" || len(reply.ListIDs) != 0 {
+				t.Fatalf("reply = %+v, want empty subscribed", reply)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type EventHandler struct {
+	broker    *service.EventBroker
+	lists     service.ListService
+	heartbeat time.Duration
+}
+
+func NewEventHandler(broker *service.EventBroker, lists service.ListService, heartbeat time.Duration) *EventHandler {
+	return &EventHandler{broker: broker, lists: lists, heartbeat: heartbeat}
+}
+
+// ListEvents — поток изменений списка и его задач (Server-Sent Events).
+// При переподключении браузер присылает Last-Event-ID, и пропущенные события
+// досылаются из буфера; если их там уже нет, первым приходит событие reset.
+func (h *EventHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	_, err := h.lists.GetByID(ctx, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to load list","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	var lastEventID *int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, `{"code":"VALIDATION_FAILED","message":"Last-Event-ID must be an integer","details":{}}`, http.StatusBadRequest)
+			return
+		}
+		lastEventID = &n
+	}
+
+	// Поток живёт дольше WriteTimeout сервера.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub := h.broker.Subscribe(id, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if sub.Missed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, ev := range sub.Replay {
+		writeEvent(w, ev)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			writeEvent(w, ev)
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			if ev.Type == domain.EventListDeleted {
+				return
+			}
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev domain.ChangeEvent) {
+	data, _ := json.Marshal(ev)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestEvents_ListEventsErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	svc := service.NewListService(lists, &fakeTaskRepo{fail: fail}, fakeTransactor{}, fakeAuditRepo{}, nil, nil)
+	h := NewEventHandler(service.NewEventBroker(10), svc, time.Minute)
+	router := chi.NewRouter()
+	router.Get("/api/v1/lists/{id}/events", h.ListEvents)
+
+	for _, tc := range []struct {
+		name   string
+		listID string
+		dbErr  error
+		status int
+	}{
+		{"unknown list", "missing", nil, http.StatusNotFound},
+		{"storage failure", "l1", errDBDown, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/lists/"+tc.listID+"/events", nil))
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %s", rec.Body)
+			}
+		})
+	}
+}
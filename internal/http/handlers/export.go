@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// exportWriteTimeout — сколько может писаться одна порция выгрузки. Срок
+// продлевается перед каждой записью: длинная выгрузка не упирается
+// в WriteTimeout сервера, а клиент, переставший читать, отключается.
+const exportWriteTimeout = 30 * time.Second
+
+type ExportHandler struct {
+	svc *service.ExportService
+}
+
+func NewExportHandler(svc *service.ExportService) *ExportHandler {
+	return &ExportHandler{svc: svc}
+}
+
+// ListExport выгружает список с задачами в формате из параметра format.
+func (h *ExportHandler) ListExport(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	format, err := service.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeExportFormatError(w)
+		return
+	}
+
+	out := newAttachment(w, format, "list-"+id)
+	err = h.svc.ExportList(r.Context(), out, id, format)
+	if errors.Is(err, storage.ErrNotFound) && !out.started {
+		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	if err != nil && !out.started {
+		logger.Info("export list " + id + " failed: " + err.Error())
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to export list","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		logger.Info("export list " + id + " interrupted: " + err.Error())
+	}
+}
+
+// AccountExport выгружает все списки с задачами.
+func (h *ExportHandler) AccountExport(w http.ResponseWriter, r *http.Request) {
+	format, err := service.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeExportFormatError(w)
+		return
+	}
+
+	out := newAttachment(w, format, "todo-export")
+	err = h.svc.ExportAll(r.Context(), out, format)
+	if err != nil && !out.started {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to export lists","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		logger.Info("account export interrupted: " + err.Error())
+	}
+}
+
+// attachment отправляет заголовки выгрузки перед первой записью: ошибка,
+// случившаяся до начала выгрузки, возвращается обычным ответом, а после —
+// только обрывает поток.
+type attachment struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func newAttachment(w http.ResponseWriter, format service.ExportFormat, name string) *attachment {
+	return &attachment{w: w, contentType: format.ContentType(), filename: name + "." + format.Extension()}
+}
+
+func (a *attachment) Write(p []byte) (int, error) {
+	_ = http.NewResponseController(a.w).SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+	if !a.started {
+		a.started = true
+		a.w.Header().Set("Content-Type", a.contentType)
+		a.w.Header().Set("Content-Disposition", `attachment; filename="`+a.filename+`"`)
+		a.w.WriteHeader(http.StatusOK)
+	}
+	return a.w.Write(p)
+}
+
+func writeExportFormatError(w http.ResponseWriter) {
+	http.Error(w, `{"code":"VALIDATION_FAILED","message":"`+service.ErrInvalidExportFormat.Error()+`","details":{}}`, http.StatusBadRequest)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type FolderHandler struct {
+	svc *service.FolderService
+}
+
+func NewFolderHandler(svc *service.FolderService) *FolderHandler {
+	return &FolderHandler{svc: svc}
+}
+
+func (h *FolderHandler) ListFolders(w http.ResponseWriter, r *http.Request) {
+	folders, err := h.svc.ListFolders(r.Context())
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to list folders","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(folders)
+}
+
+func (h *FolderHandler) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name     string  `json:"name"`
+		ParentID *string `json:"parent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	f, err := h.svc.CreateFolder(r.Context(), req.Name, req.ParentID)
+	if err != nil {
+		writeFolderError(w, err, "parent folder not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(f)
+}
+
+// Tree возвращает всю иерархию папок и списков с числом невыполненных задач.
+func (h *FolderHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.svc.Tree(r.Context())
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to build folder tree","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(tree)
+}
+
+func (h *FolderHandler) GetFolder(w http.ResponseWriter, r *http.Request) {
+	f, err := h.svc.GetFolder(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeFolderError(w, err, "folder not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(f)
+}
+
+func (h *FolderHandler) RenameFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	f, err := h.svc.RenameFolder(r.Context(), chi.URLParam(r, "id"), req.Name)
+	if err != nil {
+		writeFolderError(w, err, "folder not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(f)
+}
+
+func (h *FolderHandler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.DeleteFolder(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeFolderError(w, err, "folder not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MoveFolder переносит папку в другую; parent_id null — на верхний уровень.
+func (h *FolderHandler) MoveFolder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ParentID *string `json:"parent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	f, err := h.svc.MoveFolder(r.Context(), chi.URLParam(r, "id"), req.ParentID)
+	if err != nil {
+		writeFolderError(w, err, "folder not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(f)
+}
+
+// FolderLists возвращает списки папки постранично, без вложенных папок.
+func (h *FolderHandler) FolderLists(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parsePagination(r)
+
+	lists, total, err := h.svc.FolderLists(r.Context(), chi.URLParam(r, "id"), limit, offset)
+	if err != nil {
+		writeFolderError(w, err, "folder not found")
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(lists)
+}
+
+// MoveList переносит список в папку; folder_id null — вне папок. Токен
+// отмены возвращается в X-Undo-Token, как у остальных изменений списка.
+func (h *FolderHandler) MoveList(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FolderID *string `json:"folder_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	list, undoToken, err := h.svc.MoveList(r.Context(), chi.URLParam(r, "id"), req.FolderID)
+	if err != nil {
+		writeFolderError(w, err, "list or folder not found")
+		return
+	}
+
+	if undoToken != "" {
+		w.Header().Set(UndoTokenHeader, undoToken)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+func writeFolderError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidFolderName), errors.Is(err, service.ErrFolderTooDeep),
+		errors.Is(err, service.ErrFolderCycle):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrNotFound):
+		body, _ := json.Marshal(map[string]any{"code": "NOT_FOUND", "message": notFound, "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusNotFound)
+	default:
+		logger.Info("folder request failed: " + err.Error())
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+	}
+}
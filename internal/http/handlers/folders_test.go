@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakeFolderRepo struct {
+	storage.FolderRepository
+	fail *failure
+}
+
+func (r *fakeFolderRepo) Get(ctx context.Context, id string) (*domain.Folder, error) {
+	if err := r.fail.get(); err != nil {
+		return nil, err
+	}
+	if id == "f1" {
+		return &domain.Folder{ID: "f1", Name: "Работа"}, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func TestFolders_GetFolderErrors(t *testing.T) {
+	fail := &failure{}
+	h := NewFolderHandler(service.NewFolderService(&fakeFolderRepo{fail: fail}, nil, fakeTransactor{}))
+	router := chi.NewRouter()
+	router.Get("/api/v1/folders/{id}", h.GetFolder)
+
+	for _, tc := range []struct {
+		name     string
+		folderID string
+		dbErr    error
+		status   int
+	}{
+		{"found", "f1", nil, http.StatusOK},
+		{"unknown folder", "missing", nil, http.StatusNotFound},
+		{"storage failure", "f1", errDBDown, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/folders/"+tc.folderID, nil))
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %s", rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/service"
+)
+
+// maxImportSize ограничивает размер импортируемого файла.
+const maxImportSize = 10 << 20
+
+type ImportHandler struct {
+	svc *service.ImportService
+}
+
+func NewImportHandler(svc *service.ImportService) *ImportHandler {
+	return &ImportHandler{svc: svc}
+}
+
+// Import создаёт списки и задачи из файла в теле запроса.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format, err := service.ParseImportFormat(q.Get("format"))
+	if err != nil {
+		writeImportError(w, http.StatusBadRequest, "VALIDATION_FAILED", err.Error(), nil)
+		return
+	}
+	columns, err := service.ParseCSVColumns(q.Get("columns"))
+	if err != nil {
+		writeImportError(w, http.StatusBadRequest, "VALIDATION_FAILED", err.Error(), nil)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(q.Get("dry_run"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		writeImportError(w, http.StatusBadRequest, "VALIDATION_FAILED", "file must be at most 10 MiB", nil)
+		return
+	}
+
+	res, err := h.svc.Import(r.Context(), bytes.NewReader(body), service.ImportOptions{
+		Format:      format,
+		DryRun:      dryRun,
+		DefaultList: q.Get("list"),
+		Columns:     columns,
+	})
+	switch {
+	case errors.Is(err, service.ErrImportRejected):
+		writeImportError(w, http.StatusBadRequest, "VALIDATION_FAILED", err.Error(), res.Errors)
+		return
+	case err != nil:
+		writeImportError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to import", nil)
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func writeImportError(w http.ResponseWriter, status int, code, message string, issues []service.ImportIssue) {
+	details := map[string]any{}
+	if issues != nil {
+		details["errors"] = issues
+	}
+	body, _ := json.Marshal(map[string]any{"code": code, "message": message, "details": details})
+	http.Error(w, string(body), status)
+}
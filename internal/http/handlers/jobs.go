@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// JobHandler — администрирование очереди фоновых задач. Доступно только
+// пользователям из admins.
+type JobHandler struct {
+	queue  *service.JobQueue
+	admins map[string]bool
+}
+
+func NewJobHandler(queue *service.JobQueue, admins []string) *JobHandler {
+	h := &JobHandler{queue: queue, admins: map[string]bool{}}
+	for _, id := range admins {
+		h.admins[id] = true
+	}
+	return h
+}
+
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	limit, offset := parsePagination(r)
+	q := r.URL.Query()
+
+	jobs, total, err := h.queue.ListJobs(r.Context(), q.Get("state"), q.Get("kind"), limit, offset)
+	if errors.Is(err, service.ErrInvalidJobState) {
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to list jobs","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(jobs)
+}
+
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"code":"NOT_FOUND","message":"job not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+
+	job, err := h.queue.GetJob(r.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"code":"NOT_FOUND","message":"job not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to load job","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// RetryJob возвращает задачу в состоянии failed в очередь.
+func (h *JobHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"code":"NOT_FOUND","message":"job not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+
+	job, err := h.queue.RetryJob(r.Context(), id)
+	switch {
+	case errors.Is(err, service.ErrJobNotFailed):
+		http.Error(w, `{"code":"CONFLICT","message":"only failed jobs can be retried","details":{}}`, http.StatusConflict)
+		return
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"code":"NOT_FOUND","message":"job not found","details":{}}`, http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to retry job","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+func (h *JobHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	userID := reqctx.UserID(r.Context())
+	if userID == reqctx.AnonymousUser {
+		http.Error(w, `{"code":"UNAUTHORIZED","message":"X-User-Id is required","details":{}}`, http.StatusUnauthorized)
+		return false
+	}
+	if !h.admins[userID] {
+		http.Error(w, `{"code":"FORBIDDEN","message":"admin access required","details":{}}`, http.StatusForbidden)
+		return false
+	}
+	return true
+}
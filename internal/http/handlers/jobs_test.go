@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakeJobRepo struct {
+	storage.JobRepository
+	fail *failure
+}
+
+func (r *fakeJobRepo) Get(ctx context.Context, id int64) (*domain.Job, error) {
+	if err := r.fail.get(); err != nil {
+		return nil, err
+	}
+	if id == 1 {
+		return &domain.Job{ID: 1, Kind: "jobs.purge", State: domain.JobFailed}, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (r *fakeJobRepo) Retry(ctx context.Context, id int64) (*domain.Job, error) {
+	job, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.State = domain.JobPending
+	return job, nil
+}
+
+func TestJobs_Errors(t *testing.T) {
+	fail := &failure{}
+	h := NewJobHandler(service.NewJobQueue(&fakeJobRepo{fail: fail}, fakeTransactor{}, 1, 3), []string{"admin"})
+	router := chi.NewRouter()
+	router.Get("/api/v1/admin/jobs/{id}", h.GetJob)
+	router.Post("/api/v1/admin/jobs/{id}/retry", h.RetryJob)
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		path   string
+		dbErr  error
+		status int
+	}{
+		{"get", http.MethodGet, "/api/v1/admin/jobs/1", nil, http.StatusOK},
+		{"get unknown job", http.MethodGet, "/api/v1/admin/jobs/2", nil, http.StatusNotFound},
+		{"get storage failure", http.MethodGet, "/api/v1/admin/jobs/1", errDBDown, http.StatusInternalServerError},
+		{"retry", http.MethodPost, "/api/v1/admin/jobs/1/retry", nil, http.StatusAccepted},
+		{"retry unknown job", http.MethodPost, "/api/v1/admin/jobs/2/retry", nil, http.StatusNotFound},
+		{"retry storage failure", http.MethodPost, "/api/v1/admin/jobs/1/retry", errDBDown, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req = req.WithContext(reqctx.WithUserID(req.Context(), "admin"))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %s", rec.Body)
+			}
+		})
+	}
+}
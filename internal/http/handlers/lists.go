@@ -1,13 +1,13 @@
 package handlers
 
 import (
-	"encoding/json"
-	"net/http"
-	"strconv"
+	"context"
+	"errors"
 
+	"todo-api/internal/api"
+	"todo-api/internal/domain"
 	"todo-api/internal/service"
-
-	"github.com/go-chi/chi/v5"
+	"todo-api/internal/storage"
 )
 
 type ListHandler struct {
@@ -18,120 +18,136 @@ func NewListHandler(s service.ListService) *ListHandler {
 	return &ListHandler{svc: s}
 }
 
-func (h *ListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
-		return
+func (h *ListHandler) CreateList(ctx context.Context, req api.CreateListRequestObject) (api.CreateListResponseObject, error) {
+	list, undoToken, err := h.svc.CreateList(ctx, req.Body.Title, deref(req.Body.Description))
+	if errors.Is(err, service.ErrInvalidListTitle) {
+		return api.CreateList400JSONResponse{ValidationErrorJSONResponse: validationFailed("title must be 1..100 chars")}, nil
 	}
-
-	list, err := h.svc.CreateList(ctx, req.Title, req.Description)
 	if err != nil {
-		http.Error(w, `{"code":"VALIDATION_FAILED","message":"title must be 1..100 chars","details":{}}`, http.StatusBadRequest)
-		return
+		return api.CreateList500JSONResponse{ServerErrorJSONResponse: internalError("failed to create list")}, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusCreated)
-
-	_ = json.NewEncoder(w).Encode(list)
+	return api.CreateList201JSONResponse{
+		Body:    toAPIList(list),
+		Headers: api.CreateList201ResponseHeaders{XUndoToken: undoToken},
+	}, nil
 }
 
-func (h *ListHandler) GetAllLists(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
+func (h *ListHandler) ListLists(ctx context.Context, req api.ListListsRequestObject) (api.ListListsResponseObject, error) {
 	limit := 20
 	offset := 0
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l >= 0 {
-			limit = l
-		}
+	if l := req.Params.Limit; l != nil && *l >= 0 {
+		limit = *l
 	}
 
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	if o := req.Params.Offset; o != nil && *o >= 0 {
+		offset = *o
 	}
 
 	lists, total := h.svc.GetAllListsWithPagination(ctx, limit, offset)
-	w.Header().Set("X-Total-Count", strconv.Itoa(total))
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(lists)
+	return api.ListLists200JSONResponse{
+		Body:    toAPILists(lists),
+		Headers: api.ListLists200ResponseHeaders{XTotalCount: total},
+	}, nil
 }
 
-func (h *ListHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	id := chi.URLParam(r, "id")
-
-	list, err := h.svc.GetByID(ctx, id)
+func (h *ListHandler) GetList(ctx context.Context, req api.GetListRequestObject) (api.GetListResponseObject, error) {
+	list, err := h.svc.GetByID(ctx, req.Id)
 	if err != nil {
-		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
-		return
+		return api.GetList404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
 	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	_ = json.NewEncoder(w).Encode(list)
+	return api.GetList200JSONResponse(toAPIList(list)), nil
 }
 
-func (h *ListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	id := chi.URLParam(r, "id")
-	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
+func (h *ListHandler) UpdateList(ctx context.Context, req api.UpdateListRequestObject) (api.UpdateListResponseObject, error) {
+	list, undoToken, err := h.svc.UpdateList(ctx, req.Id, req.Body.Title, deref(req.Body.Description))
+	if errors.Is(err, service.ErrInvalidListTitle) {
+		return api.UpdateList400JSONResponse{ValidationErrorJSONResponse: validationFailed("title must be 1..100 chars")}, nil
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
-		return
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.UpdateList404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
 	}
-	list, err := h.svc.UpdateList(ctx, id, req.Title, req.Description)
 	if err != nil {
-		http.Error(w, `{"code":"VALIDATION_FAILED","message":"title must be 1..100 chars","details":{}}`, http.StatusBadRequest)
-		return
+		return api.UpdateList500JSONResponse{ServerErrorJSONResponse: internalError("failed to update list")}, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	_ = json.NewEncoder(w).Encode(list)
+	return api.UpdateList200JSONResponse{
+		Body:    toAPIList(list),
+		Headers: api.UpdateList200ResponseHeaders{XUndoToken: undoToken},
+	}, nil
 }
 
-func (h *ListHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	id := chi.URLParam(r, "id")
+func (h *ListHandler) ArchiveList(ctx context.Context, req api.ArchiveListRequestObject) (api.ArchiveListResponseObject, error) {
+	list, undoToken, err := h.svc.ArchiveList(ctx, req.Id, true)
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.ArchiveList404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
+	}
+	if err != nil {
+		return api.ArchiveList500JSONResponse{ServerErrorJSONResponse: internalError("failed to archive list")}, nil
+	}
+	return api.ArchiveList200JSONResponse{
+		Body:    toAPIList(list),
+		Headers: api.ArchiveList200ResponseHeaders{XUndoToken: undoToken},
+	}, nil
+}
 
-	err := h.svc.Delete(ctx, id)
+func (h *ListHandler) UnarchiveList(ctx context.Context, req api.UnarchiveListRequestObject) (api.UnarchiveListResponseObject, error) {
+	list, undoToken, err := h.svc.ArchiveList(ctx, req.Id, false)
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.UnarchiveList404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
+	}
 	if err != nil {
-		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
-		return
+		return api.UnarchiveList500JSONResponse{ServerErrorJSONResponse: internalError("failed to unarchive list")}, nil
 	}
-	w.WriteHeader(http.StatusNoContent)
+	return api.UnarchiveList200JSONResponse{
+		Body:    toAPIList(list),
+		Headers: api.UnarchiveList200ResponseHeaders{XUndoToken: undoToken},
+	}, nil
 }
 
-func (h *ListHandler) SearchLists(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("title")
-	if query == "" {
-		http.Error(w, "missing title parameter", http.StatusBadRequest)
-		return
+func (h *ListHandler) CloneList(ctx context.Context, req api.CloneListRequestObject) (api.CloneListResponseObject, error) {
+	list, count, err := h.svc.CloneList(ctx, req.Id, deref(req.Body.Title), domain.CloneOptions{
+		IncludeTasks:    derefOr(req.Body.IncludeTasks, true),
+		ResetCompletion: derefOr(req.Body.ResetCompletion, false),
+		KeepLabels:      derefOr(req.Body.KeepLabels, true),
+		KeepOrder:       derefOr(req.Body.KeepOrder, true),
+	})
+	if errors.Is(err, service.ErrInvalidListTitle) {
+		return api.CloneList400JSONResponse{ValidationErrorJSONResponse: validationFailed("title must be 1..100 chars")}, nil
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.CloneList404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
 	}
+	if err != nil {
+		return api.CloneList500JSONResponse{ServerErrorJSONResponse: internalError("failed to clone list")}, nil
+	}
+	return api.CloneList201JSONResponse{List: toAPIList(list), TasksCloned: count}, nil
+}
 
-	lists, err := h.svc.SearchByTitle(r.Context(), query)
+func (h *ListHandler) DeleteList(ctx context.Context, req api.DeleteListRequestObject) (api.DeleteListResponseObject, error) {
+	undoToken, err := h.svc.Delete(ctx, req.Id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return api.DeleteList404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
 	}
+	return api.DeleteList204Response{
+		Headers: api.DeleteList204ResponseHeaders{XUndoToken: undoToken},
+	}, nil
+}
 
-	if len(lists) == 0 {
-		http.Error(w, "list not found", http.StatusNotFound)
-		return
+func (h *ListHandler) SearchLists(ctx context.Context, req api.SearchListsRequestObject) (api.SearchListsResponseObject, error) {
+	if req.Params.Title == "" {
+		return api.SearchLists400JSONResponse{ValidationErrorJSONResponse: validationFailed("missing title parameter")}, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(lists)
+	lists, err := h.svc.SearchByTitle(ctx, req.Params.Title)
+	if err != nil {
+		return api.SearchLists500JSONResponse{ServerErrorJSONResponse: internalError("failed to search lists")}, nil
+	}
+
+	res := make(api.SearchLists200JSONResponse, 0, len(lists))
+	for i := range lists {
+		res = append(res, toAPIList(&lists[i]))
+	}
+	return res, nil
 }
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"todo-api/internal/api"
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+func (r *fakeListRepo) Clone(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error) {
+	return nil, r.fail.get()
+}
+
+func (r *fakeListRepo) Update(ctx context.Context, list *domain.List) error {
+	return nil
+}
+
+func TestLists_UpdateErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	h := NewListHandler(service.NewListService(lists, &fakeTaskRepo{fail: fail}, fakeTransactor{}, fakeAuditRepo{}, nil, nil))
+
+	for _, tc := range []struct {
+		name   string
+		listID string
+		dbErr  error
+		want   api.UpdateListResponseObject
+	}{
+		{"updated", "l1", nil, api.UpdateList200JSONResponse{}},
+		{"unknown list", "missing", nil, api.UpdateList404JSONResponse{}},
+		{"storage failure", "l1", errDBDown, api.UpdateList500JSONResponse{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			resp, err := h.UpdateList(context.Background(), api.UpdateListRequestObject{Id: tc.listID, Body: &api.UpdateListJSONRequestBody{Title: "Покупки"}})
+			if err != nil {
+				t.Fatalf("UpdateList: %v", err)
+			}
+			if got, want := fmt.Sprintf("%T", resp), fmt.Sprintf("%T", tc.want); got != want {
+				t.Fatalf("response = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestLists_CloneErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	h := NewListHandler(service.NewListService(lists, &fakeTaskRepo{fail: fail}, fakeTransactor{}, fakeAuditRepo{}, nil, nil))
+
+	for _, tc := range []struct {
+		name   string
+		listID string
+		dbErr  error
+		want   api.CloneListResponseObject
+	}{
+		{"cloned", "l1", nil, api.CloneList201JSONResponse{}},
+		{"unknown list", "missing", nil, api.CloneList404JSONResponse{}},
+		{"storage failure", "l1", errDBDown, api.CloneList500JSONResponse{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			resp, err := h.CloneList(context.Background(), api.CloneListRequestObject{Id: tc.listID, Body: &api.CloneListJSONRequestBody{}})
+			if err != nil {
+				t.Fatalf("CloneList: %v", err)
+			}
+			if got, want := fmt.Sprintf("%T", resp), fmt.Sprintf("%T", tc.want); got != want {
+				t.Fatalf("response = %s, want %s", got, want)
+			}
+		})
+	}
+}
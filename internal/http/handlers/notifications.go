@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type NotificationHandler struct {
+	svc *service.NotificationService
+}
+
+func NewNotificationHandler(svc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
+
+func (h *NotificationHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.svc.GetSettings(r.Context(), userID)
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to load notification settings","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(settings)
+}
+
+func (h *NotificationHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	var req domain.NotificationSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.svc.UpdateSettings(r.Context(), userID, req)
+	switch {
+	case errors.Is(err, service.ErrInvalidEmail), errors.Is(err, service.ErrInvalidLocale),
+		errors.Is(err, service.ErrInvalidTimeZone), errors.Is(err, service.ErrInvalidPreference):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to save notification settings","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(settings)
+}
+
+// SubscribeDigest подписывает пользователя на ежедневную сводку открытых
+// задач списка.
+func (h *NotificationHandler) SubscribeDigest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.SubscribeDigest(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeDigestError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) UnsubscribeDigest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.UnsubscribeDigest(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeDigestError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeDigestError(w http.ResponseWriter, err error) {
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to update digest subscription","details":{}}`, http.StatusInternalServerError)
+}
+
+// notificationUser возвращает пользователя запроса; уведомления анонимному
+// пользователю не настраиваются.
+func notificationUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := reqctx.UserID(r.Context())
+	if userID == reqctx.AnonymousUser {
+		http.Error(w, `{"code":"UNAUTHORIZED","message":"X-User-Id is required","details":{}}`, http.StatusUnauthorized)
+		return "", false
+	}
+	return userID, true
+}
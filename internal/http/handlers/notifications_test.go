@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (fakeNotificationRepo) Subscribe(ctx context.Context, userID, listID string) error { return nil }
+
+func (fakeNotificationRepo) Unsubscribe(ctx context.Context, userID, listID string) error { return nil }
+
+func TestNotifications_DigestErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	h := NewNotificationHandler(service.NewNotificationService(fakeNotificationRepo{}, lists))
+	router := chi.NewRouter()
+	router.Put("/api/v1/lists/{id}/digest", h.SubscribeDigest)
+	router.Delete("/api/v1/lists/{id}/digest", h.UnsubscribeDigest)
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		for _, tc := range []struct {
+			name   string
+			listID string
+			dbErr  error
+			status int
+		}{
+			{"ok", "l1", nil, http.StatusNoContent},
+			{"unknown list", "missing", nil, http.StatusNotFound},
+			{"storage failure", "l1", errDBDown, http.StatusInternalServerError},
+		} {
+			t.Run(method+" "+tc.name, func(t *testing.T) {
+				fail.set(tc.dbErr)
+				req := httptest.NewRequest(method, "/api/v1/lists/"+tc.listID+"/digest", nil)
+				req = req.WithContext(reqctx.WithUserID(req.Context(), "olga"))
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if rec.Code != tc.status {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+				}
+				if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+					t.Errorf("internal error leaked to client: %s", rec.Body)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReminderHandler struct {
+	svc *service.ReminderService
+}
+
+func NewReminderHandler(svc *service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{svc: svc}
+}
+
+func (h *ReminderHandler) CreateReminder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		RemindAt         *time.Time `json:"remind_at"`
+		DueOffsetMinutes *int       `json:"due_offset_minutes"`
+		Channels         []string   `json:"channels"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	rem, err := h.svc.CreateReminder(r.Context(), userID, chi.URLParam(r, "taskID"), req.RemindAt, req.DueOffsetMinutes, req.Channels)
+	if err != nil {
+		writeReminderError(w, err, "task not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(rem)
+}
+
+func (h *ReminderHandler) ListReminders(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+
+	reminders, err := h.svc.ListReminders(r.Context(), userID, chi.URLParam(r, "taskID"))
+	if err != nil {
+		writeReminderError(w, err, "task not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(reminders)
+}
+
+// Snooze откладывает напоминание. Тело необязательно: без него напоминание
+// откладывается на service.DefaultSnooze.
+func (h *ReminderHandler) Snooze(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Until   *time.Time `json:"until"`
+		Minutes int        `json:"minutes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	rem, err := h.svc.Snooze(r.Context(), userID, chi.URLParam(r, "id"), req.Until, req.Minutes)
+	if err != nil {
+		writeReminderError(w, err, "reminder not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(rem)
+}
+
+func (h *ReminderHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+
+	rem, err := h.svc.Dismiss(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeReminderError(w, err, "reminder not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(rem)
+}
+
+func (h *ReminderHandler) DeleteReminder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteReminder(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeReminderError(w, err, "reminder not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeReminderError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReminderTime), errors.Is(err, service.ErrReminderInPast),
+		errors.Is(err, service.ErrInvalidReminderOffset), errors.Is(err, service.ErrInvalidReminderChannels),
+		errors.Is(err, service.ErrInvalidSnooze):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrNotFound):
+		body, _ := json.Marshal(map[string]any{"code": "NOT_FOUND", "message": notFound, "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusNotFound)
+	default:
+		logger.Info("reminder request failed: " + err.Error())
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+	}
+}
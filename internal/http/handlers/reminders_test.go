@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakeReminderRepo struct {
+	storage.ReminderRepository
+	fail *failure
+}
+
+func (r *fakeReminderRepo) Delete(ctx context.Context, userID, id string) error {
+	if err := r.fail.get(); err != nil {
+		return err
+	}
+	if id == "r1" {
+		return nil
+	}
+	return storage.ErrNotFound
+}
+
+func TestReminders_DeleteReminderErrors(t *testing.T) {
+	fail := &failure{}
+	h := NewReminderHandler(service.NewReminderService(&fakeReminderRepo{fail: fail}, nil))
+	router := chi.NewRouter()
+	router.Delete("/api/v1/reminders/{id}", h.DeleteReminder)
+
+	for _, tc := range []struct {
+		name       string
+		reminderID string
+		dbErr      error
+		status     int
+	}{
+		{"deleted", "r1", nil, http.StatusNoContent},
+		{"unknown reminder", "missing", nil, http.StatusNotFound},
+		{"storage failure", "r1", errDBDown, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/reminders/"+tc.reminderID, nil)
+			req = req.WithContext(reqctx.WithUserID(req.Context(), "olga"))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %s", rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RuleHandler struct {
+	svc *service.RuleService
+}
+
+func NewRuleHandler(svc *service.RuleService) *RuleHandler {
+	return &RuleHandler{svc: svc}
+}
+
+type ruleRequest struct {
+	ListID     string                 `json:"list_id"`
+	Name       string                 `json:"name"`
+	Trigger    string                 `json:"trigger"`
+	Conditions []domain.RuleCondition `json:"conditions"`
+	Actions    []domain.RuleAction    `json:"actions"`
+	Enabled    *bool                  `json:"enabled"`
+}
+
+func (req ruleRequest) input() service.RuleInput {
+	return service.RuleInput{
+		ListID:     req.ListID,
+		Name:       req.Name,
+		Trigger:    req.Trigger,
+		Conditions: req.Conditions,
+		Actions:    req.Actions,
+		Enabled:    req.Enabled,
+	}
+}
+
+func (h *RuleHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rules, err := h.svc.ListRules(ctx, reqctx.UserID(ctx), r.URL.Query().Get("list_id"))
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to list rules","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(rules)
+}
+
+func (h *RuleHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req ruleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.svc.CreateRule(ctx, reqctx.UserID(ctx), req.input())
+	if err != nil {
+		writeRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(rule)
+}
+
+func (h *RuleHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	rule, err := h.svc.GetRule(ctx, reqctx.UserID(ctx), id)
+	if err != nil {
+		writeRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(rule)
+}
+
+func (h *RuleHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req ruleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.svc.UpdateRule(ctx, reqctx.UserID(ctx), id, req.input())
+	if err != nil {
+		writeRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(rule)
+}
+
+func (h *RuleHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if err := h.svc.DeleteRule(ctx, reqctx.UserID(ctx), id); err != nil {
+		writeRuleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RuleHandler) ListExecutions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	limit, offset := parsePagination(r)
+
+	executions, total, err := h.svc.ListExecutions(ctx, reqctx.UserID(ctx), id, limit, offset)
+	if err != nil {
+		writeRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(executions)
+}
+
+// TestRule проверяет правило на задаче без выполнения действий.
+func (h *RuleHandler) TestRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.svc.TestRule(ctx, reqctx.UserID(ctx), id, req.TaskID)
+	if err != nil {
+		writeRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func writeRuleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRule), errors.Is(err, service.ErrRuleTaskRequired), errors.Is(err, service.ErrTaskNotInList):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"code":"NOT_FOUND","message":"rule not found","details":{}}`, http.StatusNotFound)
+	default:
+		logger.Info("rule request failed: " + err.Error())
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+type SearchHandler struct {
+	svc *service.SearchService
+}
+
+func NewSearchHandler(svc *service.SearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query().Get("q")
+	mode := r.URL.Query().Get("mode")
+	limitStr := r.URL.Query().Get("limit")
+	offsetStr := r.URL.Query().Get("offset")
+	thresholdStr := r.URL.Query().Get("threshold")
+
+	limit := 20
+	offset := 0
+	threshold := 0.0
+
+	if limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l >= 0 {
+			limit = l
+		}
+	}
+
+	if offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	if thresholdStr != "" {
+		t, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			http.Error(w, `{"code":"VALIDATION_FAILED","message":"threshold must be a number","details":{}}`, http.StatusBadRequest)
+			return
+		}
+		threshold = t
+	}
+
+	var (
+		hits  []domain.SearchHit
+		total int
+		err   error
+	)
+	switch mode {
+	case "", "fulltext":
+		hits, total, err = h.svc.Search(ctx, query, limit, offset)
+	case "fuzzy":
+		hits, total, err = h.svc.FuzzySearch(ctx, query, threshold, limit, offset)
+	default:
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"mode must be fulltext or fuzzy","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidSearchQuery) {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"query must be 1..200 chars","details":{}}`, http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidThreshold) {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"threshold must be in (0, 1]","details":{}}`, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"search failed","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(hits)
+}
+
+func (h *SearchHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query().Get("q")
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 {
+			http.Error(w, `{"code":"VALIDATION_FAILED","message":"limit must be 1..20","details":{}}`, http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	suggestions, err := h.svc.Suggest(ctx, query, limit)
+	if errors.Is(err, service.ErrInvalidSearchQuery) {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"query must be 1..200 chars","details":{}}`, http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidSuggestLimit) {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"limit must be 1..20","details":{}}`, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"suggest failed","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=5")
+	_ = json.NewEncoder(w).Encode(suggestions)
+}
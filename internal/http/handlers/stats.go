@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type StatsHandler struct {
+	svc *service.StatsService
+}
+
+func NewStatsHandler(svc *service.StatsService) *StatsHandler {
+	return &StatsHandler{svc: svc}
+}
+
+func (h *StatsHandler) ListStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	rng, ok := parseStatsRange(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.svc.ListStats(ctx, id, rng)
+	if errors.Is(err, service.ErrInvalidStatsRange) {
+		writeStatsRangeError(w)
+		return
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"code":"NOT_FOUND","message":"list not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to compute stats","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(stats)
+}
+
+func (h *StatsHandler) GlobalStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rng, ok := parseStatsRange(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.svc.GlobalStats(ctx, rng)
+	if errors.Is(err, service.ErrInvalidStatsRange) {
+		writeStatsRangeError(w)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to compute stats","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(stats)
+}
+
+// parseStatsRange разбирает from/to (RFC3339 или YYYY-MM-DD) и interval.
+func parseStatsRange(w http.ResponseWriter, r *http.Request) (domain.StatsRange, bool) {
+	var rng domain.StatsRange
+	q := r.URL.Query()
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &rng.From}, {"to", &rng.To}} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := parseTimeParam(v)
+		if err != nil {
+			http.Error(w, `{"code":"VALIDATION_FAILED","message":"`+p.name+` must be RFC3339 or YYYY-MM-DD","details":{}}`, http.StatusBadRequest)
+			return rng, false
+		}
+		*p.dst = t
+	}
+	rng.Interval = q.Get("interval")
+
+	return rng, true
+}
+
+func parseTimeParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
+
+func writeStatsRangeError(w http.ResponseWriter) {
+	http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid range: from must be before to, interval must be day or week, at most 366 buckets","details":{}}`, http.StatusBadRequest)
+}
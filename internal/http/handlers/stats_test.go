@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakeStatsRepo struct{}
+
+func (fakeStatsRepo) TaskStats(ctx context.Context, listID string, r domain.StatsRange) (*domain.TaskStats, error) {
+	return &domain.TaskStats{}, nil
+}
+
+func TestStats_ListStatsErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	h := NewStatsHandler(service.NewStatsService(fakeStatsRepo{}, lists))
+	router := chi.NewRouter()
+	router.Get("/api/v1/lists/{id}/stats", h.ListStats)
+
+	for _, tc := range []struct {
+		name   string
+		listID string
+		dbErr  error
+		status int
+	}{
+		{"found", "l1", nil, http.StatusOK},
+		{"unknown list", "missing", nil, http.StatusNotFound},
+		{"storage failure", "l1", errDBDown, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/lists/"+tc.listID+"/stats", nil))
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %s", rec.Body)
+			}
+		})
+	}
+}
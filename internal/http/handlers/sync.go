@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+type SyncHandler struct {
+	svc *service.SyncService
+}
+
+func NewSyncHandler(svc *service.SyncService) *SyncHandler {
+	return &SyncHandler{svc: svc}
+}
+
+func (h *SyncHandler) Pull(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	page, token, err := h.svc.Changes(r.Context(), r.URL.Query().Get("since"), limit)
+	if err != nil {
+		writeSyncError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(struct {
+		*domain.SyncPage
+		Token string `json:"token"`
+	}{page, token})
+}
+
+func (h *SyncHandler) Push(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Changes []domain.SyncChange `json:"changes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.svc.Push(r.Context(), req.Changes)
+	if err != nil {
+		writeSyncError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func writeSyncError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSyncToken), errors.Is(err, service.ErrTooManySyncChanges):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+	default:
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"sync failed","details":{}}`, http.StatusInternalServerError)
+	}
+}
@@ -1,114 +1,146 @@
 package handlers
 
 import (
-	"encoding/json"
-	"net/http"
-	"strconv"
+	"context"
+	"errors"
 
+	"todo-api/internal/api"
 	"todo-api/internal/service"
-
-	"github.com/go-chi/chi/v5"
+	"todo-api/internal/storage"
 )
 
 type TaskHandler struct {
-	svc *service.TaskService
+	svc   *service.TaskService
+	quick *service.QuickAddService
 }
 
-func NewTaskHandler(svc *service.TaskService) *TaskHandler {
-	return &TaskHandler{svc: svc}
+func NewTaskHandler(svc *service.TaskService, quick *service.QuickAddService) *TaskHandler {
+	return &TaskHandler{svc: svc, quick: quick}
 }
 
-func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	listID := chi.URLParam(r, "listID")
-
-	var req struct {
-		Text string `json:"text"`
+func (h *TaskHandler) CreateTask(ctx context.Context, req api.CreateTaskRequestObject) (api.CreateTaskResponseObject, error) {
+	task, undoToken, err := h.svc.CreateTask(ctx, req.ListID, req.Body.Text, req.Body.DueAt)
+	if errors.Is(err, service.ErrInvalidTaskText) {
+		return api.CreateTask400JSONResponse{ValidationErrorJSONResponse: validationFailed(err.Error())}, nil
 	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
-		return
+	if err != nil {
+		return api.CreateTask404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
 	}
 
-	task, err := h.svc.CreateTask(ctx, listID, req.Text)
+	return api.CreateTask201JSONResponse{
+		Body:    toAPITask(task),
+		Headers: api.CreateTask201ResponseHeaders{XUndoToken: undoToken},
+	}, nil
+}
+
+// QuickAddTask создаёт задачу из текста с датой, метками, приоритетом
+// и повторением и сообщает, какие фрагменты текста распознаны.
+func (h *TaskHandler) QuickAddTask(ctx context.Context, req api.QuickAddTaskRequestObject) (api.QuickAddTaskResponseObject, error) {
+	task, parsed, undoToken, err := h.quick.QuickAdd(ctx, req.ListID, req.Body.Text)
+	if errors.Is(err, service.ErrInvalidTaskText) || errors.Is(err, service.ErrQuickAddNoTitle) {
+		return api.QuickAddTask400JSONResponse{ValidationErrorJSONResponse: validationFailed(err.Error())}, nil
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.QuickAddTask404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return api.QuickAddTask500JSONResponse{ServerErrorJSONResponse: internalError("failed to create task")}, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(task)
+	return api.QuickAddTask201JSONResponse{
+		Body:    api.QuickAddTaskResponse{Task: toAPITask(task), Parsed: toAPIQuickAdd(parsed)},
+		Headers: api.QuickAddTask201ResponseHeaders{XUndoToken: undoToken},
+	}, nil
 }
 
-func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	listID := chi.URLParam(r, "listID")
-
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	if limit == 0 {
+func (h *TaskHandler) GetTasks(ctx context.Context, req api.GetTasksRequestObject) (api.GetTasksResponseObject, error) {
+	limit := deref(req.Params.Limit)
+	offset := deref(req.Params.Offset)
+	if limit <= 0 {
 		limit = 20
 	}
+	if offset < 0 {
+		offset = 0
+	}
 
-	tasks, total, err := h.svc.ListTasks(ctx, listID, limit, offset)
+	tasks, total, err := h.svc.ListTasks(ctx, req.ListID, limit, offset)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return api.GetTasks404JSONResponse{NotFoundJSONResponse: notFound("list not found")}, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Total-Count", strconv.Itoa(total))
-	_ = json.NewEncoder(w).Encode(tasks)
+	return api.GetTasks200JSONResponse{
+		Body:    toAPITasks(tasks),
+		Headers: api.GetTasks200ResponseHeaders{XTotalCount: total},
+	}, nil
 }
 
-func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	taskID := chi.URLParam(r, "taskID")
+func (h *TaskHandler) GetTask(ctx context.Context, req api.GetTaskRequestObject) (api.GetTaskResponseObject, error) {
+	task, err := h.svc.GetTask(ctx, req.TaskID)
+	if err != nil {
+		return api.GetTask404JSONResponse{NotFoundJSONResponse: notFound("task not found")}, nil
+	}
+	return api.GetTask200JSONResponse(toAPITask(task)), nil
+}
 
-	task, err := h.svc.GetTask(ctx, taskID)
+func (h *TaskHandler) UpdateTask(ctx context.Context, req api.UpdateTaskRequestObject) (api.UpdateTaskResponseObject, error) {
+	task, undoToken, err := h.svc.UpdateTask(ctx, req.TaskID, deref(req.Body.Text), req.Body.Completed, req.Body.DueAt)
+	if errors.Is(err, service.ErrInvalidTaskText) {
+		return api.UpdateTask400JSONResponse{ValidationErrorJSONResponse: validationFailed(err.Error())}, nil
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
+		return api.UpdateTask404JSONResponse{NotFoundJSONResponse: notFound("task not found")}, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(task)
+	return api.UpdateTask200JSONResponse{
+		Body:    toAPITask(task),
+		Headers: api.UpdateTask200ResponseHeaders{XUndoToken: undoToken},
+	}, nil
 }
 
-func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	taskID := chi.URLParam(r, "taskID")
-
-	var req struct {
-		Text      string `json:"text"`
-		Completed *bool  `json:"completed"`
+// AssignTask назначает исполнителя задачи; пустая строка снимает назначение.
+func (h *TaskHandler) AssignTask(ctx context.Context, req api.AssignTaskRequestObject) (api.AssignTaskResponseObject, error) {
+	task, undoToken, err := h.svc.AssignTask(ctx, req.TaskID, req.Body.Assignee)
+	if errors.Is(err, service.ErrInvalidAssignee) {
+		return api.AssignTask400JSONResponse{ValidationErrorJSONResponse: validationFailed(err.Error())}, nil
 	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
-		return
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.AssignTask404JSONResponse{NotFoundJSONResponse: notFound("task not found")}, nil
 	}
-
-	task, err := h.svc.UpdateTask(ctx, taskID, req.Text, req.Completed)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return api.AssignTask500JSONResponse{ServerErrorJSONResponse: internalError("failed to assign task")}, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(task)
+	return api.AssignTask200JSONResponse{
+		Body:    toAPITask(task),
+		Headers: api.AssignTask200ResponseHeaders{XUndoToken: undoToken},
+	}, nil
 }
 
-func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	taskID := chi.URLParam(r, "taskID")
+func (h *TaskHandler) DeleteTask(ctx context.Context, req api.DeleteTaskRequestObject) (api.DeleteTaskResponseObject, error) {
+	undoToken, err := h.svc.DeleteTask(ctx, req.TaskID)
+	if err != nil {
+		return api.DeleteTask404JSONResponse{NotFoundJSONResponse: notFound("task not found")}, nil
+	}
+	return api.DeleteTask204Response{
+		Headers: api.DeleteTask204ResponseHeaders{XUndoToken: undoToken},
+	}, nil
+}
 
-	if err := h.svc.DeleteTask(ctx, taskID); err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
+// CompleteTasks отмечает выполненными несколько задач списка; отменяется
+// одним токеном.
+func (h *TaskHandler) CompleteTasks(ctx context.Context, req api.CompleteTasksRequestObject) (api.CompleteTasksResponseObject, error) {
+	tasks, undoToken, err := h.svc.CompleteTasks(ctx, req.ListID, req.Body.Ids)
+	switch {
+	case errors.Is(err, service.ErrNoTaskIDs), errors.Is(err, service.ErrTaskNotInList):
+		return api.CompleteTasks400JSONResponse{ValidationErrorJSONResponse: validationFailed(err.Error())}, nil
+	case errors.Is(err, storage.ErrNotFound):
+		return api.CompleteTasks404JSONResponse{NotFoundJSONResponse: notFound("list or task not found")}, nil
+	case err != nil:
+		return api.CompleteTasks500JSONResponse{ServerErrorJSONResponse: internalError("failed to complete tasks")}, nil
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return api.CompleteTasks200JSONResponse{
+		Body:    toAPITasks(tasks),
+		Headers: api.CompleteTasks200ResponseHeaders{XUndoToken: undoToken},
+	}, nil
 }
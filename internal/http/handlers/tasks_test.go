@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"todo-api/internal/api"
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+)
+
+type fakeNotificationRepo struct {
+	storage.NotificationRepository
+}
+
+func (fakeNotificationRepo) Settings(ctx context.Context, userID string) (*domain.NotificationSettings, error) {
+	return nil, nil
+}
+
+func TestTasks_QuickAddErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	tasks := service.NewTaskService(&fakeTaskRepo{tasks: map[string]*domain.Task{}, fail: fail}, lists, fakeTransactor{}, fakeAuditRepo{}, nil, nil)
+	h := NewTaskHandler(tasks, service.NewQuickAddService(tasks, fakeNotificationRepo{}))
+
+	for _, tc := range []struct {
+		name   string
+		listID string
+		text   string
+		dbErr  error
+		want   api.QuickAddTaskResponseObject
+	}{
+		{"created", "l1", "Купить молоко завтра", nil, api.QuickAddTask201JSONResponse{}},
+		{"no title", "l1", "завтра #дом", nil, api.QuickAddTask400JSONResponse{}},
+		{"unknown list", "missing", "Купить молоко", nil, api.QuickAddTask404JSONResponse{}},
+		{"storage failure", "l1", "Купить молоко", errDBDown, api.QuickAddTask500JSONResponse{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			resp, err := h.QuickAddTask(context.Background(), api.QuickAddTaskRequestObject{
+				ListID: tc.listID,
+				Body:   &api.QuickAddTaskJSONRequestBody{Text: tc.text},
+			})
+			if err != nil {
+				t.Fatalf("QuickAddTask: %v", err)
+			}
+			if got, want := fmt.Sprintf("%T", resp), fmt.Sprintf("%T", tc.want); got != want {
+				t.Fatalf("response = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestTasks_CompleteTasksErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	taskRepo := &fakeTaskRepo{tasks: map[string]*domain.Task{"t1": {ID: "t1", ListID: "l1", Text: "Купить молоко"}}, fail: fail}
+	h := NewTaskHandler(service.NewTaskService(taskRepo, lists, fakeTransactor{}, fakeAuditRepo{}, nil, nil), nil)
+
+	for _, tc := range []struct {
+		name   string
+		listID string
+		dbErr  error
+		want   api.CompleteTasksResponseObject
+	}{
+		{"completed", "l1", nil, api.CompleteTasks200JSONResponse{}},
+		{"unknown list", "missing", nil, api.CompleteTasks404JSONResponse{}},
+		{"storage failure", "l1", errDBDown, api.CompleteTasks500JSONResponse{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			resp, err := h.CompleteTasks(context.Background(), api.CompleteTasksRequestObject{
+				ListID: tc.listID,
+				Body:   &api.CompleteTasksJSONRequestBody{Ids: []string{"t1"}},
+			})
+			if err != nil {
+				t.Fatalf("CompleteTasks: %v", err)
+			}
+			if got, want := fmt.Sprintf("%T", resp), fmt.Sprintf("%T", tc.want); got != want {
+				t.Fatalf("response = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestTasks_AssignTaskErrors(t *testing.T) {
+	fail := &failure{}
+	lists := &fakeListRepo{lists: map[string]*domain.List{"l1": {ID: "l1", Title: "Inbox"}}, fail: fail}
+	taskRepo := &fakeTaskRepo{tasks: map[string]*domain.Task{"t1": {ID: "t1", ListID: "l1", Text: "Купить молоко"}}, fail: fail}
+	h := NewTaskHandler(service.NewTaskService(taskRepo, lists, fakeTransactor{}, fakeAuditRepo{}, nil, nil), nil)
+
+	for _, tc := range []struct {
+		name   string
+		taskID string
+		dbErr  error
+		want   api.AssignTaskResponseObject
+	}{
+		{"assigned", "t1", nil, api.AssignTask200JSONResponse{}},
+		{"unknown task", "missing", nil, api.AssignTask404JSONResponse{}},
+		{"storage failure", "t1", errDBDown, api.AssignTask500JSONResponse{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			resp, err := h.AssignTask(context.Background(), api.AssignTaskRequestObject{
+				TaskID: tc.taskID,
+				Body:   &api.AssignTaskJSONRequestBody{Assignee: "olga"},
+			})
+			if err != nil {
+				t.Fatalf("AssignTask: %v", err)
+			}
+			if got, want := fmt.Sprintf("%T", resp), fmt.Sprintf("%T", tc.want); got != want {
+				t.Fatalf("response = %s, want %s", got, want)
+			}
+		})
+	}
+}
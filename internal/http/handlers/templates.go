@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TemplateHandler struct {
+	svc *service.TemplateService
+}
+
+func NewTemplateHandler(svc *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{svc: svc}
+}
+
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	templates, err := h.svc.ListTemplates(ctx, reqctx.UserID(ctx))
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to list templates","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(templates)
+}
+
+// CreateTemplate сохраняет существующий список как шаблон.
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req struct {
+		ListID    string `json:"list_id"`
+		Name      string `json:"name"`
+		StartDate string `json:"start_date"`
+		Shared    bool   `json:"shared"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.svc.SaveList(ctx, reqctx.UserID(ctx), req.ListID, req.Name, req.StartDate, req.Shared)
+	if err != nil {
+		writeTemplateError(w, err, "list not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(t)
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	t, err := h.svc.GetTemplate(ctx, reqctx.UserID(ctx), id)
+	if err != nil {
+		writeTemplateError(w, err, "template not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(t)
+}
+
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req struct {
+		Name        *string               `json:"name"`
+		Title       *string               `json:"title"`
+		Description *string               `json:"description"`
+		Tasks       []domain.TemplateTask `json:"tasks"`
+		Shared      *bool                 `json:"shared"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.svc.UpdateTemplate(ctx, reqctx.UserID(ctx), id, service.TemplateInput{
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		Tasks:       req.Tasks,
+		Shared:      req.Shared,
+	})
+	if err != nil {
+		writeTemplateError(w, err, "template not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(t)
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if err := h.svc.DeleteTemplate(ctx, reqctx.UserID(ctx), id); err != nil {
+		writeTemplateError(w, err, "template not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Instantiate создаёт по шаблону новый список с задачами.
+func (h *TemplateHandler) Instantiate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req struct {
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	list, tasks, err := h.svc.Instantiate(ctx, reqctx.UserID(ctx), id, req.Variables)
+	if err != nil {
+		writeTemplateError(w, err, "template not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]any{"list": list, "tasks": tasks})
+}
+
+func writeTemplateError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTemplate), errors.Is(err, service.ErrTemplateVariables),
+		errors.Is(err, service.ErrInvalidListTitle), errors.Is(err, service.ErrInvalidTaskText):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrNotFound):
+		body, _ := json.Marshal(map[string]any{"code": "NOT_FOUND", "message": notFound, "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusNotFound)
+	default:
+		logger.Info("template request failed: " + err.Error())
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"todo-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UndoTokenHeader — заголовок ответа с токеном отмены изменения.
+const UndoTokenHeader = "X-Undo-Token"
+
+type UndoHandler struct {
+	svc *service.UndoService
+}
+
+func NewUndoHandler(svc *service.UndoService) *UndoHandler {
+	return &UndoHandler{svc: svc}
+}
+
+func (h *UndoHandler) Undo(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.Undo(r.Context(), chi.URLParam(r, "token"))
+	switch {
+	case errors.Is(err, service.ErrUndoExpired), errors.Is(err, service.ErrUndoConflict):
+		body, _ := json.Marshal(map[string]any{"code": "CONFLICT", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusConflict)
+		return
+	case errors.Is(err, service.ErrUndoNotFound):
+		http.Error(w, `{"code":"NOT_FOUND","message":"undo token not found","details":{}}`, http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to undo","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ViewHandler struct {
+	svc *service.ViewService
+}
+
+func NewViewHandler(svc *service.ViewService) *ViewHandler {
+	return &ViewHandler{svc: svc}
+}
+
+func (h *ViewHandler) ListViews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	views, err := h.svc.ListViews(ctx, reqctx.UserID(ctx))
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to list views","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(views)
+}
+
+func (h *ViewHandler) CreateView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req struct {
+		Name   string            `json:"name"`
+		Filter domain.TaskFilter `json:"filter"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.svc.CreateView(ctx, reqctx.UserID(ctx), req.Name, req.Filter)
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(view)
+}
+
+func (h *ViewHandler) GetView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	view, err := h.svc.GetView(ctx, reqctx.UserID(ctx), id)
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(view)
+}
+
+func (h *ViewHandler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req struct {
+		Name   *string            `json:"name"`
+		Filter *domain.TaskFilter `json:"filter"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.svc.UpdateView(ctx, reqctx.UserID(ctx), id, req.Name, req.Filter)
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(view)
+}
+
+func (h *ViewHandler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if err := h.svc.DeleteView(ctx, reqctx.UserID(ctx), id); err != nil {
+		writeViewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ViewHandler) ViewTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	limit, offset := 20, 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 0 {
+			http.Error(w, `{"code":"VALIDATION_FAILED","message":"limit must be a non-negative integer","details":{}}`, http.StatusBadRequest)
+			return
+		}
+		if l > 0 {
+			limit = l
+		}
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		o, err := strconv.Atoi(offsetStr)
+		if err != nil || o < 0 {
+			http.Error(w, `{"code":"VALIDATION_FAILED","message":"offset must be a non-negative integer","details":{}}`, http.StatusBadRequest)
+			return
+		}
+		offset = o
+	}
+
+	tasks, total, err := h.svc.ViewTasks(ctx, reqctx.UserID(ctx), id, limit, offset)
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	_ = json.NewEncoder(w).Encode(tasks)
+}
+
+func writeViewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidViewName), errors.Is(err, service.ErrInvalidFilter):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+	case errors.Is(err, service.ErrBuiltInView):
+		http.Error(w, `{"code":"FORBIDDEN","message":"built-in views are read-only","details":{}}`, http.StatusForbidden)
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"code":"NOT_FOUND","message":"view not found","details":{}}`, http.StatusNotFound)
+	default:
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakeViewRepo struct {
+	storage.ViewRepository
+	fail *failure
+}
+
+func (r *fakeViewRepo) GetByID(ctx context.Context, ownerID, id string) (*domain.View, error) {
+	if err := r.fail.get(); err != nil {
+		return nil, err
+	}
+	if id == "v1" {
+		return &domain.View{ID: "v1", Name: "Срочное"}, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func TestViews_GetViewErrors(t *testing.T) {
+	fail := &failure{}
+	h := NewViewHandler(service.NewViewService(&fakeViewRepo{fail: fail}, nil))
+	router := chi.NewRouter()
+	router.Get("/api/v1/views/{id}", h.GetView)
+
+	for _, tc := range []struct {
+		name   string
+		viewID string
+		dbErr  error
+		status int
+	}{
+		{"found", "v1", nil, http.StatusOK},
+		{"unknown view", "missing", nil, http.StatusNotFound},
+		{"storage failure", "v1", errDBDown, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fail.set(tc.dbErr)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/views/"+tc.viewID, nil))
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.status, rec.Body)
+			}
+			if tc.dbErr != nil && strings.Contains(rec.Body.String(), tc.dbErr.Error()) {
+				t.Errorf("internal error leaked to client: %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestViews_ViewTasksRejectsBadPaging(t *testing.T) {
+	h := NewViewHandler(service.NewViewService(&fakeViewRepo{fail: &failure{}}, nil))
+	router := chi.NewRouter()
+	router.Get("/api/v1/views/{id}/tasks", h.ViewTasks)
+
+	for _, query := range []string{"limit=abc", "limit=-1", "offset=x", "offset=-5"} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/views/today/tasks?"+query, nil))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body)
+			}
+		})
+	}
+}
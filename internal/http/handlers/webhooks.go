@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type WebhookHandler struct {
+	svc *service.WebhookService
+}
+
+func NewWebhookHandler(svc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subs, err := h.svc.ListSubscriptions(ctx, reqctx.UserID(ctx))
+	if err != nil {
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"failed to list webhooks","details":{}}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(subs)
+}
+
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req struct {
+		URL        string   `json:"url"`
+		EventTypes []string `json:"event_types"`
+		ListID     string   `json:"list_id"`
+		Secret     string   `json:"secret"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.svc.CreateSubscription(ctx, reqctx.UserID(ctx), req.URL, req.EventTypes, req.ListID, req.Secret)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(sub)
+}
+
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	sub, err := h.svc.GetSubscription(ctx, reqctx.UserID(ctx), id)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(sub)
+}
+
+func (h *WebhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req struct {
+		URL        *string  `json:"url"`
+		EventTypes []string `json:"event_types"`
+		ListID     *string  `json:"list_id"`
+		Secret     *string  `json:"secret"`
+		Active     *bool    `json:"active"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"code":"VALIDATION_FAILED","message":"invalid json","details":{}}`, http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.svc.UpdateSubscription(ctx, reqctx.UserID(ctx), id, service.WebhookUpdate{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		ListID:     req.ListID,
+		Secret:     req.Secret,
+		Active:     req.Active,
+	})
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(sub)
+}
+
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if err := h.svc.DeleteSubscription(ctx, reqctx.UserID(ctx), id); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	limit, offset := parsePagination(r)
+
+	deliveries, total, err := h.svc.ListDeliveries(ctx, reqctx.UserID(ctx), id, limit, offset)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(deliveries)
+}
+
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"code":"NOT_FOUND","message":"delivery not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+
+	delivery, err := h.svc.GetDelivery(ctx, reqctx.UserID(ctx), id, deliveryID)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(delivery)
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"code":"NOT_FOUND","message":"delivery not found","details":{}}`, http.StatusNotFound)
+		return
+	}
+
+	if err := h.svc.Redeliver(ctx, reqctx.UserID(ctx), id, deliveryID); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidWebhookURL),
+		errors.Is(err, service.ErrWebhookURLNotAllowed),
+		errors.Is(err, service.ErrInvalidEventTypes),
+		errors.Is(err, service.ErrInvalidWebhookSecret),
+		errors.Is(err, service.ErrInvalidWebhookListID):
+		body, _ := json.Marshal(map[string]any{"code": "VALIDATION_FAILED", "message": err.Error(), "details": map[string]any{}})
+		http.Error(w, string(body), http.StatusBadRequest)
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"code":"NOT_FOUND","message":"webhook not found","details":{}}`, http.StatusNotFound)
+	default:
+		http.Error(w, `{"code":"INTERNAL_ERROR","message":"internal error","details":{}}`, http.StatusInternalServerError)
+	}
+}
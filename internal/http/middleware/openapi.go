@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"todo-api/pkg/logger"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/google/uuid"
+)
+
+func init() {
+	// Формат uuid kin-openapi по умолчанию не проверяет.
+	openapi3.DefineStringFormatCallback("uuid", func(s string) error {
+		_, err := uuid.Parse(s)
+		return err
+	})
+	// Markdown, как и text/plain, проверяется как строка.
+	openapi3filter.RegisterBodyDecoder("text/markdown", openapi3filter.FileBodyDecoder)
+}
+
+// OpenAPIValidator проверяет запросы по спецификации docs/openapi.yaml:
+// параметры пути, запроса и заголовков, Content-Type и схему тела.
+// Запросы к путям и методам вне спецификации пропускаются без проверки.
+type OpenAPIValidator struct {
+	router            routers.Router
+	validateResponses bool
+}
+
+// NewOpenAPIValidator разбирает спецификацию spec. С validateResponses
+// проверяются и ответы: несоответствие заменяет ответ ошибкой 500 — режим
+// для тестов, ответ целиком буферизуется.
+func NewOpenAPIValidator(spec []byte, validateResponses bool) (*OpenAPIValidator, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	// Пути в спецификации абсолютные, хост из servers не сверяется.
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build openapi router: %w", err)
+	}
+	return &OpenAPIValidator{router: router, validateResponses: validateResponses}, nil
+}
+
+// FieldError — нарушение спецификации. Pointer — JSON Pointer внутри
+// запроса: /path/{имя}, /query/{имя}, /header/{имя} или /body/... для тела.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+type validationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details struct {
+		Fields []FieldError `json:"fields"`
+	} `json:"details"`
+}
+
+func (v *OpenAPIValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:          true,
+				SkipSettingDefaults: true,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			writeValidationError(w, http.StatusBadRequest, "VALIDATION_FAILED",
+				"request does not match the API specification", err)
+			return
+		}
+
+		if !v.validateResponses || streaming(r, route) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		out := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.status,
+			Header:                 rec.header,
+			Options:                &openapi3filter.Options{MultiError: true, IncludeResponseStatus: true},
+		}
+		out.SetBodyBytes(rec.body.Bytes())
+		if err := openapi3filter.ValidateResponse(r.Context(), out); err != nil {
+			logger.Info("response does not match the API specification: " + r.Method + " " + r.URL.Path + ": " + err.Error())
+			writeValidationError(w, http.StatusInternalServerError, "INTERNAL_ERROR",
+				"response does not match the API specification", err)
+			return
+		}
+
+		for k, vals := range rec.header {
+			w.Header()[k] = vals
+		}
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+	})
+}
+
+// streaming сообщает, что ответ — поток (SSE или WebSocket) и буферизовать
+// его для проверки нельзя.
+func streaming(r *http.Request, route *routers.Route) bool {
+	if r.Header.Get("Upgrade") != "" || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return true
+	}
+	for code, resp := range route.Operation.Responses.Map() {
+		if code == "101" {
+			return true
+		}
+		if resp.Value != nil && resp.Value.Content.Get("text/event-stream") != nil && resp.Value.Content.Get("application/json") == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func writeValidationError(w http.ResponseWriter, status int, code, message string, err error) {
+	body := validationError{Code: code, Message: message}
+	body.Details.Fields = fieldErrors("", err, nil)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// fieldErrors раскладывает ошибку проверки на нарушения с указателями.
+func fieldErrors(pointer string, err error, res []FieldError) []FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, err := range e {
+			res = fieldErrors(pointer, err, res)
+		}
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			pointer = "/" + e.Parameter.In + "/" + escapePointer(e.Parameter.Name)
+		case strings.HasPrefix(e.Reason, "header Content-Type"):
+			pointer = "/header/Content-Type"
+		case e.RequestBody != nil:
+			pointer = "/body"
+		}
+		if e.Err == nil {
+			return append(res, FieldError{Pointer: pointer, Message: e.Reason})
+		}
+		res = fieldErrors(pointer, e.Err, res)
+	case *openapi3filter.ResponseError:
+		if strings.Contains(e.Reason, "body") {
+			pointer = "/body"
+		}
+		if e.Err == nil {
+			return append(res, FieldError{Pointer: pointer, Message: e.Reason})
+		}
+		res = fieldErrors(pointer, e.Err, res)
+	case *openapi3.SchemaError:
+		for _, p := range e.JSONPointer() {
+			pointer += "/" + escapePointer(p)
+		}
+		res = append(res, FieldError{Pointer: pointer, Message: e.Reason})
+	default:
+		res = append(res, FieldError{Pointer: pointer, Message: err.Error()})
+	}
+	return res
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// bufferedResponse накапливает ответ обработчика до проверки.
+type bufferedResponse struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status = status
+		b.wroteHeader = true
+	}
+}
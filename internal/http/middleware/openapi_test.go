@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/docs"
+)
+
+func newValidator(t *testing.T, validateResponses bool) *OpenAPIValidator {
+	t.Helper()
+	v, err := NewOpenAPIValidator(docs.OpenAPI, validateResponses)
+	if err != nil {
+		t.Fatalf("NewOpenAPIValidator: %v", err)
+	}
+	return v
+}
+
+func decodeValidationError(t *testing.T, rec *httptest.ResponseRecorder) validationError {
+	t.Helper()
+	var body validationError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func TestOpenAPIValidatorRejectsInvalidRequests(t *testing.T) {
+	reached := false
+	h := newValidator(t, false).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+
+	cases := []struct {
+		name, method, target, contentType, body string
+		pointer                                 string
+	}{
+		{"body schema", http.MethodPost, "/api/v1/lists", "application/json", `{"title":"` + strings.Repeat("a", 101) + `"}`, "/body/title"},
+		{"missing required property", http.MethodPost, "/api/v1/lists/l1/tasks", "application/json", `{}`, "/body/text"},
+		{"nested body", http.MethodPost, "/api/v1/lists/l1/tasks/complete", "application/json", `{"ids":["a",1]}`, "/body/ids/1"},
+		{"quick add text", http.MethodPost, "/api/v1/lists/l1/tasks:quick", "application/json", `{"text":""}`, "/body/text"},
+		{"clone option type", http.MethodPost, "/api/v1/lists/l1/clone", "application/json", `{"include_tasks":"yes"}`, "/body/include_tasks"},
+		{"folder parent format", http.MethodPost, "/api/v1/folders/550e8400-e29b-41d4-a716-446655440000/move", "application/json", `{"parent_id":"root"}`, "/body/parent_id"},
+		{"query type", http.MethodGet, "/api/v1/lists?limit=abc", "", "", "/query/limit"},
+		{"query minimum", http.MethodGet, "/api/v1/lists?offset=-1", "", "", "/query/offset"},
+		{"path format", http.MethodGet, "/api/v1/webhooks/not-a-uuid", "", "", "/path/id"},
+		{"content type", http.MethodPost, "/api/v1/lists", "text/plain", `{"title":"a"}`, "/header/Content-Type"},
+		{"missing body", http.MethodPost, "/api/v1/lists", "application/json", "", "/body"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached = false
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400; body %s", rec.Code, rec.Body.String())
+			}
+			if reached {
+				t.Fatal("invalid request reached the handler")
+			}
+			body := decodeValidationError(t, rec)
+			if body.Code != "VALIDATION_FAILED" {
+				t.Errorf("code = %q", body.Code)
+			}
+			if len(body.Details.Fields) == 0 || body.Details.Fields[0].Pointer != tc.pointer {
+				t.Errorf("fields = %+v, want pointer %s", body.Details.Fields, tc.pointer)
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidatorPassesValidRequests(t *testing.T) {
+	var got string
+	h := newValidator(t, false).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	const payload = `{"title":"Дом","description":"дела"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/lists", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d; body %s", rec.Code, rec.Body.String())
+	}
+	if got != payload {
+		t.Errorf("handler body = %q, want %q", got, payload)
+	}
+
+	// Импорт принимает файл как есть, в том числе Markdown.
+	const checklist = "# Дом\n\n- [ ] Полить цветы\n"
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/import?format=md&dry_run=true", strings.NewReader(checklist))
+	req.Header.Set("Content-Type", "text/markdown")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || got != checklist {
+		t.Errorf("markdown import: status = %d, body %q; handler body = %q", rec.Code, rec.Body.String(), got)
+	}
+
+	// Пути вне спецификации не проверяются.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health?limit=abc", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("undocumented path: status = %d", rec.Code)
+	}
+}
+
+func TestOpenAPIValidatorResponses(t *testing.T) {
+	respond := func(status int, body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, body)
+		})
+	}
+	v := newValidator(t, true)
+
+	rec := httptest.NewRecorder()
+	v.Middleware(respond(http.StatusOK, `{"title":"Дом","created_at":"2025-01-01T12:00:00Z"}`)).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/lists/l1", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500; body %s", rec.Code, rec.Body.String())
+	}
+	body := decodeValidationError(t, rec)
+	if len(body.Details.Fields) == 0 || body.Details.Fields[0].Pointer != "/body/id" {
+		t.Errorf("fields = %+v, want pointer /body/id", body.Details.Fields)
+	}
+
+	rec = httptest.NewRecorder()
+	v.Middleware(respond(http.StatusTeapot, `{}`)).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/lists/l1", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("undocumented status: status = %d, want 500", rec.Code)
+	}
+
+	const valid = `{"id":"l1","title":"Дом","created_at":"2025-01-01T12:00:00Z"}`
+	rec = httptest.NewRecorder()
+	v.Middleware(respond(http.StatusOK, valid)).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/lists/l1", nil))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != valid {
+		t.Errorf("valid response changed: %d %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
+
+	"todo-api/internal/reqctx"
 )
 
 const RequestIDHeader = "X-Request-Id"
@@ -11,11 +15,29 @@ const RequestIDHeader = "X-Request-Id"
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestId := r.Header.Get(RequestIDHeader)
+		if len(requestId) > reqctx.MaxIDLength {
+			w.Header().Set(RequestIDHeader, uuid.New().String())
+			writeHeaderTooLong(w, RequestIDHeader)
+			return
+		}
 		if requestId == "" {
 			requestId = uuid.New().String()
 		}
 		w.Header().Set(RequestIDHeader, requestId)
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(reqctx.WithRequestID(r.Context(), requestId)))
 
 	})
 }
+
+// writeHeaderTooLong отвечает 400 в формате ошибок проверки по спецификации.
+func writeHeaderTooLong(w http.ResponseWriter, header string) {
+	body := validationError{Code: "VALIDATION_FAILED", Message: "request does not match the API specification"}
+	body.Details.Fields = []FieldError{{
+		Pointer: "/header/" + header,
+		Message: fmt.Sprintf("must be at most %d chars", reqctx.MaxIDLength),
+	}}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todo-api/internal/reqctx"
+)
+
+func TestIDHeaders_RejectTooLong(t *testing.T) {
+	var gotUser, gotRequest string
+	h := RequestID(UserID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotRequest = reqctx.UserID(r.Context()), reqctx.RequestID(r.Context())
+	})))
+
+	ok := strings.Repeat("a", reqctx.MaxIDLength)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/lists", nil)
+	req.Header.Set(UserIDHeader, ok)
+	req.Header.Set(RequestIDHeader, ok)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || gotUser != ok || gotRequest != ok {
+		t.Fatalf("status %d, user %q, request %q", rec.Code, gotUser, gotRequest)
+	}
+
+	for _, header := range []string{UserIDHeader, RequestIDHeader} {
+		t.Run(header, func(t *testing.T) {
+			gotUser = ""
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/lists", nil)
+			req.Header.Set(header, ok+"a")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rec.Code)
+			}
+			if gotUser != "" {
+				t.Fatal("request with oversized header reached the handler")
+			}
+			body := decodeValidationError(t, rec)
+			if len(body.Details.Fields) != 1 || body.Details.Fields[0].Pointer != "/header/"+header {
+				t.Errorf("fields = %+v", body.Details.Fields)
+			}
+			if len(rec.Header().Get(RequestIDHeader)) > reqctx.MaxIDLength {
+				t.Error("oversized request id echoed back")
+			}
+		})
+	}
+}
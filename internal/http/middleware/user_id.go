@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"todo-api/internal/reqctx"
+)
+
+const UserIDHeader = "X-User-Id"
+
+// UserID кладёт в контекст идентификатор пользователя из заголовка X-User-Id.
+// Аутентификацию выполняет шлюз перед сервисом, здесь заголовку доверяем,
+// но слишком длинный идентификатор отклоняем: он не поместится в базу.
+func UserID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userID := r.Header.Get(UserIDHeader)
+		if len(userID) > reqctx.MaxIDLength {
+			writeHeaderTooLong(w, UserIDHeader)
+			return
+		}
+		if userID != "" {
+			ctx = reqctx.WithUserID(ctx, userID)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -11,33 +11,133 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func NewRouter(listHandler *handlers.ListHandler, taskHandler *handlers.TaskHandler) http.Handler {
+type Handlers struct {
+	Lists         *handlers.ListHandler
+	Tasks         *handlers.TaskHandler
+	Search        *handlers.SearchHandler
+	Views         *handlers.ViewHandler
+	Stats         *handlers.StatsHandler
+	Audit         *handlers.AuditHandler
+	Undo          *handlers.UndoHandler
+	Events        *handlers.EventHandler
+	Collab        *handlers.CollabHandler
+	Webhooks      *handlers.WebhookHandler
+	Sync          *handlers.SyncHandler
+	Export        *handlers.ExportHandler
+	Import        *handlers.ImportHandler
+	Calendar      *handlers.CalendarHandler
+	Notifications *handlers.NotificationHandler
+	Reminders     *handlers.ReminderHandler
+	Jobs          *handlers.JobHandler
+	Rules         *handlers.RuleHandler
+	Templates     *handlers.TemplateHandler
+	Folders       *handlers.FolderHandler
+	GraphQL       http.Handler
+	// OpenAPI проверяет запросы по docs/openapi.yaml; nil — без проверки.
+	OpenAPI *middleware.OpenAPIValidator
+}
+
+func NewRouter(h Handlers) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
+	r.Use(middleware.UserID)
 	r.Use(middleware.Logging)
+	if h.OpenAPI != nil {
+		r.Use(h.OpenAPI.Middleware)
+	}
+
+	// Операции со списками и задачами описаны в docs/openapi.yaml,
+	// их маршруты генерируются вместе с интерфейсом сервера.
+	handlers.API{ListHandler: h.Lists, TaskHandler: h.Tasks}.Register(r)
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Route("/lists", func(r chi.Router) {
-			r.Post("/", listHandler.CreateList)
-			r.Get("/", listHandler.GetAllLists)
-			r.Get("/search", listHandler.SearchLists)
-			r.Get("/{id}", listHandler.GetByID)
-			r.Patch("/{id}", listHandler.UpdateList)
-			r.Delete("/{id}", listHandler.Delete)
+			r.Get("/{id}/stats", h.Stats.ListStats)
+			r.Get("/{id}/activity", h.Audit.ListActivity)
+			r.Get("/{id}/events", h.Events.ListEvents)
+			r.Get("/{id}/export", h.Export.ListExport)
+			r.Post("/{id}/calendar-token", h.Calendar.IssueToken)
+			r.Get("/{id}/calendar.ics", h.Calendar.Feed)
+			r.Put("/{id}/digest", h.Notifications.SubscribeDigest)
+			r.Delete("/{id}/digest", h.Notifications.UnsubscribeDigest)
+			r.Post("/{id}/move", h.Folders.MoveList)
+		})
+		r.Route("/folders", func(r chi.Router) {
+			r.Get("/", h.Folders.ListFolders)
+			r.Post("/", h.Folders.CreateFolder)
+			r.Get("/tree", h.Folders.Tree)
+			r.Get("/{id}", h.Folders.GetFolder)
+			r.Patch("/{id}", h.Folders.RenameFolder)
+			r.Delete("/{id}", h.Folders.DeleteFolder)
+			r.Post("/{id}/move", h.Folders.MoveFolder)
+			r.Get("/{id}/lists", h.Folders.FolderLists)
+		})
+		r.Get("/tasks/{taskID}/history", h.Audit.TaskHistory)
+		r.Get("/tasks/{taskID}/reminders", h.Reminders.ListReminders)
+		r.Post("/tasks/{taskID}/reminders", h.Reminders.CreateReminder)
+		r.Route("/reminders", func(r chi.Router) {
+			r.Delete("/{id}", h.Reminders.DeleteReminder)
+			r.Post("/{id}/snooze", h.Reminders.Snooze)
+			r.Post("/{id}/dismiss", h.Reminders.Dismiss)
 		})
-		r.Route("/lists/{listID}/tasks", func(r chi.Router) {
-			r.Post("/", taskHandler.CreateTask)
-			r.Get("/", taskHandler.ListTasks)
+		r.Get("/stats", h.Stats.GlobalStats)
+		r.Get("/search", h.Search.Search)
+		r.Get("/search/suggest", h.Search.Suggest)
+		r.Route("/views", func(r chi.Router) {
+			r.Get("/", h.Views.ListViews)
+			r.Post("/", h.Views.CreateView)
+			r.Get("/{id}", h.Views.GetView)
+			r.Patch("/{id}", h.Views.UpdateView)
+			r.Delete("/{id}", h.Views.DeleteView)
+			r.Get("/{id}/tasks", h.Views.ViewTasks)
 		})
+		r.Post("/undo/{token}", h.Undo.Undo)
+		r.Get("/ws", h.Collab.Collab)
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Get("/", h.Webhooks.ListSubscriptions)
+			r.Post("/", h.Webhooks.CreateSubscription)
+			r.Get("/{id}", h.Webhooks.GetSubscription)
+			r.Patch("/{id}", h.Webhooks.UpdateSubscription)
+			r.Delete("/{id}", h.Webhooks.DeleteSubscription)
+			r.Get("/{id}/deliveries", h.Webhooks.ListDeliveries)
+			r.Get("/{id}/deliveries/{deliveryID}", h.Webhooks.GetDelivery)
+			r.Post("/{id}/deliveries/{deliveryID}/redeliver", h.Webhooks.Redeliver)
+		})
+		r.Route("/rules", func(r chi.Router) {
+			r.Get("/", h.Rules.ListRules)
+			r.Post("/", h.Rules.CreateRule)
+			r.Get("/{id}", h.Rules.GetRule)
+			r.Put("/{id}", h.Rules.UpdateRule)
+			r.Delete("/{id}", h.Rules.DeleteRule)
+			r.Get("/{id}/executions", h.Rules.ListExecutions)
+			r.Post("/{id}/test", h.Rules.TestRule)
+		})
+		r.Route("/templates", func(r chi.Router) {
+			r.Get("/", h.Templates.ListTemplates)
+			r.Post("/", h.Templates.CreateTemplate)
+			r.Get("/{id}", h.Templates.GetTemplate)
+			r.Patch("/{id}", h.Templates.UpdateTemplate)
+			r.Delete("/{id}", h.Templates.DeleteTemplate)
+			r.Post("/{id}/instantiate", h.Templates.Instantiate)
+		})
+		r.Get("/sync", h.Sync.Pull)
+		r.Post("/sync", h.Sync.Push)
+		r.Get("/export", h.Export.AccountExport)
+		r.Post("/import", h.Import.Import)
+		r.Route("/admin/jobs", func(r chi.Router) {
+			r.Get("/", h.Jobs.ListJobs)
+			r.Get("/{id}", h.Jobs.GetJob)
+			r.Post("/{id}/retry", h.Jobs.RetryJob)
+		})
+		r.Get("/me/notifications", h.Notifications.GetSettings)
+		r.Put("/me/notifications", h.Notifications.UpdateSettings)
 
 	})
 
-	r.Route("/api/v1/tasks/{taskID}", func(r chi.Router) {
-		r.Get("/", taskHandler.GetTask)
-		r.Patch("/", taskHandler.UpdateTask)
-		r.Delete("/", taskHandler.DeleteTask)
-	})
+	r.Handle("/graphql", h.GraphQL)
+	// CalDAV использует методы WebDAV и в OpenAPI не описывается.
+	r.Mount("/caldav", h.Calendar.CalDAV())
 
 	r.Get("/openapi.yaml", handlers.OpenAPISpec)
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+// undocumented — служебные маршруты, которых нет в docs/openapi.yaml;
+// "/*" на конце — все маршруты с этим префиксом.
+var undocumented = []string{"/health", "/openapi.yaml", "/swagger/*", "/graphql", "/caldav/*"}
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// TestRoutesMatchSpec проверяет, что каждая операция спецификации
+// обслуживается роутером, а у каждого маршрута API есть операция.
+func TestRoutesMatchSpec(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromFile("../../docs/openapi.yaml")
+	if err != nil {
+		t.Fatalf("load docs/openapi.yaml: %v", err)
+	}
+	routes := NewRouter(Handlers{GraphQL: http.NotFoundHandler()}).(chi.Routes)
+
+	documented := map[string]bool{}
+	for path, item := range spec.Paths.Map() {
+		concrete := pathParam.ReplaceAllString(path, "x")
+		for method := range item.Operations() {
+			documented[method+" "+pathParam.ReplaceAllString(path, "{}")] = true
+			if !routes.Match(chi.NewRouteContext(), method, concrete) {
+				t.Errorf("%s %s: no route", method, path)
+			}
+		}
+	}
+
+	err = chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(route, "/")
+		for _, p := range undocumented {
+			if route == p || (strings.HasSuffix(p, "/*") && strings.HasPrefix(route, strings.TrimSuffix(p, "*"))) {
+				return nil
+			}
+		}
+		if !documented[method+" "+pathParam.ReplaceAllString(route, "{}")] {
+			t.Errorf("%s %s: route is not documented in docs/openapi.yaml", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
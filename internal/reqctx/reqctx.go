@@ -0,0 +1,41 @@
+// Package reqctx хранит в контексте данные текущего запроса,
+// которые нужны слоям ниже HTTP (сервисам и репозиториям).
+package reqctx
+
+import "context"
+
+// AnonymousUser используется, когда клиент не передал идентификатор пользователя.
+const AnonymousUser = "anonymous"
+
+// MaxIDLength — наибольшая длина идентификатора пользователя и запроса:
+// столько вмещают колонки owner_id, actor и request_id.
+const MaxIDLength = 100
+
+type ctxKey int
+
+const (
+	userIDKey ctxKey = iota
+	requestIDKey
+)
+
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID возвращает идентификатор пользователя запроса или AnonymousUser.
+func UserID(ctx context.Context) string {
+	if id, ok := ctx.Value(userIDKey).(string); ok && id != "" {
+		return id
+	}
+	return AnonymousUser
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID возвращает идентификатор запроса или пустую строку вне HTTP-запроса.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
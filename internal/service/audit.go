@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/storage"
+)
+
+// recordAudit добавляет в журнал событие об изменении сущности. before и after —
+// состояние до и после изменения (nil для создания и удаления соответственно).
+// Вызывается внутри транзакции изменения.
+func recordAudit(ctx context.Context, repo storage.AuditRepository, entityType, entityID, listID, action string, before, after any) error {
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+	diff, err := diffSnapshots(beforeJSON, afterJSON)
+	if err != nil {
+		return err
+	}
+
+	return repo.Append(ctx, &domain.AuditEvent{
+		Actor:      reqctx.UserID(ctx),
+		RequestID:  reqctx.RequestID(ctx),
+		EntityType: entityType,
+		EntityID:   entityID,
+		ListID:     listID,
+		Action:     action,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		Diff:       diff,
+	})
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal snapshot: %w", err)
+	}
+	return b, nil
+}
+
+// diffSnapshots сравнивает верхнеуровневые поля двух JSON-объектов
+// и возвращает только изменившиеся.
+func diffSnapshots(before, after json.RawMessage) (map[string]domain.FieldChange, error) {
+	var b, a map[string]any
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &b); err != nil {
+			return nil, fmt.Errorf("unmarshal snapshot: %w", err)
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &a); err != nil {
+			return nil, fmt.Errorf("unmarshal snapshot: %w", err)
+		}
+	}
+
+	diff := map[string]domain.FieldChange{}
+	for k, bv := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(bv, av) {
+			diff[k] = domain.FieldChange{Before: bv, After: av}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			diff[k] = domain.FieldChange{Before: nil, After: av}
+		}
+	}
+	return diff, nil
+}
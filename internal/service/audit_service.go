@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+type AuditService struct {
+	repo storage.AuditRepository
+}
+
+func NewAuditService(repo storage.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// ListActivity возвращает изменения списка и его задач, новые первыми.
+// История доступна и после удаления списка.
+func (s *AuditService) ListActivity(ctx context.Context, listID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return s.repo.ListByList(ctx, listID, limit, offset)
+}
+
+func (s *AuditService) TaskHistory(ctx context.Context, taskID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return s.repo.ListByEntity(ctx, domain.EntityTask, taskID, limit, offset)
+}
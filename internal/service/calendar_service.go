@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+var (
+	ErrCalendarUnauthorized = errors.New("invalid calendar token")
+	// ErrCalendarPrecondition — не выполнено условие If-Match или If-None-Match.
+	ErrCalendarPrecondition   = errors.New("calendar object was changed or already exists")
+	ErrInvalidCalendarData    = errors.New("body must be an iCalendar object with one VTODO and a UID")
+	ErrInvalidCalendarName    = errors.New("resource name must end with .ics and must not contain /")
+	ErrCalendarObjectNotFound = errors.New("calendar object not found")
+)
+
+// CalendarService отдаёт задачи списка календарным приложениям: лентой
+// iCalendar и коллекцией CalDAV. Изменения из CalDAV проходят через
+// TaskService, как изменения через API.
+type CalendarService struct {
+	repo  storage.CalendarRepository
+	lists storage.ListRepository
+	tasks *TaskService
+	tx    storage.Transactor
+	now   func() time.Time
+}
+
+func NewCalendarService(repo storage.CalendarRepository, lists storage.ListRepository, tasks *TaskService, tx storage.Transactor) *CalendarService {
+	return &CalendarService{repo: repo, lists: lists, tasks: tasks, tx: tx, now: time.Now}
+}
+
+// IssueToken выпускает новый токен календаря списка; прежний перестаёт
+// действовать. Токен возвращается только здесь, хранится его хеш.
+func (s *CalendarService) IssueToken(ctx context.Context, listID string) (string, error) {
+	if _, err := s.lists.GetByID(ctx, listID); err != nil {
+		return "", err
+	}
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+	if err := s.repo.SetToken(ctx, listID, hashCalendarToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Authorize проверяет, что token выдан списку listID, и возвращает список.
+func (s *CalendarService) Authorize(ctx context.Context, listID, token string) (*domain.List, error) {
+	if token == "" {
+		return nil, ErrCalendarUnauthorized
+	}
+	owner, err := s.repo.ListIDByToken(ctx, hashCalendarToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(owner), []byte(listID)) != 1 {
+		return nil, ErrCalendarUnauthorized
+	}
+	return s.lists.GetByID(ctx, listID)
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteFeed пишет ленту iCalendar: задачи списка компонентами VTODO.
+func (s *CalendarService) WriteFeed(w io.Writer, list *domain.List, objects []*domain.CalendarObject) error {
+	return writeCalendar(w, list.Title, objects, s.now())
+}
+
+// Objects возвращает задачи списка как ресурсы коллекции CalDAV.
+func (s *CalendarService) Objects(ctx context.Context, listID string) ([]*domain.CalendarObject, error) {
+	return s.repo.Objects(ctx, listID)
+}
+
+// Object возвращает ресурс по имени; nil — ресурса нет.
+func (s *CalendarService) Object(ctx context.Context, listID, name string) (*domain.CalendarObject, error) {
+	return s.repo.Object(ctx, listID, name)
+}
+
+// CalendarData — ресурс в формате iCalendar.
+func (s *CalendarService) CalendarData(o *domain.CalendarObject) string {
+	return calendarData(o, s.now())
+}
+
+// Put создаёт или обновляет задачу из объекта календаря data. ifMatch и
+// ifNoneMatch — заголовки запроса: ETag текущей версии или "*".
+// SUMMARY, статус и DUE переносятся в задачу; снять срок через CalDAV
+// нельзя, как и через PATCH.
+func (s *CalendarService) Put(ctx context.Context, listID, name string, data []byte, ifMatch, ifNoneMatch string) (*domain.CalendarObject, bool, error) {
+	if !strings.HasSuffix(name, ".ics") || strings.Contains(name, "/") || len(name) > 255 {
+		return nil, false, ErrInvalidCalendarName
+	}
+	todo, err := parseVTODO(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		obj     *domain.CalendarObject
+		created bool
+	)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.repo.Object(ctx, listID, name)
+		if err != nil {
+			return err
+		}
+		if err := checkCalendarPreconditions(current, ifMatch, ifNoneMatch); err != nil {
+			return err
+		}
+
+		if current == nil {
+			task, _, err := s.tasks.CreateTask(ctx, listID, todo.Summary, todo.Due)
+			if err != nil {
+				return err
+			}
+			if todo.Completed {
+				if task, _, err = s.tasks.UpdateTask(ctx, task.ID, "", &todo.Completed, nil); err != nil {
+					return err
+				}
+			}
+			obj, created = &domain.CalendarObject{Task: task, Name: name, UID: todo.UID}, true
+			return s.repo.SaveObject(ctx, listID, obj)
+		}
+
+		task, _, err := s.tasks.UpdateTask(ctx, current.Task.ID, todo.Summary, &todo.Completed, todo.Due)
+		if err != nil {
+			return err
+		}
+		obj = &domain.CalendarObject{Task: task, Name: current.Name, UID: current.UID}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return obj, created, nil
+}
+
+// Delete удаляет задачу ресурса name.
+func (s *CalendarService) Delete(ctx context.Context, listID, name, ifMatch string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.repo.Object(ctx, listID, name)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrCalendarObjectNotFound
+		}
+		if err := checkCalendarPreconditions(current, ifMatch, ""); err != nil {
+			return err
+		}
+		_, err = s.tasks.DeleteTask(ctx, current.Task.ID)
+		return err
+	})
+}
+
+func checkCalendarPreconditions(current *domain.CalendarObject, ifMatch, ifNoneMatch string) error {
+	if ifNoneMatch == "*" && current != nil {
+		return ErrCalendarPrecondition
+	}
+	if ifMatch == "" {
+		return nil
+	}
+	if current == nil || (ifMatch != "*" && !etagListed(ifMatch, current.ETag())) {
+		return ErrCalendarPrecondition
+	}
+	return nil
+}
+
+// etagListed сообщает, что etag есть в списке заголовка If-Match.
+func etagListed(header, etag string) bool {
+	for _, e := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(e), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
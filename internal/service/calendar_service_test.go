@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+// mockCalendarRepo хранит ресурсы поверх задач, созданных через mockTaskRepo.
+type mockCalendarRepo struct {
+	tokens  map[string]string
+	tasks   map[string]*domain.Task
+	objects map[string]*domain.CalendarObject
+}
+
+func (m *mockCalendarRepo) SetToken(ctx context.Context, listID, tokenHash string) error {
+	m.tokens[tokenHash] = listID
+	return nil
+}
+
+func (m *mockCalendarRepo) ListIDByToken(ctx context.Context, tokenHash string) (string, error) {
+	return m.tokens[tokenHash], nil
+}
+
+func (m *mockCalendarRepo) Objects(ctx context.Context, listID string) ([]*domain.CalendarObject, error) {
+	var res []*domain.CalendarObject
+	for _, t := range m.tasks {
+		res = append(res, m.object(t))
+	}
+	return res, nil
+}
+
+func (m *mockCalendarRepo) Object(ctx context.Context, listID, name string) (*domain.CalendarObject, error) {
+	for _, t := range m.tasks {
+		if o := m.object(t); o.Name == name {
+			return o, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) SaveObject(ctx context.Context, listID string, o *domain.CalendarObject) error {
+	m.objects[o.Task.ID] = &domain.CalendarObject{Name: o.Name, UID: o.UID}
+	return nil
+}
+
+func (m *mockCalendarRepo) object(t *domain.Task) *domain.CalendarObject {
+	copied := *t
+	if o, ok := m.objects[t.ID]; ok {
+		return &domain.CalendarObject{Task: &copied, Name: o.Name, UID: o.UID}
+	}
+	return &domain.CalendarObject{Task: &copied, Name: t.ID + ".ics", UID: t.ID}
+}
+
+func newCalendarFixture() (*service.CalendarService, *mockCalendarRepo) {
+	repo := &mockCalendarRepo{tokens: map[string]string{}, tasks: map[string]*domain.Task{}, objects: map[string]*domain.CalendarObject{}}
+	taskRepo := &mockTaskRepo{
+		createFunc: func(ctx context.Context, task *domain.Task) error {
+			task.Version = 1
+			repo.tasks[task.ID] = task
+			return nil
+		},
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Task, error) {
+			copied := *repo.tasks[id]
+			return &copied, nil
+		},
+		updateFunc: func(ctx context.Context, task *domain.Task) error {
+			task.Version++
+			repo.tasks[task.ID] = task
+			return nil
+		},
+	}
+	tasks := service.NewTaskService(taskRepo, &mockListRepo{}, mockTransactor{}, &mockAuditRepo{}, nil, nil)
+	return service.NewCalendarService(repo, &mockListRepo{}, tasks, mockTransactor{}), repo
+}
+
+const thunderbirdTodo = "BEGIN:VCALENDAR\r\n" +
+	"PRODID:-//Mozilla.org/NONSGML Mozilla Calendar V1.1//EN\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\nTZID:Europe/Moscow\r\nEND:VTIMEZONE\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:4f8a2c1e-tb\r\n" +
+	"SUMMARY:Позвонить в банк\\, уточнить\r\n" +
+	"  лимит\r\n" +
+	"DUE;TZID=Europe/Moscow:20261020T120000\r\n" +
+	"STATUS:NEEDS-ACTION\r\n" +
+	"END:VTODO\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestCalendarPutCreatesAndUpdatesTask(t *testing.T) {
+	svc, repo := newCalendarFixture()
+	ctx := context.Background()
+
+	obj, created, err := svc.Put(ctx, "l1", "4f8a2c1e-tb.ics", []byte(thunderbirdTodo), "", "*")
+	if err != nil || !created {
+		t.Fatalf("create: created=%v err=%v", created, err)
+	}
+	task := repo.tasks[obj.Task.ID]
+	if task.Text != "Позвонить в банк, уточнить лимит" {
+		t.Errorf("summary = %q", task.Text)
+	}
+	if task.DueAt == nil || !task.DueAt.Equal(time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("due = %v, want 09:00 UTC", task.DueAt)
+	}
+	if obj.ETag() != `"1"` || obj.UID != "4f8a2c1e-tb" {
+		t.Errorf("object: etag %s uid %s", obj.ETag(), obj.UID)
+	}
+
+	// Повторное создание с If-None-Match: * и изменение по устаревшему ETag
+	// отклоняются.
+	if _, _, err := svc.Put(ctx, "l1", "4f8a2c1e-tb.ics", []byte(thunderbirdTodo), "", "*"); !errors.Is(err, service.ErrCalendarPrecondition) {
+		t.Errorf("If-None-Match: expected precondition error, got %v", err)
+	}
+	done := strings.Replace(thunderbirdTodo, "STATUS:NEEDS-ACTION", "STATUS:COMPLETED\r\nCOMPLETED:20261019T100000Z", 1)
+	if _, _, err := svc.Put(ctx, "l1", "4f8a2c1e-tb.ics", []byte(done), `"7"`, ""); !errors.Is(err, service.ErrCalendarPrecondition) {
+		t.Errorf("stale If-Match: expected precondition error, got %v", err)
+	}
+
+	obj, created, err = svc.Put(ctx, "l1", "4f8a2c1e-tb.ics", []byte(done), `"1"`, "")
+	if err != nil || created {
+		t.Fatalf("update: created=%v err=%v", created, err)
+	}
+	if !repo.tasks[obj.Task.ID].Completed || obj.ETag() != `"2"` {
+		t.Errorf("completed task: %+v etag %s", repo.tasks[obj.Task.ID], obj.ETag())
+	}
+
+	if err := svc.Delete(ctx, "l1", "missing.ics", ""); !errors.Is(err, service.ErrCalendarObjectNotFound) {
+		t.Errorf("delete missing: %v", err)
+	}
+}
+
+func TestCalendarPutRejectsInvalidData(t *testing.T) {
+	svc, _ := newCalendarFixture()
+	cases := map[string]string{
+		"no vtodo":    "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"no uid":      "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:a\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+		"invalid due": "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1\r\nDUE:tomorrow\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+	}
+	for name, data := range cases {
+		if _, _, err := svc.Put(context.Background(), "l1", "a.ics", []byte(data), "", ""); !errors.Is(err, service.ErrInvalidCalendarData) {
+			t.Errorf("%s: expected ErrInvalidCalendarData, got %v", name, err)
+		}
+	}
+	if _, _, err := svc.Put(context.Background(), "l1", "a.txt", []byte(thunderbirdTodo), "", ""); !errors.Is(err, service.ErrInvalidCalendarName) {
+		t.Errorf("name: %v", err)
+	}
+}
+
+func TestCalendarFeed(t *testing.T) {
+	svc, _ := newCalendarFixture()
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	objects := []*domain.CalendarObject{{
+		Name: "t1.ics", UID: "t1",
+		Task: &domain.Task{ID: "t1", Text: "Купить молоко; хлеб\nи " + strings.Repeat("яблоки ", 10), Completed: true,
+			CompletedAt: &due, DueAt: &due, Version: 3, CreatedAt: created, UpdatedAt: due},
+	}}
+	var buf bytes.Buffer
+	if err := svc.WriteFeed(&buf, &domain.List{ID: "l1", Title: "Дом"}, objects); err != nil {
+		t.Fatal(err)
+	}
+	feed := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n", "X-WR-CALNAME:Дом\r\n", "UID:t1\r\n", "DUE:20261020T090000Z\r\n",
+		"STATUS:COMPLETED\r\n", "SEQUENCE:2\r\n", `SUMMARY:Купить молоко\; хлеб\nи `, "END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(feed, want) {
+			t.Errorf("feed lacks %q:\n%s", want, feed)
+		}
+	}
+	for _, line := range strings.Split(feed, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line is not folded (%d octets): %q", len(line), line)
+		}
+	}
+}
+
+func TestCalendarAuthorize(t *testing.T) {
+	svc, _ := newCalendarFixture()
+	ctx := context.Background()
+	token, err := svc.IssueToken(ctx, "l1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Authorize(ctx, "l1", token); err != nil {
+		t.Errorf("valid token: %v", err)
+	}
+	if _, err := svc.Authorize(ctx, "l2", token); !errors.Is(err, service.ErrCalendarUnauthorized) {
+		t.Errorf("other list: %v", err)
+	}
+	next, _ := svc.IssueToken(ctx, "l1")
+	if _, err := svc.Authorize(ctx, "l1", next); err != nil {
+		t.Errorf("reissued token: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+// Change — изменение списка или задачи. Prev* — состояние до изменения
+// (nil при создании), List/Task — после (nil при удалении).
+type Change struct {
+	EntityType string
+	EntityID   string
+	ListID     string
+	Action     string
+	PrevList   *domain.List
+	List       *domain.List
+	PrevTask   *domain.Task
+	Task       *domain.Task
+}
+
+// ChangeHook получает изменения внутри транзакции изменения; ошибка хука
+// откатывает изменение.
+type ChangeHook interface {
+	OnChange(ctx context.Context, c Change) error
+}
+
+// ChangeHooks вызывает хуки по порядку.
+type ChangeHooks []ChangeHook
+
+func (hs ChangeHooks) OnChange(ctx context.Context, c Change) error {
+	for _, h := range hs {
+		if err := h.OnChange(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordChange записывает изменение в журнал и передаёт его хукам.
+// Вызывается внутри транзакции изменения.
+func recordChange(ctx context.Context, audit storage.AuditRepository, hooks ChangeHook, entityType, entityID, listID, action string, before, after any) error {
+	if err := recordAudit(ctx, audit, entityType, entityID, listID, action, before, after); err != nil {
+		return err
+	}
+	if hooks == nil {
+		return nil
+	}
+
+	c := Change{EntityType: entityType, EntityID: entityID, ListID: listID, Action: action}
+	switch v := before.(type) {
+	case *domain.List:
+		c.PrevList = v
+	case *domain.Task:
+		c.PrevTask = v
+	}
+	switch v := after.(type) {
+	case *domain.List:
+		c.List = v
+	case *domain.Task:
+		c.Task = v
+	}
+	return hooks.OnChange(ctx, c)
+}
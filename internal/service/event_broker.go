@@ -0,0 +1,142 @@
+package service
+
+import (
+	"sync"
+
+	"todo-api/internal/domain"
+)
+
+// subscriberBuffer — сколько событий может ждать отправки подписчику. Подписчик,
+// который не успевает их забирать, отключается и возобновляет поток по Last-Event-ID.
+const subscriberBuffer = 64
+
+// EventBroker рассылает события изменений подписчикам списков и хранит
+// последние replaySize событий для возобновления потока по Last-Event-ID.
+type EventBroker struct {
+	mu     sync.Mutex
+	ring   []domain.ChangeEvent
+	start  int
+	count  int
+	subs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription — подписка на события одного списка.
+type Subscription struct {
+	// Replay — события после запрошенного Last-Event-ID из буфера.
+	Replay []domain.ChangeEvent
+	// Missed — запрошенного события в буфере уже нет: часть изменений
+	// пропущена, клиенту нужно перечитать список целиком.
+	Missed bool
+	// Events закрывается при отключении подписчика брокером.
+	Events <-chan domain.ChangeEvent
+
+	ch     chan domain.ChangeEvent
+	listID string
+	broker *EventBroker
+}
+
+func NewEventBroker(replaySize int) *EventBroker {
+	return &EventBroker{
+		ring: make([]domain.ChangeEvent, max(replaySize, 1)),
+		subs: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe подписывает на события списка listID. Если передан lastEventID,
+// в Replay попадают события списка, пришедшие после него.
+func (b *EventBroker) Subscribe(listID string, lastEventID *int64) *Subscription {
+	ch := make(chan domain.ChangeEvent, subscriberBuffer)
+	sub := &Subscription{Events: ch, ch: ch, listID: listID, broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastEventID != nil {
+		sub.Replay, sub.Missed = b.replayAfter(listID, *lastEventID)
+	}
+	if b.closed {
+		close(ch)
+		return sub
+	}
+	if b.subs[listID] == nil {
+		b.subs[listID] = make(map[*Subscription]struct{})
+	}
+	b.subs[listID][sub] = struct{}{}
+	return sub
+}
+
+// Close отписывает; повторный вызов ничего не делает.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// Publish сохраняет событие в буфере и отправляет его подписчикам списка.
+func (b *EventBroker) Publish(ev domain.ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ring[(b.start+b.count)%len(b.ring)] = ev
+	if b.count < len(b.ring) {
+		b.count++
+	} else {
+		b.start = (b.start + 1) % len(b.ring)
+	}
+
+	for sub := range b.subs[ev.ListID] {
+		select {
+		case sub.ch <- ev:
+		default:
+			b.remove(sub)
+		}
+	}
+}
+
+// Close отключает всех подписчиков; вызывается при остановке сервера,
+// чтобы открытые потоки завершились.
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.subs {
+		for sub := range subs {
+			b.remove(sub)
+		}
+	}
+}
+
+func (b *EventBroker) remove(sub *Subscription) {
+	subs, ok := b.subs[sub.listID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subs, sub.listID)
+	}
+	close(sub.ch)
+}
+
+// replayAfter ищет событие lastEventID в буфере. События приходят в порядке
+// фиксации транзакций, поэтому ищется само событие, а не первое с большим ID.
+func (b *EventBroker) replayAfter(listID string, lastEventID int64) ([]domain.ChangeEvent, bool) {
+	for i := b.count - 1; i >= 0; i-- {
+		if b.ring[(b.start+i)%len(b.ring)].ID != lastEventID {
+			continue
+		}
+		replay := []domain.ChangeEvent{}
+		for j := i + 1; j < b.count; j++ {
+			if ev := b.ring[(b.start+j)%len(b.ring)]; ev.ListID == listID {
+				replay = append(replay, ev)
+			}
+		}
+		return replay, false
+	}
+	return nil, true
+}
@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+func TestEventBroker_ReplayAfterLastEventID(t *testing.T) {
+	b := service.NewEventBroker(3)
+	for i, listID := range []string{"a", "b", "a", "a"} {
+		b.Publish(domain.ChangeEvent{ID: int64(i + 1), ListID: listID, Type: domain.EventTaskUpdated})
+	}
+
+	last := int64(2)
+	sub := b.Subscribe("a", &last)
+	defer sub.Close()
+	if sub.Missed || len(sub.Replay) != 2 || sub.Replay[0].ID != 3 || sub.Replay[1].ID != 4 {
+		t.Errorf("expected replay of events 3 and 4, got %+v (missed %v)", sub.Replay, sub.Missed)
+	}
+
+	// Событие 1 вытеснено из буфера размером 3.
+	last = 1
+	old := b.Subscribe("a", &last)
+	defer old.Close()
+	if !old.Missed {
+		t.Errorf("expected missed for evicted event")
+	}
+
+	b.Publish(domain.ChangeEvent{ID: 5, ListID: "b"})
+	b.Publish(domain.ChangeEvent{ID: 6, ListID: "a"})
+	if ev := <-sub.Events; ev.ID != 6 {
+		t.Errorf("expected live event 6, got %d", ev.ID)
+	}
+}
+
+func TestEventBroker_DisconnectsSlowSubscriber(t *testing.T) {
+	b := service.NewEventBroker(10)
+	sub := b.Subscribe("a", nil)
+
+	for i := 0; i < 100; i++ {
+		b.Publish(domain.ChangeEvent{ID: int64(i + 1), ListID: "a"})
+	}
+
+	n := 0
+	for range sub.Events {
+		n++
+	}
+	if n == 0 || n == 100 {
+		t.Errorf("expected subscriber to be cut off after its buffer filled, got %d events", n)
+	}
+	sub.Close()
+}
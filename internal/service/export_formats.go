@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo-api/internal/domain"
+)
+
+// exportEncoder пишет выгрузку по мере чтения: list вызывается в начале
+// каждого списка, task — для каждой его задачи.
+type exportEncoder interface {
+	begin() error
+	list(l *domain.List) error
+	task(l *domain.List, t *domain.Task) error
+	end() error
+}
+
+func newExportEncoder(w io.Writer, format ExportFormat, now time.Time) exportEncoder {
+	switch format {
+	case ExportCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}
+	case ExportMarkdown:
+		return &markdownEncoder{w: bufio.NewWriter(w)}
+	case ExportTodoTxt:
+		return &todoTxtEncoder{w: bufio.NewWriter(w)}
+	default:
+		return &jsonEncoder{w: bufio.NewWriter(w), now: now}
+	}
+}
+
+// jsonEncoder пишет {"exported_at": ..., "lists": [{...списка, "tasks": [...]}]}.
+type jsonEncoder struct {
+	w      *bufio.Writer
+	now    time.Time
+	lists  int
+	tasks  int
+	inList bool
+}
+
+func (e *jsonEncoder) begin() error {
+	at, _ := json.Marshal(e.now)
+	_, err := e.w.WriteString(`{"exported_at":` + string(at) + `,"lists":[`)
+	return err
+}
+
+func (e *jsonEncoder) list(l *domain.List) error {
+	if e.inList {
+		e.w.WriteString("]}")
+	}
+	if e.lists > 0 {
+		e.w.WriteByte(',')
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	// Задачи дописываются полем объекта списка.
+	e.w.Write(b[:len(b)-1])
+	_, err = e.w.WriteString(`,"tasks":[`)
+	e.lists++
+	e.tasks = 0
+	e.inList = true
+	return err
+}
+
+func (e *jsonEncoder) task(_ *domain.List, t *domain.Task) error {
+	if e.tasks > 0 {
+		e.w.WriteByte(',')
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	e.tasks++
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonEncoder) end() error {
+	if e.inList {
+		e.w.WriteString("]}")
+	}
+	e.w.WriteString("]}\n")
+	return e.w.Flush()
+}
+
+var csvHeader = []string{
+	"list_id", "list_title", "list_description",
+	"task_id", "text", "completed", "due_at", "completed_at", "created_at", "updated_at",
+}
+
+// csvEncoder пишет строку на задачу; список без задач — строкой с пустыми
+// полями задачи, чтобы он не потерялся.
+type csvEncoder struct {
+	w       *csv.Writer
+	pending *domain.List
+}
+
+func (e *csvEncoder) begin() error {
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvEncoder) list(l *domain.List) error {
+	if err := e.flushPending(); err != nil {
+		return err
+	}
+	e.pending = l
+	return nil
+}
+
+func (e *csvEncoder) task(l *domain.List, t *domain.Task) error {
+	e.pending = nil
+	return e.w.Write([]string{
+		l.ID, csvText(l.Title), csvText(l.Description),
+		t.ID, csvText(t.Text), strconv.FormatBool(t.Completed),
+		formatTimePtr(t.DueAt), formatTimePtr(t.CompletedAt),
+		t.CreatedAt.UTC().Format(time.RFC3339), t.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) flushPending() error {
+	if l := e.pending; l != nil {
+		e.pending = nil
+		return e.w.Write([]string{l.ID, csvText(l.Title), csvText(l.Description), "", "", "", "", "", "", ""})
+	}
+	return nil
+}
+
+func (e *csvEncoder) end() error {
+	if err := e.flushPending(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvText защищает от CSV-инъекции: Excel и Google Sheets считают формулой
+// ячейку, которая начинается с =, +, -, @, табуляции или возврата каретки,
+// поэтому такой текст получает префикс-апостроф.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// markdownEncoder пишет список заголовком, задачи — чекбоксами GitHub.
+type markdownEncoder struct {
+	w     *bufio.Writer
+	lists int
+}
+
+func (e *markdownEncoder) begin() error { return nil }
+
+func (e *markdownEncoder) list(l *domain.List) error {
+	if e.lists > 0 {
+		e.w.WriteString("\n")
+	}
+	e.lists++
+	e.w.WriteString("# " + markdownText(oneLine(l.Title)) + "\n\n")
+	if l.Description != "" {
+		e.w.WriteString(markdownBlock(l.Description) + "\n\n")
+	}
+	return nil
+}
+
+func (e *markdownEncoder) task(_ *domain.List, t *domain.Task) error {
+	box := "[ ]"
+	if t.Completed {
+		box = "[x]"
+	}
+	line := "- " + box + " " + markdownText(oneLine(t.Text))
+	if t.DueAt != nil {
+		line += " (due " + t.DueAt.UTC().Format(time.DateOnly) + ")"
+	}
+	_, err := e.w.WriteString(line + "\n")
+	return err
+}
+
+func (e *markdownEncoder) end() error { return e.w.Flush() }
+
+// markdownEscaper экранирует символы разметки внутри строки, в том числе
+// < и >, чтобы текст задачи не превращался в HTML при отображении.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`, "~", `\~`, "!", `\!`,
+)
+
+func markdownText(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownListMarker — нумерованный пункт списка в начале строки.
+var markdownListMarker = regexp.MustCompile(`^(\d+)([.)])`)
+
+// markdownBlock экранирует многострочный текст: кроме разметки внутри строк,
+// строка не должна начинаться как пункт списка, заголовок или код.
+func markdownBlock(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = markdownText(strings.TrimSpace(line))
+		if line != "" && strings.ContainsRune("-+=", rune(line[0])) {
+			line = `\` + line
+		}
+		lines[i] = markdownListMarker.ReplaceAllString(line, `$1\$2`)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// todoTxtEncoder пишет задачи по формату todo.txt
+// (https://github.com/todotxt/todo.txt): отметка "x" и дата выполнения,
+// дата создания, текст, проект +<название списка> и due:ГГГГ-ММ-ДД.
+type todoTxtEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *todoTxtEncoder) begin() error { return nil }
+
+func (e *todoTxtEncoder) list(*domain.List) error { return nil }
+
+func (e *todoTxtEncoder) task(l *domain.List, t *domain.Task) error {
+	var b strings.Builder
+	if t.Completed {
+		done := t.UpdatedAt
+		if t.CompletedAt != nil {
+			done = *t.CompletedAt
+		}
+		b.WriteString("x " + done.UTC().Format(time.DateOnly) + " ")
+	}
+	b.WriteString(t.CreatedAt.UTC().Format(time.DateOnly) + " " + oneLine(t.Text))
+	if project := todoTxtProject(l.Title); project != "" {
+		b.WriteString(" +" + project)
+	}
+	if t.DueAt != nil {
+		b.WriteString(" due:" + t.DueAt.UTC().Format(time.DateOnly))
+	}
+	b.WriteString("\n")
+	_, err := e.w.WriteString(b.String())
+	return err
+}
+
+func (e *todoTxtEncoder) end() error { return e.w.Flush() }
+
+// todoTxtProject превращает название списка в тег проекта: пробелов в теге
+// быть не может, слова соединяются дефисом.
+func todoTxtProject(title string) string {
+	return strings.Join(strings.Fields(title), "-")
+}
+
+// oneLine заменяет переводы строк пробелами: в Markdown и todo.txt задача
+// занимает одну строку.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
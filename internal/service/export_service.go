@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+var ErrInvalidExportFormat = errors.New("format must be csv, json, md or todotxt")
+
+// ExportFormat — формат выгрузки списков и задач.
+type ExportFormat string
+
+const (
+	ExportCSV      ExportFormat = "csv"
+	ExportJSON     ExportFormat = "json"
+	ExportMarkdown ExportFormat = "md"
+	ExportTodoTxt  ExportFormat = "todotxt"
+)
+
+// ParseExportFormat разбирает параметр format; пустое значение — JSON.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch f := ExportFormat(s); f {
+	case "":
+		return ExportJSON, nil
+	case ExportCSV, ExportJSON, ExportMarkdown, ExportTodoTxt:
+		return f, nil
+	default:
+		return "", ErrInvalidExportFormat
+	}
+}
+
+// ContentType — тип содержимого ответа с выгрузкой.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	case ExportMarkdown:
+		return "text/markdown; charset=utf-8"
+	case ExportTodoTxt:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// Extension — расширение файла выгрузки.
+func (f ExportFormat) Extension() string {
+	switch f {
+	case ExportMarkdown:
+		return "md"
+	case ExportTodoTxt:
+		return "txt"
+	default:
+		return string(f)
+	}
+}
+
+type ExportService struct {
+	repo  storage.ExportRepository
+	lists storage.ListRepository
+	now   func() time.Time
+}
+
+func NewExportService(repo storage.ExportRepository, lists storage.ListRepository) *ExportService {
+	return &ExportService{repo: repo, lists: lists, now: time.Now}
+}
+
+// ExportList пишет в w список с задачами. Если списка нет, ошибка
+// возвращается до записи в w.
+func (s *ExportService) ExportList(ctx context.Context, w io.Writer, listID string, format ExportFormat) error {
+	if _, err := s.lists.GetByID(ctx, listID); err != nil {
+		return err
+	}
+	return s.export(ctx, w, listID, format)
+}
+
+// ExportAll пишет в w все списки с задачами.
+func (s *ExportService) ExportAll(ctx context.Context, w io.Writer, format ExportFormat) error {
+	return s.export(ctx, w, "", format)
+}
+
+func (s *ExportService) export(ctx context.Context, w io.Writer, listID string, format ExportFormat) error {
+	enc := newExportEncoder(w, format, s.now().UTC())
+	if err := enc.begin(); err != nil {
+		return err
+	}
+	var current string
+	err := s.repo.Stream(ctx, listID, func(list *domain.List, task *domain.Task) error {
+		if list.ID != current {
+			current = list.ID
+			if err := enc.list(list); err != nil {
+				return err
+			}
+		}
+		if task == nil {
+			return nil
+		}
+		return enc.task(list, task)
+	})
+	if err != nil {
+		return err
+	}
+	return enc.end()
+}
@@ -0,0 +1,196 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+type mockExportRepo struct {
+	lists  []*domain.List
+	tasks  map[string][]*domain.Task
+	listID string
+}
+
+func (m *mockExportRepo) Stream(ctx context.Context, listID string, fn func(list *domain.List, task *domain.Task) error) error {
+	m.listID = listID
+	for _, l := range m.lists {
+		if listID != "" && l.ID != listID {
+			continue
+		}
+		if len(m.tasks[l.ID]) == 0 {
+			if err := fn(l, nil); err != nil {
+				return err
+			}
+		}
+		for _, t := range m.tasks[l.ID] {
+			if err := fn(l, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newExportFixture() *mockExportRepo {
+	created := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	done := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	return &mockExportRepo{
+		lists: []*domain.List{
+			{ID: "l1", Title: "Домашние дела", Description: "Выходные"},
+			{ID: "l2", Title: "Пустой"},
+		},
+		tasks: map[string][]*domain.Task{
+			"l1": {
+				{ID: "t1", ListID: "l1", Text: "Купить молоко", Completed: true, CompletedAt: &done, CreatedAt: created, UpdatedAt: done},
+				{ID: "t2", ListID: "l1", Text: "Починить\nкран", DueAt: &due, CreatedAt: created, UpdatedAt: created},
+			},
+		},
+	}
+}
+
+func export(t *testing.T, format service.ExportFormat, listID string) string {
+	t.Helper()
+	svc := service.NewExportService(newExportFixture(), &mockListRepo{})
+	var buf bytes.Buffer
+	var err error
+	if listID == "" {
+		err = svc.ExportAll(context.Background(), &buf, format)
+	} else {
+		err = svc.ExportList(context.Background(), &buf, listID, format)
+	}
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	return buf.String()
+}
+
+func TestExportMarkdownUsesCheckboxes(t *testing.T) {
+	got := export(t, service.ExportMarkdown, "l1")
+	want := "# Домашние дела\n\nВыходные\n\n- [x] Купить молоко\n- [ ] Починить кран (due 2026-10-20)\n"
+	if got != want {
+		t.Errorf("markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportTodoTxt(t *testing.T) {
+	got := export(t, service.ExportTodoTxt, "l1")
+	want := "x 2026-10-19 2026-10-18 Купить молоко +Домашние-дела\n" +
+		"2026-10-18 Починить кран +Домашние-дела due:2026-10-20\n"
+	if got != want {
+		t.Errorf("todo.txt:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportCSVKeepsEmptyLists(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(export(t, service.ExportCSV, ""))).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected header and 3 rows, got %d", len(rows))
+	}
+	if rows[0][0] != "list_id" || rows[1][3] != "t1" || rows[1][5] != "true" || rows[1][7] != "2026-10-19T12:00:00Z" {
+		t.Errorf("unexpected task row: %v", rows[1])
+	}
+	if rows[3][0] != "l2" || rows[3][3] != "" {
+		t.Errorf("empty list row: %v", rows[3])
+	}
+}
+
+func TestExportCSVNeutralizesFormulas(t *testing.T) {
+	repo := &mockExportRepo{
+		lists: []*domain.List{{ID: "l1", Title: "=HYPERLINK(\"http://evil\")", Description: "@SUM(A1)"}},
+		tasks: map[string][]*domain.Task{"l1": {
+			{ID: "t1", ListID: "l1", Text: "+1 позвонить"},
+			{ID: "t2", ListID: "l1", Text: "-2"},
+			{ID: "t3", ListID: "l1", Text: "Купить 2+2"},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := service.NewExportService(repo, &mockListRepo{}).ExportAll(context.Background(), &buf, service.ExportCSV); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if rows[1][1] != `'=HYPERLINK("http://evil")` || rows[1][2] != "'@SUM(A1)" {
+		t.Errorf("list cells: %q, %q", rows[1][1], rows[1][2])
+	}
+	for i, want := range []string{"'+1 позвонить", "'-2", "Купить 2+2"} {
+		if got := rows[i+1][4]; got != want {
+			t.Errorf("task %d: %q, want %q", i+1, got, want)
+		}
+	}
+}
+
+func TestExportMarkdownEscapes(t *testing.T) {
+	repo := &mockExportRepo{
+		lists: []*domain.List{{ID: "l1", Title: "Проект *X*", Description: "# не заголовок\n- не пункт\n1. и не номер\n<b>жирный</b>"}},
+		tasks: map[string][]*domain.Task{"l1": {
+			{ID: "t1", ListID: "l1", Text: "[ссылка](javascript:alert(1)) <img src=x>"},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := service.NewExportService(repo, &mockListRepo{}).ExportAll(context.Background(), &buf, service.ExportMarkdown); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	want := "# Проект \\*X\\*\n\n" +
+		"\\# не заголовок\n\\- не пункт\n1\\. и не номер\n\\<b\\>жирный\\</b\\>\n\n" +
+		"- [ ] \\[ссылка\\](javascript:alert(1)) \\<img src=x\\>\n"
+	if got := buf.String(); got != want {
+		t.Errorf("markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	var doc struct {
+		ExportedAt time.Time `json:"exported_at"`
+		Lists      []struct {
+			ID    string         `json:"id"`
+			Tasks []*domain.Task `json:"tasks"`
+		} `json:"lists"`
+	}
+	if err := json.Unmarshal([]byte(export(t, service.ExportJSON, "")), &doc); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(doc.Lists) != 2 || len(doc.Lists[0].Tasks) != 2 || len(doc.Lists[1].Tasks) != 0 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	if doc.Lists[0].Tasks[1].Text != "Починить\nкран" {
+		t.Errorf("task text: %q", doc.Lists[0].Tasks[1].Text)
+	}
+}
+
+func TestExportListNotFoundWritesNothing(t *testing.T) {
+	lists := &mockListRepo{getByIDFunc: func(ctx context.Context, id string) (*domain.List, error) {
+		return nil, errors.New("not found")
+	}}
+	svc := service.NewExportService(newExportFixture(), lists)
+	var buf bytes.Buffer
+	if err := svc.ExportList(context.Background(), &buf, "missing", service.ExportCSV); err == nil {
+		t.Fatal("expected error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	if f, err := service.ParseExportFormat(""); err != nil || f != service.ExportJSON {
+		t.Errorf("default format: %v, %v", f, err)
+	}
+	if _, err := service.ParseExportFormat("xml"); !errors.Is(err, service.ErrInvalidExportFormat) {
+		t.Errorf("expected ErrInvalidExportFormat, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidFolderName = errors.New("name must be 1..100 chars")
+	ErrFolderTooDeep     = fmt.Errorf("folders can be nested at most %d levels deep", domain.MaxFolderDepth)
+	ErrFolderCycle       = errors.New("folder cannot be moved into itself or its subfolder")
+)
+
+// FolderService управляет папками и размещением списков в них. Изменения
+// дерева выполняются в транзакции под блокировкой дерева, поэтому
+// параллельные переносы не создают циклов и не превышают глубину. Списки
+// переносятся через ListService, как и другие изменения списков.
+type FolderService struct {
+	repo  storage.FolderRepository
+	lists ListService
+	tx    storage.Transactor
+}
+
+func NewFolderService(repo storage.FolderRepository, lists ListService, tx storage.Transactor) *FolderService {
+	return &FolderService{repo: repo, lists: lists, tx: tx}
+}
+
+// CreateFolder создаёт папку внутри parentID; nil — на верхнем уровне.
+func (s *FolderService) CreateFolder(ctx context.Context, name string, parentID *string) (*domain.Folder, error) {
+	if err := validateFolderName(name); err != nil {
+		return nil, err
+	}
+	f := &domain.Folder{ID: uuid.NewString(), ParentID: parentID, Name: name}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.LockTree(ctx); err != nil {
+			return err
+		}
+		if parentID != nil {
+			ancestors, err := s.repo.Ancestors(ctx, *parentID)
+			if err != nil {
+				return err
+			}
+			if len(ancestors)+1 > domain.MaxFolderDepth {
+				return ErrFolderTooDeep
+			}
+		}
+		return s.repo.Create(ctx, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *FolderService) ListFolders(ctx context.Context) ([]*domain.Folder, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *FolderService) GetFolder(ctx context.Context, id string) (*domain.Folder, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *FolderService) RenameFolder(ctx context.Context, id, name string) (*domain.Folder, error) {
+	if err := validateFolderName(name); err != nil {
+		return nil, err
+	}
+	return s.repo.Rename(ctx, id, name)
+}
+
+// DeleteFolder удаляет папку; её вложенные папки и списки переходят в
+// родительскую папку (или на верхний уровень). Списки переносятся через
+// ListService, поэтому каждый перенос попадает в журнал и хуки.
+func (s *FolderService) DeleteFolder(ctx context.Context, id string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.LockTree(ctx); err != nil {
+			return err
+		}
+		listIDs, err := s.repo.ListIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		f, err := s.repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, listID := range listIDs {
+			if _, _, err := s.lists.MoveList(ctx, listID, f.ParentID); err != nil {
+				return err
+			}
+		}
+		return s.repo.Delete(ctx, id)
+	})
+}
+
+// MoveFolder переносит папку вместе с содержимым в parentID; nil — на
+// верхний уровень.
+func (s *FolderService) MoveFolder(ctx context.Context, id string, parentID *string) (*domain.Folder, error) {
+	var f *domain.Folder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.LockTree(ctx); err != nil {
+			return err
+		}
+		if _, err := s.repo.Get(ctx, id); err != nil {
+			return err
+		}
+		depth := 0
+		if parentID != nil {
+			ancestors, err := s.repo.Ancestors(ctx, *parentID)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(ancestors, id) {
+				return ErrFolderCycle
+			}
+			depth = len(ancestors)
+		}
+		height, err := s.repo.Height(ctx, id)
+		if err != nil {
+			return err
+		}
+		if depth+height > domain.MaxFolderDepth {
+			return ErrFolderTooDeep
+		}
+		f, err = s.repo.Move(ctx, id, parentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// MoveList переносит список в папку folderID; nil — вне папок. Возвращает
+// список и токен отмены.
+func (s *FolderService) MoveList(ctx context.Context, listID string, folderID *string) (*domain.List, string, error) {
+	return s.lists.MoveList(ctx, listID, folderID)
+}
+
+// FolderLists возвращает списки, лежащие непосредственно в папке id.
+func (s *FolderService) FolderLists(ctx context.Context, id string, limit, offset int) ([]*domain.ListNode, int, error) {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.Lists(ctx, id, limit, offset)
+}
+
+// Tree собирает всю иерархию папок и списков с числом невыполненных задач.
+func (s *FolderService) Tree(ctx context.Context) (*domain.FolderTree, error) {
+	folders, lists, err := s.repo.Tree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &domain.FolderTree{Folders: []*domain.FolderNode{}, Lists: []*domain.ListNode{}}
+	byID := make(map[string]*domain.FolderNode, len(folders))
+	for _, f := range folders {
+		f.Folders = []*domain.FolderNode{}
+		f.Lists = []*domain.ListNode{}
+		byID[f.ID] = f
+	}
+	// Дочерние узлы сохраняют порядок, в котором их вернул репозиторий.
+	for _, f := range folders {
+		if parent, ok := byID[folderKey(f.ParentID)]; ok {
+			parent.Folders = append(parent.Folders, f)
+		} else {
+			tree.Folders = append(tree.Folders, f)
+		}
+	}
+	for _, l := range lists {
+		tree.OpenTasks += l.OpenTasks
+		if folder, ok := byID[folderKey(l.FolderID)]; ok {
+			folder.Lists = append(folder.Lists, l)
+		} else {
+			tree.Lists = append(tree.Lists, l)
+		}
+	}
+	return tree, nil
+}
+
+// folderKey превращает ссылку на папку в ключ byID; "" — верхний уровень.
+func folderKey(id *string) string {
+	if id == nil {
+		return ""
+	}
+	return *id
+}
+
+func validateFolderName(name string) error {
+	if len(name) < 1 || len(name) > 100 {
+		return ErrInvalidFolderName
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+// mockFolderRepo хранит папки и списки в памяти и считает глубину и
+// поддеревья так же, как рекурсивные запросы postgres.FolderRepo.
+type mockFolderRepo struct {
+	folders map[string]*domain.Folder
+	order   []string
+	lists   []*domain.ListNode
+}
+
+func newMockFolderRepo() *mockFolderRepo {
+	return &mockFolderRepo{folders: map[string]*domain.Folder{}}
+}
+
+func (m *mockFolderRepo) Create(ctx context.Context, f *domain.Folder) error {
+	if f.ParentID != nil && m.folders[*f.ParentID] == nil {
+		return errors.New("not found")
+	}
+	copied := *f
+	m.folders[f.ID] = &copied
+	m.order = append(m.order, f.ID)
+	return nil
+}
+
+func (m *mockFolderRepo) Get(ctx context.Context, id string) (*domain.Folder, error) {
+	f, ok := m.folders[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *f
+	return &copied, nil
+}
+
+func (m *mockFolderRepo) List(ctx context.Context) ([]*domain.Folder, error) {
+	var res []*domain.Folder
+	for _, id := range m.order {
+		if f, ok := m.folders[id]; ok {
+			res = append(res, f)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockFolderRepo) Rename(ctx context.Context, id, name string) (*domain.Folder, error) {
+	f, ok := m.folders[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	f.Name = name
+	copied := *f
+	return &copied, nil
+}
+
+func (m *mockFolderRepo) Move(ctx context.Context, id string, parentID *string) (*domain.Folder, error) {
+	f, ok := m.folders[id]
+	if !ok || (parentID != nil && m.folders[*parentID] == nil) {
+		return nil, errors.New("not found")
+	}
+	f.ParentID = parentID
+	copied := *f
+	return &copied, nil
+}
+
+func (m *mockFolderRepo) Delete(ctx context.Context, id string) error {
+	f, ok := m.folders[id]
+	if !ok {
+		return errors.New("not found")
+	}
+	for _, child := range m.folders {
+		if child.ParentID != nil && *child.ParentID == id {
+			child.ParentID = f.ParentID
+		}
+	}
+	for _, l := range m.lists {
+		if l.FolderID != nil && *l.FolderID == id {
+			l.FolderID = nil
+		}
+	}
+	delete(m.folders, id)
+	return nil
+}
+
+func (m *mockFolderRepo) ListIDs(ctx context.Context, folderID string) ([]string, error) {
+	if m.folders[folderID] == nil {
+		return nil, errors.New("not found")
+	}
+	var ids []string
+	for _, l := range m.lists {
+		if l.FolderID != nil && *l.FolderID == folderID {
+			ids = append(ids, l.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *mockFolderRepo) LockTree(ctx context.Context) error { return nil }
+
+func (m *mockFolderRepo) Ancestors(ctx context.Context, id string) ([]string, error) {
+	if m.folders[id] == nil {
+		return nil, errors.New("not found")
+	}
+	var res []string
+	for cur := &id; cur != nil; cur = m.folders[*cur].ParentID {
+		res = append(res, *cur)
+	}
+	return res, nil
+}
+
+func (m *mockFolderRepo) Height(ctx context.Context, id string) (int, error) {
+	height := 1
+	for _, f := range m.folders {
+		if f.ParentID != nil && *f.ParentID == id {
+			h, _ := m.Height(ctx, f.ID)
+			height = max(height, h+1)
+		}
+	}
+	return height, nil
+}
+
+func (m *mockFolderRepo) Lists(ctx context.Context, folderID string, limit, offset int) ([]*domain.ListNode, int, error) {
+	var res []*domain.ListNode
+	for _, l := range m.lists {
+		if l.FolderID != nil && *l.FolderID == folderID {
+			res = append(res, l)
+		}
+	}
+	total := len(res)
+	res = res[min(offset, total):min(offset+limit, total)]
+	return res, total, nil
+}
+
+func (m *mockFolderRepo) Tree(ctx context.Context) ([]*domain.FolderNode, []*domain.ListNode, error) {
+	var nodes []*domain.FolderNode
+	for _, id := range m.order {
+		f, ok := m.folders[id]
+		if !ok {
+			continue
+		}
+		ancestors, _ := m.Ancestors(ctx, id)
+		node := &domain.FolderNode{Folder: *f, Depth: len(ancestors)}
+		for _, l := range m.lists {
+			if l.FolderID == nil {
+				continue
+			}
+			if up, _ := m.Ancestors(ctx, *l.FolderID); slices.Contains(up, id) {
+				node.OpenTasks += l.OpenTasks
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	slices.SortStableFunc(nodes, func(a, b *domain.FolderNode) int { return a.Depth - b.Depth })
+	return nodes, m.lists, nil
+}
+
+// nodeListService переносит списки прямо в mockFolderRepo.lists.
+type nodeListService struct {
+	service.ListService
+	repo *mockFolderRepo
+}
+
+func (s nodeListService) MoveList(ctx context.Context, id string, folderID *string) (*domain.List, string, error) {
+	for _, l := range s.repo.lists {
+		if l.ID == id {
+			l.FolderID = folderID
+			copied := l.List
+			return &copied, "", nil
+		}
+	}
+	return nil, "", errors.New("not found")
+}
+
+// createChain создаёт цепочку из n вложенных папок и возвращает их id от
+// верхней к нижней.
+func createChain(t *testing.T, svc *service.FolderService, parentID *string, n int) []string {
+	t.Helper()
+	var ids []string
+	for i := 0; i < n; i++ {
+		f, err := svc.CreateFolder(context.Background(), "Папка", parentID)
+		if err != nil {
+			t.Fatalf("CreateFolder at level %d: %v", i+1, err)
+		}
+		ids = append(ids, f.ID)
+		parentID = &f.ID
+	}
+	return ids
+}
+
+func TestFolderService_CreateFolder_DepthLimit(t *testing.T) {
+	svc := service.NewFolderService(newMockFolderRepo(), nil, mockTransactor{})
+	ctx := context.Background()
+
+	chain := createChain(t, svc, nil, domain.MaxFolderDepth)
+	deepest := chain[len(chain)-1]
+	if _, err := svc.CreateFolder(ctx, "Слишком глубоко", &deepest); !errors.Is(err, service.ErrFolderTooDeep) {
+		t.Errorf("expected ErrFolderTooDeep, got %v", err)
+	}
+	if _, err := svc.CreateFolder(ctx, "", nil); !errors.Is(err, service.ErrInvalidFolderName) {
+		t.Errorf("expected ErrInvalidFolderName, got %v", err)
+	}
+	missing := "missing"
+	if _, err := svc.CreateFolder(ctx, "Сирота", &missing); err == nil {
+		t.Error("expected error for missing parent")
+	}
+}
+
+func TestFolderService_MoveFolder(t *testing.T) {
+	repo := newMockFolderRepo()
+	svc := service.NewFolderService(repo, nil, mockTransactor{})
+	ctx := context.Background()
+
+	work := createChain(t, svc, nil, 3)
+	home := createChain(t, svc, nil, 3)
+
+	if _, err := svc.MoveFolder(ctx, work[0], &work[2]); !errors.Is(err, service.ErrFolderCycle) {
+		t.Errorf("move into descendant: expected ErrFolderCycle, got %v", err)
+	}
+	if _, err := svc.MoveFolder(ctx, work[0], &work[0]); !errors.Is(err, service.ErrFolderCycle) {
+		t.Errorf("move into itself: expected ErrFolderCycle, got %v", err)
+	}
+	// Поддерево высотой 3 под папкой глубины 3 дало бы глубину 6.
+	if _, err := svc.MoveFolder(ctx, home[0], &work[2]); !errors.Is(err, service.ErrFolderTooDeep) {
+		t.Errorf("expected ErrFolderTooDeep, got %v", err)
+	}
+
+	moved, err := svc.MoveFolder(ctx, home[0], &work[1])
+	if err != nil {
+		t.Fatalf("MoveFolder: %v", err)
+	}
+	if moved.ParentID == nil || *moved.ParentID != work[1] {
+		t.Errorf("parent = %v, want %s", moved.ParentID, work[1])
+	}
+	if up, _ := repo.Ancestors(ctx, home[2]); len(up) != domain.MaxFolderDepth {
+		t.Errorf("depth after move = %d, want %d", len(up), domain.MaxFolderDepth)
+	}
+
+	moved, err = svc.MoveFolder(ctx, home[0], nil)
+	if err != nil || moved.ParentID != nil {
+		t.Errorf("move to top level = %+v, %v", moved, err)
+	}
+}
+
+func TestFolderService_Tree(t *testing.T) {
+	repo := newMockFolderRepo()
+	svc := service.NewFolderService(repo, nodeListService{repo: repo}, mockTransactor{})
+	ctx := context.Background()
+
+	chain := createChain(t, svc, nil, 2)
+	other := createChain(t, svc, nil, 1)
+	repo.lists = []*domain.ListNode{
+		{List: domain.List{ID: "top", Title: "Входящие"}, OpenTasks: 1},
+		{List: domain.List{ID: "outer", Title: "Работа", FolderID: &chain[0]}, OpenTasks: 2},
+		{List: domain.List{ID: "inner", Title: "Спринт", FolderID: &chain[1]}, OpenTasks: 3},
+	}
+
+	tree, err := svc.Tree(ctx)
+	if err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+	if tree.OpenTasks != 6 || len(tree.Lists) != 1 || tree.Lists[0].ID != "top" {
+		t.Errorf("root = open %d, lists %+v", tree.OpenTasks, tree.Lists)
+	}
+	if len(tree.Folders) != 2 || tree.Folders[0].ID != chain[0] || tree.Folders[1].ID != other[0] {
+		t.Fatalf("top-level folders = %+v", tree.Folders)
+	}
+	outer := tree.Folders[0]
+	if outer.OpenTasks != 5 || len(outer.Lists) != 1 || outer.Lists[0].ID != "outer" || len(outer.Folders) != 1 {
+		t.Errorf("outer folder = %+v", outer)
+	}
+	inner := outer.Folders[0]
+	if inner.ID != chain[1] || inner.Depth != 2 || inner.OpenTasks != 3 || len(inner.Lists) != 1 || inner.Lists[0].ID != "inner" {
+		t.Errorf("inner folder = %+v", inner)
+	}
+	if empty := tree.Folders[1]; empty.Folders == nil || empty.Lists == nil {
+		t.Error("empty folder should have non-nil folders and lists")
+	}
+
+	// Удаление папки поднимает её содержимое на уровень выше.
+	if err := svc.DeleteFolder(ctx, chain[0]); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+	tree, _ = svc.Tree(ctx)
+	if len(tree.Folders) != 2 || len(tree.Lists) != 2 {
+		t.Errorf("after delete: %d folders, %d lists at top level", len(tree.Folders), len(tree.Lists))
+	}
+}
+
+func TestFolderService_FolderLists(t *testing.T) {
+	repo := newMockFolderRepo()
+	svc := service.NewFolderService(repo, nil, mockTransactor{})
+	ctx := context.Background()
+
+	if _, _, err := svc.FolderLists(ctx, "missing", 20, 0); err == nil {
+		t.Error("expected error for missing folder")
+	}
+	f, _ := svc.CreateFolder(ctx, "Работа", nil)
+	for _, id := range []string{"a", "b", "c"} {
+		repo.lists = append(repo.lists, &domain.ListNode{List: domain.List{ID: id, FolderID: &f.ID}})
+	}
+	lists, total, err := svc.FolderLists(ctx, f.ID, 2, 1)
+	if err != nil || total != 3 || len(lists) != 2 || lists[0].ID != "b" {
+		t.Errorf("FolderLists = %+v, %d, %v", lists, total, err)
+	}
+}
+
+func TestFolderService_RenameKeepsParent(t *testing.T) {
+	repo := newMockFolderRepo()
+	svc := service.NewFolderService(repo, nil, mockTransactor{})
+	ctx := context.Background()
+
+	chain := createChain(t, svc, nil, 2)
+	renamed, err := svc.RenameFolder(ctx, chain[1], "Спринт 43")
+	if err != nil {
+		t.Fatalf("RenameFolder: %v", err)
+	}
+	if renamed.Name != "Спринт 43" || renamed.ParentID == nil || *renamed.ParentID != chain[0] {
+		t.Errorf("renamed = %+v", renamed)
+	}
+	if _, err := svc.RenameFolder(ctx, chain[1], ""); !errors.Is(err, service.ErrInvalidFolderName) {
+		t.Errorf("expected ErrInvalidFolderName, got %v", err)
+	}
+}
+
+func TestFolderService_MoveListGoesThroughListService(t *testing.T) {
+	ctx := context.Background()
+	folderID := "work"
+	stored := &domain.List{ID: "l1", Title: "Планы", Version: 1}
+	listRepo := &mockListRepo{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.List, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		updateFunc: func(ctx context.Context, list *domain.List) error {
+			list.Version++
+			copied := *list
+			stored = &copied
+			return nil
+		},
+	}
+	audit := &mockAuditRepo{}
+	undo := &mockUndoRepo{}
+	lists := service.NewListService(listRepo, &mockTaskRepo{}, mockTransactor{}, audit, service.NewUndoLog(undo, time.Minute), nil)
+	svc := service.NewFolderService(newMockFolderRepo(), lists, mockTransactor{})
+
+	list, token, err := svc.MoveList(ctx, "l1", &folderID)
+	if err != nil {
+		t.Fatalf("MoveList: %v", err)
+	}
+	if list.FolderID == nil || *list.FolderID != folderID || list.Version != 2 {
+		t.Errorf("list = %+v", list)
+	}
+	if token == "" || len(undo.entries) != 1 {
+		t.Errorf("token = %q, %d undo entries", token, len(undo.entries))
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != domain.ActionUpdate {
+		t.Errorf("audit = %+v", audit.events)
+	}
+
+	// Повторный перенос в ту же папку ничего не меняет.
+	if _, token, err = svc.MoveList(ctx, "l1", &folderID); err != nil || token != "" || len(audit.events) != 1 {
+		t.Errorf("second move: token %q, %d audit events, err %v", token, len(audit.events), err)
+	}
+}
+
+func TestFolderService_DeleteMovesListsThroughListService(t *testing.T) {
+	ctx := context.Background()
+	folders := newMockFolderRepo()
+	audit := &mockAuditRepo{}
+	stored := map[string]*domain.List{}
+	listRepo := &mockListRepo{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.List, error) {
+			copied := *stored[id]
+			return &copied, nil
+		},
+		updateFunc: func(ctx context.Context, list *domain.List) error {
+			list.Version++
+			copied := *list
+			stored[list.ID] = &copied
+			return nil
+		},
+	}
+	lists := service.NewListService(listRepo, &mockTaskRepo{}, mockTransactor{}, audit, nil, nil)
+	svc := service.NewFolderService(folders, lists, mockTransactor{})
+
+	chain := createChain(t, svc, nil, 2)
+	for _, id := range []string{"l1", "l2"} {
+		stored[id] = &domain.List{ID: id, FolderID: &chain[1], Version: 1}
+		folders.lists = append(folders.lists, &domain.ListNode{List: *stored[id]})
+	}
+
+	if err := svc.DeleteFolder(ctx, chain[1]); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+	for _, id := range []string{"l1", "l2"} {
+		if l := stored[id]; l.FolderID == nil || *l.FolderID != chain[0] || l.Version != 2 {
+			t.Errorf("list %s = %+v", id, l)
+		}
+	}
+	if len(audit.events) != 2 || audit.events[0].Action != domain.ActionUpdate {
+		t.Errorf("audit = %+v", audit.events)
+	}
+	if err := svc.DeleteFolder(ctx, "missing"); err == nil {
+		t.Error("expected error for missing folder")
+	}
+}
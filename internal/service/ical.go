@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todo-api/internal/domain"
+)
+
+// icalProdID — PRODID календарей, которые отдаёт сервис.
+const icalProdID = "-//todo-api//Tasks//RU"
+
+const (
+	icalDateTime = "20060102T150405Z"
+	icalDate     = "20060102"
+)
+
+// icalWriter пишет строки iCalendar (RFC 5545): CRLF и перенос строк
+// длиннее 75 октетов.
+type icalWriter struct {
+	w *bufio.Writer
+}
+
+func (w icalWriter) line(name, value string) {
+	s := name + ":" + value
+	// Строка продолжения начинается с пробела, он входит в 75 октетов.
+	for limit := 75; len(s) > limit; limit = 74 {
+		cut := limit
+		for !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+	}
+	w.w.WriteString(s + "\r\n")
+}
+
+func (w icalWriter) begin(title string) {
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", icalProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	if title != "" {
+		w.line("X-WR-CALNAME", icalEscape(title))
+	}
+}
+
+func (w icalWriter) todo(o *domain.CalendarObject, now time.Time) {
+	t := o.Task
+	w.line("BEGIN", "VTODO")
+	w.line("UID", icalEscape(o.UID))
+	w.line("DTSTAMP", now.UTC().Format(icalDateTime))
+	w.line("CREATED", t.CreatedAt.UTC().Format(icalDateTime))
+	w.line("LAST-MODIFIED", t.UpdatedAt.UTC().Format(icalDateTime))
+	// SEQUENCE растёт с каждым изменением задачи, как и ETag.
+	w.line("SEQUENCE", strconv.FormatInt(max(t.Version-1, 0), 10))
+	w.line("SUMMARY", icalEscape(t.Text))
+	if t.DueAt != nil {
+		w.line("DUE", t.DueAt.UTC().Format(icalDateTime))
+	}
+	if t.Completed {
+		w.line("STATUS", "COMPLETED")
+		w.line("PERCENT-COMPLETE", "100")
+		if t.CompletedAt != nil {
+			w.line("COMPLETED", t.CompletedAt.UTC().Format(icalDateTime))
+		}
+	} else {
+		w.line("STATUS", "NEEDS-ACTION")
+	}
+	w.line("END", "VTODO")
+}
+
+func (w icalWriter) end() error {
+	w.line("END", "VCALENDAR")
+	return w.w.Flush()
+}
+
+// writeCalendar пишет календарь с задачами objects.
+func writeCalendar(out io.Writer, title string, objects []*domain.CalendarObject, now time.Time) error {
+	w := icalWriter{w: bufio.NewWriter(out)}
+	w.begin(title)
+	for _, o := range objects {
+		w.todo(o, now)
+	}
+	return w.end()
+}
+
+// calendarData — объект календаря с одной задачей, как его отдаёт CalDAV.
+func calendarData(o *domain.CalendarObject, now time.Time) string {
+	var b bytes.Buffer
+	_ = writeCalendar(&b, "", []*domain.CalendarObject{o}, now)
+	return b.String()
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icalEscape(s string) string { return icalEscaper.Replace(s) }
+
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func icalUnescape(s string) string { return icalUnescaper.Replace(s) }
+
+// icalTodo — поля VTODO, которые сервис переносит в задачу.
+type icalTodo struct {
+	UID       string
+	Summary   string
+	Completed bool
+	Due       *time.Time
+}
+
+// parseVTODO разбирает объект календаря с одним VTODO. Время с TZID
+// переводится из указанного пояса, «плавающее» время и даты считаются UTC.
+func parseVTODO(data []byte) (*icalTodo, error) {
+	var (
+		todo      icalTodo
+		inTodo    bool
+		found     int
+		status    string
+		completed bool
+		unfolded  []string
+	)
+	for _, l := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(unfolded) > 0 {
+			unfolded[len(unfolded)-1] += l[1:]
+			continue
+		}
+		unfolded = append(unfolded, l)
+	}
+
+	for _, l := range unfolded {
+		nameParams, value, ok := strings.Cut(l, ":")
+		if !ok {
+			continue
+		}
+		params := strings.Split(nameParams, ";")
+		name := strings.ToUpper(params[0])
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VTODO"):
+			inTodo = true
+			found++
+		case name == "END" && strings.EqualFold(value, "VTODO"):
+			inTodo = false
+		case !inTodo:
+		case name == "UID":
+			todo.UID = value
+		case name == "SUMMARY":
+			todo.Summary = icalUnescape(value)
+		case name == "STATUS":
+			status = strings.ToUpper(value)
+		case name == "COMPLETED":
+			completed = true
+		case name == "PERCENT-COMPLETE":
+			completed = completed || value == "100"
+		case name == "DUE":
+			due, err := parseICalTime(value, params[1:])
+			if err != nil {
+				return nil, ErrInvalidCalendarData
+			}
+			todo.Due = &due
+		}
+	}
+	if found != 1 || todo.UID == "" {
+		return nil, ErrInvalidCalendarData
+	}
+	// STATUS главнее: клиенты, снимая отметку, не всегда удаляют COMPLETED.
+	todo.Completed = status == "COMPLETED" || (status == "" && completed)
+	return &todo, nil
+}
+
+func parseICalTime(value string, params []string) (time.Time, error) {
+	loc := time.UTC
+	for _, p := range params {
+		k, v, _ := strings.Cut(p, "=")
+		switch strings.ToUpper(k) {
+		case "VALUE":
+			if strings.EqualFold(v, "DATE") {
+				return time.Parse(icalDate, value)
+			}
+		case "TZID":
+			if l, err := time.LoadLocation(strings.Trim(v, `"`)); err == nil {
+				loc = l
+			}
+		}
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icalDateTime, value)
+	}
+	if len(value) == len(icalDate) {
+		return time.Parse(icalDate, value)
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t.UTC(), err
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importParser накапливает разобранные списки; задачи без списка попадают
+// в список по умолчанию.
+type importParser struct {
+	defaultTitle string
+	lists        []*ImportedList
+	byTitle      map[string]*ImportedList
+	issues       []ImportIssue
+}
+
+func newImportParser(defaultTitle string) *importParser {
+	return &importParser{defaultTitle: defaultTitle, byTitle: map[string]*ImportedList{}}
+}
+
+// list возвращает список с названием title, создавая его при первом
+// упоминании. Одноимённые списки файла сливаются.
+func (p *importParser) list(title string, line int) *ImportedList {
+	if title == "" {
+		title = p.defaultTitle
+	}
+	if l, ok := p.byTitle[title]; ok {
+		return l
+	}
+	l := &ImportedList{Title: title, Line: line, Tasks: []ImportedTask{}}
+	p.byTitle[title] = l
+	p.lists = append(p.lists, l)
+	return l
+}
+
+func (p *importParser) fail(line int, path, format string, args ...any) {
+	p.issues = append(p.issues, ImportIssue{Line: line, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// parseImportDate принимает дату ГГГГ-ММ-ДД или время RFC3339.
+func parseImportDate(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: want YYYY-MM-DD or RFC3339", s)
+	}
+	return &t, nil
+}
+
+// lines читает r построчно; номера строк начинаются с 1.
+func lines(r io.Reader, fn func(n int, line string)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	n := 0
+	for sc.Scan() {
+		n++
+		fn(n, strings.TrimRight(sc.Text(), "\r"))
+	}
+	return sc.Err()
+}
+
+var todoTxtDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// parseTodoTxt разбирает формат todo.txt: "x" и дата выполнения, приоритет
+// (A), дата создания, текст. Первый +проект задаёт список, due: — срок.
+// Остальные теги (@контексты, key:value) остаются в тексте.
+func parseTodoTxt(r io.Reader, p *importParser) error {
+	return lines(r, func(n int, line string) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return
+		}
+		var task ImportedTask
+		task.Line = n
+		if fields[0] == "x" {
+			task.Completed = true
+			fields = fields[1:]
+			if len(fields) > 0 && todoTxtDate.MatchString(fields[0]) {
+				fields = fields[1:]
+			}
+		}
+		if len(fields) > 0 && len(fields[0]) == 3 && fields[0][0] == '(' && fields[0][2] == ')' &&
+			fields[0][1] >= 'A' && fields[0][1] <= 'Z' {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && todoTxtDate.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+
+		var project string
+		text := make([]string, 0, len(fields))
+		for _, f := range fields {
+			switch {
+			case strings.HasPrefix(f, "+") && len(f) > 1:
+				if project == "" {
+					project = f[1:]
+				} else {
+					text = append(text, f)
+				}
+			case strings.HasPrefix(f, "due:"):
+				due, err := parseImportDate(f[len("due:"):])
+				if err != nil {
+					p.fail(n, "", "%v", err)
+					return
+				}
+				task.DueAt = due
+			default:
+				text = append(text, f)
+			}
+		}
+		task.Text = strings.Join(text, " ")
+		l := p.list(project, n)
+		l.Tasks = append(l.Tasks, task)
+	})
+}
+
+var (
+	mdHeading  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdCheckbox = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s*(.*)$`)
+	mdDue      = regexp.MustCompile(`\s*\(due (\d{4}-\d{2}-\d{2})\)$`)
+)
+
+// parseMarkdown разбирает чек-листы GitHub: заголовок начинает список,
+// "- [ ]" и "- [x]" — задачи. Текст между заголовком и первой задачей
+// становится описанием списка, суффикс "(due ГГГГ-ММ-ДД)" — сроком.
+func parseMarkdown(r io.Reader, p *importParser) error {
+	var (
+		current     *ImportedList
+		description []string
+	)
+	flush := func() {
+		if current != nil && current.Description == "" && len(description) > 0 {
+			current.Description = strings.Join(description, "\n")
+		}
+		description = nil
+	}
+	return lines(r, func(n int, line string) {
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			current = p.list(m[1], n)
+			return
+		}
+		m := mdCheckbox.FindStringSubmatch(line)
+		if m == nil {
+			if current != nil && len(current.Tasks) == 0 && strings.TrimSpace(line) != "" {
+				description = append(description, strings.TrimSpace(line))
+			}
+			return
+		}
+		if current == nil {
+			current = p.list("", n)
+		}
+		flush()
+		task := ImportedTask{Line: n, Text: strings.TrimSpace(m[2]), Completed: m[1] != " "}
+		if due := mdDue.FindStringSubmatch(task.Text); due != nil {
+			task.DueAt, _ = parseImportDate(due[1])
+			task.Text = strings.TrimSpace(task.Text[:len(task.Text)-len(due[0])])
+		}
+		current.Tasks = append(current.Tasks, task)
+	})
+}
+
+// CSVColumns сопоставляет поля импорта с колонками CSV по заголовку.
+type CSVColumns struct {
+	List        string
+	Description string
+	Text        string
+	Completed   string
+	DueAt       string
+}
+
+// DefaultCSVColumns — колонки выгрузки в CSV.
+var DefaultCSVColumns = CSVColumns{
+	List:        "list_title",
+	Description: "list_description",
+	Text:        "text",
+	Completed:   "completed",
+	DueAt:       "due_at",
+}
+
+var ErrInvalidCSVColumns = errors.New("columns must be a comma-separated list of field:header pairs, fields: list, description, text, completed, due_at")
+
+// ParseCSVColumns разбирает сопоставление вида "text:Название,due_at:Срок".
+// Не указанные поля берутся из DefaultCSVColumns.
+func ParseCSVColumns(s string) (CSVColumns, error) {
+	cols := DefaultCSVColumns
+	if strings.TrimSpace(s) == "" {
+		return cols, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		field, header, ok := strings.Cut(pair, ":")
+		header = strings.TrimSpace(header)
+		if !ok || header == "" {
+			return CSVColumns{}, ErrInvalidCSVColumns
+		}
+		switch strings.TrimSpace(field) {
+		case "list":
+			cols.List = header
+		case "description":
+			cols.Description = header
+		case "text":
+			cols.Text = header
+		case "completed":
+			cols.Completed = header
+		case "due_at":
+			cols.DueAt = header
+		default:
+			return CSVColumns{}, ErrInvalidCSVColumns
+		}
+	}
+	return cols, nil
+}
+
+// parseCSV разбирает CSV с заголовком. Строка без текста задачи только
+// объявляет список — так выгружаются пустые списки.
+func parseCSV(r io.Reader, cols CSVColumns, p *importParser) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read csv header: %w", err)
+	}
+	index := map[string]int{}
+	for i, h := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+	if _, ok := index[cols.Text]; !ok {
+		p.fail(1, "", "column %q for task text not found", cols.Text)
+		return nil
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				p.fail(parseErr.Line, "", "%v", parseErr.Err)
+				continue
+			}
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		get := func(col string) string {
+			if i, ok := index[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		l := p.list(get(cols.List), line)
+		if d := get(cols.Description); d != "" && l.Description == "" {
+			l.Description = d
+		}
+		text := get(cols.Text)
+		if text == "" {
+			continue
+		}
+		task := ImportedTask{Line: line, Text: text}
+		if task.Completed, err = parseCSVBool(get(cols.Completed)); err != nil {
+			p.fail(line, "", "column %q: %v", cols.Completed, err)
+			continue
+		}
+		if task.DueAt, err = parseImportDate(get(cols.DueAt)); err != nil {
+			p.fail(line, "", "column %q: %v", cols.DueAt, err)
+			continue
+		}
+		l.Tasks = append(l.Tasks, task)
+	}
+}
+
+func parseCSVBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "", "0", "no", "n", "false", "-":
+		return false, nil
+	case "1", "yes", "y", "true", "x", "done":
+		return true, nil
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// todoistExport — проекты и задачи из выгрузки Todoist (формат Sync API).
+type todoistExport struct {
+	Projects []struct {
+		ID   json.RawMessage `json:"id"`
+		Name string          `json:"name"`
+	} `json:"projects"`
+	Items []struct {
+		ProjectID json.RawMessage `json:"project_id"`
+		Content   string          `json:"content"`
+		Checked   json.RawMessage `json:"checked"`
+		Due       *struct {
+			Date string `json:"date"`
+		} `json:"due"`
+	} `json:"items"`
+}
+
+// parseTodoist превращает проекты Todoist в списки, задачи — в задачи.
+func parseTodoist(r io.Reader, p *importParser) error {
+	var doc todoistExport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		p.fail(0, "", "invalid json: %v", err)
+		return nil
+	}
+	projects := make(map[string]string, len(doc.Projects))
+	for i, pr := range doc.Projects {
+		projects[rawID(pr.ID)] = pr.Name
+		p.list(pr.Name, 0)
+		if pr.Name == "" {
+			p.fail(0, fmt.Sprintf("/projects/%d/name", i), "project name is empty")
+		}
+	}
+	for i, it := range doc.Items {
+		path := fmt.Sprintf("/items/%d", i)
+		task := ImportedTask{Path: path, Text: strings.TrimSpace(it.Content)}
+		task.Completed = rawID(it.Checked) == "true" || rawID(it.Checked) == "1"
+		if it.Due != nil {
+			due, err := parseImportDate(it.Due.Date)
+			if err != nil {
+				p.fail(0, path+"/due/date", "%v", err)
+				continue
+			}
+			task.DueAt = due
+		}
+		l := p.list(projects[rawID(it.ProjectID)], 0)
+		l.Tasks = append(l.Tasks, task)
+	}
+	return nil
+}
+
+// trelloExport — доска Trello, выгруженная в JSON.
+type trelloExport struct {
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		Name        string  `json:"name"`
+		IDList      string  `json:"idList"`
+		Closed      bool    `json:"closed"`
+		Due         *string `json:"due"`
+		DueComplete bool    `json:"dueComplete"`
+	} `json:"cards"`
+}
+
+// parseTrello превращает колонки доски в списки, карточки — в задачи.
+// Архивные колонки и карточки пропускаются, выполненной считается карточка
+// с отмеченным сроком.
+func parseTrello(r io.Reader, p *importParser) error {
+	var doc trelloExport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		p.fail(0, "", "invalid json: %v", err)
+		return nil
+	}
+	names := make(map[string]string, len(doc.Lists))
+	for i, l := range doc.Lists {
+		if l.Closed {
+			continue
+		}
+		names[l.ID] = l.Name
+		p.list(l.Name, 0)
+		if l.Name == "" {
+			p.fail(0, fmt.Sprintf("/lists/%d/name", i), "list name is empty")
+		}
+	}
+	for i, c := range doc.Cards {
+		name, ok := names[c.IDList]
+		if c.Closed || !ok {
+			continue
+		}
+		path := fmt.Sprintf("/cards/%d", i)
+		task := ImportedTask{Path: path, Text: strings.TrimSpace(c.Name), Completed: c.DueComplete}
+		if c.Due != nil {
+			due, err := parseImportDate(*c.Due)
+			if err != nil {
+				p.fail(0, path+"/due", "%v", err)
+				continue
+			}
+			task.DueAt = due
+		}
+		l := p.list(name, 0)
+		l.Tasks = append(l.Tasks, task)
+	}
+	return nil
+}
+
+// rawID приводит идентификатор Todoist к строке: в разных версиях API это
+// число или строка.
+func rawID(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return strings.TrimSpace(string(raw))
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+var (
+	ErrInvalidImportFormat = errors.New("format must be todotxt, md, csv, todoist or trello")
+	// ErrImportRejected — в файле есть ошибки, ничего не создано; сами
+	// ошибки перечислены в ImportResult.Errors.
+	ErrImportRejected = errors.New("import file has errors, nothing was imported")
+)
+
+// ImportFormat — формат импортируемого файла.
+type ImportFormat string
+
+const (
+	ImportTodoTxt  ImportFormat = "todotxt"
+	ImportMarkdown ImportFormat = "md"
+	ImportCSV      ImportFormat = "csv"
+	ImportTodoist  ImportFormat = "todoist"
+	ImportTrello   ImportFormat = "trello"
+)
+
+func ParseImportFormat(s string) (ImportFormat, error) {
+	switch f := ImportFormat(s); f {
+	case ImportTodoTxt, ImportMarkdown, ImportCSV, ImportTodoist, ImportTrello:
+		return f, nil
+	default:
+		return "", ErrInvalidImportFormat
+	}
+}
+
+// DefaultImportList — список для задач, для которых файл не задаёт списка.
+const DefaultImportList = "Imported"
+
+// MaxImportTasks — наибольшее число задач в одном файле.
+const MaxImportTasks = 5000
+
+type ImportOptions struct {
+	Format ImportFormat
+	// DryRun только разбирает и проверяет файл.
+	DryRun bool
+	// DefaultList — название списка для задач без списка; по умолчанию
+	// DefaultImportList.
+	DefaultList string
+	// Columns — колонки CSV; нулевое значение — DefaultCSVColumns.
+	Columns CSVColumns
+}
+
+// ImportedTask — задача из файла. Line — строка в текстовых форматах,
+// Path — JSON Pointer элемента в выгрузках Todoist и Trello.
+type ImportedTask struct {
+	ID        string     `json:"id,omitempty"`
+	Line      int        `json:"line,omitempty"`
+	Path      string     `json:"path,omitempty"`
+	Text      string     `json:"text"`
+	Completed bool       `json:"completed"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+}
+
+type ImportedList struct {
+	ID          string         `json:"id,omitempty"`
+	Line        int            `json:"line,omitempty"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Tasks       []ImportedTask `json:"tasks"`
+}
+
+// ImportIssue — ошибка в строке или элементе файла.
+type ImportIssue struct {
+	Line    int    `json:"line,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportResult — созданные (или, в dry-run, разобранные) списки и задачи.
+type ImportResult struct {
+	DryRun bool            `json:"dry_run"`
+	Lists  []*ImportedList `json:"lists"`
+	Tasks  int             `json:"tasks"`
+	Errors []ImportIssue   `json:"errors"`
+}
+
+// ImportService создаёт списки и задачи из файлов других приложений через
+// сервисы списков и задач, поэтому действуют те же проверки. Как при
+// копировании списка, в журнал и вебхуки попадает создание списков, а
+// задачи каждого списка вставляются одной операцией.
+type ImportService struct {
+	lists ListService
+	tasks *TaskService
+	tx    storage.Transactor
+}
+
+func NewImportService(lists ListService, tasks *TaskService, tx storage.Transactor) *ImportService {
+	return &ImportService{lists: lists, tasks: tasks, tx: tx}
+}
+
+// Import разбирает файл r и создаёт списки с задачами в одной транзакции.
+// Если в файле есть ошибки, не создаётся ничего: возвращается результат
+// с ошибками и ErrImportRejected. В dry-run результат возвращается без
+// ошибки, даже если файл с ошибками.
+func (s *ImportService) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	if opts.DefaultList == "" {
+		opts.DefaultList = DefaultImportList
+	}
+	if opts.Columns == (CSVColumns{}) {
+		opts.Columns = DefaultCSVColumns
+	}
+
+	p := newImportParser(opts.DefaultList)
+	var err error
+	switch opts.Format {
+	case ImportTodoTxt:
+		err = parseTodoTxt(r, p)
+	case ImportMarkdown:
+		err = parseMarkdown(r, p)
+	case ImportCSV:
+		err = parseCSV(r, opts.Columns, p)
+	case ImportTodoist:
+		err = parseTodoist(r, p)
+	case ImportTrello:
+		err = parseTrello(r, p)
+	default:
+		return nil, ErrInvalidImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	validateImport(p)
+
+	res := &ImportResult{DryRun: opts.DryRun, Lists: p.lists, Errors: p.issues}
+	if res.Lists == nil {
+		res.Lists = []*ImportedList{}
+	}
+	if res.Errors == nil {
+		res.Errors = []ImportIssue{}
+	}
+	for _, l := range res.Lists {
+		res.Tasks += len(l.Tasks)
+	}
+	if opts.DryRun {
+		return res, nil
+	}
+	if len(res.Errors) > 0 {
+		return res, ErrImportRejected
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, l := range res.Lists {
+			list, _, err := s.lists.CreateList(ctx, l.Title, l.Description)
+			if err != nil {
+				return err
+			}
+			l.ID = list.ID
+			tasks := make([]*domain.Task, len(l.Tasks))
+			for i, t := range l.Tasks {
+				tasks[i] = &domain.Task{ListID: list.ID, Text: t.Text, Completed: t.Completed, DueAt: t.DueAt}
+			}
+			if err := s.tasks.createTasks(ctx, tasks); err != nil {
+				return err
+			}
+			for i, t := range tasks {
+				l.Tasks[i].ID = t.ID
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// validateImport проверяет разобранные списки и задачи по правилам сервисов
+// списков и задач и дописывает нарушения к ошибкам разбора.
+func validateImport(p *importParser) {
+	if len(p.lists) == 0 && len(p.issues) == 0 {
+		p.fail(0, "", "file contains no lists or tasks")
+	}
+	total := 0
+	for _, l := range p.lists {
+		total += len(l.Tasks)
+	}
+	if total > MaxImportTasks {
+		p.fail(0, "", "file contains %d tasks, at most %d are allowed", total, MaxImportTasks)
+	}
+	for _, l := range p.lists {
+		if err := validateListTitle(l.Title); err != nil {
+			p.fail(l.Line, "", "list %q: %v", l.Title, err)
+		}
+		for _, t := range l.Tasks {
+			if err := validateTaskText(t.Text); err != nil {
+				p.fail(t.Line, t.Path, "%v", err)
+			}
+		}
+	}
+	sort.SliceStable(p.issues, func(i, j int) bool { return p.issues[i].Line < p.issues[j].Line })
+}
@@ -0,0 +1,203 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+// importFixture собирает сервис импорта поверх моков и запоминает созданное.
+type importFixture struct {
+	svc   *service.ImportService
+	lists []*domain.List
+	tasks map[string]*domain.Task
+	// batches — число вызовов CreateMany.
+	batches int
+}
+
+func newImportFixture() *importFixture {
+	f := &importFixture{tasks: map[string]*domain.Task{}}
+	listRepo := &recordingListRepo{created: &f.lists}
+	taskRepo := &mockTaskRepo{
+		createManyFunc: func(ctx context.Context, tasks []*domain.Task) error {
+			f.batches++
+			for _, t := range tasks {
+				f.tasks[t.ID] = t
+			}
+			return nil
+		},
+	}
+	lists := service.NewListService(listRepo, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil, nil)
+	tasks := service.NewTaskService(taskRepo, listRepo, mockTransactor{}, &mockAuditRepo{}, nil, nil)
+	f.svc = service.NewImportService(lists, tasks, mockTransactor{})
+	return f
+}
+
+type recordingListRepo struct {
+	mockListRepo
+	created *[]*domain.List
+}
+
+func (m *recordingListRepo) Create(ctx context.Context, list *domain.List) (*domain.List, error) {
+	*m.created = append(*m.created, list)
+	return list, nil
+}
+
+func (f *importFixture) run(t *testing.T, format service.ImportFormat, file string, dryRun bool) *service.ImportResult {
+	t.Helper()
+	res, err := f.svc.Import(context.Background(), strings.NewReader(file), service.ImportOptions{Format: format, DryRun: dryRun})
+	if err != nil {
+		t.Fatalf("import: %v (errors: %+v)", err, res)
+	}
+	return res
+}
+
+func TestImportTodoTxt(t *testing.T) {
+	f := newImportFixture()
+	res := f.run(t, service.ImportTodoTxt, "x 2026-10-19 2026-10-18 Купить молоко +Покупки\n"+
+		"\n"+
+		"(A) 2026-10-18 Позвонить маме @телефон due:2026-10-20\n"+
+		"Купить хлеб +Покупки\n", false)
+
+	if len(res.Lists) != 2 || res.Tasks != 3 || len(f.lists) != 2 || len(f.tasks) != 3 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	shop, inbox := res.Lists[0], res.Lists[1]
+	if shop.Title != "Покупки" || len(shop.Tasks) != 2 || shop.ID == "" {
+		t.Fatalf("project list: %+v", shop)
+	}
+	if inbox.Title != service.DefaultImportList {
+		t.Errorf("default list title = %q", inbox.Title)
+	}
+	milk := f.tasks[shop.Tasks[0].ID]
+	if milk.Text != "Купить молоко" || !milk.Completed {
+		t.Errorf("completed task: %+v", milk)
+	}
+	call := inbox.Tasks[0]
+	if call.Text != "Позвонить маме @телефон" || call.Line != 3 || call.DueAt == nil || call.DueAt.Format("2006-01-02") != "2026-10-20" {
+		t.Errorf("priority task: %+v", call)
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	f := newImportFixture()
+	res := f.run(t, service.ImportMarkdown, "# Дом\n\nДела на выходные\n\n- [x] Полить цветы\n* [ ] Починить кран (due 2026-10-20)\n\n## Работа\n- [X] Отчёт\n", true)
+
+	if !res.DryRun || len(f.lists) != 0 || len(f.tasks) != 0 {
+		t.Fatal("dry run must not create anything")
+	}
+	if len(res.Lists) != 2 {
+		t.Fatalf("expected 2 lists, got %+v", res.Lists)
+	}
+	home := res.Lists[0]
+	if home.Title != "Дом" || home.Description != "Дела на выходные" || len(home.Tasks) != 2 {
+		t.Fatalf("home list: %+v", home)
+	}
+	if !home.Tasks[0].Completed || home.Tasks[1].Completed || home.Tasks[1].Text != "Починить кран" || home.Tasks[1].DueAt == nil {
+		t.Errorf("home tasks: %+v", home.Tasks)
+	}
+	if res.Lists[1].Title != "Работа" || !res.Lists[1].Tasks[0].Completed {
+		t.Errorf("work list: %+v", res.Lists[1])
+	}
+}
+
+func TestImportCSVWithColumnMapping(t *testing.T) {
+	cols, err := service.ParseCSVColumns("text:Task,completed:Done,due_at:Due,list:Project")
+	if err != nil {
+		t.Fatalf("ParseCSVColumns: %v", err)
+	}
+	file := "Task,Project,Done,Due\n" +
+		"Купить молоко,Покупки,yes,2026-10-20\n" +
+		"Отчёт,,no,\n" +
+		"Сломанная,Покупки,maybe,\n"
+	res, err := newImportFixture().svc.Import(context.Background(), strings.NewReader(file),
+		service.ImportOptions{Format: service.ImportCSV, DryRun: true, Columns: cols})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Line != 4 {
+		t.Fatalf("expected an error on line 4, got %+v", res.Errors)
+	}
+	if res.Lists[0].Title != "Покупки" || !res.Lists[0].Tasks[0].Completed || res.Lists[1].Title != service.DefaultImportList {
+		t.Errorf("unexpected lists: %+v %+v", res.Lists[0], res.Lists[1])
+	}
+
+	if _, err := service.ParseCSVColumns("title:Task"); !errors.Is(err, service.ErrInvalidCSVColumns) {
+		t.Errorf("unknown field: %v", err)
+	}
+}
+
+func TestImportTodoistAndTrello(t *testing.T) {
+	todoist := `{"projects":[{"id":"2203306141","name":"Inbox"}],
+		"items":[{"project_id":"2203306141","content":"Buy milk","checked":true,"due":{"date":"2026-10-20"}},
+		         {"project_id":2203306141,"content":"Call Bob","checked":false,"due":null}]}`
+	res := newImportFixture().run(t, service.ImportTodoist, todoist, true)
+	if len(res.Lists) != 1 || len(res.Lists[0].Tasks) != 2 || !res.Lists[0].Tasks[0].Completed || res.Lists[0].Tasks[1].Path != "/items/1" {
+		t.Errorf("todoist: %+v", res.Lists)
+	}
+
+	trello := `{"name":"Board","lists":[{"id":"a","name":"Doing"},{"id":"b","name":"Old","closed":true}],
+		"cards":[{"name":"Write spec","idList":"a","dueComplete":true,"due":"2026-10-20T09:00:00.000Z"},
+		         {"name":"Archived","idList":"a","closed":true},
+		         {"name":"In closed list","idList":"b"}]}`
+	res = newImportFixture().run(t, service.ImportTrello, trello, true)
+	if len(res.Lists) != 1 || res.Lists[0].Title != "Doing" || res.Tasks != 1 || !res.Lists[0].Tasks[0].Completed {
+		t.Errorf("trello: %+v", res.Lists)
+	}
+}
+
+func TestImportRejectsInvalidFile(t *testing.T) {
+	f := newImportFixture()
+	file := "Купить молоко due:завтра\n" + strings.Repeat("a", 501) + "\nОк\n"
+	res, err := f.svc.Import(context.Background(), strings.NewReader(file), service.ImportOptions{Format: service.ImportTodoTxt})
+	if !errors.Is(err, service.ErrImportRejected) {
+		t.Fatalf("expected ErrImportRejected, got %v", err)
+	}
+	if len(res.Errors) != 2 || res.Errors[0].Line != 1 || res.Errors[1].Line != 2 {
+		t.Errorf("per-line errors: %+v", res.Errors)
+	}
+	if len(f.lists) != 0 || len(f.tasks) != 0 {
+		t.Error("nothing must be created when the file has errors")
+	}
+
+	if _, err := service.ParseImportFormat("xlsx"); !errors.Is(err, service.ErrInvalidImportFormat) {
+		t.Errorf("unknown format: %v", err)
+	}
+}
+
+func TestImportInsertsTasksOfEachListInOneBatch(t *testing.T) {
+	f := newImportFixture()
+	res := f.run(t, service.ImportTodoTxt, strings.Repeat("Купить хлеб +Покупки\nПозвонить маме\n", 100), false)
+
+	if len(res.Lists) != 2 || res.Tasks != 200 || len(f.tasks) != 200 {
+		t.Fatalf("unexpected result: lists %d, tasks %d, created %d", len(res.Lists), res.Tasks, len(f.tasks))
+	}
+	if f.batches != 2 {
+		t.Errorf("CreateMany called %d times, want once per list", f.batches)
+	}
+}
+
+func TestImportRejectsTooManyTasks(t *testing.T) {
+	f := newImportFixture()
+	file := strings.Repeat("Купить хлеб\n", service.MaxImportTasks+1)
+	res, err := f.svc.Import(context.Background(), strings.NewReader(file), service.ImportOptions{Format: service.ImportTodoTxt})
+	if !errors.Is(err, service.ErrImportRejected) {
+		t.Fatalf("expected ErrImportRejected, got %v", err)
+	}
+	if len(res.Errors) != 1 || !strings.Contains(res.Errors[0].Message, "at most 5000") {
+		t.Errorf("errors: %+v", res.Errors)
+	}
+	if len(f.lists) != 0 || len(f.tasks) != 0 {
+		t.Error("nothing must be created when the file is too large")
+	}
+
+	f = newImportFixture()
+	f.run(t, service.ImportTodoTxt, strings.Repeat("Купить хлеб\n", service.MaxImportTasks), false)
+	if len(f.tasks) != service.MaxImportTasks {
+		t.Errorf("created %d tasks, want %d", len(f.tasks), service.MaxImportTasks)
+	}
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+)
+
+var (
+	ErrUnknownJobKind  = errors.New("no handler registered for job kind")
+	ErrInvalidJobState = errors.New("state must be one of: pending, running, completed, failed")
+	ErrJobNotFailed    = errors.New("only failed jobs can be retried")
+)
+
+// JobArgs — аргументы фоновой задачи. Kind связывает их с обработчиком,
+// зарегистрированным через HandleJob; сами аргументы хранятся в JSON.
+type JobArgs interface {
+	Kind() string
+}
+
+// JobOptions уточняют постановку задачи в очередь.
+type JobOptions struct {
+	// RunAt — не раньше какого времени выполнять; нулевое — сразу.
+	RunAt time.Time
+	// MaxAttempts — сколько попыток до перехода в failed; 0 — по умолчанию
+	// очереди.
+	MaxAttempts int
+	// UniqueKey, если не пустой, не даёт поставить задачу, пока задача с тем
+	// же ключом ждёт или выполняется.
+	UniqueKey string
+}
+
+type jobHandler struct {
+	timeout time.Duration
+	handle  func(ctx context.Context, args json.RawMessage) error
+}
+
+type jobSchedule struct {
+	name string
+	cron *cronSchedule
+	args JobArgs
+	opts JobOptions
+}
+
+// JobQueue — очередь фоновых задач в Postgres. Задачи захватываются с SKIP
+// LOCKED и скрываются от других обработчиков на время выполнения
+// (visibility timeout), поэтому очередь можно обрабатывать с нескольких
+// экземпляров сервиса. Неудачная попытка повторяется с экспоненциальной
+// задержкой, после MaxAttempts задача переходит в failed.
+type JobQueue struct {
+	repo      storage.JobRepository
+	tx        storage.Transactor
+	handlers  map[string]*jobHandler
+	schedules []*jobSchedule
+	now       func() time.Time
+
+	// Workers — сколько задач выполняется одновременно.
+	Workers     int
+	MaxAttempts int
+	// Timeout — сколько по умолчанию длится попытка; задача скрыта от
+	// других обработчиков на Timeout и ещё минуту сверху.
+	Timeout     time.Duration
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func NewJobQueue(repo storage.JobRepository, tx storage.Transactor, workers, maxAttempts int) *JobQueue {
+	return &JobQueue{
+		repo:        repo,
+		tx:          tx,
+		handlers:    map[string]*jobHandler{},
+		now:         time.Now,
+		Workers:     workers,
+		MaxAttempts: maxAttempts,
+		Timeout:     5 * time.Minute,
+		BaseBackoff: 10 * time.Second,
+		MaxBackoff:  time.Hour,
+	}
+}
+
+// HandleJob регистрирует обработчик задач вида T. timeout ограничивает
+// одну попытку; 0 — Timeout очереди. Регистрировать обработчики нужно до
+// Run.
+func HandleJob[T JobArgs](q *JobQueue, timeout time.Duration, handle func(ctx context.Context, args T) error) {
+	var zero T
+	q.handlers[zero.Kind()] = &jobHandler{
+		timeout: timeout,
+		handle: func(ctx context.Context, raw json.RawMessage) error {
+			var args T
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return fmt.Errorf("unmarshal %s args: %w", zero.Kind(), err)
+			}
+			return handle(ctx, args)
+		},
+	}
+}
+
+// Schedule ставит задачу args по расписанию cron (в UTC). Время следующего
+// запуска хранится в базе, поэтому при нескольких экземплярах сервиса
+// задача ставится один раз; пропущенные, пока сервис не работал, запуски
+// сливаются в один.
+func (q *JobQueue) Schedule(name, cron string, args JobArgs, opts JobOptions) error {
+	s, err := parseCron(cron)
+	if err != nil {
+		return err
+	}
+	if _, ok := q.handlers[args.Kind()]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJobKind, args.Kind())
+	}
+	q.schedules = append(q.schedules, &jobSchedule{name: name, cron: s, args: args, opts: opts})
+	return nil
+}
+
+// Enqueue ставит задачу в очередь. В транзакции изменения задача появится
+// только вместе с ним. Если задача с тем же UniqueKey уже ждёт или
+// выполняется, возвращается nil без ошибки.
+func (q *JobQueue) Enqueue(ctx context.Context, args JobArgs, opts JobOptions) (*domain.Job, error) {
+	if _, ok := q.handlers[args.Kind()]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobKind, args.Kind())
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s args: %w", args.Kind(), err)
+	}
+	j := &domain.Job{
+		Kind:        args.Kind(),
+		Args:        raw,
+		MaxAttempts: opts.MaxAttempts,
+		RunAt:       opts.RunAt,
+		UniqueKey:   opts.UniqueKey,
+	}
+	if j.MaxAttempts <= 0 {
+		j.MaxAttempts = q.MaxAttempts
+	}
+	if j.RunAt.IsZero() {
+		j.RunAt = q.now()
+	}
+	j.RunAt = j.RunAt.UTC()
+
+	inserted, err := q.repo.Enqueue(ctx, j)
+	if err != nil || !inserted {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Run обрабатывает очередь до отмены ctx: Workers обработчиков по одному
+// захватывают и выполняют задачи, а раз в interval ставятся задачи по
+// расписаниям. Обработчик, не нашедший задачи, ждёт interval. После отмены
+// новые задачи не захватываются, а Run возвращается, когда завершатся
+// начатые.
+func (q *JobQueue) Run(ctx context.Context, interval time.Duration) {
+	var wg sync.WaitGroup
+	for range q.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx, interval)
+		}()
+	}
+	defer wg.Wait()
+
+	for {
+		if err := q.schedule(ctx); err != nil && ctx.Err() == nil {
+			logger.Info("job queue: " + err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// work — цикл одного обработчика: следующая задача захватывается сразу
+// после завершения предыдущей.
+func (q *JobQueue) work(ctx context.Context, interval time.Duration) {
+	for ctx.Err() == nil {
+		ok, err := q.Work(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Info("job queue: " + err.Error())
+		}
+		if ok {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Work захватывает и выполняет одну задачу; false — задач, время которых
+// наступило, нет.
+func (q *JobQueue) Work(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil || len(q.handlers) == 0 {
+		return false, nil
+	}
+	kinds := make([]string, 0, len(q.handlers))
+	var visibility time.Duration
+	for kind, h := range q.handlers {
+		kinds = append(kinds, kind)
+		visibility = max(visibility, q.timeout(h))
+	}
+	slices.Sort(kinds)
+
+	jobs, err := q.repo.ClaimDue(ctx, kinds, 1, visibility+time.Minute)
+	if err != nil || len(jobs) == 0 {
+		return false, err
+	}
+
+	// Начатая задача доделывается и после отмены ctx: так очередь
+	// опустошается при остановке сервиса.
+	j := jobs[0]
+	if err := q.run(context.WithoutCancel(ctx), j); err != nil {
+		logger.Info(fmt.Sprintf("job queue: job %d: %s", j.ID, err))
+	}
+	return true, nil
+}
+
+func (q *JobQueue) run(ctx context.Context, j *domain.Job) error {
+	var err error
+	if j.Attempts > j.MaxAttempts {
+		// Обработчик предыдущей попытки не уложился в visibility timeout
+		// и больше не отвечает.
+		err = errors.New("attempt timed out")
+	} else {
+		err = q.call(ctx, j)
+	}
+
+	now := q.now().UTC()
+	j.LastError = ""
+	switch {
+	case err == nil:
+		j.State = domain.JobCompleted
+		j.FinishedAt = &now
+	case j.Attempts >= j.MaxAttempts:
+		j.State = domain.JobFailed
+		j.LastError = err.Error()
+		j.FinishedAt = &now
+	default:
+		j.State = domain.JobPending
+		j.LastError = err.Error()
+		j.RunAt = now.Add(q.backoff(j.Attempts))
+	}
+	if cerr := q.repo.Complete(ctx, j); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// call выполняет попытку с тайм-аутом обработчика; паника считается ошибкой.
+func (q *JobQueue) call(ctx context.Context, j *domain.Job) (err error) {
+	h, ok := q.handlers[j.Kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJobKind, j.Kind)
+	}
+	ctx, cancel := context.WithTimeout(ctx, q.timeout(h))
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h.handle(ctx, j.Args)
+}
+
+func (q *JobQueue) timeout(h *jobHandler) time.Duration {
+	if h.timeout > 0 {
+		return h.timeout
+	}
+	return q.Timeout
+}
+
+// schedule ставит задачи, время запуска которых по расписанию наступило.
+func (q *JobQueue) schedule(ctx context.Context) error {
+	var errs []error
+	for _, s := range q.schedules {
+		err := q.tx.WithinTx(ctx, func(ctx context.Context) error {
+			now := q.now().UTC()
+			due, err := q.repo.ClaimSchedule(ctx, s.name, now, s.cron.next(now))
+			if err != nil || !due {
+				return err
+			}
+			_, err = q.Enqueue(ctx, s.args, s.opts)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schedule %s: %w", s.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backoff — задержка перед попыткой attempts+1: BaseBackoff, 2×, 4×…, не больше MaxBackoff.
+func (q *JobQueue) backoff(attempts int) time.Duration {
+	delay := q.BaseBackoff
+	for i := 1; i < attempts && delay < q.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, q.MaxBackoff)
+}
+
+// PurgeJobsArgs — очистка очереди от выполненных задач.
+type PurgeJobsArgs struct{}
+
+func (PurgeJobsArgs) Kind() string { return "jobs.purge" }
+
+// HandlePurge регистрирует обработчик PurgeJobsArgs: он удаляет выполненные
+// задачи, закончившиеся больше retention назад. Задачи в failed остаются,
+// пока их не перезапустят.
+func (q *JobQueue) HandlePurge(retention time.Duration) {
+	HandleJob(q, 0, func(ctx context.Context, _ PurgeJobsArgs) error {
+		n, err := q.repo.Purge(ctx, q.now().Add(-retention))
+		if err == nil && n > 0 {
+			logger.Info(fmt.Sprintf("job queue: purged %d completed jobs", n))
+		}
+		return err
+	})
+}
+
+// ListJobs возвращает задачи для администратора; пустые state и kind не
+// фильтруют.
+func (q *JobQueue) ListJobs(ctx context.Context, state, kind string, limit, offset int) ([]*domain.Job, int, error) {
+	if state != "" && !slices.Contains(domain.JobStates, state) {
+		return nil, 0, ErrInvalidJobState
+	}
+	return q.repo.List(ctx, state, kind, limit, offset)
+}
+
+func (q *JobQueue) GetJob(ctx context.Context, id int64) (*domain.Job, error) {
+	return q.repo.Get(ctx, id)
+}
+
+// RetryJob возвращает задачу в состоянии failed в очередь со сброшенным
+// счётчиком попыток.
+func (q *JobQueue) RetryJob(ctx context.Context, id int64) (*domain.Job, error) {
+	j, err := q.repo.Retry(ctx, id)
+	if errors.Is(err, storage.ErrConflict) {
+		return nil, ErrJobNotFailed
+	}
+	return j, err
+}
@@ -0,0 +1,330 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+)
+
+// mockJobRepo хранит задачи в памяти. ClaimDue, как и Postgres, переводит
+// ждущие задачи в running и увеличивает attempts. Очередь обращается к нему
+// из параллельных обработчиков, поэтому изменения под mu.
+type mockJobRepo struct {
+	mu        sync.Mutex
+	jobs      map[int64]*domain.Job
+	nextID    int64
+	schedules map[string]time.Time
+}
+
+func newMockJobRepo() *mockJobRepo {
+	return &mockJobRepo{jobs: map[int64]*domain.Job{}, schedules: map[string]time.Time{}}
+}
+
+func (m *mockJobRepo) Enqueue(ctx context.Context, j *domain.Job) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j.UniqueKey != "" {
+		for _, other := range m.jobs {
+			if other.UniqueKey == j.UniqueKey && (other.State == domain.JobPending || other.State == domain.JobRunning) {
+				return false, nil
+			}
+		}
+	}
+	m.nextID++
+	j.ID, j.State = m.nextID, domain.JobPending
+	copied := *j
+	m.jobs[j.ID] = &copied
+	return true, nil
+}
+
+func (m *mockJobRepo) ClaimDue(ctx context.Context, kinds []string, limit int, visibility time.Duration) ([]*domain.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []*domain.Job
+	for id := int64(1); id <= m.nextID && len(res) < limit; id++ {
+		j, ok := m.jobs[id]
+		if !ok || j.State != domain.JobPending || j.RunAt.After(time.Now()) {
+			continue
+		}
+		j.State = domain.JobRunning
+		j.Attempts++
+		copied := *j
+		res = append(res, &copied)
+	}
+	return res, nil
+}
+
+func (m *mockJobRepo) Complete(ctx context.Context, j *domain.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.jobs[j.ID]
+	if !ok || stored.State != domain.JobRunning || stored.Attempts != j.Attempts {
+		return storage.ErrConflict
+	}
+	copied := *j
+	m.jobs[j.ID] = &copied
+	return nil
+}
+
+func (m *mockJobRepo) Get(ctx context.Context, id int64) (*domain.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return j, nil
+}
+
+func (m *mockJobRepo) List(ctx context.Context, state, kind string, limit, offset int) ([]*domain.Job, int, error) {
+	var res []*domain.Job
+	for _, j := range m.jobs {
+		if (state == "" || j.State == state) && (kind == "" || j.Kind == kind) {
+			res = append(res, j)
+		}
+	}
+	return res, len(res), nil
+}
+
+func (m *mockJobRepo) Retry(ctx context.Context, id int64) (*domain.Job, error) {
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	if j.State != domain.JobFailed {
+		return nil, storage.ErrConflict
+	}
+	j.State, j.Attempts, j.RunAt, j.FinishedAt = domain.JobPending, 0, time.Now(), nil
+	return j, nil
+}
+
+func (m *mockJobRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockJobRepo) ClaimSchedule(ctx context.Context, name string, now, next time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	at, ok := m.schedules[name]
+	if !ok {
+		at = next
+	}
+	if at.After(now) {
+		m.schedules[name] = at
+		return false, nil
+	}
+	m.schedules[name] = next
+	return true, nil
+}
+
+type sendDigestArgs struct {
+	UserID string `json:"user_id"`
+}
+
+func (sendDigestArgs) Kind() string { return "test.digest" }
+
+func TestJobQueue_DispatchesTypedArgs(t *testing.T) {
+	repo := newMockJobRepo()
+	q := service.NewJobQueue(repo, mockTransactor{}, 4, 3)
+	var mu sync.Mutex
+	var got []string
+	service.HandleJob(q, 0, func(ctx context.Context, args sendDigestArgs) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, args.UserID)
+		return nil
+	})
+
+	for _, user := range []string{"olga", "ivan"} {
+		if _, err := q.Enqueue(context.Background(), sendDigestArgs{UserID: user}, service.JobOptions{}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	for i := range 3 {
+		ok, err := q.Work(context.Background())
+		if err != nil || ok != (i < 2) {
+			t.Fatalf("Work #%d = %v, %v", i+1, ok, err)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("handled %v, want olga and ivan", got)
+	}
+	for _, j := range repo.jobs {
+		if j.State != domain.JobCompleted || j.FinishedAt == nil {
+			t.Errorf("job %d = %+v", j.ID, j)
+		}
+	}
+
+	if _, err := q.Enqueue(context.Background(), service.PurgeJobsArgs{}, service.JobOptions{}); !errors.Is(err, service.ErrUnknownJobKind) {
+		t.Errorf("err = %v, want ErrUnknownJobKind", err)
+	}
+}
+
+func TestJobQueue_RetriesWithBackoffThenFails(t *testing.T) {
+	repo := newMockJobRepo()
+	q := service.NewJobQueue(repo, mockTransactor{}, 1, 2)
+	q.BaseBackoff = time.Minute
+	calls := 0
+	service.HandleJob(q, 0, func(ctx context.Context, args sendDigestArgs) error {
+		calls++
+		if calls == 1 {
+			return errors.New("smtp unavailable")
+		}
+		panic("template missing")
+	})
+
+	j, err := q.Enqueue(context.Background(), sendDigestArgs{UserID: "olga"}, service.JobOptions{})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	before := time.Now()
+	if _, err := q.Work(context.Background()); err != nil {
+		t.Fatalf("Work: %v", err)
+	}
+	stored := repo.jobs[j.ID]
+	if stored.State != domain.JobPending || stored.LastError != "smtp unavailable" {
+		t.Fatalf("after first attempt job = %+v", stored)
+	}
+	if d := stored.RunAt.Sub(before); d < time.Minute-time.Second || d > time.Minute+time.Second {
+		t.Errorf("retry in %v, want about 1m", d)
+	}
+
+	// Вторая попытка — последняя; паника обработчика считается ошибкой.
+	stored.RunAt = time.Now()
+	if _, err := q.Work(context.Background()); err != nil {
+		t.Fatalf("Work: %v", err)
+	}
+	stored = repo.jobs[j.ID]
+	if stored.State != domain.JobFailed || stored.LastError != "panic: template missing" || stored.FinishedAt == nil {
+		t.Fatalf("after last attempt job = %+v", stored)
+	}
+
+	if _, err := q.RetryJob(context.Background(), j.ID); err != nil {
+		t.Fatalf("RetryJob: %v", err)
+	}
+	if _, err := q.RetryJob(context.Background(), j.ID); !errors.Is(err, service.ErrJobNotFailed) {
+		t.Errorf("retry of pending job: err = %v, want ErrJobNotFailed", err)
+	}
+}
+
+func TestJobQueue_UniqueKey(t *testing.T) {
+	repo := newMockJobRepo()
+	q := service.NewJobQueue(repo, mockTransactor{}, 1, 3)
+	service.HandleJob(q, 0, func(ctx context.Context, args sendDigestArgs) error { return nil })
+	opts := service.JobOptions{UniqueKey: "digest:olga"}
+
+	first, err := q.Enqueue(context.Background(), sendDigestArgs{UserID: "olga"}, opts)
+	if err != nil || first == nil {
+		t.Fatalf("Enqueue = %v, %v", first, err)
+	}
+	dup, err := q.Enqueue(context.Background(), sendDigestArgs{UserID: "olga"}, opts)
+	if err != nil || dup != nil {
+		t.Fatalf("duplicate Enqueue = %v, %v; want nil, nil", dup, err)
+	}
+
+	// Выполненная задача ключ освобождает.
+	if _, err := q.Work(context.Background()); err != nil {
+		t.Fatalf("Work: %v", err)
+	}
+	again, err := q.Enqueue(context.Background(), sendDigestArgs{UserID: "olga"}, opts)
+	if err != nil || again == nil {
+		t.Fatalf("Enqueue after completion = %v, %v", again, err)
+	}
+}
+
+func TestJobQueue_WorkersDoNotWaitForEachOther(t *testing.T) {
+	repo := newMockJobRepo()
+	q := service.NewJobQueue(repo, mockTransactor{}, 2, 3)
+	started, release, done := make(chan struct{}), make(chan struct{}), make(chan string, 2)
+	service.HandleJob(q, 0, func(ctx context.Context, args sendDigestArgs) error {
+		if args.UserID == "slow" {
+			close(started)
+			<-release
+		}
+		done <- args.UserID
+		return nil
+	})
+	if _, err := q.Enqueue(context.Background(), sendDigestArgs{UserID: "slow"}, service.JobOptions{}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		q.Run(ctx, 10*time.Millisecond)
+	}()
+	<-started
+
+	// Пока первая задача выполняется, свободный обработчик берёт следующую.
+	if _, err := q.Enqueue(context.Background(), sendDigestArgs{UserID: "fast"}, service.JobOptions{}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	select {
+	case user := <-done:
+		if user != "fast" {
+			t.Fatalf("finished %q first, want fast", user)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second job waited for the first one")
+	}
+
+	// После отмены Run дожидается начатой задачи.
+	cancel()
+	select {
+	case <-stopped:
+		t.Fatal("Run returned before the running job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-stopped
+	if user := <-done; user != "slow" {
+		t.Fatalf("finished %q, want slow", user)
+	}
+}
+
+func TestJobQueue_Schedule(t *testing.T) {
+	repo := newMockJobRepo()
+	q := service.NewJobQueue(repo, mockTransactor{}, 1, 3)
+	service.HandleJob(q, 0, func(ctx context.Context, args sendDigestArgs) error { return nil })
+
+	for _, spec := range []string{"* * *", "61 * * * *", "0 0 30-31/0 * *", "0 0 * 13 *"} {
+		if err := q.Schedule("bad", spec, sendDigestArgs{}, service.JobOptions{}); err == nil {
+			t.Errorf("Schedule(%q) accepted", spec)
+		}
+	}
+	if err := q.Schedule("purge", "@daily", service.PurgeJobsArgs{}, service.JobOptions{}); !errors.Is(err, service.ErrUnknownJobKind) {
+		t.Errorf("err = %v, want ErrUnknownJobKind", err)
+	}
+	if err := q.Schedule("digest", "30 3 * * 1-5", sendDigestArgs{UserID: "olga"}, service.JobOptions{}); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+
+	// Первый проход только регистрирует расписание: запуск — в ближайшие
+	// 3:30 UTC по будням.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	q.Run(ctx, time.Hour)
+	next := repo.schedules["digest"]
+	if next.Hour() != 3 || next.Minute() != 30 || next.Weekday() == time.Saturday || next.Weekday() == time.Sunday || !next.After(time.Now()) {
+		t.Fatalf("next run = %v", next)
+	}
+	if len(repo.jobs) != 0 {
+		t.Fatalf("enqueued %d jobs before schedule was due", len(repo.jobs))
+	}
+
+	// Время подошло: задача ставится один раз, следующий запуск сдвигается.
+	repo.schedules["digest"] = time.Now().Add(-time.Minute)
+	q.Run(ctx, time.Hour)
+	q.Run(ctx, time.Hour)
+	if len(repo.jobs) != 1 || !repo.schedules["digest"].After(time.Now()) {
+		t.Fatalf("jobs = %d, next run = %v", len(repo.jobs), repo.schedules["digest"])
+	}
+}
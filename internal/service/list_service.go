@@ -3,54 +3,233 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"todo-api/internal/domain"
 	"todo-api/internal/storage"
 )
 
+var ErrInvalidListTitle = errors.New("title must be 1..100 chars")
+
 type ListService interface {
-	CreateList(ctx context.Context, title, description string) (*domain.List, error)
-	UpdateList(ctx context.Context, id string, title, description string) (*domain.List, error)
+	CreateList(ctx context.Context, title, description string) (*domain.List, string, error)
+	UpdateList(ctx context.Context, id string, title, description string) (*domain.List, string, error)
+	ArchiveList(ctx context.Context, id string, archived bool) (*domain.List, string, error)
+	MoveList(ctx context.Context, id string, folderID *string) (*domain.List, string, error)
+	CloneList(ctx context.Context, id, title string, opts domain.CloneOptions) (*domain.List, int, error)
 	GetAllLists(ctx context.Context) ([]*domain.List, int)
 	GetByID(ctx context.Context, id string) (*domain.List, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) (string, error)
 	GetAllListsWithPagination(ctx context.Context, limit, offset int) ([]*domain.List, int)
 	SearchByTitle(ctx context.Context, query string) ([]domain.List, error)
 }
 
 type listService struct {
-	repo storage.ListRepository
+	repo     storage.ListRepository
+	taskRepo storage.TaskRepository
+	tx       storage.Transactor
+	audit    storage.AuditRepository
+	undo     *UndoLog
+	hooks    ChangeHook
 }
 
-func NewListService(repo storage.ListRepository) ListService {
+// NewListService создаёт сервис списков. undo и hooks могут быть nil.
+func NewListService(repo storage.ListRepository, taskRepo storage.TaskRepository, tx storage.Transactor, audit storage.AuditRepository, undo *UndoLog, hooks ChangeHook) ListService {
 	return &listService{
-		repo: repo,
+		repo:     repo,
+		taskRepo: taskRepo,
+		tx:       tx,
+		audit:    audit,
+		undo:     undo,
+		hooks:    hooks,
 	}
 }
 
-func (s *listService) CreateList(ctx context.Context, title, description string) (*domain.List, error) {
-	if len(title) < 1 || len(title) > 100 {
-		return nil, errors.New("title must be 1..100 chars")
+// CreateList создаёт список и возвращает его вместе с токеном отмены.
+func (s *listService) CreateList(ctx context.Context, title, description string) (*domain.List, string, error) {
+	if err := validateListTitle(title); err != nil {
+		return nil, "", err
 	}
 
 	list := domain.NewList(title, description)
-	_, _ = s.repo.Create(ctx, list)
-	return list, nil
+	var token string
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.Create(ctx, list); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionCreate, nil, list); err != nil {
+			return err
+		}
+		var err error
+		token, err = s.undo.record(ctx, domain.UndoCreateList, domain.Snapshot{}, domain.Snapshot{Lists: []*domain.List{list}})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return list, token, nil
 }
 
-func (s *listService) UpdateList(ctx context.Context, id, title, description string) (*domain.List, error) {
-	if len(title) < 1 || len(title) > 100 {
-		return nil, errors.New("title must be 1..100 chars")
+func (s *listService) UpdateList(ctx context.Context, id, title, description string) (*domain.List, string, error) {
+	if err := validateListTitle(title); err != nil {
+		return nil, "", err
 	}
 
-	list, err := s.repo.GetByID(ctx, id)
+	var (
+		list  *domain.List
+		token string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		list, err = s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := *list
+
+		list.Title = title
+		list.Description = description
+		if err := s.repo.Update(ctx, list); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionUpdate, &before, list); err != nil {
+			return err
+		}
+		token, err = s.undo.record(ctx, domain.UndoUpdateList,
+			domain.Snapshot{Lists: []*domain.List{&before}}, domain.Snapshot{Lists: []*domain.List{list}})
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	list.Title = title
-	list.Description = description
-	_ = s.repo.Update(ctx, list)
-	return list, nil
+	return list, token, nil
+}
+
+// ArchiveList отправляет список в архив или возвращает из него. Если список
+// уже в нужном состоянии, он не меняется и токен отмены пустой.
+func (s *listService) ArchiveList(ctx context.Context, id string, archived bool) (*domain.List, string, error) {
+	var (
+		list  *domain.List
+		token string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		list, err = s.repo.GetByID(ctx, id)
+		if err != nil || (list.ArchivedAt != nil) == archived {
+			return err
+		}
+		before := *list
+
+		list.ArchivedAt = nil
+		if archived {
+			now := time.Now().UTC()
+			list.ArchivedAt = &now
+		}
+		if err := s.repo.Update(ctx, list); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionUpdate, &before, list); err != nil {
+			return err
+		}
+		token, err = s.undo.record(ctx, domain.UndoUpdateList,
+			domain.Snapshot{Lists: []*domain.List{&before}}, domain.Snapshot{Lists: []*domain.List{list}})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return list, token, nil
+}
+
+// MoveList переносит список в папку folderID; nil — вне папок. Если список
+// уже там, он не меняется и токен отмены пустой.
+func (s *listService) MoveList(ctx context.Context, id string, folderID *string) (*domain.List, string, error) {
+	var (
+		list  *domain.List
+		token string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		list, err = s.repo.GetByID(ctx, id)
+		if err != nil || folderKey(list.FolderID) == folderKey(folderID) {
+			return err
+		}
+		before := *list
+
+		list.FolderID = folderID
+		if err := s.repo.Update(ctx, list); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionUpdate, &before, list); err != nil {
+			return err
+		}
+		token, err = s.undo.record(ctx, domain.UndoUpdateList,
+			domain.Snapshot{Lists: []*domain.List{&before}}, domain.Snapshot{Lists: []*domain.List{list}})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return list, token, nil
+}
+
+// CloneList создаёт копию списка id и возвращает её вместе с числом
+// скопированных задач. Пустой title — название исходного списка с
+// пометкой «(copy)». Задачи копируются одной операцией хранилища, но в
+// журнал и хуки, как при обычном создании, попадает создание списка и
+// каждой задачи. Клонирование не отменяется через undo, копию удаляют как
+// обычный список.
+func (s *listService) CloneList(ctx context.Context, id, title string, opts domain.CloneOptions) (*domain.List, int, error) {
+	if title != "" {
+		if err := validateListTitle(title); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var (
+		clone *domain.List
+		count int
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		src, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if title == "" {
+			title = src.Title + " (copy)"
+			if validateListTitle(title) != nil {
+				title = src.Title
+			}
+		}
+
+		clone = domain.NewList(title, src.Description)
+		tasks, err := s.repo.Clone(ctx, src.ID, clone, opts)
+		if err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, clone.ID, clone.ID, domain.ActionCreate, nil, clone); err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, t.ID, clone.ID, domain.ActionCreate, nil, t); err != nil {
+				return err
+			}
+		}
+		count = len(tasks)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return clone, count, nil
+}
+
+// validateListTitle проверяет название списка.
+func validateListTitle(title string) error {
+	if len(title) < 1 || len(title) > 100 {
+		return ErrInvalidListTitle
+	}
+	return nil
 }
 
 func (s *listService) GetAllLists(ctx context.Context) ([]*domain.List, int) {
@@ -61,8 +240,33 @@ func (s *listService) GetByID(ctx context.Context, id string) (*domain.List, err
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *listService) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+// Delete удаляет список вместе с задачами. Задачи попадают в состояние
+// для отмены, поэтому отмена восстанавливает и их.
+func (s *listService) Delete(ctx context.Context, id string) (string, error) {
+	var token string
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		list, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		tasks, err := s.taskRepo.ListAllByListID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionDelete, list, nil); err != nil {
+			return err
+		}
+		token, err = s.undo.record(ctx, domain.UndoDeleteList,
+			domain.Snapshot{Lists: []*domain.List{list}, Tasks: tasks}, domain.Snapshot{})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
 }
 
 func (s *listService) GetAllListsWithPagination(ctx context.Context, limit, offset int) ([]*domain.List, int) {
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+func TestListService_CloneList(t *testing.T) {
+	var (
+		gotSrc  string
+		gotOpts domain.CloneOptions
+	)
+	listRepo := &mockListRepo{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.List, error) {
+			if id != "src" {
+				return nil, errors.New("not found")
+			}
+			return &domain.List{ID: "src", Title: "Спринт", Description: "Задачи спринта", Version: 7}, nil
+		},
+		cloneFunc: func(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error) {
+			gotSrc, gotOpts = srcID, opts
+			clone.Version = 1
+			return []*domain.Task{
+				{ID: "t1", ListID: clone.ID, Text: "Первая"},
+				{ID: "t2", ListID: clone.ID, Text: "Вторая"},
+			}, nil
+		},
+	}
+	audit := &mockAuditRepo{}
+	svc := service.NewListService(listRepo, &mockTaskRepo{}, mockTransactor{}, audit, nil, nil)
+
+	opts := domain.CloneOptions{IncludeTasks: true, ResetCompletion: true, KeepOrder: true}
+	clone, count, err := svc.CloneList(context.Background(), "src", "", opts)
+	if err != nil {
+		t.Fatalf("CloneList: %v", err)
+	}
+	if count != 2 || gotSrc != "src" || gotOpts != opts {
+		t.Errorf("count, src, opts = %d, %q, %+v", count, gotSrc, gotOpts)
+	}
+	if clone.ID == "" || clone.ID == "src" || clone.Title != "Спринт (copy)" || clone.Description != "Задачи спринта" || clone.Version != 1 {
+		t.Errorf("unexpected clone %+v", clone)
+	}
+	if len(audit.events) != 3 || audit.events[0].EntityID != clone.ID || audit.events[0].Action != domain.ActionCreate {
+		t.Fatalf("audit = %+v, want list create and two task creates", audit.events)
+	}
+	for i, id := range []string{"t1", "t2"} {
+		e := audit.events[i+1]
+		if e.EntityType != domain.EntityTask || e.EntityID != id || e.ListID != clone.ID || e.Action != domain.ActionCreate {
+			t.Errorf("audit[%d] = %+v, want create of task %s", i+1, e, id)
+		}
+	}
+
+	clone, _, err = svc.CloneList(context.Background(), "src", "Спринт 43", opts)
+	if err != nil || clone.Title != "Спринт 43" {
+		t.Errorf("clone with title = %+v, %v", clone, err)
+	}
+
+	if _, _, err := svc.CloneList(context.Background(), "src", strings.Repeat("a", 101), opts); !errors.Is(err, service.ErrInvalidListTitle) {
+		t.Errorf("expected ErrInvalidListTitle, got %v", err)
+	}
+	if _, _, err := svc.CloneList(context.Background(), "missing", "", opts); err == nil {
+		t.Error("expected error for missing list")
+	}
+}
+
+func TestListService_CloneList_LongTitleKeepsSourceTitle(t *testing.T) {
+	title := strings.Repeat("a", 100)
+	listRepo := &mockListRepo{getByIDFunc: func(ctx context.Context, id string) (*domain.List, error) {
+		return &domain.List{ID: id, Title: title}, nil
+	}}
+	svc := service.NewListService(listRepo, &mockTaskRepo{}, mockTransactor{}, &mockAuditRepo{}, nil, nil)
+
+	clone, _, err := svc.CloneList(context.Background(), "src", "", domain.CloneOptions{})
+	if err != nil {
+		t.Fatalf("CloneList: %v", err)
+	}
+	if clone.Title != title {
+		t.Errorf("title = %q, want the source title", clone.Title)
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Mail — письмо одному получателю.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма.
+type Mailer interface {
+	Send(ctx context.Context, m Mail) error
+}
+
+// SMTPMailer отправляет письма через SMTP-релей. Если сервер поддерживает
+// STARTTLS, соединение шифруется; аутентификация PLAIN выполняется, только
+// когда задано имя пользователя.
+type SMTPMailer struct {
+	addr    string
+	host    string
+	from    *mail.Address
+	auth    smtp.Auth
+	Timeout time.Duration
+}
+
+func NewSMTPMailer(host, port, username, password, from string) (*SMTPMailer, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	m := &SMTPMailer{addr: net.JoinHostPort(host, port), host: host, from: addr, Timeout: 30 * time.Second}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Mail) error {
+	ctx, cancel := context.WithTimeout(ctx, m.Timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.message(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message собирает письмо: тема в кодировке RFC 2047, текст в UTF-8
+// quoted-printable.
+func (m *SMTPMailer) message(msg Mail) []byte {
+	id := make([]byte, 12)
+	_, _ = rand.Read(id)
+	subject := strings.Join(strings.Fields(msg.Subject), " ")
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), m.host)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	_, _ = qp.Write([]byte(msg.Body))
+	_ = qp.Close()
+	return b.Bytes()
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+)
+
+// NotificationDispatcher отправляет уведомления из очереди. Письмо
+// собирается при отправке по текущим настройкам получателя; если канал
+// к этому времени отключён или адрес удалён, уведомление пропускается.
+// Неудачная отправка повторяется с экспоненциальной задержкой, после
+// MaxAttempts попыток уведомление переходит в состояние dead.
+type NotificationDispatcher struct {
+	repo   storage.NotificationRepository
+	svc    *NotificationService
+	mailer Mailer
+
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+	// Lease — на сколько уведомление скрывается от других отправителей.
+	Lease time.Duration
+}
+
+func NewNotificationDispatcher(repo storage.NotificationRepository, svc *NotificationService, mailer Mailer, maxAttempts int) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		repo:        repo,
+		svc:         svc,
+		mailer:      mailer,
+		MaxAttempts: maxAttempts,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+		BatchSize:   20,
+		Lease:       2 * time.Minute,
+	}
+}
+
+// Run отправляет уведомления до отмены ctx, опрашивая очередь раз в interval.
+// Начатые отправки завершаются и после отмены.
+func (d *NotificationDispatcher) Run(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := d.DispatchDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Info("notification dispatcher: " + err.Error())
+		}
+		if n == d.BatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// DispatchDue захватывает и отправляет одну пачку уведомлений и возвращает
+// их количество.
+func (d *NotificationDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+	ns, err := d.repo.ClaimDue(ctx, d.BatchSize, d.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for _, n := range ns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.deliver(ctx, n); err != nil {
+				logger.Info("notification dispatcher: " + err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+	return len(ns), nil
+}
+
+func (d *NotificationDispatcher) deliver(ctx context.Context, n *domain.Notification) error {
+	sent, err := d.send(ctx, n)
+
+	now := time.Now().UTC()
+	n.Attempts++
+	n.LastError = ""
+	switch {
+	case err == nil && !sent:
+		n.State = domain.NotificationSkipped
+	case err == nil:
+		n.State = domain.NotificationSent
+		n.SentAt = &now
+	case n.Attempts >= d.MaxAttempts:
+		n.State = domain.NotificationDead
+		n.LastError = err.Error()
+	default:
+		n.State = domain.NotificationPending
+		n.LastError = err.Error()
+		n.NextAttemptAt = now.Add(d.backoff(n.Attempts))
+	}
+	return d.repo.Complete(ctx, n)
+}
+
+// send отправляет уведомление; false без ошибки — уведомление пропущено.
+func (d *NotificationDispatcher) send(ctx context.Context, n *domain.Notification) (bool, error) {
+	settings, err := d.svc.GetSettings(ctx, n.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !deliverable(settings, n.Event, n.Channel) {
+		return false, nil
+	}
+
+	var p NotificationPayload
+	if err := json.Unmarshal(n.Payload, &p); err != nil {
+		return false, fmt.Errorf("unmarshal notification %d: %w", n.ID, err)
+	}
+	subject, body, err := renderNotification(settings, n.Event, &p)
+	if err != nil {
+		return false, err
+	}
+
+	switch n.Channel {
+	case domain.ChannelEmail:
+		return true, d.mailer.Send(ctx, Mail{To: settings.Email, Subject: subject, Body: body})
+	}
+	return false, fmt.Errorf("unknown notification channel %q", n.Channel)
+}
+
+// backoff — задержка перед попыткой attempts+1: BaseBackoff, 2×, 4×…, не больше MaxBackoff.
+func (d *NotificationDispatcher) backoff(attempts int) time.Duration {
+	delay := d.BaseBackoff
+	for i := 1; i < attempts && delay < d.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.MaxBackoff)
+}
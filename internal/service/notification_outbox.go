@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+)
+
+// NotificationOutbox — хук изменений, который ставит в очередь уведомления
+// о назначении задачи и об упоминании @пользователя в её тексте. Уведомления
+// записываются в транзакции изменения, письма отправляются отдельно.
+// Автор изменения о своих действиях не уведомляется.
+type NotificationOutbox struct {
+	svc *NotificationService
+}
+
+func NewNotificationOutbox(svc *NotificationService) *NotificationOutbox {
+	return &NotificationOutbox{svc: svc}
+}
+
+func (o *NotificationOutbox) OnChange(ctx context.Context, c Change) error {
+	if c.EntityType != domain.EntityTask || c.Task == nil {
+		return nil
+	}
+	actor := reqctx.UserID(ctx)
+
+	recipients := map[string]string{}
+	var order []string
+	add := func(userID, event string) {
+		if userID == actor {
+			return
+		}
+		if _, ok := recipients[userID]; !ok {
+			order = append(order, userID)
+			recipients[userID] = event
+		}
+	}
+
+	var prevAssignee, prevText string
+	if c.PrevTask != nil {
+		prevAssignee, prevText = c.PrevTask.Assignee, c.PrevTask.Text
+	}
+	if c.Task.Assignee != "" && c.Task.Assignee != prevAssignee {
+		add(c.Task.Assignee, domain.NotifyAssigned)
+	}
+	known := map[string]bool{}
+	for _, m := range mentions(prevText) {
+		known[m] = true
+	}
+	for _, m := range mentions(c.Task.Text) {
+		if !known[m] {
+			add(m, domain.NotifyMentioned)
+		}
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	list, err := o.svc.lists.GetByID(ctx, c.ListID)
+	if err != nil {
+		return err
+	}
+	p := &NotificationPayload{Actor: actor, ListID: list.ID, ListTitle: list.Title, Task: c.Task}
+	for _, userID := range order {
+		if err := o.svc.Notify(ctx, userID, recipients[userID], "", p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mentionPattern — @идентификатор, перед которым нет буквы или цифры:
+// адреса почты упоминаниями не считаются.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@([\p{L}\p{N}_.-]+)`)
+
+// mentions возвращает пользователей, упомянутых в тексте, без повторов.
+// Точка и дефис в конце («спросить @olga.») к идентификатору не относятся.
+func mentions(text string) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		id := strings.TrimRight(m[1], ".-")
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		res = append(res, id)
+	}
+	return res
+}
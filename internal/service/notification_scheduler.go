@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+)
+
+// NotificationScheduler ставит в очередь уведомления, которые зависят от
+// времени: о скором сроке задачи и ежедневную сводку. Ключи дедупликации
+// не дают поставить уведомление повторно, поэтому планировщик можно
+// запускать на нескольких экземплярах сервиса.
+type NotificationScheduler struct {
+	svc  *NotificationService
+	repo storage.NotificationRepository
+	now  func() time.Time
+
+	// DueSoon — за сколько до срока исполнитель получает напоминание.
+	DueSoon time.Duration
+	// DigestHour — час по времени получателя, начиная с которого
+	// отправляется сводка за день.
+	DigestHour int
+	// DigestTasks — сколько открытых задач списка попадает в сводку.
+	DigestTasks int
+
+	// digested — день последней сводки каждого пользователя, чтобы не
+	// собирать её заново при каждом запуске.
+	digested map[string]string
+}
+
+func NewNotificationScheduler(svc *NotificationService, repo storage.NotificationRepository, dueSoon time.Duration, digestHour int) *NotificationScheduler {
+	return &NotificationScheduler{
+		svc:         svc,
+		repo:        repo,
+		now:         time.Now,
+		DueSoon:     dueSoon,
+		DigestHour:  digestHour,
+		DigestTasks: 20,
+		digested:    map[string]string{},
+	}
+}
+
+// Run планирует уведомления до отмены ctx раз в interval.
+func (s *NotificationScheduler) Run(ctx context.Context, interval time.Duration) {
+	for {
+		if err := s.Schedule(ctx); err != nil && ctx.Err() == nil {
+			logger.Info("notification scheduler: " + err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Schedule ставит в очередь напоминания о сроках, наступающих в ближайшие
+// DueSoon, и сводки пользователям, у которых наступил DigestHour.
+func (s *NotificationScheduler) Schedule(ctx context.Context) error {
+	return errors.Join(s.scheduleDueSoon(ctx), s.scheduleDigests(ctx))
+}
+
+func (s *NotificationScheduler) scheduleDueSoon(ctx context.Context) error {
+	now := s.now().UTC()
+	tasks, err := s.repo.DueBetween(ctx, now, now.Add(s.DueSoon))
+	if err != nil {
+		return err
+	}
+
+	titles := map[string]string{}
+	for _, t := range tasks {
+		title, ok := titles[t.ListID]
+		if !ok {
+			list, err := s.svc.lists.GetByID(ctx, t.ListID)
+			if err != nil {
+				return err
+			}
+			title, titles[t.ListID] = list.Title, list.Title
+		}
+		// Перенос срока или смена исполнителя дают новое напоминание.
+		key := "due_soon:" + t.ID + ":" + strconv.FormatInt(t.DueAt.Unix(), 10) + ":" + t.Assignee
+		p := &NotificationPayload{ListID: t.ListID, ListTitle: title, Task: t}
+		if err := s.svc.Notify(ctx, t.Assignee, domain.NotifyDueSoon, key, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NotificationScheduler) scheduleDigests(ctx context.Context) error {
+	subscribers, err := s.repo.DigestSubscribers(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, sub := range subscribers {
+		loc, err := time.LoadLocation(sub.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		local := now.In(loc)
+		date := local.Format(time.DateOnly)
+		if local.Hour() < s.DigestHour || s.digested[sub.UserID] == date {
+			continue
+		}
+
+		p := &NotificationPayload{Date: date}
+		for _, listID := range sub.DigestLists {
+			list, err := s.svc.lists.GetByID(ctx, listID)
+			if err != nil {
+				return err
+			}
+			tasks, err := s.repo.OpenTasks(ctx, listID, s.DigestTasks)
+			if err != nil {
+				return err
+			}
+			if len(tasks) > 0 {
+				p.Lists = append(p.Lists, DigestList{ID: list.ID, Title: list.Title, Tasks: tasks})
+			}
+		}
+		if len(p.Lists) > 0 {
+			key := "digest:" + sub.UserID + ":" + date
+			if err := s.svc.Notify(ctx, sub.UserID, domain.NotifyDigest, key, p); err != nil {
+				return err
+			}
+		}
+		s.digested[sub.UserID] = date
+	}
+	return nil
+}
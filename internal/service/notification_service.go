@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+var (
+	ErrInvalidEmail      = errors.New("email must be a valid address")
+	ErrInvalidLocale     = errors.New("locale must be one of: en, ru")
+	ErrInvalidTimeZone   = errors.New("timezone must be an IANA time zone, e.g. Europe/Moscow")
+	ErrInvalidPreference = errors.New("preferences must map known events to known channels")
+)
+
+// NotificationService хранит настройки уведомлений пользователей и ставит
+// уведомления в очередь с учётом этих настроек. Отправкой занимается
+// NotificationDispatcher.
+type NotificationService struct {
+	repo  storage.NotificationRepository
+	lists storage.ListRepository
+}
+
+func NewNotificationService(repo storage.NotificationRepository, lists storage.ListRepository) *NotificationService {
+	return &NotificationService{repo: repo, lists: lists}
+}
+
+// GetSettings возвращает настройки пользователя. Не заданные пользователем
+// значения заполняются по умолчанию: язык en, пояс UTC, все каналы включены.
+func (s *NotificationService) GetSettings(ctx context.Context, userID string) (*domain.NotificationSettings, error) {
+	stored, err := s.repo.Settings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return withNotificationDefaults(userID, stored), nil
+}
+
+// UpdateSettings заменяет адрес, язык, часовой пояс и предпочтения
+// пользователя. Пустой адрес отключает письма; подписки на сводки не меняются.
+func (s *NotificationService) UpdateSettings(ctx context.Context, userID string, in domain.NotificationSettings) (*domain.NotificationSettings, error) {
+	if in.Email != "" {
+		addr, err := mail.ParseAddress(in.Email)
+		if err != nil {
+			return nil, ErrInvalidEmail
+		}
+		in.Email = addr.Address
+	}
+	if in.Locale == "" {
+		in.Locale = DefaultLocale
+	}
+	if !slices.Contains(Locales, in.Locale) {
+		return nil, ErrInvalidLocale
+	}
+	if in.TimeZone == "" {
+		in.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(in.TimeZone); err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	for event, byChannel := range in.Preferences {
+		if !slices.Contains(domain.NotificationEvents, event) {
+			return nil, ErrInvalidPreference
+		}
+		for channel := range byChannel {
+			if !slices.Contains(domain.NotificationChannels, channel) {
+				return nil, ErrInvalidPreference
+			}
+		}
+	}
+
+	in.UserID = userID
+	if err := s.repo.SaveSettings(ctx, &in); err != nil {
+		return nil, err
+	}
+	return s.GetSettings(ctx, userID)
+}
+
+// SubscribeDigest подписывает пользователя на ежедневную сводку открытых
+// задач списка.
+func (s *NotificationService) SubscribeDigest(ctx context.Context, userID, listID string) error {
+	if _, err := s.lists.GetByID(ctx, listID); err != nil {
+		return err
+	}
+	return s.repo.Subscribe(ctx, userID, listID)
+}
+
+func (s *NotificationService) UnsubscribeDigest(ctx context.Context, userID, listID string) error {
+	if _, err := s.lists.GetByID(ctx, listID); err != nil {
+		return err
+	}
+	return s.repo.Unsubscribe(ctx, userID, listID)
+}
+
+// Notify ставит в очередь уведомление userID о событии event по всем
+// каналам, которые пользователь не отключил и которыми его можно известить.
+// dedupKey, если не пустой, не даёт поставить то же уведомление дважды.
+func (s *NotificationService) Notify(ctx context.Context, userID, event, dedupKey string, p *NotificationPayload) error {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal notification: %w", err)
+	}
+
+	var ns []*domain.Notification
+	for _, channel := range domain.NotificationChannels {
+		if !deliverable(settings, event, channel) {
+			continue
+		}
+		ns = append(ns, &domain.Notification{
+			UserID:   userID,
+			Event:    event,
+			Channel:  channel,
+			Payload:  payload,
+			DedupKey: dedupKey,
+		})
+	}
+	if len(ns) == 0 {
+		return nil
+	}
+	return s.repo.Enqueue(ctx, ns)
+}
+
+// deliverable сообщает, что канал включён для события и у пользователя есть
+// для него адрес.
+func deliverable(s *domain.NotificationSettings, event, channel string) bool {
+	if !s.Preferences[event][channel] {
+		return false
+	}
+	switch channel {
+	case domain.ChannelEmail:
+		return s.Email != ""
+	}
+	return true
+}
+
+func withNotificationDefaults(userID string, stored *domain.NotificationSettings) *domain.NotificationSettings {
+	s := &domain.NotificationSettings{UserID: userID, DigestLists: []string{}}
+	if stored != nil {
+		*s = *stored
+		s.UserID = userID
+	}
+	if s.Locale == "" {
+		s.Locale = DefaultLocale
+	}
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+	if s.DigestLists == nil {
+		s.DigestLists = []string{}
+	}
+
+	prefs := make(map[string]map[string]bool, len(domain.NotificationEvents))
+	for _, event := range domain.NotificationEvents {
+		prefs[event] = make(map[string]bool, len(domain.NotificationChannels))
+		for _, channel := range domain.NotificationChannels {
+			enabled, ok := s.Preferences[event][channel]
+			prefs[event][channel] = !ok || enabled
+		}
+	}
+	s.Preferences = prefs
+	return s
+}
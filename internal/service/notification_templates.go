@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"todo-api/internal/domain"
+)
+
+// Языки писем. Для остальных значений используется DefaultLocale.
+const (
+	LocaleEN      = "en"
+	LocaleRU      = "ru"
+	DefaultLocale = LocaleEN
+)
+
+// Locales — поддерживаемые языки писем.
+var Locales = []string{LocaleEN, LocaleRU}
+
+// Шаблоны лежат в templates/notifications/<язык>/<событие>.tmpl и определяют
+// шаблоны subject и body. В них доступны поля NotificationPayload и функция
+// when, которая выводит время в часовом поясе и формате получателя.
+//
+//go:embed templates/notifications
+var notificationFS embed.FS
+
+var notificationTemplates = loadNotificationTemplates()
+
+func loadNotificationTemplates() map[string]map[string]*template.Template {
+	res := map[string]map[string]*template.Template{}
+	for _, locale := range Locales {
+		res[locale] = map[string]*template.Template{}
+		for _, event := range domain.NotificationEvents {
+			file := path.Join("templates/notifications", locale, event+".tmpl")
+			// when подменяется при выполнении, здесь нужна только сигнатура.
+			t := template.Must(template.New(event).
+				Funcs(template.FuncMap{"when": func(any) string { return "" }}).
+				ParseFS(notificationFS, file))
+			res[locale][event] = t
+		}
+	}
+	return res
+}
+
+// NotificationPayload — данные уведомления, которые сохраняются в очереди.
+// Письмо собирается из них при отправке на языке получателя.
+type NotificationPayload struct {
+	Actor     string       `json:"actor,omitempty"`
+	ListID    string       `json:"list_id,omitempty"`
+	ListTitle string       `json:"list_title,omitempty"`
+	Task      *domain.Task `json:"task,omitempty"`
+	// Date — день сводки в часовом поясе получателя, YYYY-MM-DD.
+	Date  string       `json:"date,omitempty"`
+	Lists []DigestList `json:"lists,omitempty"`
+}
+
+// DigestList — список в ежедневной сводке с его открытыми задачами.
+type DigestList struct {
+	ID    string         `json:"id"`
+	Title string         `json:"title"`
+	Tasks []*domain.Task `json:"tasks"`
+}
+
+// renderNotification возвращает тему и текст письма о событии event
+// для получателя с настройками s.
+func renderNotification(s *domain.NotificationSettings, event string, p *NotificationPayload) (string, string, error) {
+	byEvent, ok := notificationTemplates[s.Locale]
+	if !ok {
+		byEvent = notificationTemplates[DefaultLocale]
+	}
+	t, ok := byEvent[event]
+	if !ok {
+		return "", "", fmt.Errorf("no template for notification %q", event)
+	}
+
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	layout := "Jan 2, 2006 15:04 MST"
+	if s.Locale == LocaleRU {
+		layout = "02.01.2006 15:04 MST"
+	}
+	t, err = t.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	t.Funcs(template.FuncMap{"when": func(v any) string {
+		switch v := v.(type) {
+		case time.Time:
+			return v.In(loc).Format(layout)
+		case *time.Time:
+			if v != nil {
+				return v.In(loc).Format(layout)
+			}
+		}
+		return ""
+	}})
+
+	var subject, body bytes.Buffer
+	if err := t.ExecuteTemplate(&subject, "subject", p); err != nil {
+		return "", "", fmt.Errorf("render %s subject: %w", event, err)
+	}
+	if err := t.ExecuteTemplate(&body, "body", p); err != nil {
+		return "", "", fmt.Errorf("render %s body: %w", event, err)
+	}
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
@@ -0,0 +1,477 @@
+package service_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+)
+
+// mockNotificationRepo хранит настройки и очередь уведомлений в памяти.
+type mockNotificationRepo struct {
+	settings  map[string]*domain.NotificationSettings
+	queued    []*domain.Notification
+	completed []*domain.Notification
+	due       []*domain.Task
+	open      map[string][]*domain.Task
+}
+
+func newMockNotificationRepo() *mockNotificationRepo {
+	return &mockNotificationRepo{settings: map[string]*domain.NotificationSettings{}, open: map[string][]*domain.Task{}}
+}
+
+func (m *mockNotificationRepo) Settings(ctx context.Context, userID string) (*domain.NotificationSettings, error) {
+	if s, ok := m.settings[userID]; ok {
+		copied := *s
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *mockNotificationRepo) SaveSettings(ctx context.Context, s *domain.NotificationSettings) error {
+	copied := *s
+	if prev, ok := m.settings[s.UserID]; ok {
+		copied.DigestLists = prev.DigestLists
+	}
+	m.settings[s.UserID] = &copied
+	return nil
+}
+
+func (m *mockNotificationRepo) Subscribe(ctx context.Context, userID, listID string) error {
+	s, ok := m.settings[userID]
+	if !ok {
+		s = &domain.NotificationSettings{UserID: userID}
+		m.settings[userID] = s
+	}
+	s.DigestLists = append(s.DigestLists, listID)
+	return nil
+}
+
+func (m *mockNotificationRepo) Unsubscribe(ctx context.Context, userID, listID string) error {
+	return nil
+}
+
+func (m *mockNotificationRepo) DigestSubscribers(ctx context.Context) ([]*domain.NotificationSettings, error) {
+	var res []*domain.NotificationSettings
+	for _, s := range m.settings {
+		if s.Email != "" && len(s.DigestLists) > 0 {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockNotificationRepo) OpenTasks(ctx context.Context, listID string, limit int) ([]*domain.Task, error) {
+	return m.open[listID], nil
+}
+
+func (m *mockNotificationRepo) DueBetween(ctx context.Context, from, to time.Time) ([]*domain.Task, error) {
+	return m.due, nil
+}
+
+func (m *mockNotificationRepo) Enqueue(ctx context.Context, ns []*domain.Notification) error {
+	for _, n := range ns {
+		if n.DedupKey != "" {
+			for _, q := range m.queued {
+				if q.Channel == n.Channel && q.DedupKey == n.DedupKey {
+					return nil
+				}
+			}
+		}
+		n.ID = int64(len(m.queued) + 1)
+		n.State = domain.NotificationPending
+		m.queued = append(m.queued, n)
+	}
+	return nil
+}
+
+func (m *mockNotificationRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.Notification, error) {
+	var res []*domain.Notification
+	for _, n := range m.queued {
+		if n.State == domain.NotificationPending && len(res) < limit {
+			res = append(res, n)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockNotificationRepo) Complete(ctx context.Context, n *domain.Notification) error {
+	m.completed = append(m.completed, n)
+	return nil
+}
+
+// fakeSMTPServer — SMTP-сервер, который принимает любые письма и отдаёт их
+// в канал messages.
+type fakeSMTPServer struct {
+	addr     string
+	messages chan fakeMail
+}
+
+type fakeMail struct {
+	From string
+	To   []string
+	Data string
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := &fakeSMTPServer{addr: ln.Addr().String(), messages: make(chan fakeMail, 10)}
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		ln.Close()
+		wg.Wait()
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				srv.serve(conn)
+			}()
+		}
+	}()
+	return srv
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	var m fakeMail
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-fake")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			m = fakeMail{From: smtpPath(line)}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			m.To = append(m.To, smtpPath(line))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			m.Data = data.String()
+			s.messages <- m
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// smtpPath возвращает адрес в угловых скобках из команды MAIL или RCPT.
+func smtpPath(line string) string {
+	_, rest, _ := strings.Cut(line, "<")
+	addr, _, _ := strings.Cut(rest, ">")
+	return addr
+}
+
+func newFakeMailer(t *testing.T, srv *fakeSMTPServer) *service.SMTPMailer {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(srv.addr)
+	mailer, err := service.NewSMTPMailer(host, port, "", "", "Todo <todo@example.com>")
+	if err != nil {
+		t.Fatalf("NewSMTPMailer: %v", err)
+	}
+	mailer.Timeout = 5 * time.Second
+	return mailer
+}
+
+// readMail разбирает письмо и возвращает декодированные тему и текст.
+func readMail(t *testing.T, data string) (string, string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return subject, strings.ReplaceAll(string(body), "\r\n", "\n")
+}
+
+func TestNotificationOutbox_AssignmentAndMentions(t *testing.T) {
+	repo := newMockNotificationRepo()
+	for _, id := range []string{"alice", "bob", "carol"} {
+		repo.settings[id] = &domain.NotificationSettings{UserID: id, Email: id + "@example.com"}
+	}
+	outbox := service.NewNotificationOutbox(service.NewNotificationService(repo, &mockListRepo{}))
+	ctx := reqctx.WithUserID(context.Background(), "alice")
+
+	prev := &domain.Task{ID: "t1", ListID: "l1", Text: "Invoice for @bob"}
+	task := &domain.Task{ID: "t1", ListID: "l1", Text: "Invoice for @bob, ask @carol. cc @alice, mail@dave.example", Assignee: "bob"}
+	err := outbox.OnChange(ctx, service.Change{
+		EntityType: domain.EntityTask, EntityID: "t1", ListID: "l1", Action: domain.ActionUpdate,
+		PrevTask: prev, Task: task,
+	})
+	if err != nil {
+		t.Fatalf("OnChange: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, n := range repo.queued {
+		got[n.UserID] = n.Event
+	}
+	want := map[string]string{"bob": domain.NotifyAssigned, "carol": domain.NotifyMentioned}
+	if len(got) != len(want) || got["bob"] != want["bob"] || got["carol"] != want["carol"] {
+		t.Fatalf("queued %v, want %v", got, want)
+	}
+
+	var p service.NotificationPayload
+	if err := json.Unmarshal(repo.queued[0].Payload, &p); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if p.Actor != "alice" || p.ListTitle != "Test List" || p.Task.ID != "t1" {
+		t.Errorf("payload = %+v", p)
+	}
+}
+
+func TestNotificationOutbox_RespectsPreferences(t *testing.T) {
+	repo := newMockNotificationRepo()
+	repo.settings["bob"] = &domain.NotificationSettings{
+		UserID: "bob", Email: "bob@example.com",
+		Preferences: map[string]map[string]bool{domain.NotifyAssigned: {domain.ChannelEmail: false}},
+	}
+	outbox := service.NewNotificationOutbox(service.NewNotificationService(repo, &mockListRepo{}))
+
+	// bob отключил письма о назначении, у carol нет адреса.
+	err := outbox.OnChange(reqctx.WithUserID(context.Background(), "alice"), service.Change{
+		EntityType: domain.EntityTask, ListID: "l1", Action: domain.ActionCreate,
+		Task: &domain.Task{ID: "t1", ListID: "l1", Text: "ping @carol", Assignee: "bob"},
+	})
+	if err != nil {
+		t.Fatalf("OnChange: %v", err)
+	}
+	if len(repo.queued) != 0 {
+		t.Fatalf("queued %d notifications, want none", len(repo.queued))
+	}
+}
+
+func TestNotificationDispatcher_SendsLocalizedMail(t *testing.T) {
+	srv := startFakeSMTP(t)
+	repo := newMockNotificationRepo()
+	svc := service.NewNotificationService(repo, &mockListRepo{})
+	if _, err := svc.UpdateSettings(context.Background(), "bob", domain.NotificationSettings{
+		Email: "Bob <bob@example.com>", Locale: service.LocaleRU, TimeZone: "Europe/Moscow",
+	}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	p := &service.NotificationPayload{
+		Actor: "alice", ListID: "l1", ListTitle: "Финансы",
+		Task: &domain.Task{ID: "t1", ListID: "l1", Text: "Оплатить счёт", DueAt: &due, Assignee: "bob"},
+	}
+	if err := svc.Notify(context.Background(), "bob", domain.NotifyAssigned, "", p); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	d := service.NewNotificationDispatcher(repo, svc, newFakeMailer(t, srv), 3)
+	if n, err := d.DispatchDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("DispatchDue = %d, %v", n, err)
+	}
+
+	var m fakeMail
+	select {
+	case m = <-srv.messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+	if m.From != "todo@example.com" || len(m.To) != 1 || m.To[0] != "bob@example.com" {
+		t.Errorf("envelope from %q to %v", m.From, m.To)
+	}
+	subject, body := readMail(t, m.Data)
+	if subject != "alice назначил(а) вам задачу: Оплатить счёт" {
+		t.Errorf("subject = %q", subject)
+	}
+	// Срок показывается в часовом поясе получателя.
+	if !strings.Contains(body, "«Финансы»") || !strings.Contains(body, "Срок: 20.10.2026 12:00 MSK") {
+		t.Errorf("body = %q", body)
+	}
+
+	if len(repo.completed) != 1 || repo.completed[0].State != domain.NotificationSent || repo.completed[0].SentAt == nil {
+		t.Fatalf("completed = %+v", repo.completed)
+	}
+}
+
+func TestNotificationDispatcher_SkipsDisabledChannel(t *testing.T) {
+	repo := newMockNotificationRepo()
+	svc := service.NewNotificationService(repo, &mockListRepo{})
+	repo.settings["bob"] = &domain.NotificationSettings{UserID: "bob", Email: "bob@example.com"}
+	p := &service.NotificationPayload{Task: &domain.Task{ID: "t1", Text: "x"}}
+	if err := svc.Notify(context.Background(), "bob", domain.NotifyMentioned, "", p); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	// Пользователь отключил канал, пока уведомление ждало отправки.
+	repo.settings["bob"].Preferences = map[string]map[string]bool{domain.NotifyMentioned: {domain.ChannelEmail: false}}
+	d := service.NewNotificationDispatcher(repo, svc, failingMailer{t}, 3)
+	if _, err := d.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("DispatchDue: %v", err)
+	}
+	if len(repo.completed) != 1 || repo.completed[0].State != domain.NotificationSkipped {
+		t.Fatalf("completed = %+v", repo.completed)
+	}
+}
+
+type failingMailer struct{ t *testing.T }
+
+func (m failingMailer) Send(ctx context.Context, msg service.Mail) error {
+	m.t.Errorf("unexpected mail to %s", msg.To)
+	return nil
+}
+
+func TestNotificationScheduler_DigestOncePerDay(t *testing.T) {
+	srv := startFakeSMTP(t)
+	repo := newMockNotificationRepo()
+	svc := service.NewNotificationService(repo, &mockListRepo{})
+	repo.settings["bob"] = &domain.NotificationSettings{UserID: "bob", Email: "bob@example.com", Locale: "en", TimeZone: "UTC"}
+	if err := svc.SubscribeDigest(context.Background(), "bob", "l1"); err != nil {
+		t.Fatalf("SubscribeDigest: %v", err)
+	}
+	if err := svc.SubscribeDigest(context.Background(), "bob", "l2"); err != nil {
+		t.Fatalf("SubscribeDigest: %v", err)
+	}
+	repo.open["l1"] = []*domain.Task{{ID: "t1", ListID: "l1", Text: "Pay the invoice"}, {ID: "t2", ListID: "l1", Text: "Call the client"}}
+
+	s := service.NewNotificationScheduler(svc, repo, time.Hour, 0)
+	for range 2 {
+		if err := s.Schedule(context.Background()); err != nil {
+			t.Fatalf("Schedule: %v", err)
+		}
+	}
+	if len(repo.queued) != 1 || repo.queued[0].Event != domain.NotifyDigest {
+		t.Fatalf("queued = %+v, want one digest", repo.queued)
+	}
+
+	d := service.NewNotificationDispatcher(repo, svc, newFakeMailer(t, srv), 3)
+	if _, err := d.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("DispatchDue: %v", err)
+	}
+	m := <-srv.messages
+	subject, body := readMail(t, m.Data)
+	if !strings.HasPrefix(subject, "Open tasks for ") {
+		t.Errorf("subject = %q", subject)
+	}
+	// Список без открытых задач в сводку не попадает.
+	if !strings.Contains(body, "  - Pay the invoice\n  - Call the client\n") || strings.Count(body, "Test List") != 1 {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestNotificationScheduler_DueSoonDeduplicated(t *testing.T) {
+	repo := newMockNotificationRepo()
+	svc := service.NewNotificationService(repo, &mockListRepo{})
+	repo.settings["bob"] = &domain.NotificationSettings{UserID: "bob", Email: "bob@example.com"}
+	due := time.Now().Add(time.Hour)
+	repo.due = []*domain.Task{{ID: "t1", ListID: "l1", Text: "Report", DueAt: &due, Assignee: "bob"}}
+
+	s := service.NewNotificationScheduler(svc, repo, 24*time.Hour, 8)
+	for range 2 {
+		if err := s.Schedule(context.Background()); err != nil {
+			t.Fatalf("Schedule: %v", err)
+		}
+	}
+	if len(repo.queued) != 1 || repo.queued[0].Event != domain.NotifyDueSoon || repo.queued[0].UserID != "bob" {
+		t.Fatalf("queued = %+v, want one due-soon reminder for bob", repo.queued)
+	}
+
+	// Перенос срока — новое напоминание.
+	later := due.Add(time.Hour)
+	repo.due[0].DueAt = &later
+	if err := s.Schedule(context.Background()); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if len(repo.queued) != 2 {
+		t.Fatalf("queued %d notifications after due date change, want 2", len(repo.queued))
+	}
+}
+
+func TestNotificationService_UpdateSettingsValidation(t *testing.T) {
+	svc := service.NewNotificationService(newMockNotificationRepo(), &mockListRepo{})
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		in   domain.NotificationSettings
+		want error
+	}{
+		"bad email":    {domain.NotificationSettings{Email: "not an address"}, service.ErrInvalidEmail},
+		"bad locale":   {domain.NotificationSettings{Locale: "de"}, service.ErrInvalidLocale},
+		"bad timezone": {domain.NotificationSettings{TimeZone: "Mars/Olympus"}, service.ErrInvalidTimeZone},
+		"bad event": {domain.NotificationSettings{
+			Preferences: map[string]map[string]bool{"task.exploded": {domain.ChannelEmail: false}},
+		}, service.ErrInvalidPreference},
+		"bad channel": {domain.NotificationSettings{
+			Preferences: map[string]map[string]bool{domain.NotifyDigest: {"pigeon": true}},
+		}, service.ErrInvalidPreference},
+	}
+	for name, tc := range cases {
+		if _, err := svc.UpdateSettings(ctx, "bob", tc.in); err != tc.want {
+			t.Errorf("%s: err = %v, want %v", name, err, tc.want)
+		}
+	}
+
+	got, err := svc.UpdateSettings(ctx, "bob", domain.NotificationSettings{
+		Email:       "Bob <bob@example.com>",
+		Preferences: map[string]map[string]bool{domain.NotifyDigest: {domain.ChannelEmail: false}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if got.Email != "bob@example.com" || got.Locale != "en" || got.TimeZone != "UTC" {
+		t.Errorf("settings = %+v", got)
+	}
+	if got.Preferences[domain.NotifyDigest][domain.ChannelEmail] || !got.Preferences[domain.NotifyAssigned][domain.ChannelEmail] {
+		t.Errorf("preferences = %v", got.Preferences)
+	}
+}
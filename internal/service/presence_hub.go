@@ -0,0 +1,57 @@
+package service
+
+import (
+	"sync"
+
+	"todo-api/internal/domain"
+)
+
+// PresenceHub рассылает сигналы присутствия участникам списка. Сигналы
+// эфемерны: не сохраняются и не пересылаются между экземплярами сервиса.
+type PresenceHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan domain.Presence]struct{}
+}
+
+func NewPresenceHub() *PresenceHub {
+	return &PresenceHub{subs: make(map[string]map[chan domain.Presence]struct{})}
+}
+
+// Subscribe подписывает на сигналы списка; cancel отписывает и закрывает канал.
+func (h *PresenceHub) Subscribe(listID string) (<-chan domain.Presence, func()) {
+	ch := make(chan domain.Presence, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[listID] == nil {
+		h.subs[listID] = make(map[chan domain.Presence]struct{})
+	}
+	h.subs[listID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subs[listID], ch)
+			if len(h.subs[listID]) == 0 {
+				delete(h.subs, listID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish отправляет сигнал подписчикам списка. Сигнал для подписчика
+// с заполненным буфером отбрасывается: следующий его заменит.
+func (h *PresenceHub) Publish(p domain.Presence) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[p.ListID] {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/storage"
+)
+
+// ErrQuickAddNoTitle — в тексте нет ничего, кроме распознанных фрагментов.
+var ErrQuickAddNoTitle = errors.New("text must contain a task title besides dates, labels and priority")
+
+// QuickAddService создаёт задачи из текста быстрого добавления, например
+// «Купить молоко завтра 18:00 #дом !high every week». Относительные даты
+// отсчитываются в часовом поясе пользователя из настроек уведомлений.
+type QuickAddService struct {
+	tasks    *TaskService
+	settings storage.NotificationRepository
+	now      func() time.Time
+}
+
+func NewQuickAddService(tasks *TaskService, settings storage.NotificationRepository) *QuickAddService {
+	return &QuickAddService{tasks: tasks, settings: settings, now: time.Now}
+}
+
+// QuickAdd разбирает text и создаёт задачу в списке listID. Вместе с
+// задачей возвращаются результат разбора и токен отмены.
+func (s *QuickAddService) QuickAdd(ctx context.Context, listID, text string) (*domain.Task, *domain.QuickAdd, string, error) {
+	loc, err := userLocation(ctx, s.settings)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	parsed := ParseQuickAdd(text, s.now().In(loc))
+	if parsed.Title == "" {
+		return nil, nil, "", ErrQuickAddNoTitle
+	}
+
+	task, token, err := s.tasks.createTask(ctx, &domain.Task{
+		ListID:     listID,
+		Text:       parsed.Title,
+		DueAt:      parsed.DueAt,
+		Labels:     parsed.Labels,
+		Priority:   parsed.Priority,
+		Recurrence: parsed.Recurrence,
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return task, &parsed, token, nil
+}
+
+// userLocation возвращает часовой пояс пользователя из настроек
+// уведомлений; по умолчанию UTC.
+func userLocation(ctx context.Context, repo storage.NotificationRepository) (*time.Location, error) {
+	settings, err := repo.Settings(ctx, reqctx.UserID(ctx))
+	if err != nil || settings == nil || settings.TimeZone == "" {
+		return time.UTC, err
+	}
+	loc, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		// Пояс проверяется при сохранении настроек; устаревший пояс из базы
+		// не мешает создавать задачи.
+		return time.UTC, nil
+	}
+	return loc, nil
+}
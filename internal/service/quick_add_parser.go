@@ -0,0 +1,463 @@
+package service
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"todo-api/internal/domain"
+)
+
+var (
+	quickLabelRe = regexp.MustCompile(`^#([\p{L}\p{N}_-]{1,50})$`)
+	quickISORe   = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	quickDotRe   = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{4}))?$`)
+	quickDayRe   = regexp.MustCompile(`^\d{1,2}$`)
+	quickClockRe = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+)
+
+var quickPriorities = map[string]string{
+	"!high": domain.TaskPriorityHigh, "!1": domain.TaskPriorityHigh, "!высокий": domain.TaskPriorityHigh,
+	"!medium": domain.TaskPriorityMedium, "!2": domain.TaskPriorityMedium, "!средний": domain.TaskPriorityMedium,
+	"!low": domain.TaskPriorityLow, "!3": domain.TaskPriorityLow, "!низкий": domain.TaskPriorityLow,
+}
+
+// quickRecurrences — правила повторения одним словом.
+var quickRecurrences = map[string]string{
+	"daily": domain.RecurrenceDaily, "everyday": domain.RecurrenceDaily, "ежедневно": domain.RecurrenceDaily,
+	"weekly": domain.RecurrenceWeekly, "еженедельно": domain.RecurrenceWeekly,
+	"monthly": domain.RecurrenceMonthly, "ежемесячно": domain.RecurrenceMonthly,
+	"yearly": domain.RecurrenceYearly, "annually": domain.RecurrenceYearly, "ежегодно": domain.RecurrenceYearly,
+}
+
+// quickEveryUnits — правила повторения после every или «каждый».
+var quickEveryUnits = map[string]string{
+	"day": domain.RecurrenceDaily, "день": domain.RecurrenceDaily,
+	"week": domain.RecurrenceWeekly, "неделю": domain.RecurrenceWeekly,
+	"month": domain.RecurrenceMonthly, "месяц": domain.RecurrenceMonthly,
+	"year": domain.RecurrenceYearly, "год": domain.RecurrenceYearly,
+}
+
+var quickWeekdays = map[string]time.Weekday{
+	"monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday, "thursday": time.Thursday,
+	"friday": time.Friday, "saturday": time.Saturday, "sunday": time.Sunday,
+	"понедельник": time.Monday, "вторник": time.Tuesday, "среда": time.Wednesday, "среду": time.Wednesday,
+	"четверг": time.Thursday, "пятница": time.Friday, "пятницу": time.Friday,
+	"суббота": time.Saturday, "субботу": time.Saturday, "воскресенье": time.Sunday,
+}
+
+var quickMonths = map[string]time.Month{
+	"jan": time.January, "january": time.January, "feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March, "apr": time.April, "april": time.April, "may": time.May,
+	"jun": time.June, "june": time.June, "jul": time.July, "july": time.July, "aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September, "oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November, "dec": time.December, "december": time.December,
+	"января": time.January, "янв": time.January, "февраля": time.February, "фев": time.February,
+	"марта": time.March, "мар": time.March, "апреля": time.April, "апр": time.April, "мая": time.May,
+	"июня": time.June, "июн": time.June, "июля": time.July, "июл": time.July, "августа": time.August, "авг": time.August,
+	"сентября": time.September, "сен": time.September, "сент": time.September, "октября": time.October, "окт": time.October,
+	"ноября": time.November, "ноя": time.November, "декабря": time.December, "дек": time.December,
+}
+
+// quickOffsetUnits — единицы «in 3 days» и «через 3 дня»: дни, месяцы и годы
+// прибавляемого смещения.
+var quickOffsetUnits = map[string][3]int{
+	"day": {0, 0, 1}, "days": {0, 0, 1}, "день": {0, 0, 1}, "дня": {0, 0, 1}, "дней": {0, 0, 1},
+	"week": {0, 0, 7}, "weeks": {0, 0, 7}, "неделю": {0, 0, 7}, "недели": {0, 0, 7}, "недель": {0, 0, 7},
+	"month": {0, 1, 0}, "months": {0, 1, 0}, "месяц": {0, 1, 0}, "месяца": {0, 1, 0}, "месяцев": {0, 1, 0},
+	"year": {1, 0, 0}, "years": {1, 0, 0}, "год": {1, 0, 0}, "года": {1, 0, 0}, "лет": {1, 0, 0},
+}
+
+// quickUnits — единицы измерения и валюты: 1.5 kg или 2.10 руб — число,
+// а не дата.
+var quickUnits = map[string]bool{
+	"kg": true, "g": true, "mg": true, "l": true, "ml": true, "km": true, "m": true, "cm": true, "mm": true,
+	"lb": true, "lbs": true, "oz": true, "h": true, "min": true, "pcs": true, "x": true, "%": true,
+	"usd": true, "eur": true, "rub": true, "$": true, "€": true, "₽": true,
+	"кг": true, "г": true, "мг": true, "л": true, "мл": true, "км": true, "м": true, "см": true, "мм": true,
+	"ч": true, "мин": true, "шт": true, "раз": true, "руб": true, "р": true, "рублей": true, "рубля": true,
+	"литра": true, "литров": true, "килограмма": true, "килограммов": true, "штук": true, "штуки": true,
+}
+
+// quickWord — слово текста. key — слово в нижнем регистре без знаков
+// препинания в конце; start и end — смещения key в тексте в символах.
+type quickWord struct {
+	raw        string
+	key        string
+	start, end int
+}
+
+func splitQuickWords(s string) []quickWord {
+	var (
+		words []quickWord
+		runes = []rune(s)
+		start = -1
+	)
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && !unicode.IsSpace(runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		raw := string(runes[start:i])
+		key := strings.TrimRight(raw, ",.;:?")
+		words = append(words, quickWord{
+			raw:   raw,
+			key:   strings.ToLower(key),
+			start: start,
+			end:   start + len([]rune(key)),
+		})
+		start = -1
+	}
+	return words
+}
+
+// quickAddParser разбирает слова текста слева направо. Дата, время и
+// повторение распознаются один раз: повторные упоминания остаются в
+// названии задачи.
+type quickAddParser struct {
+	input []rune
+	now   time.Time
+	words []quickWord
+	used  []bool
+	res   domain.QuickAdd
+
+	date         *time.Time
+	repeatDate   *time.Time
+	hasClock     bool
+	hour, minute int
+}
+
+// ParseQuickAdd разбирает текст быстрого добавления задачи на английском
+// или русском: метки (#home), приоритет (!high, !1, !высокий), повторение
+// (every week, каждый понедельник), дату (today, завтра, next friday,
+// in 3 days, через неделю, 2026-10-20, 20.10, 20 октября, oct 20) и время
+// (18:00, at 6pm, в 9:30). Относительные даты отсчитываются от now в его
+// часовом поясе.
+//
+// 20.10 без года распознаётся, только если месяц записан двумя цифрами и
+// за датой не идёт единица измерения: 1.5 kg и «глава 3.2» остаются в
+// названии.
+//
+// Дата без времени — полночь этого дня; время без даты — ближайшее такое
+// время, сегодня или завтра. День недели — ближайший такой день после
+// сегодняшнего, дата без года — ближайшая такая дата не раньше сегодня.
+func ParseQuickAdd(input string, now time.Time) domain.QuickAdd {
+	p := &quickAddParser{input: []rune(input), now: now, words: splitQuickWords(input)}
+	p.used = make([]bool, len(p.words))
+	p.res = domain.QuickAdd{Input: input, Labels: []string{}, Parts: []domain.QuickAddPart{}}
+
+	matchers := []func(i int) int{p.label, p.priority, p.recurrence, p.dateWord, p.clockWord}
+	for i := 0; i < len(p.words); i++ {
+		for _, match := range matchers {
+			if n := match(i); n > 0 {
+				i += n - 1
+				break
+			}
+		}
+	}
+
+	title := make([]string, 0, len(p.words))
+	for i, w := range p.words {
+		if !p.used[i] {
+			title = append(title, w.raw)
+		}
+	}
+	p.res.Title = strings.Join(title, " ")
+	p.res.DueAt = p.due()
+	return p.res
+}
+
+// key возвращает слово i или пустую строку, если его нет или оно уже
+// распознано.
+func (p *quickAddParser) key(i int) string {
+	if i < 0 || i >= len(p.words) || p.used[i] {
+		return ""
+	}
+	return p.words[i].key
+}
+
+// mark отмечает n слов начиная с i как фрагмент kind.
+func (p *quickAddParser) mark(kind string, i, n int, value string) {
+	for j := i; j < i+n; j++ {
+		p.used[j] = true
+	}
+	start, end := p.words[i].start, p.words[i+n-1].end
+	p.res.Parts = append(p.res.Parts, domain.QuickAddPart{
+		Kind:  kind,
+		Text:  string(p.input[start:end]),
+		Start: start,
+		End:   end,
+		Value: value,
+	})
+}
+
+func (p *quickAddParser) today() time.Time {
+	y, m, d := p.now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, p.now.Location())
+}
+
+func (p *quickAddParser) label(i int) int {
+	m := quickLabelRe.FindStringSubmatch(p.key(i))
+	if m == nil {
+		return 0
+	}
+	if !slices.Contains(p.res.Labels, m[1]) {
+		p.res.Labels = append(p.res.Labels, m[1])
+	}
+	p.mark(domain.QuickAddLabel, i, 1, m[1])
+	return 1
+}
+
+func (p *quickAddParser) priority(i int) int {
+	v, ok := quickPriorities[p.key(i)]
+	if !ok || p.res.Priority != "" {
+		return 0
+	}
+	p.res.Priority = v
+	p.mark(domain.QuickAddPriority, i, 1, v)
+	return 1
+}
+
+func (p *quickAddParser) recurrence(i int) int {
+	if p.res.Recurrence != "" {
+		return 0
+	}
+	if v, ok := quickRecurrences[p.key(i)]; ok {
+		p.res.Recurrence = v
+		p.mark(domain.QuickAddRecurrence, i, 1, v)
+		return 1
+	}
+	switch p.key(i) {
+	case "every", "каждый", "каждую", "каждое":
+	default:
+		return 0
+	}
+	if v, ok := quickEveryUnits[p.key(i+1)]; ok {
+		p.res.Recurrence = v
+		p.mark(domain.QuickAddRecurrence, i, 2, v)
+		return 2
+	}
+	if wd, ok := quickWeekdays[p.key(i+1)]; ok {
+		// Первое повторение — ближайший такой день, если дата не указана.
+		d := p.nextWeekday(wd)
+		p.repeatDate = &d
+		p.res.Recurrence = domain.RecurrenceWeekly
+		p.mark(domain.QuickAddRecurrence, i, 2, domain.RecurrenceWeekly)
+		return 2
+	}
+	return 0
+}
+
+// dateWord распознаёт дату, в том числе с предлогом: on friday, во вторник.
+func (p *quickAddParser) dateWord(i int) int {
+	if p.date != nil {
+		return 0
+	}
+	j := i
+	switch p.key(i) {
+	case "on", "в", "во":
+		j++
+	}
+	d, n := p.dateAt(j)
+	if n == 0 {
+		return 0
+	}
+	p.date = &d
+	p.mark(domain.QuickAddDate, i, j-i+n, d.Format(time.DateOnly))
+	return j - i + n
+}
+
+func (p *quickAddParser) dateAt(i int) (time.Time, int) {
+	today := p.today()
+	key := p.key(i)
+	switch key {
+	case "":
+		return time.Time{}, 0
+	case "today", "сегодня":
+		return today, 1
+	case "tomorrow", "завтра":
+		return today.AddDate(0, 0, 1), 1
+	case "послезавтра":
+		return today.AddDate(0, 0, 2), 1
+	case "day":
+		if p.key(i+1) == "after" && p.key(i+2) == "tomorrow" {
+			return today.AddDate(0, 0, 2), 3
+		}
+	case "next", "следующий", "следующую", "следующее":
+		if wd, ok := quickWeekdays[p.key(i+1)]; ok {
+			return p.nextWeekday(wd), 2
+		}
+	case "in", "через":
+		return p.offsetAt(i)
+	}
+
+	if wd, ok := quickWeekdays[key]; ok {
+		return p.nextWeekday(wd), 1
+	}
+	if m := quickISORe.FindStringSubmatch(key); m != nil {
+		y, _ := strconv.Atoi(m[1])
+		mon, _ := strconv.Atoi(m[2])
+		d, _ := strconv.Atoi(m[3])
+		if t, ok := p.civil(y, time.Month(mon), d); ok {
+			return t, 1
+		}
+		return time.Time{}, 0
+	}
+	if m := quickDotRe.FindStringSubmatch(key); m != nil {
+		d, _ := strconv.Atoi(m[1])
+		mon, _ := strconv.Atoi(m[2])
+		if m[3] == "" {
+			// Без года 1.5 и 3.2 — скорее дробь или номер раздела, чем дата.
+			if len(m[2]) != 2 || quickUnits[p.key(i+1)] {
+				return time.Time{}, 0
+			}
+			return p.nextDayOfMonth(time.Month(mon), d, 1)
+		}
+		y, _ := strconv.Atoi(m[3])
+		if t, ok := p.civil(y, time.Month(mon), d); ok {
+			return t, 1
+		}
+		return time.Time{}, 0
+	}
+	// 20 октября, 20 oct, oct 20.
+	if quickDayRe.MatchString(key) {
+		if mon, ok := quickMonths[p.key(i+1)]; ok {
+			d, _ := strconv.Atoi(key)
+			return p.nextDayOfMonth(mon, d, 2)
+		}
+	}
+	if mon, ok := quickMonths[key]; ok && quickDayRe.MatchString(p.key(i+1)) {
+		d, _ := strconv.Atoi(p.key(i + 1))
+		return p.nextDayOfMonth(mon, d, 2)
+	}
+	return time.Time{}, 0
+}
+
+// offsetAt распознаёт смещение от сегодня: in 3 days, in a week,
+// через 2 недели, через месяц.
+func (p *quickAddParser) offsetAt(i int) (time.Time, int) {
+	if unit, ok := quickOffsetUnits[p.key(i+1)]; ok && p.key(i) == "через" {
+		return p.today().AddDate(unit[0], unit[1], unit[2]), 2
+	}
+	count := 1
+	switch v := p.key(i + 1); v {
+	case "a", "an":
+	default:
+		c, err := strconv.Atoi(v)
+		if err != nil || c < 1 || c > 999 {
+			return time.Time{}, 0
+		}
+		count = c
+	}
+	unit, ok := quickOffsetUnits[p.key(i+2)]
+	if !ok {
+		return time.Time{}, 0
+	}
+	return p.today().AddDate(unit[0]*count, unit[1]*count, unit[2]*count), 3
+}
+
+func (p *quickAddParser) nextWeekday(wd time.Weekday) time.Time {
+	today := p.today()
+	days := (int(wd)-int(today.Weekday())+6)%7 + 1
+	return today.AddDate(0, 0, days)
+}
+
+// nextDayOfMonth возвращает ближайшую дату d.mon не раньше сегодня.
+func (p *quickAddParser) nextDayOfMonth(mon time.Month, d, n int) (time.Time, int) {
+	if mon < time.January || mon > time.December {
+		return time.Time{}, 0
+	}
+	today := p.today()
+	for y := today.Year(); y <= today.Year()+4; y++ {
+		// 29 февраля ищется до ближайшего високосного года.
+		if t, ok := p.civil(y, mon, d); ok && !t.Before(today) {
+			return t, n
+		}
+	}
+	return time.Time{}, 0
+}
+
+// civil возвращает полночь даты в часовом поясе пользователя; false —
+// такой даты нет.
+func (p *quickAddParser) civil(y int, mon time.Month, d int) (time.Time, bool) {
+	t := time.Date(y, mon, d, 0, 0, 0, 0, p.now.Location())
+	return t, t.Year() == y && t.Month() == mon && t.Day() == d
+}
+
+// clockWord распознаёт время, в том числе с предлогом: at 18:00, в 9:30,
+// 6pm, 6 pm.
+func (p *quickAddParser) clockWord(i int) int {
+	if p.hasClock {
+		return 0
+	}
+	j := i
+	switch p.key(i) {
+	case "at", "в":
+		j++
+	}
+	m := quickClockRe.FindStringSubmatch(p.key(j))
+	if m == nil {
+		return 0
+	}
+	n := j - i + 1
+	suffix := m[3]
+	if suffix == "" {
+		switch p.key(j + 1) {
+		case "am", "pm":
+			suffix = p.key(j + 1)
+			n++
+		}
+	}
+	// Число без минут и am/pm — не время: «купить 2 батона».
+	if m[2] == "" && suffix == "" {
+		return 0
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	if suffix != "" {
+		if hour < 1 || hour > 12 {
+			return 0
+		}
+		hour %= 12
+		if suffix == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0
+	}
+	p.hasClock, p.hour, p.minute = true, hour, minute
+	p.mark(domain.QuickAddTime, i, n, time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC).Format("15:04"))
+	return n
+}
+
+func (p *quickAddParser) due() *time.Time {
+	date := p.date
+	if date == nil {
+		date = p.repeatDate
+	}
+	switch {
+	case date != nil && p.hasClock:
+		t := time.Date(date.Year(), date.Month(), date.Day(), p.hour, p.minute, 0, 0, p.now.Location())
+		return &t
+	case date != nil:
+		return date
+	case p.hasClock:
+		today := p.today()
+		t := time.Date(today.Year(), today.Month(), today.Day(), p.hour, p.minute, 0, 0, p.now.Location())
+		if !t.After(p.now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	msk, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	// Понедельник, 15:00 по Москве.
+	now := time.Date(2026, 10, 19, 15, 0, 0, 0, msk)
+	at := func(month time.Month, day, hour, minute int) string {
+		return time.Date(2026, month, day, hour, minute, 0, 0, msk).Format(time.RFC3339)
+	}
+
+	tests := []struct {
+		input      string
+		title      string
+		due        string
+		labels     []string
+		priority   string
+		recurrence string
+	}{
+		{"Buy milk tomorrow 18:00 #home !high every week", "Buy milk", at(10, 20, 18, 0), []string{"home"}, "high", "weekly"},
+		{"Купить молоко завтра в 18:00 #Дом !высокий каждую неделю", "Купить молоко", at(10, 20, 18, 0), []string{"дом"}, "high", "weekly"},
+		{"Report friday", "Report", at(10, 23, 0, 0), nil, "", ""},
+		{"Call mom next monday at 6pm", "Call mom", at(10, 26, 18, 0), nil, "", ""},
+		{"Созвон в понедельник", "Созвон", at(10, 26, 0, 0), nil, "", ""},
+		{"Отчёт через 2 недели !2", "Отчёт", at(11, 2, 0, 0), nil, "medium", ""},
+		{"Pay rent in 3 days", "Pay rent", at(10, 22, 0, 0), nil, "", ""},
+		{"Встреча 20 октября 9:30", "Встреча", at(10, 20, 9, 30), nil, "", ""},
+		{"Party oct 20 7:15 pm", "Party", at(10, 20, 19, 15), nil, "", ""},
+		{"Налоги 01.03", "Налоги", time.Date(2027, 3, 1, 0, 0, 0, 0, msk).Format(time.RFC3339), nil, "", ""},
+		{"Налоги 1.3.2027", "Налоги", time.Date(2027, 3, 1, 0, 0, 0, 0, msk).Format(time.RFC3339), nil, "", ""},
+		{"Buy 1.5 kg apples", "Buy 1.5 kg apples", "", nil, "", ""},
+		{"Buy 2.10 kg apples", "Buy 2.10 kg apples", "", nil, "", ""},
+		{"Read chapter 3.2", "Read chapter 3.2", "", nil, "", ""},
+		{"Party 31.02", "Party 31.02", "", nil, "", ""},
+		{"Release 2026-12-01 #work #Work", "Release", at(12, 1, 0, 0), []string{"work"}, "", ""},
+		{"Stand-up every monday 10:00", "Stand-up", at(10, 26, 10, 0), nil, "", "weekly"},
+		{"Gym 7:00", "Gym", at(10, 20, 7, 0), nil, "", ""},
+		{"Water plants daily", "Water plants", "", nil, "", "daily"},
+		{"Read today, today", "Read today", at(10, 19, 0, 0), nil, "", ""},
+		{"Купить 2 батона", "Купить 2 батона", "", nil, "", ""},
+		{"Party oct 32 #fun", "Party oct 32", "", []string{"fun"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := service.ParseQuickAdd(tt.input, now)
+			if got.Title != tt.title {
+				t.Errorf("title = %q, want %q", got.Title, tt.title)
+			}
+			due := ""
+			if got.DueAt != nil {
+				due = got.DueAt.Format(time.RFC3339)
+			}
+			if due != tt.due {
+				t.Errorf("due = %q, want %q", due, tt.due)
+			}
+			if !slices.Equal(got.Labels, tt.labels) {
+				t.Errorf("labels = %v, want %v", got.Labels, tt.labels)
+			}
+			if got.Priority != tt.priority || got.Recurrence != tt.recurrence {
+				t.Errorf("priority, recurrence = %q, %q; want %q, %q", got.Priority, got.Recurrence, tt.priority, tt.recurrence)
+			}
+		})
+	}
+}
+
+func TestParseQuickAdd_Parts(t *testing.T) {
+	now := time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC)
+	got := service.ParseQuickAdd("Купить молоко завтра, в 18:00 #дом", now)
+
+	want := []domain.QuickAddPart{
+		{Kind: domain.QuickAddDate, Text: "завтра", Start: 14, End: 20, Value: "2026-10-20"},
+		{Kind: domain.QuickAddTime, Text: "в 18:00", Start: 22, End: 29, Value: "18:00"},
+		{Kind: domain.QuickAddLabel, Text: "#дом", Start: 30, End: 34, Value: "дом"},
+	}
+	if !slices.Equal(got.Parts, want) {
+		t.Errorf("parts = %+v\nwant %+v", got.Parts, want)
+	}
+	for _, p := range got.Parts {
+		if text := string([]rune(got.Input)[p.Start:p.End]); text != p.Text {
+			t.Errorf("input[%d:%d] = %q, want %q", p.Start, p.End, text, p.Text)
+		}
+	}
+}
+
+func TestQuickAddService_UsesUserTimeZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	var created *domain.Task
+	taskRepo := &mockTaskRepo{createFunc: func(ctx context.Context, task *domain.Task) error {
+		created = task
+		return nil
+	}}
+	settings := newMockNotificationRepo()
+	settings.settings["olga"] = &domain.NotificationSettings{UserID: "olga", TimeZone: "Asia/Tokyo"}
+	tasks := service.NewTaskService(taskRepo, &mockListRepo{}, mockTransactor{}, &mockAuditRepo{}, nil, nil)
+	svc := service.NewQuickAddService(tasks, settings)
+
+	ctx := reqctx.WithUserID(context.Background(), "olga")
+	before := time.Now().In(tokyo)
+	task, parsed, _, err := svc.QuickAdd(ctx, "list-1", "Позвонить завтра 18:00 #работа !low")
+	if err != nil {
+		t.Fatalf("QuickAdd: %v", err)
+	}
+	if created == nil || task.Text != "Позвонить" || task.ListID != "list-1" {
+		t.Fatalf("unexpected task %+v", task)
+	}
+	if !slices.Equal(task.Labels, []string{"работа"}) || task.Priority != domain.TaskPriorityLow {
+		t.Errorf("labels, priority = %v, %q", task.Labels, task.Priority)
+	}
+	tomorrow := before.AddDate(0, 0, 1)
+	due := task.DueAt.In(tokyo)
+	if due.Day() != tomorrow.Day() || due.Hour() != 18 || due.Minute() != 0 {
+		t.Errorf("due = %v, want tomorrow 18:00 in Asia/Tokyo", due)
+	}
+	if len(parsed.Parts) != 4 {
+		t.Errorf("parts = %+v", parsed.Parts)
+	}
+
+	if _, _, _, err := svc.QuickAdd(ctx, "list-1", "завтра #работа"); !errors.Is(err, service.ErrQuickAddNoTitle) {
+		t.Errorf("expected ErrQuickAddNoTitle, got %v", err)
+	}
+}
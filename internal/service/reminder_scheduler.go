@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+)
+
+// ReminderChannel доставляет сработавшее напоминание. Deliver вызывается
+// в транзакции срабатывания, поэтому каналы не отправляют ничего сами,
+// а записывают доставку в свой outbox: если транзакция откатится,
+// напоминание не уйдёт ни по одному каналу и сработает повторно позже.
+type ReminderChannel interface {
+	Deliver(ctx context.Context, r *domain.Reminder) error
+}
+
+// ReminderScheduler срабатывает напоминания, время которых наступило.
+// Каждое напоминание захватывается с SKIP LOCKED и отмечается сработавшим
+// в своей транзакции вместе с доставкой, поэтому при нескольких экземплярах
+// сервиса оно срабатывает один раз, а ошибка доставки не задерживает
+// остальные.
+type ReminderScheduler struct {
+	repo     storage.ReminderRepository
+	tx       storage.Transactor
+	channels map[string]ReminderChannel
+	now      func() time.Time
+
+	BatchSize int
+}
+
+// NewReminderScheduler создаёт планировщик с каналами по именам из
+// domain.ReminderChannels. Каналы напоминания, которых нет в channels,
+// пропускаются.
+func NewReminderScheduler(repo storage.ReminderRepository, tx storage.Transactor, channels map[string]ReminderChannel) *ReminderScheduler {
+	return &ReminderScheduler{
+		repo:      repo,
+		tx:        tx,
+		channels:  channels,
+		now:       time.Now,
+		BatchSize: 50,
+	}
+}
+
+// Run срабатывает напоминания до отмены ctx, опрашивая базу раз в interval.
+func (s *ReminderScheduler) Run(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := s.FireDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Info("reminder scheduler: " + err.Error())
+		}
+		if n == s.BatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// FireDue обрабатывает до BatchSize наступивших напоминаний и возвращает
+// их количество. Напоминание, которое не удалось доставить, остаётся
+// ожидающим и откладывается с растущей паузой; ошибка возвращается только
+// при сбое хранилища.
+func (s *ReminderScheduler) FireDue(ctx context.Context) (int, error) {
+	var n int
+	for n < s.BatchSize && ctx.Err() == nil {
+		now := s.now().UTC()
+		r, err := s.fireNext(ctx, now)
+		if r == nil {
+			return n, err
+		}
+		n++
+		if err == nil {
+			continue
+		}
+		logger.Info("reminder scheduler: " + err.Error())
+		if err := s.repo.RecordFailure(ctx, r.ID, now); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// fireNext захватывает и доставляет одно напоминание в отдельной
+// транзакции. nil — наступивших напоминаний нет или захват не удался.
+func (s *ReminderScheduler) fireNext(ctx context.Context, now time.Time) (*domain.Reminder, error) {
+	var claimed *domain.Reminder
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		due, err := s.repo.ClaimDue(ctx, now, 1)
+		if err != nil || len(due) == 0 {
+			return err
+		}
+		claimed = due[0]
+		for _, name := range claimed.Channels {
+			ch, ok := s.channels[name]
+			if !ok {
+				continue
+			}
+			if err := ch.Deliver(ctx, claimed); err != nil {
+				return fmt.Errorf("deliver reminder %s via %s: %w", claimed.ID, name, err)
+			}
+		}
+		return s.repo.MarkFired(ctx, []string{claimed.ID}, now)
+	})
+	return claimed, err
+}
+
+// EmailReminderChannel ставит напоминание в очередь уведомлений по почте
+// с учётом настроек пользователя.
+type EmailReminderChannel struct {
+	svc *NotificationService
+}
+
+func NewEmailReminderChannel(svc *NotificationService) *EmailReminderChannel {
+	return &EmailReminderChannel{svc: svc}
+}
+
+func (c *EmailReminderChannel) Deliver(ctx context.Context, r *domain.Reminder) error {
+	list, err := c.svc.lists.GetByID(ctx, r.ListID)
+	if err != nil {
+		return err
+	}
+	// Отложенное напоминание срабатывает в другое время и даёт новое письмо.
+	key := "reminder:" + r.ID + ":" + strconv.FormatInt(r.FireAt.Unix(), 10)
+	p := &NotificationPayload{ListID: list.ID, ListTitle: list.Title, Task: r.Task}
+	return c.svc.Notify(ctx, r.UserID, domain.NotifyReminder, key, p)
+}
+
+// WebhookReminderChannel записывает событие task.reminder в outbox вебхуков.
+type WebhookReminderChannel struct {
+	repo storage.WebhookRepository
+}
+
+func NewWebhookReminderChannel(repo storage.WebhookRepository) *WebhookReminderChannel {
+	return &WebhookReminderChannel{repo: repo}
+}
+
+func (c *WebhookReminderChannel) Deliver(ctx context.Context, r *domain.Reminder) error {
+	payload, err := json.Marshal(WebhookPayload{
+		Type:       domain.EventTaskReminder,
+		OccurredAt: time.Now().UTC(),
+		Actor:      r.UserID,
+		ListID:     r.ListID,
+		Data:       r,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	return c.repo.Enqueue(ctx, domain.EventTaskReminder, r.ListID, payload)
+}
+
+// SSEReminderChannel отправляет событие task.reminder в поток изменений
+// списка. Поток общий для всех, кто его читает: клиент показывает
+// напоминание, если reminder.user_id — его пользователь.
+type SSEReminderChannel struct {
+	repo storage.ReminderRepository
+}
+
+func NewSSEReminderChannel(repo storage.ReminderRepository) *SSEReminderChannel {
+	return &SSEReminderChannel{repo: repo}
+}
+
+func (c *SSEReminderChannel) Deliver(ctx context.Context, r *domain.Reminder) error {
+	rem := *r
+	rem.Task = nil
+	return c.repo.Publish(ctx, domain.ChangeEvent{
+		Type:     domain.EventTaskReminder,
+		ListID:   r.ListID,
+		TaskID:   r.TaskID,
+		Task:     r.Task,
+		Reminder: &rem,
+	})
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidReminderTime     = errors.New("exactly one of remind_at and due_offset_minutes is required")
+	ErrReminderInPast          = errors.New("remind_at must be in the future")
+	ErrInvalidReminderOffset   = errors.New("due_offset_minutes must be within 30 days of the due date")
+	ErrInvalidReminderChannels = errors.New("channels must be a subset of: email, webhook, sse")
+	ErrInvalidSnooze           = errors.New("snooze needs either until in the future or minutes between 1 and 43200")
+)
+
+// maxReminderMinutes — наибольшее смещение от срока и наибольшая отсрочка,
+// 30 дней.
+const maxReminderMinutes = 30 * 24 * 60
+
+// DefaultSnooze — на сколько откладывается напоминание, если время не указано.
+const DefaultSnooze = 10 * time.Minute
+
+// ReminderService управляет напоминаниями пользователя о задачах.
+// Срабатыванием занимается ReminderScheduler.
+type ReminderService struct {
+	repo  storage.ReminderRepository
+	tasks storage.TaskRepository
+	now   func() time.Time
+}
+
+func NewReminderService(repo storage.ReminderRepository, tasks storage.TaskRepository) *ReminderService {
+	return &ReminderService{repo: repo, tasks: tasks, now: time.Now}
+}
+
+// CreateReminder создаёт напоминание userID о задаче: на время remindAt или
+// за dueOffsetMinutes до срока задачи (после — при положительном смещении).
+// Пустой channels — все каналы.
+func (s *ReminderService) CreateReminder(ctx context.Context, userID, taskID string, remindAt *time.Time, dueOffsetMinutes *int, channels []string) (*domain.Reminder, error) {
+	if (remindAt == nil) == (dueOffsetMinutes == nil) {
+		return nil, ErrInvalidReminderTime
+	}
+	if remindAt != nil && !remindAt.After(s.now()) {
+		return nil, ErrReminderInPast
+	}
+	if dueOffsetMinutes != nil && (*dueOffsetMinutes < -maxReminderMinutes || *dueOffsetMinutes > maxReminderMinutes) {
+		return nil, ErrInvalidReminderOffset
+	}
+	if len(channels) == 0 {
+		channels = domain.ReminderChannels
+	}
+	for _, ch := range channels {
+		if !slices.Contains(domain.ReminderChannels, ch) {
+			return nil, ErrInvalidReminderChannels
+		}
+	}
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	r := &domain.Reminder{
+		ID:               uuid.NewString(),
+		TaskID:           taskID,
+		UserID:           userID,
+		RemindAt:         remindAt,
+		DueOffsetMinutes: dueOffsetMinutes,
+		Channels:         slices.Compact(slices.Sorted(slices.Values(channels))),
+		State:            domain.ReminderPending,
+	}
+	if remindAt != nil {
+		at := remindAt.UTC()
+		r.RemindAt = &at
+	}
+	if err := s.repo.Create(ctx, r); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, userID, r.ID)
+}
+
+// ListReminders возвращает напоминания userID о задаче.
+func (s *ReminderService) ListReminders(ctx context.Context, userID, taskID string) ([]*domain.Reminder, error) {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTask(ctx, userID, taskID)
+}
+
+// Snooze откладывает напоминание до until или на minutes минут от текущего
+// момента; без обоих — на DefaultSnooze. Отложить можно и сработавшее, и
+// отклонённое напоминание: оно снова ждёт срабатывания.
+func (s *ReminderService) Snooze(ctx context.Context, userID, id string, until *time.Time, minutes int) (*domain.Reminder, error) {
+	now := s.now()
+	var at time.Time
+	switch {
+	case until != nil && minutes != 0:
+		return nil, ErrInvalidSnooze
+	case until != nil:
+		if !until.After(now) || until.Sub(now) > maxReminderMinutes*time.Minute {
+			return nil, ErrInvalidSnooze
+		}
+		at = *until
+	case minutes != 0:
+		if minutes < 0 || minutes > maxReminderMinutes {
+			return nil, ErrInvalidSnooze
+		}
+		at = now.Add(time.Duration(minutes) * time.Minute)
+	default:
+		at = now.Add(DefaultSnooze)
+	}
+
+	r, err := s.repo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	at = at.UTC().Truncate(time.Second)
+	r.State = domain.ReminderPending
+	r.SnoozedUntil = &at
+	r.FireAt = &at
+	if err := s.repo.Update(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Dismiss отклоняет напоминание: оно больше не сработает, пока его не
+// отложат снова.
+func (s *ReminderService) Dismiss(ctx context.Context, userID, id string) (*domain.Reminder, error) {
+	r, err := s.repo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.State == domain.ReminderDismissed {
+		return r, nil
+	}
+	r.State = domain.ReminderDismissed
+	if err := s.repo.Update(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *ReminderService) DeleteReminder(ctx context.Context, userID, id string) error {
+	return s.repo.Delete(ctx, userID, id)
+}
@@ -0,0 +1,248 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+// mockReminderRepo хранит напоминания в памяти; ClaimDue отдаёт due по
+// очереди.
+type mockReminderRepo struct {
+	reminders map[string]*domain.Reminder
+	due       []*domain.Reminder
+	fired     []string
+	failed    []string
+	published []domain.ChangeEvent
+}
+
+func newMockReminderRepo() *mockReminderRepo {
+	return &mockReminderRepo{reminders: map[string]*domain.Reminder{}}
+}
+
+func (m *mockReminderRepo) Create(ctx context.Context, r *domain.Reminder) error {
+	copied := *r
+	m.reminders[r.ID] = &copied
+	return nil
+}
+
+func (m *mockReminderRepo) Get(ctx context.Context, userID, id string) (*domain.Reminder, error) {
+	r, ok := m.reminders[id]
+	if !ok || r.UserID != userID {
+		return nil, errors.New("not found")
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (m *mockReminderRepo) ListByTask(ctx context.Context, userID, taskID string) ([]*domain.Reminder, error) {
+	var res []*domain.Reminder
+	for _, r := range m.reminders {
+		if r.UserID == userID && r.TaskID == taskID {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockReminderRepo) Update(ctx context.Context, r *domain.Reminder) error {
+	copied := *r
+	m.reminders[r.ID] = &copied
+	return nil
+}
+
+func (m *mockReminderRepo) Delete(ctx context.Context, userID, id string) error {
+	delete(m.reminders, id)
+	return nil
+}
+
+func (m *mockReminderRepo) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.Reminder, error) {
+	due := m.due[:min(limit, len(m.due))]
+	m.due = m.due[len(due):]
+	return due, nil
+}
+
+func (m *mockReminderRepo) MarkFired(ctx context.Context, ids []string, at time.Time) error {
+	m.fired = append(m.fired, ids...)
+	return nil
+}
+
+func (m *mockReminderRepo) RecordFailure(ctx context.Context, id string, at time.Time) error {
+	m.failed = append(m.failed, id)
+	return nil
+}
+
+func (m *mockReminderRepo) Publish(ctx context.Context, ev domain.ChangeEvent) error {
+	m.published = append(m.published, ev)
+	return nil
+}
+
+type failingReminderChannel struct{}
+
+func (failingReminderChannel) Deliver(ctx context.Context, r *domain.Reminder) error {
+	return errors.New("outbox unavailable")
+}
+
+func TestReminderScheduler_DeliversThroughChannels(t *testing.T) {
+	repo := newMockReminderRepo()
+	fireAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	task := &domain.Task{ID: "t1", ListID: "l1", Text: "Позвонить бухгалтеру"}
+	repo.due = []*domain.Reminder{
+		{ID: "r1", TaskID: "t1", ListID: "l1", UserID: "olga", FireAt: &fireAt, Task: task,
+			Channels: []string{domain.ReminderChannelEmail, domain.ReminderChannelSSE, domain.ReminderChannelWebhook}},
+		// У ivan нет адреса: письмо не ставится, но напоминание срабатывает.
+		{ID: "r2", TaskID: "t1", ListID: "l1", UserID: "ivan", FireAt: &fireAt, Task: task,
+			Channels: []string{domain.ReminderChannelEmail}},
+	}
+	notifications := newMockNotificationRepo()
+	notifications.settings["olga"] = &domain.NotificationSettings{UserID: "olga", Email: "olga@example.com"}
+	webhooks := &mockWebhookRepo{}
+
+	scheduler := service.NewReminderScheduler(repo, mockTransactor{}, map[string]service.ReminderChannel{
+		domain.ReminderChannelEmail:   service.NewEmailReminderChannel(service.NewNotificationService(notifications, &mockListRepo{})),
+		domain.ReminderChannelWebhook: service.NewWebhookReminderChannel(webhooks),
+		domain.ReminderChannelSSE:     service.NewSSEReminderChannel(repo),
+	})
+	scheduler.BatchSize = 10
+
+	n, err := scheduler.FireDue(context.Background())
+	if err != nil {
+		t.Fatalf("FireDue: %v", err)
+	}
+	if n != 2 || len(repo.fired) != 2 {
+		t.Fatalf("fired %d (%v), want 2", n, repo.fired)
+	}
+
+	// ivan без адреса письма не получает.
+	if len(notifications.queued) != 1 {
+		t.Fatalf("queued %d notifications, want 1", len(notifications.queued))
+	}
+	if q := notifications.queued[0]; q.UserID != "olga" || q.Event != domain.NotifyReminder || q.DedupKey == "" {
+		t.Errorf("notification = %+v", q)
+	}
+
+	if len(webhooks.enqueued) != 1 || webhooks.enqueued[0].eventType != domain.EventTaskReminder {
+		t.Fatalf("webhook events = %+v", webhooks.enqueued)
+	}
+	var p struct {
+		Data domain.Reminder `json:"data"`
+	}
+	if err := json.Unmarshal(webhooks.enqueued[0].payload, &p); err != nil {
+		t.Fatalf("webhook payload: %v", err)
+	}
+	if p.Data.ID != "r1" || p.Data.Task == nil || p.Data.Task.Text != task.Text {
+		t.Errorf("webhook data = %+v", p.Data)
+	}
+
+	if len(repo.published) != 1 {
+		t.Fatalf("published %d events, want 1", len(repo.published))
+	}
+	ev := repo.published[0]
+	if ev.Type != domain.EventTaskReminder || ev.ListID != "l1" || ev.Reminder == nil || ev.Reminder.UserID != "olga" {
+		t.Errorf("event = %+v", ev)
+	}
+}
+
+func TestReminderScheduler_ChannelErrorDoesNotBlockOthers(t *testing.T) {
+	repo := newMockReminderRepo()
+	repo.due = []*domain.Reminder{
+		{ID: "r1", ListID: "l1", UserID: "olga", Channels: []string{domain.ReminderChannelWebhook}},
+		{ID: "r2", ListID: "l1", UserID: "olga", Channels: []string{domain.ReminderChannelSSE}},
+	}
+	scheduler := service.NewReminderScheduler(repo, mockTransactor{}, map[string]service.ReminderChannel{
+		domain.ReminderChannelWebhook: failingReminderChannel{},
+		domain.ReminderChannelSSE:     service.NewSSEReminderChannel(repo),
+	})
+
+	n, err := scheduler.FireDue(context.Background())
+	if err != nil {
+		t.Fatalf("FireDue: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("processed %d, want 2", n)
+	}
+	if len(repo.fired) != 1 || repo.fired[0] != "r2" {
+		t.Errorf("fired %v, want [r2]", repo.fired)
+	}
+	if len(repo.failed) != 1 || repo.failed[0] != "r1" {
+		t.Errorf("failed %v, want [r1]", repo.failed)
+	}
+}
+
+func TestReminderService_CreateValidation(t *testing.T) {
+	tasks := &mockTaskRepo{getByIDFunc: func(ctx context.Context, id string) (*domain.Task, error) {
+		return &domain.Task{ID: id, ListID: "l1"}, nil
+	}}
+	svc := service.NewReminderService(newMockReminderRepo(), tasks)
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	offset, tooFar := -30, 60*24*31
+
+	tests := []struct {
+		name     string
+		at       *time.Time
+		offset   *int
+		channels []string
+		want     error
+	}{
+		{"neither", nil, nil, nil, service.ErrInvalidReminderTime},
+		{"both", &future, &offset, nil, service.ErrInvalidReminderTime},
+		{"past", &past, nil, nil, service.ErrReminderInPast},
+		{"offset too far", nil, &tooFar, nil, service.ErrInvalidReminderOffset},
+		{"unknown channel", &future, nil, []string{"sms"}, service.ErrInvalidReminderChannels},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateReminder(context.Background(), "olga", "t1", tt.at, tt.offset, tt.channels); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	r, err := svc.CreateReminder(context.Background(), "olga", "t1", nil, &offset, nil)
+	if err != nil {
+		t.Fatalf("CreateReminder: %v", err)
+	}
+	if r.State != domain.ReminderPending || len(r.Channels) != len(domain.ReminderChannels) || *r.DueOffsetMinutes != -30 {
+		t.Errorf("reminder = %+v", r)
+	}
+}
+
+func TestReminderService_SnoozeRearmsFiredReminder(t *testing.T) {
+	repo := newMockReminderRepo()
+	fired := time.Now().Add(-time.Minute)
+	repo.reminders["r1"] = &domain.Reminder{ID: "r1", UserID: "olga", State: domain.ReminderFired, FiredAt: &fired}
+	svc := service.NewReminderService(repo, &mockTaskRepo{})
+
+	if _, err := svc.Snooze(context.Background(), "ivan", "r1", nil, 15); err == nil {
+		t.Fatal("snoozed someone else's reminder")
+	}
+	if _, err := svc.Snooze(context.Background(), "olga", "r1", nil, -5); !errors.Is(err, service.ErrInvalidSnooze) {
+		t.Fatalf("err = %v, want ErrInvalidSnooze", err)
+	}
+
+	before := time.Now()
+	r, err := svc.Snooze(context.Background(), "olga", "r1", nil, 15)
+	if err != nil {
+		t.Fatalf("Snooze: %v", err)
+	}
+	if r.State != domain.ReminderPending || r.SnoozedUntil == nil {
+		t.Fatalf("reminder = %+v", r)
+	}
+	if d := r.SnoozedUntil.Sub(before); d < 14*time.Minute || d > 16*time.Minute {
+		t.Errorf("snoozed for %v, want about 15m", d)
+	}
+
+	r, err = svc.Dismiss(context.Background(), "olga", "r1")
+	if err != nil || r.State != domain.ReminderDismissed {
+		t.Fatalf("Dismiss: %+v, %v", r, err)
+	}
+	if repo.reminders["r1"].State != domain.ReminderDismissed {
+		t.Errorf("stored state = %s", repo.reminders["r1"].State)
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/storage"
+)
+
+// maxRuleDepth — сколько правил подряд могут вызвать друг друга своими
+// действиями.
+const maxRuleDepth = 5
+
+// ruleJobArgs — выполнение правила RuleID по событию Trigger. Chain —
+// правила, действия которых привели к событию, от первого к последнему.
+type ruleJobArgs struct {
+	RuleID  string   `json:"rule_id"`
+	Trigger string   `json:"trigger"`
+	ListID  string   `json:"list_id"`
+	TaskID  string   `json:"task_id,omitempty"`
+	Chain   []string `json:"chain,omitempty"`
+}
+
+func (ruleJobArgs) Kind() string { return "rules.run" }
+
+type ruleChainKey struct{}
+
+// ruleChain возвращает цепочку правил, выполняющих действия в ctx.
+func ruleChain(ctx context.Context) []string {
+	chain, _ := ctx.Value(ruleChainKey{}).([]string)
+	return chain
+}
+
+// RuleEngine — хук изменений, запускающий правила автоматизации. Хук
+// в транзакции изменения находит подходящие правила и ставит их
+// выполнение в очередь фоновых задач; действия выполняются уже там,
+// методами TaskService и ListService, в отдельной транзакции вместе с
+// записью в журнал.
+//
+// Изменения, сделанные действиями правила, снова проходят через хук,
+// и за ними тянется цепочка правил. Правило, уже присутствующее в цепочке,
+// и цепочки длиннее maxRuleDepth не запускаются — в журнал пишется
+// loop_prevented.
+type RuleEngine struct {
+	repo     storage.RuleRepository
+	taskRepo storage.TaskRepository
+	tx       storage.Transactor
+	jobs     *JobQueue
+	tasks    *TaskService
+	lists    ListService
+}
+
+// NewRuleEngine создаёт движок и регистрирует его обработчик в очереди
+// jobs. Сервисы, через которые выполняются действия, передаются в
+// SetServices: они сами получают движок как хук.
+func NewRuleEngine(repo storage.RuleRepository, taskRepo storage.TaskRepository, tx storage.Transactor, jobs *JobQueue) *RuleEngine {
+	e := &RuleEngine{repo: repo, taskRepo: taskRepo, tx: tx, jobs: jobs}
+	HandleJob(jobs, 0, e.run)
+	return e
+}
+
+func (e *RuleEngine) SetServices(tasks *TaskService, lists ListService) {
+	e.tasks, e.lists = tasks, lists
+}
+
+func (e *RuleEngine) OnChange(ctx context.Context, c Change) error {
+	if c.EntityType != domain.EntityTask {
+		return nil
+	}
+
+	if c.Task != nil {
+		var triggers []string
+		for _, t := range webhookEventTypes(c) {
+			if slices.Contains(domain.RuleTriggers, t) {
+				triggers = append(triggers, t)
+			}
+		}
+		rules, err := e.repo.ListEnabled(ctx, c.Task.ListID, triggers)
+		if err != nil {
+			return err
+		}
+		for _, r := range rules {
+			if !matchRuleConditions(r.Conditions, c.Task) {
+				continue
+			}
+			if err := e.schedule(ctx, r, c.Task.ListID, c.Task.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	listID := completionCandidate(c)
+	if listID == "" {
+		return nil
+	}
+	rules, err := e.repo.ListEnabled(ctx, listID, []string{domain.RuleTriggerListCompleted})
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	done, err := listCompleted(ctx, e.taskRepo, listID)
+	if err != nil || !done {
+		return err
+	}
+	for _, r := range rules {
+		if err := e.schedule(ctx, r, listID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completionCandidate возвращает список, в котором после изменения могли
+// не остаться невыполненные задачи: задачу в нём выполнили, удалили или
+// унесли из него.
+func completionCandidate(c Change) string {
+	switch {
+	case c.Task == nil:
+		if c.PrevTask != nil && !c.PrevTask.Completed {
+			return c.PrevTask.ListID
+		}
+	case c.PrevTask != nil && c.PrevTask.ListID != c.Task.ListID:
+		if !c.PrevTask.Completed {
+			return c.PrevTask.ListID
+		}
+	case c.Task.Completed && (c.PrevTask == nil || !c.PrevTask.Completed):
+		return c.Task.ListID
+	}
+	return ""
+}
+
+func (e *RuleEngine) schedule(ctx context.Context, r *domain.Rule, listID, taskID string) error {
+	chain := ruleChain(ctx)
+	if len(chain) >= maxRuleDepth || slices.Contains(chain, r.ID) {
+		return e.repo.AppendExecution(ctx, &domain.RuleExecution{
+			RuleID:  r.ID,
+			Trigger: r.Trigger,
+			ListID:  listID,
+			TaskID:  taskID,
+			Status:  domain.RuleExecutionLoop,
+			Actions: []domain.RuleAction{},
+			Depth:   len(chain),
+		})
+	}
+	_, err := e.jobs.Enqueue(ctx, ruleJobArgs{RuleID: r.ID, Trigger: r.Trigger, ListID: listID, TaskID: taskID, Chain: chain}, JobOptions{})
+	return err
+}
+
+// run выполняет правило. Условия проверяются ещё раз: задача могла
+// измениться, пока выполнение ждало в очереди. Ошибка действия откатывает
+// все действия правила и пишется в журнал; повторять такое выполнение
+// бессмысленно, поэтому очереди возвращается только ошибка записи журнала.
+func (e *RuleEngine) run(ctx context.Context, args ruleJobArgs) error {
+	rule, err := e.repo.GetEnabled(ctx, args.RuleID)
+	if err != nil || rule == nil {
+		return err
+	}
+
+	exec := &domain.RuleExecution{
+		RuleID:  rule.ID,
+		Trigger: args.Trigger,
+		ListID:  args.ListID,
+		TaskID:  args.TaskID,
+		Actions: []domain.RuleAction{},
+		Depth:   len(args.Chain),
+	}
+	// Действия выполняются от имени владельца правила, а их изменения
+	// несут цепочку дальше.
+	ctx = reqctx.WithUserID(ctx, rule.OwnerID)
+	ctx = context.WithValue(ctx, ruleChainKey{}, append(slices.Clone(args.Chain), rule.ID))
+
+	err = e.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ok, err := e.stillMatches(ctx, rule, args)
+		if err != nil || !ok {
+			return err
+		}
+		for _, a := range rule.Actions {
+			if err := e.apply(ctx, rule, args.TaskID, a); err != nil {
+				return fmt.Errorf("%s: %w", a.Type, err)
+			}
+			exec.Actions = append(exec.Actions, a)
+		}
+		exec.Status = domain.RuleExecutionApplied
+		return e.repo.AppendExecution(ctx, exec)
+	})
+	if err == nil {
+		return nil
+	}
+
+	exec.Status = domain.RuleExecutionFailed
+	exec.Error = err.Error()
+	return e.repo.AppendExecution(ctx, exec)
+}
+
+func (e *RuleEngine) stillMatches(ctx context.Context, rule *domain.Rule, args ruleJobArgs) (bool, error) {
+	if args.TaskID == "" {
+		return listCompleted(ctx, e.taskRepo, args.ListID)
+	}
+	task, err := e.taskRepo.GetByID(ctx, args.TaskID)
+	if err != nil {
+		return false, fmt.Errorf("load task: %w", err)
+	}
+	return task.ListID == args.ListID && matchRuleConditions(rule.Conditions, task), nil
+}
+
+func (e *RuleEngine) apply(ctx context.Context, rule *domain.Rule, taskID string, a domain.RuleAction) error {
+	if e.tasks == nil || e.lists == nil {
+		return errors.New("rule engine services are not set")
+	}
+	var err error
+	switch a.Type {
+	case domain.RuleActionMoveTask:
+		_, _, err = e.tasks.MoveTask(ctx, taskID, a.ListID)
+	case domain.RuleActionAssign:
+		_, _, err = e.tasks.AssignTask(ctx, taskID, a.Assignee)
+	case domain.RuleActionCompleteTask:
+		completed := true
+		_, _, err = e.tasks.UpdateTask(ctx, taskID, "", &completed, nil)
+	case domain.RuleActionArchiveList:
+		_, _, err = e.lists.ArchiveList(ctx, rule.ListID, true)
+	default:
+		err = fmt.Errorf("unknown action %q", a.Type)
+	}
+	return err
+}
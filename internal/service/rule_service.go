@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRule      = errors.New("invalid rule")
+	ErrRuleTaskRequired = errors.New("task_id is required to test a task rule")
+)
+
+// ruleFieldOps — допустимые операторы для каждого поля условия.
+var ruleFieldOps = map[string][]string{
+	domain.RuleFieldText:      {domain.RuleOpContains, domain.RuleOpEquals, domain.RuleOpNotEquals},
+	domain.RuleFieldAssignee:  {domain.RuleOpEquals, domain.RuleOpNotEquals, domain.RuleOpIsSet, domain.RuleOpNotSet},
+	domain.RuleFieldCompleted: {domain.RuleOpEquals},
+	domain.RuleFieldDueAt:     {domain.RuleOpIsSet, domain.RuleOpNotSet},
+}
+
+// RuleInput — поля правила, которые задаёт пользователь. Enabled nil
+// означает включённое правило.
+type RuleInput struct {
+	ListID     string
+	Name       string
+	Trigger    string
+	Conditions []domain.RuleCondition
+	Actions    []domain.RuleAction
+	Enabled    *bool
+}
+
+type RuleService struct {
+	repo     storage.RuleRepository
+	listRepo storage.ListRepository
+	taskRepo storage.TaskRepository
+}
+
+func NewRuleService(repo storage.RuleRepository, listRepo storage.ListRepository, taskRepo storage.TaskRepository) *RuleService {
+	return &RuleService{repo: repo, listRepo: listRepo, taskRepo: taskRepo}
+}
+
+func (s *RuleService) CreateRule(ctx context.Context, ownerID string, in RuleInput) (*domain.Rule, error) {
+	rule := &domain.Rule{ID: uuid.NewString(), OwnerID: ownerID}
+	if err := s.apply(ctx, rule, in); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *RuleService) ListRules(ctx context.Context, ownerID, listID string) ([]*domain.Rule, error) {
+	return s.repo.ListByOwner(ctx, ownerID, listID)
+}
+
+func (s *RuleService) GetRule(ctx context.Context, ownerID, id string) (*domain.Rule, error) {
+	return s.repo.Get(ctx, ownerID, id)
+}
+
+// UpdateRule заменяет правило целиком.
+func (s *RuleService) UpdateRule(ctx context.Context, ownerID, id string, in RuleInput) (*domain.Rule, error) {
+	rule, err := s.repo.Get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, rule, in); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *RuleService) DeleteRule(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+func (s *RuleService) ListExecutions(ctx context.Context, ownerID, id string, limit, offset int) ([]*domain.RuleExecution, int, error) {
+	if _, err := s.repo.Get(ctx, ownerID, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListExecutions(ctx, id, limit, offset)
+}
+
+// TestRule проверяет правило на задаче taskID (для list.completed — на его
+// списке) и сообщает, какие действия оно выполнило бы, ничего не меняя.
+func (s *RuleService) TestRule(ctx context.Context, ownerID, id, taskID string) (*domain.RuleTestResult, error) {
+	rule, err := s.repo.Get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.RuleTestResult{Conditions: []domain.RuleConditionResult{}, Actions: []domain.RuleAction{}}
+	if rule.Trigger == domain.RuleTriggerListCompleted {
+		res.Matched, err = listCompleted(ctx, s.taskRepo, rule.ListID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if taskID == "" {
+			return nil, ErrRuleTaskRequired
+		}
+		task, err := s.taskRepo.GetByID(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.ListID != rule.ListID {
+			return nil, ErrTaskNotInList
+		}
+		res.Matched = true
+		for _, c := range rule.Conditions {
+			ok := matchRuleCondition(c, task)
+			res.Conditions = append(res.Conditions, domain.RuleConditionResult{RuleCondition: c, Matched: ok})
+			res.Matched = res.Matched && ok
+		}
+	}
+	if res.Matched {
+		res.Actions = rule.Actions
+	}
+	return res, nil
+}
+
+// apply проверяет in и переносит его в rule.
+func (s *RuleService) apply(ctx context.Context, rule *domain.Rule, in RuleInput) error {
+	if len(in.Name) < 1 || len(in.Name) > 100 {
+		return fmt.Errorf("%w: name must be 1..100 chars", ErrInvalidRule)
+	}
+	if !slices.Contains(domain.RuleTriggers, in.Trigger) {
+		return fmt.Errorf("%w: trigger must be one of: %s", ErrInvalidRule, strings.Join(domain.RuleTriggers, ", "))
+	}
+	if _, err := s.listRepo.GetByID(ctx, in.ListID); errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("%w: list %q not found", ErrInvalidRule, in.ListID)
+	} else if err != nil {
+		return err
+	}
+
+	taskRule := in.Trigger != domain.RuleTriggerListCompleted
+	if len(in.Conditions) > 10 {
+		return fmt.Errorf("%w: at most 10 conditions", ErrInvalidRule)
+	}
+	if !taskRule && len(in.Conditions) > 0 {
+		return fmt.Errorf("%w: %s rules have no conditions", ErrInvalidRule, in.Trigger)
+	}
+	for i, c := range in.Conditions {
+		if err := validateRuleCondition(c); err != nil {
+			return fmt.Errorf("%w: conditions[%d]: %s", ErrInvalidRule, i, err)
+		}
+	}
+
+	if len(in.Actions) < 1 || len(in.Actions) > 10 {
+		return fmt.Errorf("%w: actions must contain 1..10 actions", ErrInvalidRule)
+	}
+	for i, a := range in.Actions {
+		if err := validateRuleAction(a, taskRule); err != nil {
+			return fmt.Errorf("%w: actions[%d]: %s", ErrInvalidRule, i, err)
+		}
+		if a.Type != domain.RuleActionMoveTask {
+			continue
+		}
+		if _, err := s.listRepo.GetByID(ctx, a.ListID); errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("%w: actions[%d]: list %q not found", ErrInvalidRule, i, a.ListID)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	rule.ListID = in.ListID
+	rule.Name = in.Name
+	rule.Trigger = in.Trigger
+	rule.Conditions = in.Conditions
+	if rule.Conditions == nil {
+		rule.Conditions = []domain.RuleCondition{}
+	}
+	rule.Actions = in.Actions
+	rule.Enabled = in.Enabled == nil || *in.Enabled
+	return nil
+}
+
+func validateRuleCondition(c domain.RuleCondition) error {
+	ops, ok := ruleFieldOps[c.Field]
+	if !ok {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+	if !slices.Contains(ops, c.Op) {
+		return fmt.Errorf("field %s supports ops: %s", c.Field, strings.Join(ops, ", "))
+	}
+	switch {
+	case c.Field == domain.RuleFieldCompleted && c.Value != "true" && c.Value != "false":
+		return errors.New("completed value must be true or false")
+	case c.Op == domain.RuleOpContains && c.Value == "":
+		return errors.New("contains requires a value")
+	}
+	return nil
+}
+
+// validateRuleAction проверяет параметры действия, кроме существования
+// списка; сами изменения ещё раз проверят методы сервисов при выполнении.
+func validateRuleAction(a domain.RuleAction, taskRule bool) error {
+	switch a.Type {
+	case domain.RuleActionMoveTask:
+	case domain.RuleActionAssign:
+		if len(a.Assignee) > 100 {
+			return ErrInvalidAssignee
+		}
+	case domain.RuleActionCompleteTask, domain.RuleActionArchiveList:
+	default:
+		return fmt.Errorf("unknown action %q", a.Type)
+	}
+	if !taskRule && a.Type != domain.RuleActionArchiveList {
+		return fmt.Errorf("%s needs a task trigger", a.Type)
+	}
+	return nil
+}
+
+// matchRuleCondition проверяет условие на задаче. Текст сравнивается без
+// учёта регистра.
+func matchRuleCondition(c domain.RuleCondition, t *domain.Task) bool {
+	var value string
+	switch c.Field {
+	case domain.RuleFieldText:
+		value = t.Text
+	case domain.RuleFieldAssignee:
+		value = t.Assignee
+	case domain.RuleFieldCompleted:
+		value = fmt.Sprint(t.Completed)
+	case domain.RuleFieldDueAt:
+		if t.DueAt != nil {
+			value = t.DueAt.String()
+		}
+	}
+
+	switch c.Op {
+	case domain.RuleOpContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(c.Value))
+	case domain.RuleOpEquals:
+		return strings.EqualFold(value, c.Value)
+	case domain.RuleOpNotEquals:
+		return !strings.EqualFold(value, c.Value)
+	case domain.RuleOpIsSet:
+		return value != ""
+	case domain.RuleOpNotSet:
+		return value == ""
+	}
+	return false
+}
+
+func matchRuleConditions(conditions []domain.RuleCondition, t *domain.Task) bool {
+	for _, c := range conditions {
+		if !matchRuleCondition(c, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// listCompleted сообщает, что в списке есть задачи и все они выполнены.
+func listCompleted(ctx context.Context, taskRepo storage.TaskRepository, listID string) (bool, error) {
+	counts, err := taskRepo.CountByListIDs(ctx, []string{listID})
+	if err != nil {
+		return false, err
+	}
+	c := counts[listID]
+	return c.Total > 0 && c.Open == 0, nil
+}
@@ -0,0 +1,349 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+)
+
+// mockRuleRepo хранит правила и журнал в памяти.
+type mockRuleRepo struct {
+	mu         sync.Mutex
+	rules      []*domain.Rule
+	executions []*domain.RuleExecution
+}
+
+func (m *mockRuleRepo) Create(ctx context.Context, rule *domain.Rule) error {
+	m.rules = append(m.rules, rule)
+	return nil
+}
+
+func (m *mockRuleRepo) Get(ctx context.Context, ownerID, id string) (*domain.Rule, error) {
+	for _, r := range m.rules {
+		if r.ID == id && r.OwnerID == ownerID {
+			return r, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockRuleRepo) ListByOwner(ctx context.Context, ownerID, listID string) ([]*domain.Rule, error) {
+	return m.rules, nil
+}
+
+func (m *mockRuleRepo) Update(ctx context.Context, rule *domain.Rule) error { return nil }
+
+func (m *mockRuleRepo) Delete(ctx context.Context, ownerID, id string) error { return nil }
+
+func (m *mockRuleRepo) ListEnabled(ctx context.Context, listID string, triggers []string) ([]*domain.Rule, error) {
+	var res []*domain.Rule
+	for _, r := range m.rules {
+		if r.Enabled && r.ListID == listID && slices.Contains(triggers, r.Trigger) {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockRuleRepo) GetEnabled(ctx context.Context, id string) (*domain.Rule, error) {
+	for _, r := range m.rules {
+		if r.ID == id && r.Enabled {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRuleRepo) AppendExecution(ctx context.Context, e *domain.RuleExecution) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.executions = append(m.executions, e)
+	return nil
+}
+
+func (m *mockRuleRepo) ListExecutions(ctx context.Context, ruleID string, limit, offset int) ([]*domain.RuleExecution, int, error) {
+	return m.executions, len(m.executions), nil
+}
+
+// memTaskRepo — mockTaskRepo поверх задач в памяти.
+func memTaskRepo(tasks map[string]*domain.Task) *mockTaskRepo {
+	return &mockTaskRepo{
+		createFunc: func(ctx context.Context, task *domain.Task) error {
+			copied := *task
+			tasks[task.ID] = &copied
+			return nil
+		},
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Task, error) {
+			t, ok := tasks[id]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+			copied := *t
+			return &copied, nil
+		},
+		updateFunc: func(ctx context.Context, task *domain.Task) error {
+			copied := *task
+			tasks[task.ID] = &copied
+			return nil
+		},
+		countByListIDsFunc: func(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error) {
+			res := map[string]domain.TaskCounts{}
+			for _, t := range tasks {
+				c := res[t.ListID]
+				c.Total++
+				if t.Completed {
+					c.Completed++
+				} else {
+					c.Open++
+				}
+				res[t.ListID] = c
+			}
+			return res, nil
+		},
+	}
+}
+
+// workAll выполняет очередь, пока в ней есть задачи.
+func workAll(t *testing.T, q *service.JobQueue) {
+	t.Helper()
+	for {
+		ok, err := q.Work(context.Background())
+		if err != nil {
+			t.Fatalf("Work: %v", err)
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+func executionStatuses(repo *mockRuleRepo) []string {
+	var res []string
+	for _, e := range repo.executions {
+		res = append(res, e.RuleID+":"+e.Status)
+	}
+	return res
+}
+
+func TestRuleService_CreateRule_Validation(t *testing.T) {
+	svc := service.NewRuleService(&mockRuleRepo{}, &mockListRepo{}, &mockTaskRepo{})
+	move := domain.RuleAction{Type: domain.RuleActionMoveTask, ListID: "finance"}
+
+	tests := []struct {
+		name string
+		in   service.RuleInput
+	}{
+		{"empty name", service.RuleInput{ListID: "inbox", Trigger: domain.RuleTriggerTaskCreated, Actions: []domain.RuleAction{move}}},
+		{"unknown trigger", service.RuleInput{ListID: "inbox", Name: "r", Trigger: "list.deleted", Actions: []domain.RuleAction{move}}},
+		{"no actions", service.RuleInput{ListID: "inbox", Name: "r", Trigger: domain.RuleTriggerTaskCreated}},
+		{"unknown op", service.RuleInput{ListID: "inbox", Name: "r", Trigger: domain.RuleTriggerTaskCreated,
+			Conditions: []domain.RuleCondition{{Field: domain.RuleFieldDueAt, Op: domain.RuleOpContains, Value: "x"}},
+			Actions:    []domain.RuleAction{move}}},
+		{"task action on list trigger", service.RuleInput{ListID: "inbox", Name: "r", Trigger: domain.RuleTriggerListCompleted,
+			Actions: []domain.RuleAction{move}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateRule(context.Background(), "olga", tt.in); !errors.Is(err, service.ErrInvalidRule) {
+				t.Errorf("expected ErrInvalidRule, got %v", err)
+			}
+		})
+	}
+
+	rule, err := svc.CreateRule(context.Background(), "olga", service.RuleInput{
+		ListID: "inbox", Name: "Счета", Trigger: domain.RuleTriggerTaskCreated, Actions: []domain.RuleAction{move},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rule.Enabled || rule.Conditions == nil {
+		t.Errorf("expected enabled rule with empty conditions, got %+v", rule)
+	}
+}
+
+func TestRuleService_CreateRule_StorageErrorIsNotValidation(t *testing.T) {
+	dbDown := errors.New("connection refused")
+	lists := &mockListRepo{getByIDFunc: func(ctx context.Context, id string) (*domain.List, error) {
+		if id == "missing" {
+			return nil, storage.ErrNotFound
+		}
+		return nil, dbDown
+	}}
+	svc := service.NewRuleService(&mockRuleRepo{}, lists, &mockTaskRepo{})
+	in := service.RuleInput{ListID: "missing", Name: "r", Trigger: domain.RuleTriggerTaskCreated,
+		Actions: []domain.RuleAction{{Type: domain.RuleActionCompleteTask}}}
+
+	if _, err := svc.CreateRule(context.Background(), "olga", in); !errors.Is(err, service.ErrInvalidRule) {
+		t.Errorf("unknown list: err = %v, want ErrInvalidRule", err)
+	}
+	in.ListID = "inbox"
+	if _, err := svc.CreateRule(context.Background(), "olga", in); !errors.Is(err, dbDown) || errors.Is(err, service.ErrInvalidRule) {
+		t.Errorf("storage failure: err = %v, want it unchanged", err)
+	}
+}
+
+func TestRuleService_TestRule(t *testing.T) {
+	tasks := map[string]*domain.Task{
+		"t1": {ID: "t1", ListID: "inbox", Text: "Pay INVOICE #12"},
+		"t2": {ID: "t2", ListID: "inbox", Text: "Call mom"},
+	}
+	repo := &mockRuleRepo{}
+	svc := service.NewRuleService(repo, &mockListRepo{}, memTaskRepo(tasks))
+	rule, err := svc.CreateRule(context.Background(), "olga", service.RuleInput{
+		ListID: "inbox", Name: "Счета", Trigger: domain.RuleTriggerTaskCreated,
+		Conditions: []domain.RuleCondition{{Field: domain.RuleFieldText, Op: domain.RuleOpContains, Value: "invoice"}},
+		Actions:    []domain.RuleAction{{Type: domain.RuleActionAssign, Assignee: "olga"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+
+	res, err := svc.TestRule(context.Background(), "olga", rule.ID, "t1")
+	if err != nil {
+		t.Fatalf("TestRule: %v", err)
+	}
+	if !res.Matched || len(res.Actions) != 1 || !res.Conditions[0].Matched {
+		t.Errorf("expected match with one action, got %+v", res)
+	}
+
+	res, err = svc.TestRule(context.Background(), "olga", rule.ID, "t2")
+	if err != nil {
+		t.Fatalf("TestRule: %v", err)
+	}
+	if res.Matched || len(res.Actions) != 0 {
+		t.Errorf("expected no match, got %+v", res)
+	}
+
+	if _, err := svc.TestRule(context.Background(), "olga", rule.ID, ""); !errors.Is(err, service.ErrRuleTaskRequired) {
+		t.Errorf("expected ErrRuleTaskRequired, got %v", err)
+	}
+	if _, err := svc.TestRule(context.Background(), "ivan", rule.ID, "t1"); err == nil {
+		t.Error("expected error for another owner's rule")
+	}
+	if tasks["t1"].Assignee != "" || len(repo.executions) != 0 {
+		t.Error("dry run must not change tasks or write the log")
+	}
+}
+
+func TestRuleEngine_MovesAndAssigns(t *testing.T) {
+	tasks := map[string]*domain.Task{}
+	taskRepo := memTaskRepo(tasks)
+	repo := &mockRuleRepo{rules: []*domain.Rule{{
+		ID: "invoices", OwnerID: "olga", ListID: "inbox", Trigger: domain.RuleTriggerTaskCreated, Enabled: true,
+		Conditions: []domain.RuleCondition{{Field: domain.RuleFieldText, Op: domain.RuleOpContains, Value: "invoice"}},
+		Actions: []domain.RuleAction{
+			{Type: domain.RuleActionMoveTask, ListID: "finance"},
+			{Type: domain.RuleActionAssign, Assignee: "olga"},
+		},
+	}}}
+	jobs := service.NewJobQueue(newMockJobRepo(), mockTransactor{}, 1, 3)
+	engine := service.NewRuleEngine(repo, taskRepo, mockTransactor{}, jobs)
+	tasksSvc := service.NewTaskService(taskRepo, &mockListRepo{}, mockTransactor{}, &mockAuditRepo{}, nil, engine)
+	engine.SetServices(tasksSvc, service.NewListService(&mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil, engine))
+
+	ctx := reqctx.WithUserID(context.Background(), "ivan")
+	invoice, _, err := tasksSvc.CreateTask(ctx, "inbox", "Оплатить invoice", nil)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	other, _, err := tasksSvc.CreateTask(ctx, "inbox", "Купить хлеб", nil)
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	workAll(t, jobs)
+
+	if got := tasks[invoice.ID]; got.ListID != "finance" || got.Assignee != "olga" {
+		t.Errorf("expected invoice in finance assigned to olga, got %+v", got)
+	}
+	if got := tasks[other.ID]; got.ListID != "inbox" || got.Assignee != "" {
+		t.Errorf("expected other task untouched, got %+v", got)
+	}
+	if got := executionStatuses(repo); !slices.Equal(got, []string{"invoices:applied"}) {
+		t.Fatalf("executions = %v", got)
+	}
+	if e := repo.executions[0]; e.TaskID != invoice.ID || len(e.Actions) != 2 {
+		t.Errorf("unexpected execution %+v", e)
+	}
+}
+
+func TestRuleEngine_PreventsLoops(t *testing.T) {
+	tasks := map[string]*domain.Task{"t1": {ID: "t1", ListID: "a", Text: "Пинг-понг"}}
+	taskRepo := memTaskRepo(tasks)
+	repo := &mockRuleRepo{rules: []*domain.Rule{
+		{ID: "a-to-b", OwnerID: "olga", ListID: "a", Trigger: domain.RuleTriggerTaskUpdated, Enabled: true,
+			Actions: []domain.RuleAction{{Type: domain.RuleActionMoveTask, ListID: "b"}}},
+		{ID: "b-to-a", OwnerID: "olga", ListID: "b", Trigger: domain.RuleTriggerTaskUpdated, Enabled: true,
+			Actions: []domain.RuleAction{{Type: domain.RuleActionMoveTask, ListID: "a"}}},
+	}}
+	jobs := service.NewJobQueue(newMockJobRepo(), mockTransactor{}, 1, 3)
+	engine := service.NewRuleEngine(repo, taskRepo, mockTransactor{}, jobs)
+	tasksSvc := service.NewTaskService(taskRepo, &mockListRepo{}, mockTransactor{}, &mockAuditRepo{}, nil, engine)
+	engine.SetServices(tasksSvc, service.NewListService(&mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil, engine))
+
+	if _, _, err := tasksSvc.AssignTask(context.Background(), "t1", "ivan"); err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	workAll(t, jobs)
+
+	// Повторный запуск a-to-b пишется в журнал из хука, ещё внутри
+	// выполнения b-to-a.
+	want := []string{"a-to-b:applied", "a-to-b:loop_prevented", "b-to-a:applied"}
+	if got := executionStatuses(repo); !slices.Equal(got, want) {
+		t.Fatalf("executions = %v, want %v", got, want)
+	}
+	if depth := repo.executions[1].Depth; depth != 2 {
+		t.Errorf("loop_prevented depth = %d, want 2", depth)
+	}
+	if tasks["t1"].ListID != "a" {
+		t.Errorf("expected task back in a, got %q", tasks["t1"].ListID)
+	}
+}
+
+func TestRuleEngine_ArchivesCompletedList(t *testing.T) {
+	tasks := map[string]*domain.Task{
+		"t1": {ID: "t1", ListID: "trip", Text: "Билеты", Completed: true},
+		"t2": {ID: "t2", ListID: "trip", Text: "Отель"},
+	}
+	taskRepo := memTaskRepo(tasks)
+	var archived *domain.List
+	listRepo := &mockListRepo{updateFunc: func(ctx context.Context, list *domain.List) error {
+		archived = list
+		return nil
+	}}
+	repo := &mockRuleRepo{rules: []*domain.Rule{{
+		ID: "archive", OwnerID: "olga", ListID: "trip", Trigger: domain.RuleTriggerListCompleted, Enabled: true,
+		Conditions: []domain.RuleCondition{}, Actions: []domain.RuleAction{{Type: domain.RuleActionArchiveList}},
+	}}}
+	jobs := service.NewJobQueue(newMockJobRepo(), mockTransactor{}, 1, 3)
+	engine := service.NewRuleEngine(repo, taskRepo, mockTransactor{}, jobs)
+	tasksSvc := service.NewTaskService(taskRepo, listRepo, mockTransactor{}, &mockAuditRepo{}, nil, engine)
+	engine.SetServices(tasksSvc, service.NewListService(listRepo, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil, engine))
+
+	// Переименование открытой задачи список не завершает.
+	if _, _, err := tasksSvc.UpdateTask(context.Background(), "t2", "Гостиница", nil, nil); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	workAll(t, jobs)
+	if archived != nil || len(repo.executions) != 0 {
+		t.Fatal("list archived before all tasks were completed")
+	}
+
+	completed := true
+	if _, _, err := tasksSvc.UpdateTask(context.Background(), "t2", "", &completed, nil); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	workAll(t, jobs)
+	if archived == nil || archived.ID != "trip" || archived.ArchivedAt == nil {
+		t.Fatalf("expected trip archived, got %+v", archived)
+	}
+	if got := executionStatuses(repo); !slices.Equal(got, []string{"archive:applied"}) {
+		t.Errorf("executions = %v", got)
+	}
+}
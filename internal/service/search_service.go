@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+var (
+	ErrInvalidSearchQuery  = errors.New("query must be 1..200 chars")
+	ErrInvalidThreshold    = errors.New("threshold must be in (0, 1]")
+	ErrInvalidSuggestLimit = errors.New("limit must be 1..20")
+)
+
+const (
+	// maxSearchLimit ограничивает размер страницы поиска.
+	maxSearchLimit = 100
+	// maxSuggestLimit ограничивает число подсказок автодополнения.
+	maxSuggestLimit = 20
+)
+
+type SearchService struct {
+	repo      storage.SearchRepository
+	threshold float64
+}
+
+// NewSearchService создаёт сервис поиска. threshold — порог похожести
+// для нечёткого поиска по умолчанию, он должен лежать в (0, 1].
+func NewSearchService(repo storage.SearchRepository, threshold float64) (*SearchService, error) {
+	if !validThreshold(threshold) {
+		return nil, ErrInvalidThreshold
+	}
+	return &SearchService{repo: repo, threshold: threshold}, nil
+}
+
+func (s *SearchService) Search(ctx context.Context, query string, limit, offset int) ([]domain.SearchHit, int, error) {
+	query, err := normalizeQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.Search(ctx, query, min(limit, maxSearchLimit), offset)
+}
+
+// FuzzySearch ищет с учётом опечаток. Нулевой threshold означает порог по умолчанию.
+func (s *SearchService) FuzzySearch(ctx context.Context, query string, threshold float64, limit, offset int) ([]domain.SearchHit, int, error) {
+	query, err := normalizeQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	threshold, err = s.resolveThreshold(threshold)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.repo.FuzzySearch(ctx, query, threshold, min(limit, maxSearchLimit), offset)
+}
+
+// Suggest возвращает подсказки для автодополнения. Нулевой limit означает 10.
+func (s *SearchService) Suggest(ctx context.Context, query string, limit int) ([]domain.Suggestion, error) {
+	query, err := normalizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = 10
+	}
+	if limit < 0 || limit > maxSuggestLimit {
+		return nil, ErrInvalidSuggestLimit
+	}
+	return s.repo.Suggest(ctx, query, s.threshold, limit)
+}
+
+func (s *SearchService) resolveThreshold(threshold float64) (float64, error) {
+	if threshold == 0 {
+		return s.threshold, nil
+	}
+	if !validThreshold(threshold) {
+		return 0, ErrInvalidThreshold
+	}
+	return threshold, nil
+}
+
+func validThreshold(threshold float64) bool {
+	// Сравнение записано так, чтобы NaN тоже не проходил проверку.
+	return threshold > 0 && threshold <= 1
+}
+
+func normalizeQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > 200 {
+		return "", ErrInvalidSearchQuery
+	}
+	return query, nil
+}
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+// mockSearchRepo запоминает аргументы последнего вызова.
+type mockSearchRepo struct {
+	query     string
+	threshold float64
+	limit     int
+}
+
+func (m *mockSearchRepo) Search(ctx context.Context, query string, limit, offset int) ([]domain.SearchHit, int, error) {
+	m.query, m.limit = query, limit
+	return nil, 0, nil
+}
+
+func (m *mockSearchRepo) FuzzySearch(ctx context.Context, query string, threshold float64, limit, offset int) ([]domain.SearchHit, int, error) {
+	m.query, m.threshold, m.limit = query, threshold, limit
+	return nil, 0, nil
+}
+
+func (m *mockSearchRepo) Suggest(ctx context.Context, query string, threshold float64, limit int) ([]domain.Suggestion, error) {
+	m.query, m.threshold, m.limit = query, threshold, limit
+	return nil, nil
+}
+
+func TestSearchService_NormalizesQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		want  string
+		err   error
+	}{
+		{"trims spaces", "  молоко \t", "молоко", nil},
+		{"keeps websearch syntax", `"купить хлеб" -торт`, `"купить хлеб" -торт`, nil},
+		{"empty", "", "", service.ErrInvalidSearchQuery},
+		{"only spaces", " \n ", "", service.ErrInvalidSearchQuery},
+		{"200 runes", strings.Repeat("я", 200), strings.Repeat("я", 200), nil},
+		{"201 runes", strings.Repeat("я", 201), "", service.ErrInvalidSearchQuery},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockSearchRepo{}
+			svc, _ := service.NewSearchService(repo, 0.3)
+
+			_, _, err := svc.Search(context.Background(), tc.query, 20, 0)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err == nil && repo.query != tc.want {
+				t.Errorf("query = %q, want %q", repo.query, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearchService_CapsLimit(t *testing.T) {
+	repo := &mockSearchRepo{}
+	svc, _ := service.NewSearchService(repo, 0.3)
+
+	if _, _, err := svc.Search(context.Background(), "молоко", 100000, 0); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if repo.limit != 100 {
+		t.Errorf("search limit = %d, want 100", repo.limit)
+	}
+	if _, _, err := svc.FuzzySearch(context.Background(), "молоко", 0, 500, 0); err != nil {
+		t.Fatalf("FuzzySearch: %v", err)
+	}
+	if repo.limit != 100 {
+		t.Errorf("fuzzy limit = %d, want 100", repo.limit)
+	}
+}
+
+func TestSearchService_FuzzySearchThreshold(t *testing.T) {
+	cases := []struct {
+		name      string
+		threshold float64
+		want      float64
+		err       error
+	}{
+		{"zero uses default", 0, 0.3, nil},
+		{"upper bound", 1, 1, nil},
+		{"small positive", 0.01, 0.01, nil},
+		{"negative", -0.1, 0, service.ErrInvalidThreshold},
+		{"above one", 1.0001, 0, service.ErrInvalidThreshold},
+		{"NaN", math.NaN(), 0, service.ErrInvalidThreshold},
+		{"infinity", math.Inf(1), 0, service.ErrInvalidThreshold},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockSearchRepo{}
+			svc, _ := service.NewSearchService(repo, 0.3)
+
+			_, _, err := svc.FuzzySearch(context.Background(), "молоко", tc.threshold, 20, 0)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err == nil && repo.threshold != tc.want {
+				t.Errorf("threshold = %v, want %v", repo.threshold, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearchService_DefaultThreshold(t *testing.T) {
+	for _, threshold := range []float64{0, -0.5, 1.5, math.NaN()} {
+		if _, err := service.NewSearchService(&mockSearchRepo{}, threshold); !errors.Is(err, service.ErrInvalidThreshold) {
+			t.Errorf("threshold %v: err = %v, want ErrInvalidThreshold", threshold, err)
+		}
+	}
+	if _, err := service.NewSearchService(&mockSearchRepo{}, 1); err != nil {
+		t.Errorf("threshold 1: %v", err)
+	}
+}
+
+func TestSearchService_SuggestLimit(t *testing.T) {
+	cases := []struct {
+		name  string
+		limit int
+		want  int
+		err   error
+	}{
+		{"zero uses default", 0, 10, nil},
+		{"upper bound", 20, 20, nil},
+		{"negative", -1, 0, service.ErrInvalidSuggestLimit},
+		{"above max", 21, 0, service.ErrInvalidSuggestLimit},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockSearchRepo{}
+			svc, _ := service.NewSearchService(repo, 0.3)
+
+			_, err := svc.Suggest(context.Background(), "мол", tc.limit)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err == nil && repo.limit != tc.want {
+				t.Errorf("limit = %d, want %d", repo.limit, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+var ErrInvalidStatsRange = errors.New("invalid range: from must be before to, interval must be day or week, at most 366 buckets")
+
+const maxVelocityBuckets = 366
+
+type StatsService struct {
+	repo     storage.StatsRepository
+	listRepo storage.ListRepository
+}
+
+func NewStatsService(repo storage.StatsRepository, listRepo storage.ListRepository) *StatsService {
+	return &StatsService{repo: repo, listRepo: listRepo}
+}
+
+func (s *StatsService) ListStats(ctx context.Context, listID string, rng domain.StatsRange) (*domain.TaskStats, error) {
+	rng, err := normalizeStatsRange(rng, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.listRepo.GetByID(ctx, listID); err != nil {
+		return nil, err
+	}
+	return s.repo.TaskStats(ctx, listID, rng)
+}
+
+func (s *StatsService) GlobalStats(ctx context.Context, rng domain.StatsRange) (*domain.TaskStats, error) {
+	rng, err := normalizeStatsRange(rng, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.TaskStats(ctx, "", rng)
+}
+
+// normalizeStatsRange подставляет значения по умолчанию (последние 30 дней по дням)
+// и проверяет, что интервал не слишком большой.
+func normalizeStatsRange(rng domain.StatsRange, now time.Time) (domain.StatsRange, error) {
+	if rng.To.IsZero() {
+		rng.To = now
+	}
+	if rng.From.IsZero() {
+		rng.From = rng.To.AddDate(0, 0, -30)
+	}
+	if rng.Interval == "" {
+		rng.Interval = domain.StatsIntervalDay
+	}
+	rng.From, rng.To = rng.From.UTC(), rng.To.UTC()
+
+	if !rng.From.Before(rng.To) {
+		return rng, ErrInvalidStatsRange
+	}
+
+	var bucket time.Duration
+	switch rng.Interval {
+	case domain.StatsIntervalDay:
+		bucket = 24 * time.Hour
+	case domain.StatsIntervalWeek:
+		bucket = 7 * 24 * time.Hour
+	default:
+		return rng, ErrInvalidStatsRange
+	}
+	if rng.To.Sub(rng.From)/bucket > maxVelocityBuckets {
+		return rng, ErrInvalidStatsRange
+	}
+
+	return rng, nil
+}
@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+// mockStatsRepo запоминает интервал, с которым его вызвали.
+type mockStatsRepo struct {
+	rng domain.StatsRange
+}
+
+func (m *mockStatsRepo) TaskStats(ctx context.Context, listID string, rng domain.StatsRange) (*domain.TaskStats, error) {
+	m.rng = rng
+	return &domain.TaskStats{ListID: listID, From: rng.From, To: rng.To, Interval: rng.Interval}, nil
+}
+
+func TestStatsService_NormalizesRange(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		rng  domain.StatsRange
+		want domain.StatsRange
+		err  error
+	}{
+		{
+			name: "explicit range converted to UTC",
+			rng:  domain.StatsRange{From: time.Date(2026, 3, 1, 3, 0, 0, 0, moscow), To: time.Date(2026, 3, 8, 3, 0, 0, 0, moscow), Interval: domain.StatsIntervalWeek},
+			want: domain.StatsRange{From: from, To: from.AddDate(0, 0, 7), Interval: domain.StatsIntervalWeek},
+		},
+		{
+			name: "default interval is day",
+			rng:  domain.StatsRange{From: from, To: from.AddDate(0, 0, 1)},
+			want: domain.StatsRange{From: from, To: from.AddDate(0, 0, 1), Interval: domain.StatsIntervalDay},
+		},
+		{
+			name: "default from is 30 days before to",
+			rng:  domain.StatsRange{To: from},
+			want: domain.StatsRange{From: from.AddDate(0, 0, -30), To: from, Interval: domain.StatsIntervalDay},
+		},
+		{
+			name: "366 daily buckets",
+			rng:  domain.StatsRange{From: from, To: from.Add(366 * 24 * time.Hour)},
+			want: domain.StatsRange{From: from, To: from.Add(366 * 24 * time.Hour), Interval: domain.StatsIntervalDay},
+		},
+		{
+			name: "367 daily buckets",
+			rng:  domain.StatsRange{From: from, To: from.Add(367 * 24 * time.Hour)},
+			err:  service.ErrInvalidStatsRange,
+		},
+		{
+			name: "366 weekly buckets",
+			rng:  domain.StatsRange{From: from, To: from.Add(366 * 7 * 24 * time.Hour), Interval: domain.StatsIntervalWeek},
+			want: domain.StatsRange{From: from, To: from.Add(366 * 7 * 24 * time.Hour), Interval: domain.StatsIntervalWeek},
+		},
+		{
+			name: "from equals to",
+			rng:  domain.StatsRange{From: from, To: from},
+			err:  service.ErrInvalidStatsRange,
+		},
+		{
+			name: "from after to",
+			rng:  domain.StatsRange{From: from, To: from.Add(-time.Second)},
+			err:  service.ErrInvalidStatsRange,
+		},
+		{
+			name: "unknown interval",
+			rng:  domain.StatsRange{From: from, To: from.AddDate(0, 0, 1), Interval: "month"},
+			err:  service.ErrInvalidStatsRange,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockStatsRepo{}
+			svc := service.NewStatsService(repo, nil)
+
+			_, err := svc.GlobalStats(context.Background(), tc.rng)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+			if !repo.rng.From.Equal(tc.want.From) || !repo.rng.To.Equal(tc.want.To) || repo.rng.Interval != tc.want.Interval {
+				t.Errorf("range = %+v, want %+v", repo.rng, tc.want)
+			}
+			if repo.rng.From.Location() != time.UTC || repo.rng.To.Location() != time.UTC {
+				t.Errorf("range is not in UTC: %v, %v", repo.rng.From.Location(), repo.rng.To.Location())
+			}
+		})
+	}
+}
+
+func TestStatsService_DefaultRangeEndsNow(t *testing.T) {
+	repo := &mockStatsRepo{}
+	svc := service.NewStatsService(repo, nil)
+
+	before := time.Now()
+	if _, err := svc.GlobalStats(context.Background(), domain.StatsRange{}); err != nil {
+		t.Fatalf("GlobalStats: %v", err)
+	}
+	if repo.rng.To.Before(before) || repo.rng.To.After(time.Now()) {
+		t.Errorf("to = %v, want now", repo.rng.To)
+	}
+	if want := repo.rng.To.AddDate(0, 0, -30); !repo.rng.From.Equal(want) {
+		t.Errorf("from = %v, want %v", repo.rng.From, want)
+	}
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSyncToken   = errors.New("invalid sync token")
+	ErrTooManySyncChanges = errors.New("too many changes in one batch")
+)
+
+const (
+	maxSyncBatch     = 500
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+)
+
+// syncField — поле сущности, которое можно менять синхронизацией.
+type syncField[T any] struct {
+	get func(*T) any
+	set func(*T, json.RawMessage) error
+}
+
+var listSyncFields = map[string]syncField[domain.List]{
+	"title": {
+		get: func(l *domain.List) any { return l.Title },
+		set: func(l *domain.List, raw json.RawMessage) error {
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return err
+			}
+			if len(v) < 1 || len(v) > 100 {
+				return ErrInvalidListTitle
+			}
+			l.Title = v
+			return nil
+		},
+	},
+	"description": {
+		get: func(l *domain.List) any { return l.Description },
+		set: func(l *domain.List, raw json.RawMessage) error { return json.Unmarshal(raw, &l.Description) },
+	},
+}
+
+var taskSyncFields = map[string]syncField[domain.Task]{
+	"text": {
+		get: func(t *domain.Task) any { return t.Text },
+		set: func(t *domain.Task, raw json.RawMessage) error {
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return err
+			}
+			if len(v) < 1 || len(v) > 500 {
+				return ErrInvalidTaskText
+			}
+			t.Text = v
+			return nil
+		},
+	},
+	"completed": {
+		get: func(t *domain.Task) any { return t.Completed },
+		set: func(t *domain.Task, raw json.RawMessage) error { return json.Unmarshal(raw, &t.Completed) },
+	},
+	"due_at": {
+		get: func(t *domain.Task) any { return t.DueAt },
+		set: func(t *domain.Task, raw json.RawMessage) error {
+			var v *time.Time
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return err
+			}
+			t.DueAt = v
+			return nil
+		},
+	},
+}
+
+// SyncService реализует дельта-синхронизацию для офлайн-клиентов: выдачу
+// изменений с момента токена и приём пачки изменений клиента.
+type SyncService struct {
+	repo     storage.SyncRepository
+	listRepo storage.ListRepository
+	taskRepo storage.TaskRepository
+	tx       storage.Transactor
+	audit    storage.AuditRepository
+	hooks    ChangeHook
+}
+
+// NewSyncService создаёт сервис синхронизации. hooks может быть nil.
+func NewSyncService(repo storage.SyncRepository, listRepo storage.ListRepository, taskRepo storage.TaskRepository, tx storage.Transactor, audit storage.AuditRepository, hooks ChangeHook) *SyncService {
+	return &SyncService{repo: repo, listRepo: listRepo, taskRepo: taskRepo, tx: tx, audit: audit, hooks: hooks}
+}
+
+// Changes возвращает изменения с момента token (пустой — всё состояние)
+// и токен для следующего запроса. Пока HasMore, нужно запрашивать дальше.
+func (s *SyncService) Changes(ctx context.Context, token string, limit int) (*domain.SyncPage, string, error) {
+	cur, err := decodeSyncToken(token)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = defaultSyncLimit
+	}
+	limit = min(limit, maxSyncLimit)
+
+	page, err := s.repo.Changes(ctx, cur, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return page, encodeSyncToken(page.Next), nil
+}
+
+// Push применяет изменения клиента по порядку в одной транзакции.
+// Конфликты разрешаются по каждому полю: побеждает более позднее изменение.
+// Непринятые изменения и поля возвращаются в Conflicts.
+func (s *SyncService) Push(ctx context.Context, changes []domain.SyncChange) (*domain.SyncPushResult, error) {
+	if len(changes) > maxSyncBatch {
+		return nil, ErrTooManySyncChanges
+	}
+
+	var res *domain.SyncPushResult
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		res = &domain.SyncPushResult{Applied: []domain.SyncApplied{}, Conflicts: []domain.SyncConflict{}}
+		for _, c := range changes {
+			if err := s.apply(ctx, c, res); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *SyncService) apply(ctx context.Context, c domain.SyncChange, res *domain.SyncPushResult) error {
+	if _, err := uuid.Parse(c.ID); err != nil {
+		addSyncConflict(res, c, "", domain.SyncConflictInvalid, "id must be a UUID", nil, nil)
+		return nil
+	}
+	if c.ModifiedAt.IsZero() {
+		addSyncConflict(res, c, "", domain.SyncConflictInvalid, "modified_at is required", nil, nil)
+		return nil
+	}
+	// Часы клиента могут спешить: изменение из будущего иначе побеждало бы всегда.
+	if now := time.Now().UTC(); c.ModifiedAt.After(now) {
+		c.ModifiedAt = now
+	}
+	if c.Op != domain.SyncOpUpsert && c.Op != domain.SyncOpDelete {
+		addSyncConflict(res, c, "", domain.SyncConflictInvalid, "op must be upsert or delete", nil, nil)
+		return nil
+	}
+
+	switch c.EntityType {
+	case domain.EntityList:
+		return s.applyList(ctx, c, res)
+	case domain.EntityTask:
+		return s.applyTask(ctx, c, res)
+	default:
+		addSyncConflict(res, c, "", domain.SyncConflictInvalid, "entity_type must be list or task", nil, nil)
+		return nil
+	}
+}
+
+func (s *SyncService) applyList(ctx context.Context, c domain.SyncChange, res *domain.SyncPushResult) error {
+	list, clock, err := s.repo.LockList(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	if c.Op == domain.SyncOpDelete {
+		if list == nil {
+			addSyncApplied(res, c, nil)
+			return nil
+		}
+		if newerThan(clock, c.ModifiedAt) {
+			addSyncConflict(res, c, "", domain.SyncConflictModified, "list was changed after the client deleted it", nil, nil)
+			return nil
+		}
+		if err := s.listRepo.Delete(ctx, list.ID); err != nil {
+			return err
+		}
+		addSyncApplied(res, c, nil)
+		return recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionDelete, list, nil)
+	}
+
+	if list == nil {
+		ok, err := s.canCreate(ctx, c, res)
+		if err != nil || !ok {
+			return err
+		}
+		list = &domain.List{ID: c.ID}
+		clock = domain.FieldClock{}
+		if !mergeSyncFields(list, clock, c, listSyncFields, res) {
+			return nil
+		}
+		if list.Title == "" {
+			addSyncConflict(res, c, "title", domain.SyncConflictInvalid, ErrInvalidListTitle.Error(), nil, nil)
+			return nil
+		}
+		if _, err := s.listRepo.Create(ctx, list); err != nil {
+			return err
+		}
+		if err := s.repo.SetFieldClock(ctx, domain.EntityList, list.ID, clock); err != nil {
+			return err
+		}
+		addSyncApplied(res, c, slices.Sorted(maps.Keys(clock)))
+		return recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionCreate, nil, list)
+	}
+
+	before := *list
+	if clock == nil {
+		clock = domain.FieldClock{}
+	}
+	applied := mergeSyncFieldNames(list, clock, c, listSyncFields, res)
+	if len(applied) == 0 {
+		return nil
+	}
+	if err := s.listRepo.Update(ctx, list); err != nil {
+		return err
+	}
+	if err := s.repo.SetFieldClock(ctx, domain.EntityList, list.ID, clock); err != nil {
+		return err
+	}
+	addSyncApplied(res, c, applied)
+	return recordChange(ctx, s.audit, s.hooks, domain.EntityList, list.ID, list.ID, domain.ActionUpdate, &before, list)
+}
+
+func (s *SyncService) applyTask(ctx context.Context, c domain.SyncChange, res *domain.SyncPushResult) error {
+	task, clock, err := s.repo.LockTask(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	if c.Op == domain.SyncOpDelete {
+		if task == nil {
+			addSyncApplied(res, c, nil)
+			return nil
+		}
+		if newerThan(clock, c.ModifiedAt) {
+			addSyncConflict(res, c, "", domain.SyncConflictModified, "task was changed after the client deleted it", nil, nil)
+			return nil
+		}
+		if err := s.taskRepo.Delete(ctx, task.ID); err != nil {
+			return err
+		}
+		addSyncApplied(res, c, nil)
+		return recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionDelete, task, nil)
+	}
+
+	if task == nil {
+		ok, err := s.canCreate(ctx, c, res)
+		if err != nil || !ok {
+			return err
+		}
+		list, _, err := s.repo.LockList(ctx, c.ListID)
+		if err != nil {
+			return err
+		}
+		if list == nil {
+			addSyncConflict(res, c, "", domain.SyncConflictInvalid, "list_id must reference an existing list", nil, nil)
+			return nil
+		}
+		task = &domain.Task{ID: c.ID, ListID: list.ID}
+		clock = domain.FieldClock{}
+		if !mergeSyncFields(task, clock, c, taskSyncFields, res) {
+			return nil
+		}
+		if task.Text == "" {
+			addSyncConflict(res, c, "text", domain.SyncConflictInvalid, ErrInvalidTaskText.Error(), nil, nil)
+			return nil
+		}
+		// completed_at выставляет только Update, поэтому выполненная задача
+		// создаётся невыполненной и сразу отмечается.
+		completed := task.Completed
+		task.Completed = false
+		if err := s.taskRepo.Create(ctx, task); err != nil {
+			return err
+		}
+		if completed {
+			task.Completed = true
+			if err := s.taskRepo.Update(ctx, task); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.SetFieldClock(ctx, domain.EntityTask, task.ID, clock); err != nil {
+			return err
+		}
+		addSyncApplied(res, c, slices.Sorted(maps.Keys(clock)))
+		return recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionCreate, nil, task)
+	}
+
+	before := *task
+	if clock == nil {
+		clock = domain.FieldClock{}
+	}
+	applied := mergeSyncFieldNames(task, clock, c, taskSyncFields, res)
+	if len(applied) == 0 {
+		return nil
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return err
+	}
+	if err := s.repo.SetFieldClock(ctx, domain.EntityTask, task.ID, clock); err != nil {
+		return err
+	}
+	addSyncApplied(res, c, applied)
+	return recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionUpdate, &before, task)
+}
+
+// canCreate проверяет, что сущность не удалена на сервере позже изменения клиента.
+func (s *SyncService) canCreate(ctx context.Context, c domain.SyncChange, res *domain.SyncPushResult) (bool, error) {
+	deletedAt, err := s.repo.DeletedAt(ctx, c.ID)
+	if err != nil {
+		return false, err
+	}
+	if deletedAt != nil && !c.ModifiedAt.After(*deletedAt) {
+		addSyncConflict(res, c, "", domain.SyncConflictDeleted, "deleted on the server after the client change", nil, nil)
+		return false, nil
+	}
+	return true, nil
+}
+
+// mergeSyncFields применяет поля новой сущности и сообщает, прошли ли
+// проверку все поля: частично созданная сущность не сохраняется.
+func mergeSyncFields[T any](entity *T, clock domain.FieldClock, c domain.SyncChange, fields map[string]syncField[T], res *domain.SyncPushResult) bool {
+	conflicts := len(res.Conflicts)
+	mergeSyncFieldNames(entity, clock, c, fields, res)
+	return len(res.Conflicts) == conflicts
+}
+
+// mergeSyncFieldNames применяет поля, изменённые клиентом позже, чем на
+// сервере (last-writer-wins по каждому полю), и возвращает их имена.
+// Остальные поля попадают в конфликты вместе с серверным значением.
+func mergeSyncFieldNames[T any](entity *T, clock domain.FieldClock, c domain.SyncChange, fields map[string]syncField[T], res *domain.SyncPushResult) []string {
+	var applied []string
+	for _, name := range slices.Sorted(maps.Keys(c.Fields)) {
+		raw := c.Fields[name]
+		f, ok := fields[name]
+		if !ok {
+			addSyncConflict(res, c, name, domain.SyncConflictInvalid, "field cannot be synced", nil, raw)
+			continue
+		}
+		if !c.ModifiedAt.After(clock[name]) {
+			addSyncConflict(res, c, name, domain.SyncConflictStale, "", f.get(entity), raw)
+			continue
+		}
+		if err := f.set(entity, raw); err != nil {
+			addSyncConflict(res, c, name, domain.SyncConflictInvalid, err.Error(), nil, raw)
+			continue
+		}
+		clock[name] = c.ModifiedAt
+		applied = append(applied, name)
+	}
+	return applied
+}
+
+// newerThan сообщает, менялось ли какое-либо поле позже t.
+func newerThan(clock domain.FieldClock, t time.Time) bool {
+	for _, changed := range clock {
+		if changed.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func addSyncApplied(res *domain.SyncPushResult, c domain.SyncChange, fields []string) {
+	res.Applied = append(res.Applied, domain.SyncApplied{EntityType: c.EntityType, ID: c.ID, Op: c.Op, Fields: fields})
+}
+
+func addSyncConflict(res *domain.SyncPushResult, c domain.SyncChange, field, reason, message string, serverValue any, clientValue json.RawMessage) {
+	res.Conflicts = append(res.Conflicts, domain.SyncConflict{
+		EntityType:  c.EntityType,
+		ID:          c.ID,
+		Field:       field,
+		Reason:      reason,
+		Message:     message,
+		ServerValue: serverValue,
+		ClientValue: clientValue,
+	})
+}
+
+// Токен синхронизации непрозрачен для клиента: это закодированный курсор.
+func encodeSyncToken(c domain.SyncCursor) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "v1.%d.%d.%d", c.Since, c.Until, c.AfterSeq))
+}
+
+func decodeSyncToken(token string) (domain.SyncCursor, error) {
+	var c domain.SyncCursor
+	if token == "" {
+		return c, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidSyncToken
+	}
+	if n, err := fmt.Sscanf(string(raw), "v1.%d.%d.%d", &c.Since, &c.Until, &c.AfterSeq); err != nil || n != 3 {
+		return c, ErrInvalidSyncToken
+	}
+	if c.AfterSeq < 0 || (c.Until == 0 && c.AfterSeq != 0) || (c.Until != 0 && c.Until < c.Since) {
+		return c, ErrInvalidSyncToken
+	}
+	return c, nil
+}
@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+type mockSyncRepo struct {
+	page      *domain.SyncPage
+	gotCursor domain.SyncCursor
+	tasks     map[string]*domain.Task
+	lists     map[string]*domain.List
+	clocks    map[string]domain.FieldClock
+	deletedAt map[string]time.Time
+}
+
+func (m *mockSyncRepo) Changes(ctx context.Context, cur domain.SyncCursor, limit int) (*domain.SyncPage, error) {
+	m.gotCursor = cur
+	return m.page, nil
+}
+
+func (m *mockSyncRepo) LockList(ctx context.Context, id string) (*domain.List, domain.FieldClock, error) {
+	if l, ok := m.lists[id]; ok {
+		copied := *l
+		return &copied, m.clocks[id], nil
+	}
+	return nil, nil, nil
+}
+
+func (m *mockSyncRepo) LockTask(ctx context.Context, id string) (*domain.Task, domain.FieldClock, error) {
+	if t, ok := m.tasks[id]; ok {
+		copied := *t
+		return &copied, m.clocks[id], nil
+	}
+	return nil, nil, nil
+}
+
+func (m *mockSyncRepo) DeletedAt(ctx context.Context, id string) (*time.Time, error) {
+	if t, ok := m.deletedAt[id]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func (m *mockSyncRepo) SetFieldClock(ctx context.Context, entityType, id string, clock domain.FieldClock) error {
+	m.clocks[id] = clock
+	return nil
+}
+
+const (
+	syncListID = "7f0c1d7e-2b0a-4c4e-9a53-0c6a3c1f2b11"
+	syncTaskID = "0b8e5f4a-6d2c-4f0e-8b1a-3e9d7c2a5f22"
+)
+
+func TestSyncService_Changes_TokenRoundTrip(t *testing.T) {
+	repo := &mockSyncRepo{page: &domain.SyncPage{Next: domain.SyncCursor{Since: 100, Until: 250, AfterSeq: 42}}}
+	svc := service.NewSyncService(repo, &mockListRepo{}, &mockTaskRepo{}, mockTransactor{}, &mockAuditRepo{}, nil)
+
+	_, token, err := svc.Changes(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.gotCursor != (domain.SyncCursor{}) {
+		t.Errorf("expected empty cursor for initial sync, got %+v", repo.gotCursor)
+	}
+
+	if _, _, err := svc.Changes(context.Background(), token, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (domain.SyncCursor{Since: 100, Until: 250, AfterSeq: 42}); repo.gotCursor != want {
+		t.Errorf("expected cursor %+v, got %+v", want, repo.gotCursor)
+	}
+
+	if _, _, err := svc.Changes(context.Background(), "not-a-token", 0); !errors.Is(err, service.ErrInvalidSyncToken) {
+		t.Errorf("expected ErrInvalidSyncToken, got %v", err)
+	}
+}
+
+func TestSyncService_Push_PerFieldLastWriterWins(t *testing.T) {
+	base := time.Now().Add(-time.Hour).UTC()
+	repo := &mockSyncRepo{
+		tasks: map[string]*domain.Task{syncTaskID: {ID: syncTaskID, ListID: syncListID, Text: "Купить хлеб"}},
+		clocks: map[string]domain.FieldClock{syncTaskID: {
+			"text":      base.Add(20 * time.Minute),
+			"completed": base,
+		}},
+	}
+	var saved *domain.Task
+	taskRepo := &mockTaskRepo{updateFunc: func(ctx context.Context, task *domain.Task) error {
+		saved = task
+		return nil
+	}}
+	svc := service.NewSyncService(repo, &mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil)
+
+	clientTime := base.Add(10 * time.Minute)
+	res, err := svc.Push(context.Background(), []domain.SyncChange{{
+		EntityType: domain.EntityTask,
+		Op:         domain.SyncOpUpsert,
+		ID:         syncTaskID,
+		Fields: map[string]json.RawMessage{
+			"text":      json.RawMessage(`"Купить батон"`),
+			"completed": json.RawMessage(`true`),
+		},
+		ModifiedAt: clientTime,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if saved == nil || !saved.Completed || saved.Text != "Купить хлеб" {
+		t.Fatalf("expected only completed to be applied, got %+v", saved)
+	}
+	if len(res.Applied) != 1 || len(res.Applied[0].Fields) != 1 || res.Applied[0].Fields[0] != "completed" {
+		t.Errorf("expected completed applied, got %+v", res.Applied)
+	}
+	if len(res.Conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", res.Conflicts)
+	}
+	c := res.Conflicts[0]
+	if c.Field != "text" || c.Reason != domain.SyncConflictStale || c.ServerValue != "Купить хлеб" {
+		t.Errorf("expected stale text conflict with server value, got %+v", c)
+	}
+	if !repo.clocks[syncTaskID]["completed"].Equal(clientTime) {
+		t.Errorf("expected completed clock %v, got %v", clientTime, repo.clocks[syncTaskID]["completed"])
+	}
+}
+
+func TestSyncService_Push_DeleteLosesToLaterEdit(t *testing.T) {
+	base := time.Now().Add(-time.Hour).UTC()
+	repo := &mockSyncRepo{
+		tasks:  map[string]*domain.Task{syncTaskID: {ID: syncTaskID, ListID: syncListID, Text: "Позвонить"}},
+		clocks: map[string]domain.FieldClock{syncTaskID: {"text": base.Add(time.Minute)}},
+	}
+	svc := service.NewSyncService(repo, &mockListRepo{}, &mockTaskRepo{}, mockTransactor{}, &mockAuditRepo{}, nil)
+
+	res, err := svc.Push(context.Background(), []domain.SyncChange{{
+		EntityType: domain.EntityTask, Op: domain.SyncOpDelete, ID: syncTaskID, ModifiedAt: base,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Applied) != 0 || len(res.Conflicts) != 1 || res.Conflicts[0].Reason != domain.SyncConflictModified {
+		t.Errorf("expected modified conflict, got applied=%+v conflicts=%+v", res.Applied, res.Conflicts)
+	}
+}
+
+func TestSyncService_Push_CreateAfterServerDeleteConflicts(t *testing.T) {
+	base := time.Now().Add(-time.Hour).UTC()
+	repo := &mockSyncRepo{
+		lists:     map[string]*domain.List{syncListID: {ID: syncListID, Title: "Дом"}},
+		clocks:    map[string]domain.FieldClock{},
+		deletedAt: map[string]time.Time{syncTaskID: base.Add(time.Minute)},
+	}
+	created := false
+	taskRepo := &mockTaskRepo{createFunc: func(ctx context.Context, task *domain.Task) error {
+		created = true
+		return nil
+	}}
+	svc := service.NewSyncService(repo, &mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil)
+
+	res, err := svc.Push(context.Background(), []domain.SyncChange{{
+		EntityType: domain.EntityTask,
+		Op:         domain.SyncOpUpsert,
+		ID:         syncTaskID,
+		ListID:     syncListID,
+		Fields:     map[string]json.RawMessage{"text": json.RawMessage(`"Полить цветы"`)},
+		ModifiedAt: base,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created {
+		t.Error("expected task not to be recreated")
+	}
+	if len(res.Conflicts) != 1 || res.Conflicts[0].Reason != domain.SyncConflictDeleted {
+		t.Errorf("expected deleted conflict, got %+v", res.Conflicts)
+	}
+}
@@ -11,38 +11,88 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidTaskText = errors.New("text must be 1..500 chars")
+	ErrNoTaskIDs       = errors.New("ids must contain 1..100 task ids")
+	ErrTaskNotInList   = errors.New("task does not belong to the list")
+	ErrInvalidAssignee = errors.New("assignee must be at most 100 chars")
+)
+
 type TaskService struct {
 	repo     storage.TaskRepository
 	listRepo storage.ListRepository
+	tx       storage.Transactor
+	audit    storage.AuditRepository
+	undo     *UndoLog
+	hooks    ChangeHook
 }
 
-func NewTaskService(repo storage.TaskRepository, listRepo storage.ListRepository) *TaskService {
-	return &TaskService{repo: repo, listRepo: listRepo}
+// NewTaskService создаёт сервис задач. undo и hooks могут быть nil.
+func NewTaskService(repo storage.TaskRepository, listRepo storage.ListRepository, tx storage.Transactor, audit storage.AuditRepository, undo *UndoLog, hooks ChangeHook) *TaskService {
+	return &TaskService{repo: repo, listRepo: listRepo, tx: tx, audit: audit, undo: undo, hooks: hooks}
 }
 
-func (s *TaskService) CreateTask(ctx context.Context, listID, text string) (*domain.Task, error) {
-	if len(text) < 1 || len(text) > 500 {
-		return nil, errors.New("text must be 1..500 chars")
+// CreateTask создаёт задачу и возвращает её вместе с токеном отмены.
+func (s *TaskService) CreateTask(ctx context.Context, listID, text string, dueAt *time.Time) (*domain.Task, string, error) {
+	return s.createTask(ctx, &domain.Task{ListID: listID, Text: text, DueAt: dueAt})
+}
+
+// createTask создаёт задачу из полей, заданных пользователем в task:
+// списка, текста, срока, меток, приоритета и повторения.
+func (s *TaskService) createTask(ctx context.Context, task *domain.Task) (*domain.Task, string, error) {
+	if err := validateTaskText(task.Text); err != nil {
+		return nil, "", err
 	}
 
-	if _, err := s.listRepo.GetByID(ctx, listID); err != nil {
-		return nil, err
+	if _, err := s.listRepo.GetByID(ctx, task.ListID); err != nil {
+		return nil, "", err
 	}
 
-	task := &domain.Task{
-		ID:        uuid.NewString(),
-		ListID:    listID,
-		Text:      text,
-		Completed: false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	task.ID = uuid.NewString()
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+
+	var token string
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, task); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionCreate, nil, task); err != nil {
+			return err
+		}
+		var err error
+		token, err = s.undo.record(ctx, domain.UndoCreateTask, domain.Snapshot{}, domain.Snapshot{Tasks: []*domain.Task{task}})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
 	}
 
-	if err := s.repo.Create(ctx, task); err != nil {
-		return nil, err
+	return task, token, nil
+}
+
+// createTasks создаёт задачи только что созданного списка одной операцией
+// хранилища: без записей журнала, токенов отмены и событий по отдельным
+// задачам.
+func (s *TaskService) createTasks(ctx context.Context, tasks []*domain.Task) error {
+	now := time.Now()
+	for _, t := range tasks {
+		if err := validateTaskText(t.Text); err != nil {
+			return err
+		}
+		t.ID = uuid.NewString()
+		t.CreatedAt = now
+		t.UpdatedAt = now
 	}
+	return s.repo.CreateMany(ctx, tasks)
+}
 
-	return task, nil
+// validateTaskText проверяет текст новой задачи.
+func validateTaskText(text string) error {
+	if len(text) < 1 || len(text) > 500 {
+		return ErrInvalidTaskText
+	}
+	return nil
 }
 
 func (s *TaskService) GetTask(ctx context.Context, id string) (*domain.Task, error) {
@@ -56,32 +106,227 @@ func (s *TaskService) ListTasks(ctx context.Context, listID string, limit, offse
 	return s.repo.ListByListID(ctx, listID, limit, offset)
 }
 
-func (s *TaskService) UpdateTask(ctx context.Context, id, text string, completed *bool) (*domain.Task, error) {
-	task, err := s.repo.GetByID(ctx, id)
+// TasksByLists возвращает до perList последних задач каждого из списков,
+// загружая их одним запросом. Нужен для пакетной загрузки в GraphQL.
+func (s *TaskService) TasksByLists(ctx context.Context, listIDs []string, perList int) (map[string][]*domain.Task, error) {
+	tasks, err := s.repo.ListByListIDs(ctx, listIDs, perList)
 	if err != nil {
 		return nil, err
 	}
+	res := make(map[string][]*domain.Task, len(listIDs))
+	for _, t := range tasks {
+		res[t.ListID] = append(res[t.ListID], t)
+	}
+	return res, nil
+}
+
+// CountTasksByLists считает задачи каждого из списков одним запросом.
+func (s *TaskService) CountTasksByLists(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error) {
+	return s.repo.CountByListIDs(ctx, listIDs)
+}
+
+// UpdateTask обновляет переданные поля задачи; пустой text и nil-поля не меняются.
+func (s *TaskService) UpdateTask(ctx context.Context, id, text string, completed *bool, dueAt *time.Time) (*domain.Task, string, error) {
+	if text != "" && len(text) > 500 {
+		return nil, "", ErrInvalidTaskText
+	}
+
+	var (
+		task  *domain.Task
+		token string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := *task
 
-	if text != "" {
-		if len(text) < 1 || len(text) > 500 {
-			return nil, errors.New("text must be 1..500 chars")
+		if text != "" {
+			task.Text = text
+		}
+
+		if completed != nil {
+			task.Completed = *completed
+		}
+
+		if dueAt != nil {
+			task.DueAt = dueAt
+		}
+
+		task.UpdatedAt = time.Now()
+
+		if err := s.repo.Update(ctx, task); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionUpdate, &before, task); err != nil {
+			return err
 		}
-		task.Text = text
+		token, err = s.undo.record(ctx, domain.UndoUpdateTask,
+			domain.Snapshot{Tasks: []*domain.Task{&before}}, domain.Snapshot{Tasks: []*domain.Task{task}})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
 	}
 
-	if completed != nil {
-		task.Completed = *completed
+	return task, token, nil
+}
+
+// AssignTask назначает задаче исполнителя; пустой assignee снимает
+// назначение. Если исполнитель не изменился, задача не меняется и токен
+// отмены пустой.
+func (s *TaskService) AssignTask(ctx context.Context, id, assignee string) (*domain.Task, string, error) {
+	if len(assignee) > 100 {
+		return nil, "", ErrInvalidAssignee
 	}
 
-	task.UpdatedAt = time.Now()
+	var (
+		task  *domain.Task
+		token string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.repo.GetByID(ctx, id)
+		if err != nil || task.Assignee == assignee {
+			return err
+		}
+		before := *task
 
-	if err := s.repo.Update(ctx, task); err != nil {
-		return nil, err
+		task.Assignee = assignee
+		task.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, task); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionUpdate, &before, task); err != nil {
+			return err
+		}
+		token, err = s.undo.record(ctx, domain.UndoUpdateTask,
+			domain.Snapshot{Tasks: []*domain.Task{&before}}, domain.Snapshot{Tasks: []*domain.Task{task}})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
 	}
+	return task, token, nil
+}
 
-	return task, nil
+// MoveTask переносит задачу в список listID. Если задача уже в нём,
+// задача не меняется и токен отмены пустой.
+func (s *TaskService) MoveTask(ctx context.Context, id, listID string) (*domain.Task, string, error) {
+	var (
+		task  *domain.Task
+		token string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.repo.GetByID(ctx, id)
+		if err != nil || task.ListID == listID {
+			return err
+		}
+		if _, err := s.listRepo.GetByID(ctx, listID); err != nil {
+			return err
+		}
+		before := *task
+
+		task.ListID = listID
+		task.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, task); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionUpdate, &before, task); err != nil {
+			return err
+		}
+		token, err = s.undo.record(ctx, domain.UndoUpdateTask,
+			domain.Snapshot{Tasks: []*domain.Task{&before}}, domain.Snapshot{Tasks: []*domain.Task{task}})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return task, token, nil
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+func (s *TaskService) DeleteTask(ctx context.Context, id string) (string, error) {
+	var token string
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionDelete, task, nil); err != nil {
+			return err
+		}
+		token, err = s.undo.record(ctx, domain.UndoDeleteTask, domain.Snapshot{Tasks: []*domain.Task{task}}, domain.Snapshot{})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// CompleteTasks отмечает выполненными задачи списка одной операцией с общим
+// токеном отмены. Уже выполненные задачи пропускаются и в результат не входят.
+func (s *TaskService) CompleteTasks(ctx context.Context, listID string, ids []string) ([]*domain.Task, string, error) {
+	if len(ids) < 1 || len(ids) > 100 {
+		return nil, "", ErrNoTaskIDs
+	}
+
+	var (
+		completed = []*domain.Task{}
+		token     string
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.listRepo.GetByID(ctx, listID); err != nil {
+			return err
+		}
+
+		var before []*domain.Task
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			task, err := s.repo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if task.ListID != listID {
+				return ErrTaskNotInList
+			}
+			if task.Completed {
+				continue
+			}
+			prev := *task
+
+			task.Completed = true
+			task.UpdatedAt = time.Now()
+			if err := s.repo.Update(ctx, task); err != nil {
+				return err
+			}
+			if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, task.ID, task.ListID, domain.ActionUpdate, &prev, task); err != nil {
+				return err
+			}
+			before = append(before, &prev)
+			completed = append(completed, task)
+		}
+		if len(completed) == 0 {
+			return nil
+		}
+
+		var err error
+		token, err = s.undo.record(ctx, domain.UndoCompleteTasks, domain.Snapshot{Tasks: before}, domain.Snapshot{Tasks: completed})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return completed, token, nil
 }
@@ -3,14 +3,25 @@ package service_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
 	"todo-api/internal/service"
 )
 
 type mockTaskRepo struct {
-	createFunc func(ctx context.Context, task *domain.Task) error
+	createFunc      func(ctx context.Context, task *domain.Task) error
+	createManyFunc  func(ctx context.Context, tasks []*domain.Task) error
+	getByIDFunc     func(ctx context.Context, id string) (*domain.Task, error)
+	findByQueryFunc func(ctx context.Context, query domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error)
+	updateFunc      func(ctx context.Context, task *domain.Task) error
+	restoreFunc     func(ctx context.Context, task *domain.Task, expectedVersion int64) error
+
+	listByListIDsFunc  func(ctx context.Context, listIDs []string, perList int) ([]*domain.Task, error)
+	countByListIDsFunc func(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error)
+	listAllFunc        func(ctx context.Context, listID string) ([]*domain.Task, error)
 }
 
 func (m *mockTaskRepo) Create(ctx context.Context, task *domain.Task) error {
@@ -20,18 +31,80 @@ func (m *mockTaskRepo) Create(ctx context.Context, task *domain.Task) error {
 	return nil
 }
 
-func (m *mockTaskRepo) GetByID(ctx context.Context, id string) (*domain.Task, error) { return nil, nil }
+// CreateMany без createManyFunc создаёт задачи по одной через Create.
+func (m *mockTaskRepo) CreateMany(ctx context.Context, tasks []*domain.Task) error {
+	if m.createManyFunc != nil {
+		return m.createManyFunc(ctx, tasks)
+	}
+	for _, t := range tasks {
+		if err := m.Create(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockTaskRepo) GetByID(ctx context.Context, id string) (*domain.Task, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
 
 func (m *mockTaskRepo) ListByListID(ctx context.Context, listID string, limit, offset int) ([]*domain.Task, int, error) {
 	return nil, 0, nil
 }
 
-func (m *mockTaskRepo) Update(ctx context.Context, task *domain.Task) error { return nil }
+func (m *mockTaskRepo) ListByListIDs(ctx context.Context, listIDs []string, perList int) ([]*domain.Task, error) {
+	if m.listByListIDsFunc != nil {
+		return m.listByListIDsFunc(ctx, listIDs, perList)
+	}
+	return []*domain.Task{}, nil
+}
+
+func (m *mockTaskRepo) CountByListIDs(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error) {
+	if m.countByListIDsFunc != nil {
+		return m.countByListIDsFunc(ctx, listIDs)
+	}
+	return map[string]domain.TaskCounts{}, nil
+}
+
+func (m *mockTaskRepo) FindByQuery(ctx context.Context, query domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error) {
+	if m.findByQueryFunc != nil {
+		return m.findByQueryFunc(ctx, query, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *mockTaskRepo) Update(ctx context.Context, task *domain.Task) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, task)
+	}
+	return nil
+}
 
 func (m *mockTaskRepo) Delete(ctx context.Context, id string) error { return nil }
 
+func (m *mockTaskRepo) ListAllByListID(ctx context.Context, listID string) ([]*domain.Task, error) {
+	if m.listAllFunc != nil {
+		return m.listAllFunc(ctx, listID)
+	}
+	return []*domain.Task{}, nil
+}
+
+func (m *mockTaskRepo) Restore(ctx context.Context, task *domain.Task, expectedVersion int64) error {
+	if m.restoreFunc != nil {
+		return m.restoreFunc(ctx, task, expectedVersion)
+	}
+	return nil
+}
+
+func (m *mockTaskRepo) DeleteVersion(ctx context.Context, id string, version int64) error { return nil }
+
 type mockListRepo struct {
 	getByIDFunc func(ctx context.Context, id string) (*domain.List, error)
+	updateFunc  func(ctx context.Context, list *domain.List) error
+	cloneFunc   func(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error)
 }
 
 func (m *mockListRepo) Create(ctx context.Context, list *domain.List) (*domain.List, error) {
@@ -45,7 +118,12 @@ func (m *mockListRepo) GetByID(ctx context.Context, id string) (*domain.List, er
 	return &domain.List{ID: id, Title: "Test List"}, nil
 }
 
-func (m *mockListRepo) Update(ctx context.Context, list *domain.List) error { return nil }
+func (m *mockListRepo) Update(ctx context.Context, list *domain.List) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, list)
+	}
+	return nil
+}
 
 func (m *mockListRepo) Delete(ctx context.Context, id string) error { return nil }
 
@@ -65,6 +143,42 @@ func (m *mockListRepo) SearchByTitle(ctx context.Context, query string) ([]domai
 	return []domain.List{}, nil
 }
 
+func (m *mockListRepo) Restore(ctx context.Context, list *domain.List, expectedVersion int64) error {
+	return nil
+}
+
+func (m *mockListRepo) DeleteVersion(ctx context.Context, id string, version int64) error { return nil }
+
+func (m *mockListRepo) Clone(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error) {
+	if m.cloneFunc != nil {
+		return m.cloneFunc(ctx, srcID, clone, opts)
+	}
+	return nil, nil
+}
+
+type mockTransactor struct{}
+
+func (mockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type mockAuditRepo struct {
+	events []*domain.AuditEvent
+}
+
+func (m *mockAuditRepo) Append(ctx context.Context, event *domain.AuditEvent) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockAuditRepo) ListByList(ctx context.Context, listID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockAuditRepo) ListByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
 func TestTaskService_CreateTask_Success(t *testing.T) {
 	taskRepo := &mockTaskRepo{
 		createFunc: func(ctx context.Context, task *domain.Task) error {
@@ -79,9 +193,9 @@ func TestTaskService_CreateTask_Success(t *testing.T) {
 		},
 	}
 
-	svc := service.NewTaskService(taskRepo, listRepo)
+	svc := service.NewTaskService(taskRepo, listRepo, mockTransactor{}, &mockAuditRepo{}, nil, nil)
 
-	task, err := svc.CreateTask(context.Background(), "list-1", "Купить хлеб")
+	task, _, err := svc.CreateTask(context.Background(), "list-1", "Купить хлеб", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -91,10 +205,38 @@ func TestTaskService_CreateTask_Success(t *testing.T) {
 	}
 }
 
+func TestTaskService_CreateTask_RecordsAuditEvent(t *testing.T) {
+	audit := &mockAuditRepo{}
+	svc := service.NewTaskService(&mockTaskRepo{}, &mockListRepo{}, mockTransactor{}, audit, nil, nil)
+
+	ctx := reqctx.WithRequestID(reqctx.WithUserID(context.Background(), "olga"), "req-1")
+	task, _, err := svc.CreateTask(ctx, "list-1", "Купить хлеб", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(audit.events) != 1 {
+		t.Fatalf("expected 1 audit event, got %d", len(audit.events))
+	}
+	e := audit.events[0]
+	if e.Actor != "olga" || e.RequestID != "req-1" {
+		t.Errorf("expected actor olga and request req-1, got %q and %q", e.Actor, e.RequestID)
+	}
+	if e.EntityID != task.ID || e.ListID != "list-1" || e.Action != domain.ActionCreate {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e.Before != nil || e.After == nil {
+		t.Errorf("expected only after snapshot for create")
+	}
+	if change, ok := e.Diff["text"]; !ok || change.After != "Купить хлеб" {
+		t.Errorf("expected text in diff, got %+v", e.Diff)
+	}
+}
+
 func TestTaskService_CreateTask_ValidationError(t *testing.T) {
-	svc := service.NewTaskService(&mockTaskRepo{}, &mockListRepo{})
+	svc := service.NewTaskService(&mockTaskRepo{}, &mockListRepo{}, mockTransactor{}, &mockAuditRepo{}, nil, nil)
 
-	_, err := svc.CreateTask(context.Background(), "list-1", "")
+	_, _, err := svc.CreateTask(context.Background(), "list-1", "", nil)
 	if err == nil {
 		t.Fatalf("expected validation error, got nil")
 	}
@@ -108,10 +250,51 @@ func TestTaskService_CreateTask_ListNotFound(t *testing.T) {
 		},
 	}
 
-	svc := service.NewTaskService(taskRepo, listRepo)
+	svc := service.NewTaskService(taskRepo, listRepo, mockTransactor{}, &mockAuditRepo{}, nil, nil)
 
-	_, err := svc.CreateTask(context.Background(), "missing-list", "Задача")
+	_, _, err := svc.CreateTask(context.Background(), "missing-list", "Задача", nil)
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestTaskService_AssignTask(t *testing.T) {
+	stored := &domain.Task{ID: "task-1", ListID: "list-1", Text: "Счёт", Assignee: "olga"}
+	updates := 0
+	taskRepo := &mockTaskRepo{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Task, error) {
+			copied := *stored
+			return &copied, nil
+		},
+		updateFunc: func(ctx context.Context, task *domain.Task) error {
+			updates++
+			stored = task
+			return nil
+		},
+	}
+	audit := &mockAuditRepo{}
+	svc := service.NewTaskService(taskRepo, &mockListRepo{}, mockTransactor{}, audit, nil, nil)
+
+	// Тот же исполнитель — задача не меняется.
+	if _, _, err := svc.AssignTask(context.Background(), "task-1", "olga"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updates != 0 || len(audit.events) != 0 {
+		t.Fatalf("expected no changes, got %d updates and %d audit events", updates, len(audit.events))
+	}
+
+	task, _, err := svc.AssignTask(context.Background(), "task-1", "ivan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Assignee != "ivan" || updates != 1 {
+		t.Errorf("expected assignee ivan after one update, got %q after %d", task.Assignee, updates)
+	}
+	if change, ok := audit.events[0].Diff["assignee"]; !ok || change.Before != "olga" || change.After != "ivan" {
+		t.Errorf("expected assignee in diff, got %+v", audit.events[0].Diff)
+	}
+
+	if _, _, err := svc.AssignTask(context.Background(), "task-1", strings.Repeat("x", 101)); !errors.Is(err, service.ErrInvalidAssignee) {
+		t.Errorf("expected ErrInvalidAssignee, got %v", err)
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTemplate   = errors.New("invalid template")
+	ErrTemplateVariables = errors.New("template variables are not set")
+)
+
+// maxTemplateTasks ограничивает число задач в шаблоне.
+const maxTemplateTasks = 1000
+
+// templateVariable находит переменные {{имя}}; пробелы внутри скобок допускаются.
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TemplateInput — изменения шаблона; nil — поле не меняется.
+type TemplateInput struct {
+	Name        *string
+	Title       *string
+	Description *string
+	Tasks       []domain.TemplateTask
+	Shared      *bool
+}
+
+// TemplateService сохраняет списки как шаблоны и создаёт по ним новые
+// списки. Сроки задач отсчитываются в часовом поясе пользователя.
+type TemplateService struct {
+	repo     storage.TemplateRepository
+	taskRepo storage.TaskRepository
+	lists    ListService
+	tasks    *TaskService
+	tx       storage.Transactor
+	settings storage.NotificationRepository
+	now      func() time.Time
+}
+
+func NewTemplateService(repo storage.TemplateRepository, taskRepo storage.TaskRepository, lists ListService, tasks *TaskService, tx storage.Transactor, settings storage.NotificationRepository) *TemplateService {
+	return &TemplateService{repo: repo, taskRepo: taskRepo, lists: lists, tasks: tasks, tx: tx, settings: settings, now: time.Now}
+}
+
+// SaveList сохраняет список listID со всеми задачами как шаблон. Сроки
+// задач становятся смещениями от startDate (ГГГГ-ММ-ДД); без неё — от дня
+// самого раннего срока в списке.
+func (s *TemplateService) SaveList(ctx context.Context, ownerID, listID, name, startDate string, shared bool) (*domain.Template, error) {
+	loc, err := userLocation(ctx, s.settings)
+	if err != nil {
+		return nil, err
+	}
+	var start time.Time
+	if startDate != "" {
+		if start, err = time.ParseInLocation(time.DateOnly, startDate, loc); err != nil {
+			return nil, fmt.Errorf("%w: start_date must be YYYY-MM-DD", ErrInvalidTemplate)
+		}
+	}
+
+	list, err := s.lists.GetByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepo.ListAllByListID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if start.IsZero() {
+		for _, task := range tasks {
+			if task.DueAt == nil {
+				continue
+			}
+			if day := startOfDay(task.DueAt.In(loc)); start.IsZero() || day.Before(start) {
+				start = day
+			}
+		}
+	}
+
+	t := &domain.Template{
+		ID:          uuid.NewString(),
+		OwnerID:     ownerID,
+		Name:        name,
+		Title:       list.Title,
+		Description: list.Description,
+		Tasks:       make([]domain.TemplateTask, 0, len(tasks)),
+		Shared:      shared,
+	}
+	for _, task := range tasks {
+		tt := domain.TemplateTask{
+			Text:       task.Text,
+			Labels:     slices.Clone(task.Labels),
+			Priority:   task.Priority,
+			Recurrence: task.Recurrence,
+		}
+		if task.DueAt != nil {
+			due := task.DueAt.In(loc)
+			offset := daysBetween(start, due)
+			tt.DueOffsetDays = &offset
+			if h, m, _ := due.Clock(); h != 0 || m != 0 {
+				tt.DueTime = fmt.Sprintf("%02d:%02d", h, m)
+			}
+		}
+		t.Tasks = append(t.Tasks, tt)
+	}
+	if err := validateTemplate(t); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	t.Variables = templateVariables(t)
+	return t, nil
+}
+
+// ListTemplates возвращает шаблоны пользователя и общие шаблоны.
+func (s *TemplateService) ListTemplates(ctx context.Context, userID string) ([]*domain.Template, error) {
+	templates, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		t.Variables = templateVariables(t)
+	}
+	return templates, nil
+}
+
+func (s *TemplateService) GetTemplate(ctx context.Context, userID, id string) (*domain.Template, error) {
+	t, err := s.repo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	t.Variables = templateVariables(t)
+	return t, nil
+}
+
+// UpdateTemplate меняет свой шаблон; Tasks, если заданы, заменяют задачи
+// шаблона целиком.
+func (s *TemplateService) UpdateTemplate(ctx context.Context, ownerID, id string, in TemplateInput) (*domain.Template, error) {
+	t, err := s.repo.Get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		t.Name = *in.Name
+	}
+	if in.Title != nil {
+		t.Title = *in.Title
+	}
+	if in.Description != nil {
+		t.Description = *in.Description
+	}
+	if in.Tasks != nil {
+		t.Tasks = in.Tasks
+	}
+	if in.Shared != nil {
+		t.Shared = *in.Shared
+	}
+	if err := validateTemplate(t); err != nil {
+		return nil, err
+	}
+	// Чужой общий шаблон репозиторий не обновит и вернёт ErrNotFound.
+	t.OwnerID = ownerID
+	if err := s.repo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	t.Variables = templateVariables(t)
+	return t, nil
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, ownerID, id string) error {
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+// Instantiate создаёт по шаблону новый список с задачами. vars задают
+// значения переменных; start_date (ГГГГ-ММ-ДД) по умолчанию — сегодня.
+// Без значения хотя бы одной переменной шаблона возвращается
+// ErrTemplateVariables с перечнем недостающих.
+func (s *TemplateService) Instantiate(ctx context.Context, userID, id string, vars map[string]string) (*domain.List, []*domain.Task, error) {
+	t, err := s.repo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := userLocation(ctx, s.settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := startOfDay(s.now().In(loc))
+	if v, ok := vars[domain.TemplateStartDate]; ok {
+		if start, err = time.ParseInLocation(time.DateOnly, v, loc); err != nil {
+			return nil, nil, fmt.Errorf("%w: start_date must be YYYY-MM-DD", ErrInvalidTemplate)
+		}
+	}
+	values := map[string]string{domain.TemplateStartDate: start.Format(time.DateOnly)}
+	var missing []string
+	for _, name := range templateVariables(t) {
+		if name == domain.TemplateStartDate {
+			continue
+		}
+		v, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		values[name] = v
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrTemplateVariables, strings.Join(missing, ", "))
+	}
+	expand := func(text string) string {
+		return templateVariable.ReplaceAllStringFunc(text, func(m string) string {
+			return values[templateVariable.FindStringSubmatch(m)[1]]
+		})
+	}
+
+	var (
+		list  *domain.List
+		tasks = make([]*domain.Task, 0, len(t.Tasks))
+	)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		list, _, err = s.lists.CreateList(ctx, expand(t.Title), expand(t.Description))
+		if err != nil {
+			return err
+		}
+		for _, tt := range t.Tasks {
+			tasks = append(tasks, &domain.Task{
+				ListID:     list.ID,
+				Text:       expand(tt.Text),
+				DueAt:      templateDue(tt, start),
+				Labels:     slices.Clone(tt.Labels),
+				Priority:   tt.Priority,
+				Recurrence: tt.Recurrence,
+			})
+		}
+		// Задачи нового списка вставляются одним запросом, как при импорте.
+		return s.tasks.createTasks(ctx, tasks)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, tasks, nil
+}
+
+// validateTemplate проверяет шаблон до подстановки переменных.
+func validateTemplate(t *domain.Template) error {
+	if len(t.Name) < 1 || len(t.Name) > 100 {
+		return fmt.Errorf("%w: name must be 1..100 chars", ErrInvalidTemplate)
+	}
+	if len(t.Title) < 1 || len(t.Title) > 100 {
+		return fmt.Errorf("%w: title must be 1..100 chars", ErrInvalidTemplate)
+	}
+	if len(t.Tasks) > maxTemplateTasks {
+		return fmt.Errorf("%w: at most %d tasks", ErrInvalidTemplate, maxTemplateTasks)
+	}
+	for i, tt := range t.Tasks {
+		if err := validateTemplateTask(tt); err != nil {
+			return fmt.Errorf("%w: tasks[%d]: %s", ErrInvalidTemplate, i, err)
+		}
+	}
+	return nil
+}
+
+func validateTemplateTask(tt domain.TemplateTask) error {
+	if len(tt.Text) < 1 || len(tt.Text) > 500 {
+		return errors.New("text must be 1..500 chars")
+	}
+	if tt.DueOffsetDays == nil && tt.DueTime != "" {
+		return errors.New("due_time requires due_offset_days")
+	}
+	if tt.DueOffsetDays != nil && (*tt.DueOffsetDays < -3650 || *tt.DueOffsetDays > 3650) {
+		return errors.New("due_offset_days must be within ±3650")
+	}
+	if tt.DueTime != "" {
+		if _, err := time.Parse("15:04", tt.DueTime); err != nil {
+			return errors.New("due_time must be HH:MM")
+		}
+	}
+	if tt.Priority != "" && !slices.Contains([]string{domain.TaskPriorityLow, domain.TaskPriorityMedium, domain.TaskPriorityHigh}, tt.Priority) {
+		return fmt.Errorf("unknown priority %q", tt.Priority)
+	}
+	if tt.Recurrence != "" && !slices.Contains([]string{domain.RecurrenceDaily, domain.RecurrenceWeekly, domain.RecurrenceMonthly, domain.RecurrenceYearly}, tt.Recurrence) {
+		return fmt.Errorf("unknown recurrence %q", tt.Recurrence)
+	}
+	return nil
+}
+
+// templateVariables возвращает отсортированные имена переменных шаблона.
+func templateVariables(t *domain.Template) []string {
+	seen := map[string]bool{}
+	collect := func(text string) {
+		for _, m := range templateVariable.FindAllStringSubmatch(text, -1) {
+			seen[m[1]] = true
+		}
+	}
+	collect(t.Title)
+	collect(t.Description)
+	for _, tt := range t.Tasks {
+		collect(tt.Text)
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateDue переводит смещение задачи шаблона в срок от дня start.
+func templateDue(tt domain.TemplateTask, start time.Time) *time.Time {
+	if tt.DueOffsetDays == nil {
+		return nil
+	}
+	day := start.AddDate(0, 0, *tt.DueOffsetDays)
+	var clock time.Time
+	if tt.DueTime != "" {
+		clock, _ = time.Parse("15:04", tt.DueTime)
+	}
+	due := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, start.Location())
+	return &due
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween считает календарные дни от from до to без учёта перехода
+// на летнее время.
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,242 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+)
+
+// mockTemplateRepo хранит шаблоны в памяти с теми же правилами видимости,
+// что и postgres.TemplateRepo.
+type mockTemplateRepo struct {
+	templates map[string]*domain.Template
+}
+
+func (m *mockTemplateRepo) Create(ctx context.Context, t *domain.Template) error {
+	copied := *t
+	m.templates[t.ID] = &copied
+	return nil
+}
+
+func (m *mockTemplateRepo) Get(ctx context.Context, userID, id string) (*domain.Template, error) {
+	t, ok := m.templates[id]
+	if !ok || (t.OwnerID != userID && !t.Shared) {
+		return nil, storage.ErrNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (m *mockTemplateRepo) List(ctx context.Context, userID string) ([]*domain.Template, error) {
+	var res []*domain.Template
+	for _, t := range m.templates {
+		if t.OwnerID == userID || t.Shared {
+			copied := *t
+			res = append(res, &copied)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockTemplateRepo) Update(ctx context.Context, t *domain.Template) error {
+	stored, ok := m.templates[t.ID]
+	if !ok || stored.OwnerID != t.OwnerID {
+		return storage.ErrNotFound
+	}
+	copied := *t
+	m.templates[t.ID] = &copied
+	return nil
+}
+
+func (m *mockTemplateRepo) Delete(ctx context.Context, ownerID, id string) error {
+	t, ok := m.templates[id]
+	if !ok || t.OwnerID != ownerID {
+		return storage.ErrNotFound
+	}
+	delete(m.templates, id)
+	return nil
+}
+
+func newTemplateService(t *testing.T, tasks map[string]*domain.Task) (*service.TemplateService, *mockTemplateRepo, *mockTaskRepo, *time.Location) {
+	t.Helper()
+	msk, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	taskRepo := memTaskRepo(tasks)
+	taskRepo.listAllFunc = func(ctx context.Context, listID string) ([]*domain.Task, error) {
+		var res []*domain.Task
+		for _, task := range tasks {
+			if task.ListID == listID {
+				res = append(res, task)
+			}
+		}
+		slices.SortFunc(res, func(a, b *domain.Task) int { return strings.Compare(a.ID, b.ID) })
+		return res, nil
+	}
+	settings := newMockNotificationRepo()
+	settings.settings["olga"] = &domain.NotificationSettings{UserID: "olga", TimeZone: "Europe/Moscow"}
+	settings.settings["ivan"] = &domain.NotificationSettings{UserID: "ivan", TimeZone: "Europe/Moscow"}
+
+	repo := &mockTemplateRepo{templates: map[string]*domain.Template{}}
+	listRepo := &mockListRepo{}
+	lists := service.NewListService(listRepo, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil, nil)
+	taskSvc := service.NewTaskService(taskRepo, listRepo, mockTransactor{}, &mockAuditRepo{}, nil, nil)
+	return service.NewTemplateService(repo, taskRepo, lists, taskSvc, mockTransactor{}, settings), repo, taskRepo, msk
+}
+
+func TestTemplateService_SaveListStoresRelativeDueDates(t *testing.T) {
+	tasks := map[string]*domain.Task{}
+	svc, _, _, msk := newTemplateService(t, tasks)
+	due := func(day, hour, minute int) *time.Time {
+		d := time.Date(2026, 11, day, hour, minute, 0, 0, msk).UTC()
+		return &d
+	}
+	tasks["t1"] = &domain.Task{ID: "t1", ListID: "l1", Text: "Кикофф", DueAt: due(2, 10, 30), Completed: true}
+	tasks["t2"] = &domain.Task{ID: "t2", ListID: "l1", Text: "Договор", DueAt: due(4, 0, 0), Priority: domain.TaskPriorityHigh, Labels: []string{"legal"}}
+	tasks["t3"] = &domain.Task{ID: "t3", ListID: "l1", Text: "Ретро"}
+	tasks["t4"] = &domain.Task{ID: "t4", ListID: "other", Text: "Чужая"}
+
+	ctx := reqctx.WithUserID(context.Background(), "olga")
+	tpl, err := svc.SaveList(ctx, "olga", "l1", "Онбординг", "", false)
+	if err != nil {
+		t.Fatalf("SaveList: %v", err)
+	}
+	if tpl.Title != "Test List" || len(tpl.Tasks) != 3 {
+		t.Fatalf("unexpected template %+v", tpl)
+	}
+	offset := func(tt domain.TemplateTask) any {
+		if tt.DueOffsetDays == nil {
+			return nil
+		}
+		return *tt.DueOffsetDays
+	}
+	want := []struct {
+		offset any
+		time   string
+	}{{0, "10:30"}, {2, ""}, {nil, ""}}
+	for i, w := range want {
+		if got := tpl.Tasks[i]; offset(got) != w.offset || got.DueTime != w.time {
+			t.Errorf("tasks[%d] = %v %q, want %v %q", i, offset(got), got.DueTime, w.offset, w.time)
+		}
+	}
+	if tpl.Tasks[1].Priority != domain.TaskPriorityHigh || !slices.Equal(tpl.Tasks[1].Labels, []string{"legal"}) {
+		t.Errorf("tasks[1] = %+v", tpl.Tasks[1])
+	}
+
+	tpl, err = svc.SaveList(ctx, "olga", "l1", "Онбординг", "2026-10-30", false)
+	if err != nil {
+		t.Fatalf("SaveList: %v", err)
+	}
+	if offset(tpl.Tasks[0]) != 3 || offset(tpl.Tasks[1]) != 5 {
+		t.Errorf("offsets from start_date = %v, %v; want 3, 5", offset(tpl.Tasks[0]), offset(tpl.Tasks[1]))
+	}
+
+	if _, err := svc.SaveList(ctx, "olga", "l1", "Онбординг", "30.10.2026", false); !errors.Is(err, service.ErrInvalidTemplate) {
+		t.Errorf("expected ErrInvalidTemplate for start_date, got %v", err)
+	}
+}
+
+func TestTemplateService_InstantiateSubstitutesVariables(t *testing.T) {
+	tasks := map[string]*domain.Task{}
+	svc, repo, taskRepo, msk := newTemplateService(t, tasks)
+	batches := 0
+	taskRepo.createManyFunc = func(ctx context.Context, batch []*domain.Task) error {
+		batches++
+		for _, task := range batch {
+			copied := *task
+			tasks[task.ID] = &copied
+		}
+		return nil
+	}
+	ctx := reqctx.WithUserID(context.Background(), "olga")
+	days := func(n int) *int { return &n }
+	repo.templates["tpl"] = &domain.Template{ID: "tpl", OwnerID: "olga", Name: "Онбординг", Title: "Онбординг {{ client }}",
+		Description: "Старт {{start_date}}",
+		Tasks: []domain.TemplateTask{
+			{Text: "Созвон с {{client}}", DueOffsetDays: days(0), DueTime: "10:00"},
+			{Text: "Договор", DueOffsetDays: days(2), Priority: domain.TaskPriorityHigh, Labels: []string{"legal"}},
+			{Text: "Ретро"},
+		}}
+
+	tpl, err := svc.GetTemplate(ctx, "olga", "tpl")
+	if err != nil {
+		t.Fatalf("GetTemplate: %v", err)
+	}
+	if !slices.Equal(tpl.Variables, []string{"client", "start_date"}) {
+		t.Errorf("variables = %v", tpl.Variables)
+	}
+
+	_, _, err = svc.Instantiate(ctx, "olga", "tpl", nil)
+	if !errors.Is(err, service.ErrTemplateVariables) || !strings.Contains(err.Error(), "client") {
+		t.Fatalf("expected ErrTemplateVariables naming client, got %v", err)
+	}
+
+	list, created, err := svc.Instantiate(ctx, "olga", "tpl", map[string]string{"client": "Ромашка", "start_date": "2026-12-01"})
+	if err != nil {
+		t.Fatalf("Instantiate: %v", err)
+	}
+	if list.Title != "Онбординг Ромашка" || list.Description != "Старт 2026-12-01" {
+		t.Errorf("list = %q, %q", list.Title, list.Description)
+	}
+	if len(created) != 3 || created[0].Text != "Созвон с Ромашка" || created[0].ListID != list.ID {
+		t.Fatalf("tasks = %+v", created)
+	}
+	if want := time.Date(2026, 12, 1, 10, 0, 0, 0, msk); !created[0].DueAt.Equal(want) {
+		t.Errorf("tasks[0].due_at = %v, want %v", created[0].DueAt, want)
+	}
+	if want := time.Date(2026, 12, 3, 0, 0, 0, 0, msk); !created[1].DueAt.Equal(want) {
+		t.Errorf("tasks[1].due_at = %v, want %v", created[1].DueAt, want)
+	}
+	if created[1].Priority != domain.TaskPriorityHigh || created[2].DueAt != nil || created[2].Completed {
+		t.Errorf("tasks = %+v, %+v", created[1], created[2])
+	}
+	if len(tasks) != 3 || batches != 1 {
+		t.Errorf("stored %d tasks in %d batches, want 3 in 1", len(tasks), batches)
+	}
+}
+
+func TestTemplateService_SharedTemplates(t *testing.T) {
+	svc, repo, _, _ := newTemplateService(t, map[string]*domain.Task{})
+	repo.templates["shared"] = &domain.Template{ID: "shared", OwnerID: "olga", Name: "Общий", Title: "Спринт", Shared: true}
+	repo.templates["private"] = &domain.Template{ID: "private", OwnerID: "olga", Name: "Личный", Title: "Дом"}
+
+	ctx := reqctx.WithUserID(context.Background(), "ivan")
+	templates, err := svc.ListTemplates(ctx, "ivan")
+	if err != nil {
+		t.Fatalf("ListTemplates: %v", err)
+	}
+	if len(templates) != 1 || templates[0].ID != "shared" {
+		t.Fatalf("ivan sees %+v, want only the shared template", templates)
+	}
+	if _, _, err := svc.Instantiate(ctx, "ivan", "shared", nil); err != nil {
+		t.Errorf("Instantiate shared: %v", err)
+	}
+	if _, err := svc.GetTemplate(ctx, "ivan", "private"); err == nil {
+		t.Error("expected private template to be hidden")
+	}
+
+	name := "Мой"
+	if _, err := svc.UpdateTemplate(ctx, "ivan", "shared", service.TemplateInput{Name: &name}); err == nil {
+		t.Error("expected update of someone else's template to fail")
+	}
+	if err := svc.DeleteTemplate(ctx, "ivan", "shared"); err == nil {
+		t.Error("expected delete of someone else's template to fail")
+	}
+	if repo.templates["shared"].Name != "Общий" {
+		t.Errorf("shared template changed: %+v", repo.templates["shared"])
+	}
+
+	bad := []domain.TemplateTask{{Text: "Созвон", DueTime: "10:00"}}
+	if _, err := svc.UpdateTemplate(reqctx.WithUserID(context.Background(), "olga"), "olga", "private", service.TemplateInput{Tasks: bad}); !errors.Is(err, service.ErrInvalidTemplate) {
+		t.Errorf("expected ErrInvalidTemplate, got %v", err)
+	}
+}
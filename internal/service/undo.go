@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// UndoLog выдаёт токены отмены для изменений списков и задач.
+// nil-журнал допустим: изменения выполняются без токена.
+type UndoLog struct {
+	repo   storage.UndoRepository
+	window time.Duration
+}
+
+func NewUndoLog(repo storage.UndoRepository, window time.Duration) *UndoLog {
+	return &UndoLog{repo: repo, window: window}
+}
+
+// record сохраняет состояние до и после операции и возвращает токен.
+// Вызывается внутри транзакции изменения.
+func (l *UndoLog) record(ctx context.Context, action string, before, after domain.Snapshot) (string, error) {
+	if l == nil {
+		return "", nil
+	}
+	entry := &domain.UndoEntry{
+		Token:     uuid.NewString(),
+		Actor:     reqctx.UserID(ctx),
+		Action:    action,
+		Before:    before,
+		After:     after,
+		ExpiresAt: time.Now().Add(l.window),
+	}
+	if err := l.repo.Create(ctx, entry); err != nil {
+		return "", err
+	}
+	return entry.Token, nil
+}
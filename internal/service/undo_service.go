@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+)
+
+var (
+	ErrUndoNotFound = errors.New("undo token not found")
+	ErrUndoExpired  = errors.New("undo window has expired")
+	ErrUndoConflict = errors.New("changed since the operation, cannot undo")
+)
+
+type UndoService struct {
+	repo     storage.UndoRepository
+	listRepo storage.ListRepository
+	taskRepo storage.TaskRepository
+	tx       storage.Transactor
+	audit    storage.AuditRepository
+	hooks    ChangeHook
+}
+
+func NewUndoService(repo storage.UndoRepository, listRepo storage.ListRepository, taskRepo storage.TaskRepository, tx storage.Transactor, audit storage.AuditRepository, hooks ChangeHook) *UndoService {
+	return &UndoService{repo: repo, listRepo: listRepo, taskRepo: taskRepo, tx: tx, audit: audit, hooks: hooks}
+}
+
+// Undo возвращает сущности в состояние до операции одной транзакцией.
+// Если хоть одна из них изменилась после операции, не меняется ничего.
+func (s *UndoService) Undo(ctx context.Context, token string) (*domain.UndoResult, error) {
+	var res *domain.UndoResult
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entry, err := s.repo.GetForUpdate(ctx, token)
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrUndoNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if entry.Actor != reqctx.UserID(ctx) {
+			return ErrUndoNotFound
+		}
+		if entry.RedeemedAt != nil {
+			return ErrUndoConflict
+		}
+		if time.Now().After(entry.ExpiresAt) {
+			return ErrUndoExpired
+		}
+
+		res, err = s.revert(ctx, entry)
+		if err != nil {
+			return err
+		}
+		return s.repo.MarkRedeemed(ctx, entry.Token)
+	})
+	if errors.Is(err, storage.ErrConflict) {
+		return nil, ErrUndoConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PurgeExpired удаляет записи отмены, у которых истекло окно отмены:
+// истёкшим токеном воспользоваться уже нельзя.
+func (s *UndoService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.PurgeExpired(ctx, time.Now())
+}
+
+// RunPurge раз в interval удаляет истёкшие записи отмены, пока не отменён ctx.
+func (s *UndoService) RunPurge(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := s.PurgeExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Info("undo purge: " + err.Error())
+		}
+		if n > 0 {
+			logger.Info(fmt.Sprintf("undo: purged %d expired entries", n))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// revert удаляет созданные операцией сущности и восстанавливает изменённые
+// и удалённые. Задачи удаляются раньше списков и восстанавливаются после них.
+// Восстановленные сущности получают новую версию, а не прежнюю, чтобы
+// версия строки только росла.
+func (s *UndoService) revert(ctx context.Context, e *domain.UndoEntry) (*domain.UndoResult, error) {
+	res := &domain.UndoResult{
+		Action:   e.Action,
+		Restored: domain.Snapshot{Lists: []*domain.List{}, Tasks: []*domain.Task{}},
+		Removed:  domain.Snapshot{Lists: []*domain.List{}, Tasks: []*domain.Task{}},
+	}
+
+	beforeLists := make(map[string]*domain.List, len(e.Before.Lists))
+	for _, l := range e.Before.Lists {
+		beforeLists[l.ID] = l
+	}
+	beforeTasks := make(map[string]*domain.Task, len(e.Before.Tasks))
+	for _, t := range e.Before.Tasks {
+		beforeTasks[t.ID] = t
+	}
+	afterLists := make(map[string]*domain.List, len(e.After.Lists))
+	for _, l := range e.After.Lists {
+		afterLists[l.ID] = l
+	}
+	afterTasks := make(map[string]*domain.Task, len(e.After.Tasks))
+	for _, t := range e.After.Tasks {
+		afterTasks[t.ID] = t
+	}
+
+	for _, t := range e.After.Tasks {
+		if _, ok := beforeTasks[t.ID]; ok {
+			continue
+		}
+		if err := s.taskRepo.DeleteVersion(ctx, t.ID, t.Version); err != nil {
+			return nil, err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, t.ID, t.ListID, domain.ActionDelete, t, nil); err != nil {
+			return nil, err
+		}
+		res.Removed.Tasks = append(res.Removed.Tasks, t)
+	}
+
+	for _, l := range e.After.Lists {
+		if _, ok := beforeLists[l.ID]; ok {
+			continue
+		}
+		if err := s.listRepo.DeleteVersion(ctx, l.ID, l.Version); err != nil {
+			return nil, err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, l.ID, l.ID, domain.ActionDelete, l, nil); err != nil {
+			return nil, err
+		}
+		res.Removed.Lists = append(res.Removed.Lists, l)
+	}
+
+	for _, l := range e.Before.Lists {
+		restored := *l
+		var expected int64
+		action := domain.ActionCreate
+		var current *domain.List
+		if after, ok := afterLists[l.ID]; ok {
+			expected = after.Version
+			action = domain.ActionUpdate
+			current = after
+			restored.Version = after.Version + 1
+		} else {
+			restored.Version = l.Version + 1
+		}
+		if err := s.listRepo.Restore(ctx, &restored, expected); err != nil {
+			return nil, err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityList, l.ID, l.ID, action, current, &restored); err != nil {
+			return nil, err
+		}
+		res.Restored.Lists = append(res.Restored.Lists, &restored)
+	}
+
+	for _, t := range e.Before.Tasks {
+		restored := *t
+		var expected int64
+		action := domain.ActionCreate
+		var current *domain.Task
+		if after, ok := afterTasks[t.ID]; ok {
+			expected = after.Version
+			action = domain.ActionUpdate
+			current = after
+			restored.Version = after.Version + 1
+		} else {
+			restored.Version = t.Version + 1
+		}
+		if err := s.taskRepo.Restore(ctx, &restored, expected); err != nil {
+			return nil, err
+		}
+		if err := recordChange(ctx, s.audit, s.hooks, domain.EntityTask, t.ID, t.ListID, action, current, &restored); err != nil {
+			return nil, err
+		}
+		res.Restored.Tasks = append(res.Restored.Tasks, &restored)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,179 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/service"
+	"todo-api/internal/storage"
+)
+
+type mockUndoRepo struct {
+	entries map[string]*domain.UndoEntry
+	getErr  error
+}
+
+func (m *mockUndoRepo) Create(ctx context.Context, entry *domain.UndoEntry) error {
+	if m.entries == nil {
+		m.entries = map[string]*domain.UndoEntry{}
+	}
+	m.entries[entry.Token] = entry
+	return nil
+}
+
+func (m *mockUndoRepo) GetForUpdate(ctx context.Context, token string) (*domain.UndoEntry, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	e, ok := m.entries[token]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return e, nil
+}
+
+func (m *mockUndoRepo) MarkRedeemed(ctx context.Context, token string) error {
+	now := time.Now()
+	m.entries[token].RedeemedAt = &now
+	return nil
+}
+
+func (m *mockUndoRepo) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	for token, e := range m.entries {
+		if e.ExpiresAt.Before(before) {
+			delete(m.entries, token)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// completeTwoTasks выполняет массовое завершение двух задач и возвращает токен.
+func completeTwoTasks(t *testing.T, ctx context.Context, undo *mockUndoRepo, window time.Duration) (string, *mockTaskRepo) {
+	t.Helper()
+	taskRepo := &mockTaskRepo{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Task, error) {
+			return &domain.Task{ID: id, ListID: "list-1", Text: "Задача " + id, Version: 1}, nil
+		},
+		updateFunc: func(ctx context.Context, task *domain.Task) error {
+			task.Version++
+			return nil
+		},
+	}
+	svc := service.NewTaskService(taskRepo, &mockListRepo{}, mockTransactor{}, &mockAuditRepo{}, service.NewUndoLog(undo, window), nil)
+
+	tasks, token, err := svc.CompleteTasks(ctx, "list-1", []string{"t1", "t2", "t1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 2 || token == "" {
+		t.Fatalf("expected 2 completed tasks and a token, got %d and %q", len(tasks), token)
+	}
+	return token, taskRepo
+}
+
+func TestUndoService_RevertsBulkComplete(t *testing.T) {
+	ctx := reqctx.WithUserID(context.Background(), "olga")
+	undo := &mockUndoRepo{}
+	token, taskRepo := completeTwoTasks(t, ctx, undo, time.Minute)
+
+	restored := map[string]int64{}
+	taskRepo.restoreFunc = func(ctx context.Context, task *domain.Task, expectedVersion int64) error {
+		if task.Completed {
+			t.Errorf("task %s restored as completed", task.ID)
+		}
+		if task.Version != expectedVersion+1 {
+			t.Errorf("expected version %d, got %d", expectedVersion+1, task.Version)
+		}
+		restored[task.ID] = expectedVersion
+		return nil
+	}
+	svc := service.NewUndoService(undo, &mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil)
+
+	res, err := svc.Undo(ctx, token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Restored.Tasks) != 2 || restored["t1"] != 2 || restored["t2"] != 2 {
+		t.Errorf("expected both tasks restored at version 2, got %+v", restored)
+	}
+
+	if _, err := svc.Undo(ctx, token); !errors.Is(err, service.ErrUndoConflict) {
+		t.Errorf("expected ErrUndoConflict on second undo, got %v", err)
+	}
+}
+
+func TestUndoService_Errors(t *testing.T) {
+	ctx := reqctx.WithUserID(context.Background(), "olga")
+
+	t.Run("expired", func(t *testing.T) {
+		undo := &mockUndoRepo{}
+		token, taskRepo := completeTwoTasks(t, ctx, undo, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		svc := service.NewUndoService(undo, &mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil)
+		if _, err := svc.Undo(ctx, token); !errors.Is(err, service.ErrUndoExpired) {
+			t.Errorf("expected ErrUndoExpired, got %v", err)
+		}
+	})
+
+	t.Run("changed since", func(t *testing.T) {
+		undo := &mockUndoRepo{}
+		token, taskRepo := completeTwoTasks(t, ctx, undo, time.Minute)
+		taskRepo.restoreFunc = func(ctx context.Context, task *domain.Task, expectedVersion int64) error {
+			return storage.ErrConflict
+		}
+		svc := service.NewUndoService(undo, &mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil)
+		if _, err := svc.Undo(ctx, token); !errors.Is(err, service.ErrUndoConflict) {
+			t.Errorf("expected ErrUndoConflict, got %v", err)
+		}
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		undo := &mockUndoRepo{}
+		token, taskRepo := completeTwoTasks(t, ctx, undo, time.Minute)
+		svc := service.NewUndoService(undo, &mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil)
+		if _, err := svc.Undo(reqctx.WithUserID(context.Background(), "ivan"), token); !errors.Is(err, service.ErrUndoNotFound) {
+			t.Errorf("expected ErrUndoNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		svc := service.NewUndoService(&mockUndoRepo{}, &mockListRepo{}, &mockTaskRepo{}, mockTransactor{}, &mockAuditRepo{}, nil)
+		if _, err := svc.Undo(ctx, "missing"); !errors.Is(err, service.ErrUndoNotFound) {
+			t.Errorf("expected ErrUndoNotFound, got %v", err)
+		}
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		dbErr := errors.New("connection reset")
+		svc := service.NewUndoService(&mockUndoRepo{getErr: dbErr}, &mockListRepo{}, &mockTaskRepo{}, mockTransactor{}, &mockAuditRepo{}, nil)
+		_, err := svc.Undo(ctx, "token")
+		if !errors.Is(err, dbErr) || errors.Is(err, service.ErrUndoNotFound) {
+			t.Errorf("expected storage error, got %v", err)
+		}
+	})
+}
+
+func TestUndoService_PurgeExpired(t *testing.T) {
+	ctx := reqctx.WithUserID(context.Background(), "olga")
+	undo := &mockUndoRepo{}
+	expired, _ := completeTwoTasks(t, ctx, undo, time.Nanosecond)
+	live, taskRepo := completeTwoTasks(t, ctx, undo, time.Minute)
+	time.Sleep(time.Millisecond)
+
+	svc := service.NewUndoService(undo, &mockListRepo{}, taskRepo, mockTransactor{}, &mockAuditRepo{}, nil)
+	if n, err := svc.PurgeExpired(ctx); err != nil || n != 1 {
+		t.Fatalf("PurgeExpired = %d, %v; want 1", n, err)
+	}
+	if _, ok := undo.entries[expired]; ok {
+		t.Error("expired entry was not purged")
+	}
+	if _, ok := undo.entries[live]; !ok {
+		t.Error("live entry was purged")
+	}
+}
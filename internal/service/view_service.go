@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidViewName = errors.New("name must be 1..100 chars")
+	ErrInvalidFilter   = errors.New("invalid filter")
+	ErrBuiltInView     = errors.New("built-in views are read-only")
+)
+
+// maxViewTasksLimit ограничивает размер страницы задач представления.
+const maxViewTasksLimit = 100
+
+var builtInViews = []domain.View{
+	{
+		ID:      "today",
+		Name:    "Today",
+		Filter:  domain.TaskFilter{DueIn: domain.PeriodToday, Completed: boolPtr(false)},
+		BuiltIn: true,
+	},
+	{
+		ID:      "upcoming",
+		Name:    "Upcoming",
+		Filter:  domain.TaskFilter{DueIn: domain.PeriodUpcoming, Completed: boolPtr(false)},
+		BuiltIn: true,
+	},
+	{
+		ID:      "completed",
+		Name:    "Completed",
+		Filter:  domain.TaskFilter{Completed: boolPtr(true)},
+		BuiltIn: true,
+	},
+}
+
+type ViewService struct {
+	repo     storage.ViewRepository
+	taskRepo storage.TaskRepository
+}
+
+func NewViewService(repo storage.ViewRepository, taskRepo storage.TaskRepository) *ViewService {
+	return &ViewService{repo: repo, taskRepo: taskRepo}
+}
+
+// ListViews возвращает встроенные представления, а за ними — сохранённые пользователем.
+func (s *ViewService) ListViews(ctx context.Context, ownerID string) ([]*domain.View, error) {
+	views := make([]*domain.View, 0, len(builtInViews))
+	for i := range builtInViews {
+		view := builtInViews[i]
+		views = append(views, &view)
+	}
+
+	own, err := s.repo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return append(views, own...), nil
+}
+
+func (s *ViewService) GetView(ctx context.Context, ownerID, id string) (*domain.View, error) {
+	if view, ok := findBuiltInView(id); ok {
+		return view, nil
+	}
+	return s.repo.GetByID(ctx, ownerID, id)
+}
+
+func (s *ViewService) CreateView(ctx context.Context, ownerID, name string, filter domain.TaskFilter) (*domain.View, error) {
+	if len(name) < 1 || len(name) > 100 {
+		return nil, ErrInvalidViewName
+	}
+	if _, err := resolveFilter(filter, time.Now()); err != nil {
+		return nil, err
+	}
+
+	view := &domain.View{
+		ID:      uuid.NewString(),
+		OwnerID: ownerID,
+		Name:    name,
+		Filter:  filter,
+	}
+	if err := s.repo.Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// UpdateView меняет название и/или фильтр. nil-поля остаются без изменений.
+func (s *ViewService) UpdateView(ctx context.Context, ownerID, id string, name *string, filter *domain.TaskFilter) (*domain.View, error) {
+	if _, ok := findBuiltInView(id); ok {
+		return nil, ErrBuiltInView
+	}
+
+	view, err := s.repo.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		if len(*name) < 1 || len(*name) > 100 {
+			return nil, ErrInvalidViewName
+		}
+		view.Name = *name
+	}
+	if filter != nil {
+		if _, err := resolveFilter(*filter, time.Now()); err != nil {
+			return nil, err
+		}
+		view.Filter = *filter
+	}
+
+	if err := s.repo.Update(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *ViewService) DeleteView(ctx context.Context, ownerID, id string) error {
+	if _, ok := findBuiltInView(id); ok {
+		return ErrBuiltInView
+	}
+	return s.repo.Delete(ctx, ownerID, id)
+}
+
+// ViewTasks вычисляет фильтр представления на текущий момент и возвращает
+// подходящие задачи с той же пагинацией, что и TaskService.ListTasks;
+// limit не больше 100.
+func (s *ViewService) ViewTasks(ctx context.Context, ownerID, id string, limit, offset int) ([]*domain.Task, int, error) {
+	view, err := s.GetView(ctx, ownerID, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, err := resolveFilter(view.Filter, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.taskRepo.FindByQuery(ctx, query, min(limit, maxViewTasksLimit), offset)
+}
+
+func findBuiltInView(id string) (*domain.View, bool) {
+	for i := range builtInViews {
+		if builtInViews[i].ID == id {
+			view := builtInViews[i]
+			return &view, true
+		}
+	}
+	return nil, false
+}
+
+// resolveFilter проверяет фильтр и переводит относительные периоды
+// в абсолютные границы относительно now (UTC).
+func resolveFilter(f domain.TaskFilter, now time.Time) (domain.TaskQuery, error) {
+	q := domain.TaskQuery{
+		ListIDs:   f.ListIDs,
+		Completed: f.Completed,
+		Text:      f.Text,
+	}
+
+	if len(f.ListIDs) > 100 {
+		return q, fmt.Errorf("%w: at most 100 list_ids", ErrInvalidFilter)
+	}
+	for _, id := range f.ListIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return q, fmt.Errorf("%w: list_ids must be UUIDs", ErrInvalidFilter)
+		}
+	}
+	if len(f.Text) > 200 {
+		return q, fmt.Errorf("%w: text must be at most 200 chars", ErrInvalidFilter)
+	}
+
+	if f.CreatedIn != "" {
+		if f.CreatedFrom != nil || f.CreatedTo != nil {
+			return q, fmt.Errorf("%w: created_in cannot be combined with created_from/created_to", ErrInvalidFilter)
+		}
+		if f.CreatedIn == domain.PeriodNoDueDate {
+			return q, fmt.Errorf("%w: created_in cannot be %q", ErrInvalidFilter, f.CreatedIn)
+		}
+		from, to, err := periodRange(f.CreatedIn, now)
+		if err != nil {
+			return q, err
+		}
+		q.CreatedFrom, q.CreatedTo = from, to
+	} else {
+		q.CreatedFrom, q.CreatedTo = f.CreatedFrom, f.CreatedTo
+	}
+
+	if f.DueIn != "" {
+		if f.DueFrom != nil || f.DueTo != nil {
+			return q, fmt.Errorf("%w: due_in cannot be combined with due_from/due_to", ErrInvalidFilter)
+		}
+		if f.DueIn == domain.PeriodNoDueDate {
+			q.NoDueDate = true
+			return q, nil
+		}
+		from, to, err := periodRange(f.DueIn, now)
+		if err != nil {
+			return q, err
+		}
+		q.DueFrom, q.DueTo = from, to
+	} else {
+		q.DueFrom, q.DueTo = f.DueFrom, f.DueTo
+	}
+
+	return q, nil
+}
+
+// periodRange возвращает полуинтервал [from, to); nil означает отсутствие границы.
+func periodRange(p domain.Period, now time.Time) (*time.Time, *time.Time, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	span := func(from, to time.Time) (*time.Time, *time.Time, error) {
+		return &from, &to, nil
+	}
+
+	switch p {
+	case domain.PeriodToday:
+		return span(today, today.AddDate(0, 0, 1))
+	case domain.PeriodThisWeek:
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return span(monday, monday.AddDate(0, 0, 7))
+	case domain.PeriodThisMonth:
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return span(first, first.AddDate(0, 1, 0))
+	case domain.PeriodLast7Days:
+		return span(now.AddDate(0, 0, -7), now)
+	case domain.PeriodNext7Days:
+		return span(now, now.AddDate(0, 0, 7))
+	case domain.PeriodOverdue:
+		return nil, &now, nil
+	case domain.PeriodUpcoming:
+		tomorrow := today.AddDate(0, 0, 1)
+		return &tomorrow, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown period %q", ErrInvalidFilter, p)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
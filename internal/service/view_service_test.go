@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+type mockViewRepo struct {
+	views map[string]*domain.View
+}
+
+func (m *mockViewRepo) Create(ctx context.Context, view *domain.View) error {
+	if m.views == nil {
+		m.views = map[string]*domain.View{}
+	}
+	m.views[view.ID] = view
+	return nil
+}
+
+func (m *mockViewRepo) GetByID(ctx context.Context, ownerID, id string) (*domain.View, error) {
+	view, ok := m.views[id]
+	if !ok || view.OwnerID != ownerID {
+		return nil, errors.New("view not found")
+	}
+	return view, nil
+}
+
+func (m *mockViewRepo) ListByOwner(ctx context.Context, ownerID string) ([]*domain.View, error) {
+	var res []*domain.View
+	for _, view := range m.views {
+		if view.OwnerID == ownerID {
+			res = append(res, view)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockViewRepo) Update(ctx context.Context, view *domain.View) error { return nil }
+
+func (m *mockViewRepo) Delete(ctx context.Context, ownerID, id string) error { return nil }
+
+func TestViewService_ViewTasks_TodayResolvesToCurrentDay(t *testing.T) {
+	var got domain.TaskQuery
+	taskRepo := &mockTaskRepo{
+		findByQueryFunc: func(ctx context.Context, query domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error) {
+			got = query
+			return []*domain.Task{}, 0, nil
+		},
+	}
+	svc := service.NewViewService(&mockViewRepo{}, taskRepo)
+
+	if _, _, err := svc.ViewTasks(context.Background(), "user-1", "today", 20, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	if got.Completed == nil || *got.Completed {
+		t.Errorf("expected completed=false filter, got %v", got.Completed)
+	}
+	if got.DueFrom == nil || got.DueTo == nil {
+		t.Fatalf("expected due range, got %v..%v", got.DueFrom, got.DueTo)
+	}
+	if now.Before(*got.DueFrom) || !now.Before(*got.DueTo) {
+		t.Errorf("expected %v within %v..%v", now, *got.DueFrom, *got.DueTo)
+	}
+	if got.DueTo.Sub(*got.DueFrom) != 24*time.Hour {
+		t.Errorf("expected a one-day range, got %v", got.DueTo.Sub(*got.DueFrom))
+	}
+}
+
+func TestViewService_ListViews_IncludesBuiltInAndOwn(t *testing.T) {
+	svc := service.NewViewService(&mockViewRepo{}, &mockTaskRepo{})
+	ctx := context.Background()
+
+	if _, err := svc.CreateView(ctx, "user-1", "This week", domain.TaskFilter{CreatedIn: domain.PeriodThisWeek}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	views, err := svc.ListViews(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(views) != 4 {
+		t.Fatalf("expected 3 built-in views and 1 own view, got %d", len(views))
+	}
+	if !views[0].BuiltIn || views[3].BuiltIn {
+		t.Errorf("expected built-in views first")
+	}
+}
+
+func TestViewService_CreateView_InvalidFilter(t *testing.T) {
+	svc := service.NewViewService(&mockViewRepo{}, &mockTaskRepo{})
+
+	_, err := svc.CreateView(context.Background(), "user-1", "Broken", domain.TaskFilter{DueIn: "someday"})
+	if !errors.Is(err, service.ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter, got %v", err)
+	}
+}
+
+func TestViewService_DeleteView_BuiltInIsReadOnly(t *testing.T) {
+	svc := service.NewViewService(&mockViewRepo{}, &mockTaskRepo{})
+
+	err := svc.DeleteView(context.Background(), "user-1", "completed")
+	if !errors.Is(err, service.ErrBuiltInView) {
+		t.Fatalf("expected ErrBuiltInView, got %v", err)
+	}
+}
+
+func TestViewService_ViewTasks_CapsLimit(t *testing.T) {
+	var gotLimit int
+	taskRepo := &mockTaskRepo{
+		findByQueryFunc: func(ctx context.Context, query domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error) {
+			gotLimit = limit
+			return []*domain.Task{}, 0, nil
+		},
+	}
+	svc := service.NewViewService(&mockViewRepo{}, taskRepo)
+
+	if _, _, err := svc.ViewTasks(context.Background(), "user-1", "completed", 100000, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotLimit != 100 {
+		t.Errorf("limit = %d, want 100", gotLimit)
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+	"todo-api/pkg/logger"
+)
+
+// Заголовки запроса вебхука. Подпись — HMAC-SHA256 секрета подписки
+// от строки "<X-Webhook-Timestamp>.<тело>" в hex с префиксом "sha256=".
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// SignWebhook возвращает подпись тела body для заголовка X-Webhook-Signature.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewWebhookClient возвращает HTTP-клиент для доставки вебхуков. Адрес
+// проверяется после разрешения имени, перед каждым соединением, в том числе
+// при редиректах: так DNS, сменивший адрес после регистрации подписки, не
+// приведёт запрос во внутреннюю сеть. Прокси не используется, иначе
+// проверялся бы адрес прокси, а не получателя.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: webhookDialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("dial %s: %w", address, ErrWebhookURLNotAllowed)
+	}
+	return nil
+}
+
+// WebhookDispatcher доставляет события из outbox. Неудачная доставка
+// повторяется с экспоненциальной задержкой; после MaxAttempts попыток
+// доставка переходит в состояние dead и ждёт ручной переотправки.
+type WebhookDispatcher struct {
+	repo   storage.WebhookRepository
+	client *http.Client
+
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+}
+
+func NewWebhookDispatcher(repo storage.WebhookRepository, client *http.Client, maxAttempts int) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:        repo,
+		client:      client,
+		MaxAttempts: maxAttempts,
+		BaseBackoff: 10 * time.Second,
+		MaxBackoff:  time.Hour,
+		BatchSize:   20,
+	}
+}
+
+// Run доставляет события до отмены ctx, опрашивая outbox раз в interval.
+// Начатые доставки завершаются и после отмены.
+func (d *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := d.DispatchDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Info("webhook dispatcher: " + err.Error())
+		}
+		if n == d.BatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// DispatchDue захватывает и доставляет одну пачку доставок, время которых
+// наступило, и возвращает их количество.
+func (d *WebhookDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+	deliveries, err := d.repo.ClaimDue(ctx, d.BatchSize, d.lease())
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for _, del := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.deliver(ctx, del); err != nil {
+				logger.Info("webhook dispatcher: " + err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// lease — на сколько доставка скрывается от других отправителей: с запасом
+// больше таймаута запроса.
+func (d *WebhookDispatcher) lease() time.Duration {
+	timeout := d.client.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	return timeout + 30*time.Second
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, del *domain.WebhookDelivery) error {
+	start := time.Now()
+	status, err := d.send(ctx, del)
+
+	attempt := domain.WebhookAttempt{DurationMS: int(time.Since(start).Milliseconds())}
+	if status != 0 {
+		attempt.StatusCode = &status
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	now := time.Now().UTC()
+	del.Attempts++
+	switch {
+	case err == nil:
+		del.State = domain.DeliverySucceeded
+		del.DeliveredAt = &now
+	case del.Attempts >= d.MaxAttempts:
+		del.State = domain.DeliveryDead
+	default:
+		del.State = domain.DeliveryPending
+		del.NextAttemptAt = now.Add(d.backoff(del.Attempts))
+	}
+	return d.repo.CompleteAttempt(ctx, del, attempt)
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, del *domain.WebhookDelivery) (int, error) {
+	ts := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.URL, bytes.NewReader(del.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-api-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, del.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(del.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(del.Secret, ts, del.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff — задержка перед попыткой attempts+1: BaseBackoff, 2×, 4×…, не больше MaxBackoff.
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	delay := d.BaseBackoff
+	for i := 1; i < attempts && delay < d.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.MaxBackoff)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/reqctx"
+	"todo-api/internal/storage"
+)
+
+// WebhookPayload — тело запроса, которое получает подписчик вебхука.
+type WebhookPayload struct {
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	ListID     string    `json:"list_id"`
+	// Data — сущность после изменения, для удаления — до него.
+	Data any `json:"data"`
+	// Previous — сущность до изменения, только для *.updated и task.completed.
+	Previous any `json:"previous,omitempty"`
+}
+
+// WebhookOutbox — хук изменений, записывающий события вебхуков в outbox
+// в той же транзакции, что и изменение.
+type WebhookOutbox struct {
+	repo storage.WebhookRepository
+}
+
+func NewWebhookOutbox(repo storage.WebhookRepository) *WebhookOutbox {
+	return &WebhookOutbox{repo: repo}
+}
+
+func (o *WebhookOutbox) OnChange(ctx context.Context, c Change) error {
+	for _, eventType := range webhookEventTypes(c) {
+		p := WebhookPayload{
+			Type:       eventType,
+			OccurredAt: time.Now().UTC(),
+			Actor:      reqctx.UserID(ctx),
+			ListID:     c.ListID,
+		}
+		switch {
+		case c.Task != nil:
+			p.Data = c.Task
+		case c.PrevTask != nil:
+			p.Data = c.PrevTask
+		case c.List != nil:
+			p.Data = c.List
+		default:
+			p.Data = c.PrevList
+		}
+		if c.Action == domain.ActionUpdate {
+			if c.PrevTask != nil {
+				p.Previous = c.PrevTask
+			} else if c.PrevList != nil {
+				p.Previous = c.PrevList
+			}
+		}
+
+		payload, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("marshal webhook payload: %w", err)
+		}
+		if err := o.repo.Enqueue(ctx, eventType, c.ListID, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhookEventTypes сопоставляет изменению события вебхуков. Переход задачи
+// в выполненные даёт, кроме task.updated, ещё и task.completed.
+func webhookEventTypes(c Change) []string {
+	if c.EntityType == domain.EntityList {
+		switch c.Action {
+		case domain.ActionCreate:
+			return []string{domain.EventListCreated}
+		case domain.ActionUpdate:
+			return []string{domain.EventListUpdated}
+		default:
+			return []string{domain.EventListDeleted}
+		}
+	}
+
+	switch c.Action {
+	case domain.ActionCreate:
+		return []string{domain.EventTaskCreated}
+	case domain.ActionUpdate:
+		if c.Task != nil && c.Task.Completed && (c.PrevTask == nil || !c.PrevTask.Completed) {
+			return []string{domain.EventTaskUpdated, domain.EventTaskCompleted}
+		}
+		return []string{domain.EventTaskUpdated}
+	default:
+		return []string{domain.EventTaskDeleted}
+	}
+}
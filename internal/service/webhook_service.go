@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidWebhookURL    = errors.New("url must be an absolute http or https URL")
+	ErrWebhookURLNotAllowed = errors.New("url host must resolve to a public address")
+	ErrInvalidEventTypes    = errors.New("event_types must be a non-empty subset of supported events")
+	ErrInvalidWebhookSecret = errors.New("secret must be 16..200 chars")
+	ErrInvalidWebhookListID = errors.New("list_id must be a UUID")
+)
+
+// WebhookUpdate — изменяемые поля подписки; nil-поля не меняются.
+type WebhookUpdate struct {
+	URL        *string
+	EventTypes []string
+	ListID     *string
+	Secret     *string
+	Active     *bool
+}
+
+type WebhookService struct {
+	repo storage.WebhookRepository
+}
+
+func NewWebhookService(repo storage.WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+// CreateSubscription создаёт подписку. Если secret не передан, он генерируется.
+// Секрет возвращается только здесь: в остальных ответах его нет.
+func (s *WebhookService) CreateSubscription(ctx context.Context, ownerID, rawURL string, eventTypes []string, listID, secret string) (*domain.WebhookSubscription, error) {
+	if err := validateWebhookURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+	if err := validateEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookListID(listID); err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		secret = newWebhookSecret()
+	} else if len(secret) < 16 || len(secret) > 200 {
+		return nil, ErrInvalidWebhookSecret
+	}
+
+	sub := &domain.WebhookSubscription{
+		ID:         uuid.NewString(),
+		OwnerID:    ownerID,
+		URL:        rawURL,
+		EventTypes: eventTypes,
+		ListID:     listID,
+		Secret:     secret,
+		Active:     true,
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error) {
+	subs, err := s.repo.ListSubscriptions(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, ownerID, id string) (*domain.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+func (s *WebhookService) UpdateSubscription(ctx context.Context, ownerID, id string, upd WebhookUpdate) (*domain.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.URL != nil {
+		if err := validateWebhookURL(ctx, *upd.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *upd.URL
+	}
+	if upd.EventTypes != nil {
+		if err := validateEventTypes(upd.EventTypes); err != nil {
+			return nil, err
+		}
+		sub.EventTypes = upd.EventTypes
+	}
+	if upd.ListID != nil {
+		if err := validateWebhookListID(*upd.ListID); err != nil {
+			return nil, err
+		}
+		sub.ListID = *upd.ListID
+	}
+	if upd.Secret != nil {
+		if len(*upd.Secret) < 16 || len(*upd.Secret) > 200 {
+			return nil, ErrInvalidWebhookSecret
+		}
+		sub.Secret = *upd.Secret
+	}
+	if upd.Active != nil {
+		sub.Active = *upd.Active
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, ownerID, id string) error {
+	return s.repo.DeleteSubscription(ctx, ownerID, id)
+}
+
+// ListDeliveries возвращает журнал доставок подписки, новые первыми.
+func (s *WebhookService) ListDeliveries(ctx context.Context, ownerID, id string, limit, offset int) ([]*domain.WebhookDelivery, int, error) {
+	if _, err := s.repo.GetSubscription(ctx, ownerID, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListDeliveries(ctx, id, limit, offset)
+}
+
+func (s *WebhookService) GetDelivery(ctx context.Context, ownerID, id string, deliveryID int64) (*domain.WebhookDelivery, error) {
+	if _, err := s.repo.GetSubscription(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDelivery(ctx, id, deliveryID)
+}
+
+// Redeliver ставит доставку в очередь заново, в том числе после dead.
+func (s *WebhookService) Redeliver(ctx context.Context, ownerID, id string, deliveryID int64) error {
+	if _, err := s.repo.GetSubscription(ctx, ownerID, id); err != nil {
+		return err
+	}
+	return s.repo.Redeliver(ctx, id, deliveryID)
+}
+
+// validateWebhookURL проверяет адрес вебхука и то, что все адреса его хоста
+// публичные. DNS может измениться после регистрации, поэтому при доставке
+// адрес проверяется ещё раз (см. NewWebhookClient).
+func validateWebhookURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidWebhookURL
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if !isPublicIP(ip) {
+			return ErrWebhookURLNotAllowed
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrWebhookURLNotAllowed
+	}
+	for _, a := range addrs {
+		if !isPublicIP(a.IP) {
+			return ErrWebhookURLNotAllowed
+		}
+	}
+	return nil
+}
+
+// isPublicIP сообщает, можно ли отправлять вебхук на ip: loopback,
+// link-local (в том числе адрес метаданных облака 169.254.169.254),
+// частные сети и multicast запрещены.
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+func validateWebhookListID(listID string) error {
+	if listID == "" {
+		return nil
+	}
+	if _, err := uuid.Parse(listID); err != nil {
+		return ErrInvalidWebhookListID
+	}
+	return nil
+}
+
+func validateEventTypes(types []string) error {
+	if len(types) == 0 {
+		return ErrInvalidEventTypes
+	}
+	for _, t := range types {
+		if !slices.Contains(domain.WebhookEventTypes, t) {
+			return ErrInvalidEventTypes
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,275 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/service"
+)
+
+type enqueuedEvent struct {
+	eventType string
+	listID    string
+	payload   []byte
+}
+
+type mockWebhookRepo struct {
+	mu        sync.Mutex
+	due       []*domain.WebhookDelivery
+	completed []*domain.WebhookDelivery
+	attempts  []domain.WebhookAttempt
+	enqueued  []enqueuedEvent
+}
+
+func (m *mockWebhookRepo) CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	return nil
+}
+
+func (m *mockWebhookRepo) GetSubscription(ctx context.Context, ownerID, id string) (*domain.WebhookSubscription, error) {
+	return &domain.WebhookSubscription{ID: id, OwnerID: ownerID, Secret: "secret"}, nil
+}
+
+func (m *mockWebhookRepo) ListSubscriptions(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookRepo) UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	return nil
+}
+
+func (m *mockWebhookRepo) DeleteSubscription(ctx context.Context, ownerID, id string) error {
+	return nil
+}
+
+func (m *mockWebhookRepo) Enqueue(ctx context.Context, eventType, listID string, payload []byte) error {
+	m.enqueued = append(m.enqueued, enqueuedEvent{eventType: eventType, listID: listID, payload: payload})
+	return nil
+}
+
+func (m *mockWebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	due := m.due
+	m.due = nil
+	return due, nil
+}
+
+func (m *mockWebhookRepo) CompleteAttempt(ctx context.Context, d *domain.WebhookDelivery, a domain.WebhookAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *d
+	m.completed = append(m.completed, &copied)
+	m.attempts = append(m.attempts, a)
+	return nil
+}
+
+func (m *mockWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]*domain.WebhookDelivery, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockWebhookRepo) GetDelivery(ctx context.Context, subscriptionID string, id int64) (*domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookRepo) Redeliver(ctx context.Context, subscriptionID string, id int64) error {
+	return nil
+}
+
+func TestWebhookDispatcher_DeliversSignedPayload(t *testing.T) {
+	payload := []byte(`{"type":"task.completed"}`)
+	var (
+		mu       sync.Mutex
+		received *http.Request
+		body     []byte
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := &mockWebhookRepo{due: []*domain.WebhookDelivery{{
+		ID: 7, EventType: domain.EventTaskCompleted, State: domain.DeliveryPending,
+		Payload: payload, URL: receiver.URL, Secret: "s3cr3t-s3cr3t-s3cr3t",
+	}}}
+	dispatcher := service.NewWebhookDispatcher(repo, receiver.Client(), 3)
+
+	n, err := dispatcher.DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 delivery, got %d", n)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received == nil {
+		t.Fatal("receiver was not called")
+	}
+	if got := received.Header.Get(service.WebhookEventHeader); got != domain.EventTaskCompleted {
+		t.Errorf("expected event header %q, got %q", domain.EventTaskCompleted, got)
+	}
+	if got := received.Header.Get(service.WebhookDeliveryHeader); got != "7" {
+		t.Errorf("expected delivery header 7, got %q", got)
+	}
+	ts, err := strconv.ParseInt(received.Header.Get(service.WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("bad timestamp header: %v", err)
+	}
+	want := service.SignWebhook("s3cr3t-s3cr3t-s3cr3t", ts, body)
+	if got := received.Header.Get(service.WebhookSignatureHeader); got != want {
+		t.Errorf("expected signature %q, got %q", want, got)
+	}
+	if string(body) != string(payload) {
+		t.Errorf("expected body %s, got %s", payload, body)
+	}
+
+	if len(repo.completed) != 1 {
+		t.Fatalf("expected 1 completed attempt, got %d", len(repo.completed))
+	}
+	d := repo.completed[0]
+	if d.State != domain.DeliverySucceeded || d.Attempts != 1 || d.DeliveredAt == nil {
+		t.Errorf("expected succeeded after 1 attempt, got state=%s attempts=%d", d.State, d.Attempts)
+	}
+	if code := repo.attempts[0].StatusCode; code == nil || *code != http.StatusNoContent {
+		t.Errorf("expected logged status 204, got %v", code)
+	}
+}
+
+func TestWebhookDispatcher_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := &mockWebhookRepo{}
+	dispatcher := service.NewWebhookDispatcher(repo, receiver.Client(), 3)
+	dispatcher.BaseBackoff = time.Minute
+
+	delivery := &domain.WebhookDelivery{ID: 1, State: domain.DeliveryPending, Payload: []byte(`{}`), URL: receiver.URL}
+	for range 3 {
+		repo.due = []*domain.WebhookDelivery{delivery}
+		if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(repo.completed) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(repo.completed))
+	}
+	for i, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		d := repo.completed[i]
+		if d.State != domain.DeliveryPending {
+			t.Errorf("attempt %d: expected pending, got %s", i+1, d.State)
+		}
+		delay := time.Until(d.NextAttemptAt)
+		if delay > wantDelay || delay < wantDelay-5*time.Second {
+			t.Errorf("attempt %d: expected retry in ~%v, got %v", i+1, wantDelay, delay)
+		}
+	}
+	if last := repo.completed[2]; last.State != domain.DeliveryDead || last.Attempts != 3 {
+		t.Errorf("expected dead after 3 attempts, got state=%s attempts=%d", last.State, last.Attempts)
+	}
+	if repo.attempts[2].Error == "" {
+		t.Error("expected failed attempt to log an error")
+	}
+}
+
+func TestWebhookOutbox_CompletingTaskEnqueuesCompletedEvent(t *testing.T) {
+	taskRepo := &mockTaskRepo{
+		getByIDFunc: func(ctx context.Context, id string) (*domain.Task, error) {
+			return &domain.Task{ID: id, ListID: "list-1", Text: "Оплатить счёт"}, nil
+		},
+	}
+	webhooks := &mockWebhookRepo{}
+	hooks := service.ChangeHooks{service.NewWebhookOutbox(webhooks)}
+	svc := service.NewTaskService(taskRepo, &mockListRepo{}, mockTransactor{}, &mockAuditRepo{}, nil, hooks)
+
+	completed := true
+	if _, _, err := svc.UpdateTask(context.Background(), "task-1", "", &completed, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(webhooks.enqueued) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(webhooks.enqueued))
+	}
+	ev := webhooks.enqueued[1]
+	if ev.eventType != domain.EventTaskCompleted || ev.listID != "list-1" {
+		t.Errorf("expected task.completed for list-1, got %s for %s", ev.eventType, ev.listID)
+	}
+
+	var p struct {
+		Type     string       `json:"type"`
+		Data     domain.Task  `json:"data"`
+		Previous *domain.Task `json:"previous"`
+	}
+	if err := json.Unmarshal(ev.payload, &p); err != nil {
+		t.Fatalf("bad payload: %v", err)
+	}
+	if p.Type != domain.EventTaskCompleted || !p.Data.Completed || p.Previous == nil || p.Previous.Completed {
+		t.Errorf("unexpected payload %s", ev.payload)
+	}
+}
+
+func TestWebhookService_RejectsInternalURLs(t *testing.T) {
+	svc := service.NewWebhookService(&mockWebhookRepo{})
+	events := []string{domain.EventTaskCreated}
+
+	cases := []struct {
+		url string
+		err error
+	}{
+		{"https://93.184.216.34/hooks", nil},
+		{"ftp://93.184.216.34/hooks", service.ErrInvalidWebhookURL},
+		{"http://127.0.0.1:8080/admin", service.ErrWebhookURLNotAllowed},
+		{"http://localhost/hooks", service.ErrWebhookURLNotAllowed},
+		{"http://[::1]/hooks", service.ErrWebhookURLNotAllowed},
+		{"http://169.254.169.254/latest/meta-data/", service.ErrWebhookURLNotAllowed},
+		{"http://10.0.0.5/hooks", service.ErrWebhookURLNotAllowed},
+		{"http://172.16.3.4/hooks", service.ErrWebhookURLNotAllowed},
+		{"http://192.168.1.1/hooks", service.ErrWebhookURLNotAllowed},
+		{"http://0.0.0.0/hooks", service.ErrWebhookURLNotAllowed},
+		{"http://[::ffff:127.0.0.1]/hooks", service.ErrWebhookURLNotAllowed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.url, func(t *testing.T) {
+			_, err := svc.CreateSubscription(context.Background(), "olga", tc.url, events, "", "")
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("create: err = %v, want %v", err, tc.err)
+			}
+			_, err = svc.UpdateSubscription(context.Background(), "olga", "w1", service.WebhookUpdate{URL: &tc.url})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("update: err = %v, want %v", err, tc.err)
+			}
+		})
+	}
+
+	if _, err := svc.CreateSubscription(context.Background(), "olga", "https://93.184.216.34/hooks", events, "inbox", ""); !errors.Is(err, service.ErrInvalidWebhookListID) {
+		t.Errorf("list_id: err = %v, want ErrInvalidWebhookListID", err)
+	}
+}
+
+func TestWebhookClient_RefusesInternalAddresses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback receiver")
+	}))
+	defer receiver.Close()
+
+	_, err := service.NewWebhookClient(time.Second).Post(receiver.URL, "application/json", nil)
+	if !errors.Is(err, service.ErrWebhookURLNotAllowed) {
+		t.Fatalf("err = %v, want ErrWebhookURLNotAllowed", err)
+	}
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type AuditRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditRepo(pool *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{pool: pool}
+}
+
+// Append записывает событие. Вызывается внутри транзакции изменения,
+// поэтому событие фиксируется вместе с ним.
+func (r *AuditRepo) Append(ctx context.Context, e *domain.AuditEvent) error {
+	diff, err := json.Marshal(e.Diff)
+	if err != nil {
+		return fmt.Errorf("marshal diff: %w", err)
+	}
+
+	var listID *string
+	if e.ListID != "" {
+		listID = &e.ListID
+	}
+
+	query := `
+        INSERT INTO audit_events (actor, request_id, entity_type, entity_id, list_id, action, before, after, diff)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING id, created_at
+    `
+	err = conn(ctx, r.pool).QueryRow(ctx, query,
+		e.Actor, e.RequestID, e.EntityType, e.EntityID, listID, e.Action,
+		nullJSON(e.Before), nullJSON(e.After), diff,
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("append audit event: %w", err)
+	}
+	return nil
+}
+
+// ListByList возвращает события списка и его задач, новые первыми.
+func (r *AuditRepo) ListByList(ctx context.Context, listID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return r.list(ctx, `list_id = $1`, []any{listID}, limit, offset)
+}
+
+func (r *AuditRepo) ListByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	return r.list(ctx, `entity_type = $1 AND entity_id = $2`, []any{entityType, entityID}, limit, offset)
+}
+
+func (r *AuditRepo) list(ctx context.Context, where string, args []any, limit, offset int) ([]*domain.AuditEvent, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM audit_events WHERE `+where, args...).Scan(&total); err != nil {
+		// Некорректный UUID не может принадлежать ни одному списку или задаче.
+		if isInvalidID(err) {
+			return nil, 0, ErrNotFound
+		}
+		return nil, 0, fmt.Errorf("count audit events: %w", err)
+	}
+
+	n := len(args)
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+        SELECT id, actor, request_id, entity_type, entity_id, coalesce(list_id::text, ''), action, before, after, diff, created_at
+        FROM audit_events
+        WHERE %s
+        ORDER BY id DESC
+        LIMIT $%d OFFSET $%d
+    `, where, n+1, n+2), append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.AuditEvent{}
+	for rows.Next() {
+		e, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, e)
+	}
+	return events, total, rows.Err()
+}
+
+func scanAuditEvent(row pgx.Row) (*domain.AuditEvent, error) {
+	var (
+		e    domain.AuditEvent
+		diff []byte
+	)
+	if err := row.Scan(&e.ID, &e.Actor, &e.RequestID, &e.EntityType, &e.EntityID, &e.ListID, &e.Action,
+		&e.Before, &e.After, &diff, &e.CreatedAt); err != nil {
+		return nil, fmt.Errorf("scan audit event: %w", err)
+	}
+	if err := json.Unmarshal(diff, &e.Diff); err != nil {
+		return nil, fmt.Errorf("unmarshal diff: %w", err)
+	}
+	return &e, nil
+}
+
+// nullJSON превращает пустой json.RawMessage в SQL NULL.
+func nullJSON(b json.RawMessage) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return []byte(b)
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type CalendarRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewCalendarRepo(pool *pgxpool.Pool) *CalendarRepo {
+	return &CalendarRepo{pool: pool}
+}
+
+// calendarObjectColumns — колонки задачи в порядке scanTask, затем имя
+// ресурса и UID: для задач без записи в calendar_objects — <id>.ics и id.
+const calendarObjectColumns = `t.id, t.list_id, t.text, t.completed, t.due_at, t.completed_at, t.version, t.created_at, t.updated_at,
+	COALESCE(co.name, t.id::text || '.ics'), COALESCE(co.uid, t.id::text)`
+
+func (r *CalendarRepo) SetToken(ctx context.Context, listID, tokenHash string) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO calendar_tokens (list_id, token_hash) VALUES ($1, $2)
+		ON CONFLICT (list_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()`,
+		listID, tokenHash)
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("set calendar token: %w", err)
+	}
+	return nil
+}
+
+func (r *CalendarRepo) ListIDByToken(ctx context.Context, tokenHash string) (string, error) {
+	var listID string
+	err := conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT list_id::text FROM calendar_tokens WHERE token_hash = $1`, tokenHash).Scan(&listID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find calendar token: %w", err)
+	}
+	return listID, nil
+}
+
+func (r *CalendarRepo) Objects(ctx context.Context, listID string) ([]*domain.CalendarObject, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+		SELECT `+calendarObjectColumns+`
+		FROM tasks t
+		LEFT JOIN calendar_objects co ON co.task_id = t.id
+		WHERE t.list_id = $1
+		ORDER BY t.created_at, t.id`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("list calendar objects: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.CalendarObject{}
+	for rows.Next() {
+		o, err := scanCalendarObject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan calendar object: %w", err)
+		}
+		res = append(res, o)
+	}
+	return res, rows.Err()
+}
+
+func (r *CalendarRepo) Object(ctx context.Context, listID, name string) (*domain.CalendarObject, error) {
+	o, err := scanCalendarObject(conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT `+calendarObjectColumns+`
+		FROM tasks t
+		LEFT JOIN calendar_objects co ON co.task_id = t.id
+		WHERE t.list_id = $1
+		  AND (co.name = $2 OR (co.task_id IS NULL AND t.id::text || '.ics' = $2))
+		FOR UPDATE OF t`, listID, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get calendar object: %w", err)
+	}
+	return o, nil
+}
+
+func (r *CalendarRepo) SaveObject(ctx context.Context, listID string, o *domain.CalendarObject) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO calendar_objects (task_id, list_id, name, uid) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id) DO UPDATE SET name = EXCLUDED.name, uid = EXCLUDED.uid`,
+		o.Task.ID, listID, o.Name, o.UID)
+	if err != nil {
+		return fmt.Errorf("save calendar object: %w", err)
+	}
+	return nil
+}
+
+func scanCalendarObject(row pgx.Row) (*domain.CalendarObject, error) {
+	var (
+		t domain.Task
+		o = domain.CalendarObject{Task: &t}
+	)
+	err := row.Scan(&t.ID, &t.ListID, &t.Text, &t.Completed, &t.DueAt, &t.CompletedAt, &t.Version, &t.CreatedAt, &t.UpdatedAt,
+		&o.Name, &o.UID)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
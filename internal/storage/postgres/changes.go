@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+	"todo-api/pkg/logger"
+)
+
+// changesChannel — канал NOTIFY, в который репозитории пишут изменения.
+const changesChannel = "todo_changes"
+
+// changeNotice — содержимое NOTIFY. Полезная нагрузка NOTIFY ограничена
+// 8000 байт, поэтому тела списка, задачи и напоминания в неё не попадают:
+// слушатель загружает их сам.
+type changeNotice struct {
+	ID         int64     `json:"id"`
+	Type       string    `json:"type"`
+	ListID     string    `json:"list_id"`
+	TaskID     string    `json:"task_id,omitempty"`
+	ReminderID string    `json:"reminder_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// notifyChange отправляет событие в канал изменений. В транзакции Postgres
+// доставляет его слушателям только после фиксации, в порядке фиксаций.
+func notifyChange(ctx context.Context, q querier, ev domain.ChangeEvent) error {
+	n := changeNotice{Type: ev.Type, ListID: ev.ListID, TaskID: ev.TaskID, OccurredAt: time.Now().UTC()}
+	if ev.Reminder != nil {
+		n.ReminderID = ev.Reminder.ID
+	}
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshal change event: %w", err)
+	}
+	_, err = q.Exec(ctx,
+		`SELECT pg_notify($1, jsonb_set($2::jsonb, '{id}', to_jsonb(nextval('change_events_seq')))::text)`,
+		changesChannel, payload)
+	if err != nil {
+		return fmt.Errorf("notify change: %w", err)
+	}
+	return nil
+}
+
+// ChangeListener получает события из канала изменений через LISTEN, поэтому
+// их видят все экземпляры сервиса, а не только тот, что выполнил запись.
+type ChangeListener struct {
+	pool      *pgxpool.Pool
+	lists     *ListRepo
+	tasks     *taskRepo
+	reminders *ReminderRepo
+}
+
+func NewChangeListener(pool *pgxpool.Pool) *ChangeListener {
+	return &ChangeListener{pool: pool, lists: NewListRepo(pool), tasks: NewTaskRepo(pool), reminders: NewReminderRepo(pool)}
+}
+
+// Listen вызывает handle для каждого события до отмены ctx. При потере
+// соединения переподключается; события, пришедшие в разрыв, теряются.
+func (l *ChangeListener) Listen(ctx context.Context, handle func(domain.ChangeEvent)) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := l.listen(ctx, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Info("change listener: " + err.Error() + ", reconnecting in " + backoff.String())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (l *ChangeListener) listen(ctx context.Context, handle func(domain.ChangeEvent)) error {
+	pc, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	// Соединение с LISTEN забирается из пула насовсем и закрывается здесь.
+	c := pc.Hijack()
+	defer c.Close(context.Background())
+
+	if _, err := c.Exec(ctx, "LISTEN "+changesChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	for {
+		n, err := c.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		var notice changeNotice
+		if err := json.Unmarshal([]byte(n.Payload), &notice); err != nil {
+			logger.Info("change listener: bad payload: " + err.Error())
+			continue
+		}
+		ev, err := l.load(ctx, notice)
+		if errors.Is(err, ErrNotFound) {
+			// Сущность уже удалена, событие об удалении придёт следом.
+			continue
+		}
+		if err != nil {
+			logger.Info(fmt.Sprintf("change listener: load event %d: %v", notice.ID, err))
+			continue
+		}
+		handle(ev)
+	}
+}
+
+// load собирает событие по уведомлению. Сущности читаются после фиксации,
+// поэтому могут оказаться новее события, но не старее.
+func (l *ChangeListener) load(ctx context.Context, n changeNotice) (domain.ChangeEvent, error) {
+	ev := domain.ChangeEvent{ID: n.ID, Type: n.Type, ListID: n.ListID, TaskID: n.TaskID, OccurredAt: n.OccurredAt}
+	var err error
+	switch n.Type {
+	case domain.EventListCreated, domain.EventListUpdated:
+		ev.List, err = l.lists.GetByID(ctx, n.ListID)
+	case domain.EventTaskCreated, domain.EventTaskUpdated:
+		ev.Task, err = l.tasks.GetByID(ctx, n.TaskID)
+	case domain.EventTaskReminder:
+		if ev.Reminder, err = l.reminders.getByID(ctx, n.ReminderID); err == nil {
+			ev.Task, err = l.tasks.GetByID(ctx, n.TaskID)
+		}
+	}
+	return ev, err
+}
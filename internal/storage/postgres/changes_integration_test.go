@@ -0,0 +1,42 @@
+package postgres_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage/postgres"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeListener_LoadsLargeTask(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := db.Exec(ctx, `TRUNCATE TABLE tasks, lists RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+
+	events := make(chan domain.ChangeEvent, 1)
+	go postgres.NewChangeListener(db).Listen(ctx, func(ev domain.ChangeEvent) { events <- ev })
+	time.Sleep(200 * time.Millisecond)
+
+	listID := uuid.NewString()
+	_, err = db.Exec(ctx, `INSERT INTO lists (id, title) VALUES ($1, 'Большой список')`, listID)
+	require.NoError(t, err)
+	// Текст больше лимита NOTIFY в 8000 байт.
+	task := &domain.Task{ID: uuid.NewString(), ListID: listID, Text: strings.Repeat("я", 5000), CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, postgres.NewTaskRepo(db).Create(ctx, task))
+
+	select {
+	case ev := <-events:
+		require.Equal(t, domain.EventTaskCreated, ev.Type)
+		require.NotZero(t, ev.ID)
+		require.NotNil(t, ev.Task)
+		require.Equal(t, task.Text, ev.Task.Text)
+	case <-ctx.Done():
+		t.Fatal("no change event received")
+	}
+}
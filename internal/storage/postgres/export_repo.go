@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type ExportRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExportRepo(pool *pgxpool.Pool) *ExportRepo {
+	return &ExportRepo{pool: pool}
+}
+
+// exportTimeout ограничивает одну выгрузку: запрос держит соединение
+// и снимок базы всё время, пока клиент читает ответ.
+const exportTimeout = 10 * time.Minute
+
+const exportColumns = `
+		SELECT l.id, l.title, l.description, l.version, l.created_at,
+		       t.id, t.text, t.completed, t.due_at, t.completed_at, t.version, t.created_at, t.updated_at
+		FROM lists l
+		LEFT JOIN tasks t ON t.list_id = l.id`
+
+const exportOrder = `
+		ORDER BY l.created_at, l.id, t.created_at, t.id`
+
+// Stream читает списки с задачами одним запросом и передаёт строки в fn по
+// мере чтения. Запрос видит один снимок, поэтому выгрузка согласована, даже
+// если списки меняются во время неё.
+func (r *ExportRepo) Stream(ctx context.Context, listID string, fn func(list *domain.List, task *domain.Task) error) error {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	var (
+		rows pgx.Rows
+		err  error
+	)
+	if listID == "" {
+		rows, err = conn(ctx, r.pool).Query(ctx, exportColumns+exportOrder)
+	} else {
+		rows, err = conn(ctx, r.pool).Query(ctx, exportColumns+`
+		WHERE l.id = $1`+exportOrder, listID)
+	}
+	if err != nil {
+		return fmt.Errorf("export lists: %w", err)
+	}
+	defer rows.Close()
+
+	var list *domain.List
+	for rows.Next() {
+		var (
+			l           domain.List
+			taskID      *string
+			text        *string
+			completed   *bool
+			taskVersion *int64
+			createdAt   *time.Time
+			updatedAt   *time.Time
+			t           domain.Task
+		)
+		if err := rows.Scan(&l.ID, &l.Title, &l.Description, &l.Version, &l.CreatedAt,
+			&taskID, &text, &completed, &t.DueAt, &t.CompletedAt, &taskVersion, &createdAt, &updatedAt); err != nil {
+			return fmt.Errorf("scan export row: %w", err)
+		}
+		// Один и тот же список передаётся одним значением для всех его задач.
+		if list == nil || list.ID != l.ID {
+			list = &l
+		}
+		if taskID == nil {
+			if err := fn(list, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		t.ID, t.ListID, t.Text, t.Completed, t.Version = *taskID, list.ID, *text, *completed, *taskVersion
+		t.CreatedAt, t.UpdatedAt = *createdAt, *updatedAt
+		if err := fn(list, &t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
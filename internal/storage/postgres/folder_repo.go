@@ -0,0 +1,372 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type FolderRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewFolderRepo(pool *pgxpool.Pool) *FolderRepo {
+	return &FolderRepo{pool: pool}
+}
+
+const folderColumns = `id, parent_id, name, created_at, updated_at`
+
+// folderWalkLimit ограничивает рекурсию по дереву папок на случай, если в
+// базе всё же окажется цикл.
+const folderWalkLimit = 100
+
+func (r *FolderRepo) Create(ctx context.Context, f *domain.Folder) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := conn(ctx, r.pool).QueryRow(ctx, `
+        INSERT INTO folders (id, parent_id, name)
+        VALUES ($1, $2, $3)
+        RETURNING created_at, updated_at
+    `, f.ID, f.ParentID, f.Name).Scan(&f.CreatedAt, &f.UpdatedAt)
+	if isForeignKeyViolation(err) || isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("create folder: %w", err)
+	}
+	return nil
+}
+
+func (r *FolderRepo) Get(ctx context.Context, id string) (*domain.Folder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var f domain.Folder
+	err := conn(ctx, r.pool).QueryRow(ctx, `SELECT `+folderColumns+` FROM folders WHERE id = $1`, id).
+		Scan(&f.ID, &f.ParentID, &f.Name, &f.CreatedAt, &f.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get folder: %w", err)
+	}
+	return &f, nil
+}
+
+func (r *FolderRepo) List(ctx context.Context) ([]*domain.Folder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := conn(ctx, r.pool).Query(ctx, `SELECT `+folderColumns+` FROM folders ORDER BY name, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list folders: %w", err)
+	}
+	defer rows.Close()
+
+	folders := []*domain.Folder{}
+	for rows.Next() {
+		var f domain.Folder
+		if err := rows.Scan(&f.ID, &f.ParentID, &f.Name, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan folder: %w", err)
+		}
+		folders = append(folders, &f)
+	}
+	return folders, rows.Err()
+}
+
+// Rename меняет только название: родителя папки переносы меняют под
+// блокировкой дерева, и переименование его не перезаписывает.
+func (r *FolderRepo) Rename(ctx context.Context, id, name string) (*domain.Folder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var f domain.Folder
+	err := conn(ctx, r.pool).QueryRow(ctx, `
+        UPDATE folders
+        SET name = $2, updated_at = NOW()
+        WHERE id = $1
+        RETURNING `+folderColumns,
+		id, name).Scan(&f.ID, &f.ParentID, &f.Name, &f.CreatedAt, &f.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rename folder: %w", err)
+	}
+	return &f, nil
+}
+
+// Move меняет только родителя папки.
+func (r *FolderRepo) Move(ctx context.Context, id string, parentID *string) (*domain.Folder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var f domain.Folder
+	err := conn(ctx, r.pool).QueryRow(ctx, `
+        UPDATE folders
+        SET parent_id = $2, updated_at = NOW()
+        WHERE id = $1
+        RETURNING `+folderColumns,
+		id, parentID).Scan(&f.ID, &f.ParentID, &f.Name, &f.CreatedAt, &f.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || isForeignKeyViolation(err) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("move folder: %w", err)
+	}
+	return &f, nil
+}
+
+// Delete вызывается в транзакции: вложенные папки переносятся выше до
+// удаления, иначе внешний ключ не даст её удалить. Списки к этому моменту
+// уже перенесены сервисом.
+func (r *FolderRepo) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := conn(ctx, r.pool)
+	var parentID *string
+	err := q.QueryRow(ctx, `SELECT parent_id FROM folders WHERE id = $1 FOR UPDATE`, id).Scan(&parentID)
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("delete folder: %w", err)
+	}
+	if _, err := q.Exec(ctx, `UPDATE folders SET parent_id = $2, updated_at = NOW() WHERE parent_id = $1`, id, parentID); err != nil {
+		return fmt.Errorf("move subfolders: %w", err)
+	}
+	if _, err := q.Exec(ctx, `DELETE FROM folders WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete folder: %w", err)
+	}
+	return nil
+}
+
+// LockTree берёт транзакционную advisory-блокировку: проверки глубины и
+// циклов при создании и переносе папок не пересекаются между собой.
+func (r *FolderRepo) LockTree(ctx context.Context) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('folders'))`); err != nil {
+		return fmt.Errorf("lock folder tree: %w", err)
+	}
+	return nil
+}
+
+func (r *FolderRepo) Ancestors(ctx context.Context, id string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+        WITH RECURSIVE up AS (
+            SELECT id, parent_id, 1 AS depth FROM folders WHERE id = $1
+            UNION ALL
+            SELECT f.id, f.parent_id, up.depth + 1
+            FROM folders f
+            JOIN up ON f.id = up.parent_id
+            WHERE up.depth < $2
+        )
+        SELECT id FROM up ORDER BY depth
+    `, id, folderWalkLimit)
+	if isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("folder ancestors: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var ancestor string
+		if err := rows.Scan(&ancestor); err != nil {
+			return nil, fmt.Errorf("scan folder ancestor: %w", err)
+		}
+		ids = append(ids, ancestor)
+	}
+	if isInvalidID(rows.Err()) {
+		return nil, ErrNotFound
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, ErrNotFound
+	}
+	return ids, nil
+}
+
+func (r *FolderRepo) Height(ctx context.Context, id string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var height int
+	err := conn(ctx, r.pool).QueryRow(ctx, `
+        WITH RECURSIVE down AS (
+            SELECT id, 1 AS level FROM folders WHERE id = $1
+            UNION ALL
+            SELECT f.id, down.level + 1
+            FROM folders f
+            JOIN down ON f.parent_id = down.id
+            WHERE down.level < $2
+        )
+        SELECT COALESCE(MAX(level), 0) FROM down
+    `, id, folderWalkLimit).Scan(&height)
+	if err != nil {
+		return 0, fmt.Errorf("folder height: %w", err)
+	}
+	return height, nil
+}
+
+// listNodeColumns — поля domain.ListNode; требует псевдонима l для lists
+// и GROUP BY l.id с соединением задач t.
+const listNodeColumns = `l.id, l.title, COALESCE(l.description, ''), l.version, l.archived_at, l.created_at, l.folder_id,
+	COUNT(t.id) FILTER (WHERE NOT t.completed)`
+
+func (r *FolderRepo) Lists(ctx context.Context, folderID string, limit, offset int) ([]*domain.ListNode, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := conn(ctx, r.pool)
+	var total int
+	if err := q.QueryRow(ctx, `SELECT COUNT(*) FROM lists WHERE folder_id = $1`, folderID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count folder lists: %w", err)
+	}
+
+	lists, err := queryListNodes(ctx, q, `
+        SELECT `+listNodeColumns+`
+        FROM lists l
+        LEFT JOIN tasks t ON t.list_id = l.id
+        WHERE l.folder_id = $1
+        GROUP BY l.id
+        ORDER BY l.created_at DESC
+        LIMIT $2 OFFSET $3
+    `, folderID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return lists, total, nil
+}
+
+// ListIDs блокирует строку папки до конца транзакции: параллельный перенос
+// списка в папку ждёт её удаления и получает ошибку внешнего ключа.
+func (r *FolderRepo) ListIDs(ctx context.Context, folderID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := conn(ctx, r.pool)
+	err := q.QueryRow(ctx, `SELECT id FROM folders WHERE id = $1 FOR UPDATE`, folderID).Scan(&folderID)
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock folder: %w", err)
+	}
+
+	rows, err := q.Query(ctx, `SELECT id FROM lists WHERE folder_id = $1 ORDER BY created_at, id`, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("folder list ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan folder list id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Tree считает глубину папок и замыкание «предок — потомок» рекурсивными
+// CTE; невыполненные задачи папки — сумма по спискам всех её потомков.
+func (r *FolderRepo) Tree(ctx context.Context) ([]*domain.FolderNode, []*domain.ListNode, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := conn(ctx, r.pool)
+	rows, err := q.Query(ctx, `
+        WITH RECURSIVE depths AS (
+            SELECT id, 1 AS depth FROM folders WHERE parent_id IS NULL
+            UNION ALL
+            SELECT f.id, d.depth + 1
+            FROM folders f
+            JOIN depths d ON f.parent_id = d.id
+            WHERE d.depth < $1
+        ), closure AS (
+            SELECT id AS ancestor, id AS descendant, 1 AS level FROM folders
+            UNION ALL
+            SELECT c.ancestor, f.id, c.level + 1
+            FROM closure c
+            JOIN folders f ON f.parent_id = c.descendant
+            WHERE c.level < $1
+        ), open_tasks AS (
+            SELECT l.folder_id, COUNT(*) AS open
+            FROM tasks t
+            JOIN lists l ON l.id = t.list_id
+            WHERE NOT t.completed AND l.folder_id IS NOT NULL
+            GROUP BY l.folder_id
+        )
+        SELECT f.id, f.parent_id, f.name, f.created_at, f.updated_at, d.depth,
+               COALESCE(SUM(o.open), 0)::bigint
+        FROM folders f
+        JOIN depths d ON d.id = f.id
+        LEFT JOIN closure c ON c.ancestor = f.id
+        LEFT JOIN open_tasks o ON o.folder_id = c.descendant
+        GROUP BY f.id, d.depth
+        ORDER BY d.depth, f.name, f.created_at
+    `, folderWalkLimit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("folder tree: %w", err)
+	}
+	defer rows.Close()
+
+	folders := []*domain.FolderNode{}
+	for rows.Next() {
+		var n domain.FolderNode
+		if err := rows.Scan(&n.ID, &n.ParentID, &n.Name, &n.CreatedAt, &n.UpdatedAt, &n.Depth, &n.OpenTasks); err != nil {
+			return nil, nil, fmt.Errorf("scan folder node: %w", err)
+		}
+		folders = append(folders, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	lists, err := queryListNodes(ctx, q, `
+        SELECT `+listNodeColumns+`
+        FROM lists l
+        LEFT JOIN tasks t ON t.list_id = l.id
+        GROUP BY l.id
+        ORDER BY l.created_at DESC
+    `)
+	if err != nil {
+		return nil, nil, err
+	}
+	return folders, lists, nil
+}
+
+func queryListNodes(ctx context.Context, q querier, sql string, args ...any) ([]*domain.ListNode, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list folder lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []*domain.ListNode{}
+	for rows.Next() {
+		var n domain.ListNode
+		if err := rows.Scan(&n.ID, &n.Title, &n.Description, &n.Version, &n.ArchivedAt, &n.CreatedAt, &n.FolderID, &n.OpenTasks); err != nil {
+			return nil, fmt.Errorf("scan folder list: %w", err)
+		}
+		lists = append(lists, &n)
+	}
+	return lists, rows.Err()
+}
@@ -0,0 +1,105 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage/postgres"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderRepository_Tree(t *testing.T) {
+	ctx := context.Background()
+	repo := postgres.NewFolderRepo(db)
+	_, err := db.Exec(ctx, `TRUNCATE TABLE tasks, lists, folders RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+
+	newFolder := func(name string, parentID *string) *domain.Folder {
+		f := &domain.Folder{ID: uuid.NewString(), Name: name, ParentID: parentID}
+		require.NoError(t, repo.Create(ctx, f))
+		return f
+	}
+	newList := func(title string, folderID *string, open, done int) string {
+		l := domain.NewList(title, "")
+		_, err := db.Exec(ctx, `INSERT INTO lists (id, title, folder_id) VALUES ($1, $2, $3)`, l.ID, l.Title, folderID)
+		require.NoError(t, err)
+		_, err = db.Exec(ctx, `
+            INSERT INTO tasks (list_id, text, completed)
+            SELECT $1, 'task ' || i, i > $2 FROM generate_series(1, $2 + $3) AS i
+        `, l.ID, open, done)
+		require.NoError(t, err)
+		return l.ID
+	}
+
+	work := newFolder("Работа", nil)
+	sprint := newFolder("Спринт", &work.ID)
+	archive := newFolder("Архив", &sprint.ID)
+	home := newFolder("Дом", nil)
+	newList("Входящие", nil, 1, 1)
+	newList("Планы", &work.ID, 2, 0)
+	newList("Задачи", &sprint.ID, 3, 5)
+	newList("Старое", &archive.ID, 4, 0)
+
+	ancestors, err := repo.Ancestors(ctx, archive.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{archive.ID, sprint.ID, work.ID}, ancestors)
+	height, err := repo.Height(ctx, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3, height)
+	_, err = repo.Ancestors(ctx, uuid.NewString())
+	require.ErrorIs(t, err, postgres.ErrNotFound)
+
+	folders, lists, err := repo.Tree(ctx)
+	require.NoError(t, err)
+	require.Len(t, lists, 4)
+	got := map[string][2]int{}
+	for _, f := range folders {
+		got[f.Name] = [2]int{f.Depth, f.OpenTasks}
+	}
+	require.Equal(t, map[string][2]int{
+		"Работа": {1, 9},
+		"Дом":    {1, 0},
+		"Спринт": {2, 7},
+		"Архив":  {3, 4},
+	}, got)
+	require.Equal(t, home.ID, folders[0].ID, "folders are ordered by depth, then name")
+
+	page, total, err := repo.Lists(ctx, sprint.ID, 20, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, 3, page[0].OpenTasks)
+
+	listRepo := postgres.NewListRepo(db)
+	list, err := listRepo.GetByID(ctx, page[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, &sprint.ID, list.FolderID)
+	missing := uuid.NewString()
+	list.FolderID = &missing
+	require.ErrorIs(t, listRepo.Update(ctx, list), postgres.ErrNotFound)
+
+	// Переименование не трогает родителя, перенос — название.
+	renamed, err := repo.Rename(ctx, archive.ID, "Архив 2025")
+	require.NoError(t, err)
+	require.Equal(t, &sprint.ID, renamed.ParentID)
+	_, err = repo.Move(ctx, archive.ID, &missing)
+	require.ErrorIs(t, err, postgres.ErrNotFound)
+	_, err = repo.Rename(ctx, "not-a-uuid", "Папка")
+	require.ErrorIs(t, err, postgres.ErrNotFound)
+
+	ids, err := repo.ListIDs(ctx, sprint.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{list.ID}, ids)
+	_, err = repo.ListIDs(ctx, missing)
+	require.ErrorIs(t, err, postgres.ErrNotFound)
+
+	// Удалённая папка отдаёт вложенные папки родителю; списки переносит
+	// FolderService до удаления.
+	require.NoError(t, repo.Delete(ctx, sprint.ID))
+	moved, err := repo.Get(ctx, archive.ID)
+	require.NoError(t, err)
+	require.Equal(t, &work.ID, moved.ParentID)
+	require.Equal(t, "Архив 2025", moved.Name)
+}
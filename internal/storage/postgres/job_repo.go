@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage"
+)
+
+type JobRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewJobRepo(pool *pgxpool.Pool) *JobRepo {
+	return &JobRepo{pool: pool}
+}
+
+const jobColumns = `id, kind, args, state, attempts, max_attempts, run_at, locked_until, COALESCE(unique_key, ''),
+	COALESCE(last_error, ''), finished_at, created_at, updated_at`
+
+// Enqueue работает через conn, поэтому задача, поставленная в транзакции
+// изменения, появится в очереди только вместе с ним.
+func (r *JobRepo) Enqueue(ctx context.Context, j *domain.Job) (bool, error) {
+	var uniqueKey *string
+	if j.UniqueKey != "" {
+		uniqueKey = &j.UniqueKey
+	}
+	err := conn(ctx, r.pool).QueryRow(ctx, `
+		INSERT INTO jobs (kind, args, max_attempts, run_at, unique_key)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (unique_key) WHERE state IN ('pending', 'running') DO NOTHING
+		RETURNING id, state, created_at, updated_at`,
+		j.Kind, j.Args, j.MaxAttempts, j.RunAt, uniqueKey).
+		Scan(&j.ID, &j.State, &j.CreatedAt, &j.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("enqueue job: %w", err)
+	}
+	return true, nil
+}
+
+// ClaimDue захватывает задачи с SKIP LOCKED: два обработчика не возьмут
+// одну задачу, а задача упавшего обработчика снова станет доступной после
+// visibility.
+func (r *JobRepo) ClaimDue(ctx context.Context, kinds []string, limit int, visibility time.Duration) ([]*domain.Job, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+		WITH due AS (
+			SELECT id AS due_id FROM jobs
+			WHERE kind = ANY($1)
+			  AND ((state = 'pending' AND run_at <= NOW()) OR (state = 'running' AND locked_until <= NOW()))
+			ORDER BY run_at, id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE jobs j
+		SET state = 'running', attempts = j.attempts + 1,
+		    locked_until = NOW() + make_interval(secs => $3), updated_at = NOW()
+		FROM due
+		WHERE j.id = due.due_id
+		RETURNING `+jobColumns,
+		kinds, limit, visibility.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan job: %w", err)
+		}
+		res = append(res, j)
+	}
+	return res, rows.Err()
+}
+
+// Complete проверяет attempts: если задачу после истечения locked_until
+// захватили снова, результат прежней попытки не записывается.
+func (r *JobRepo) Complete(ctx context.Context, j *domain.Job) error {
+	var lastError *string
+	if j.LastError != "" {
+		lastError = &j.LastError
+	}
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE jobs
+		SET state = $3, run_at = $4, last_error = $5, finished_at = $6, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND state = 'running' AND attempts = $2`,
+		j.ID, j.Attempts, j.State, j.RunAt, lastError, j.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("complete job: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrConflict
+	}
+	return nil
+}
+
+func (r *JobRepo) Get(ctx context.Context, id int64) (*domain.Job, error) {
+	j, err := scanJob(conn(ctx, r.pool).QueryRow(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	return j, nil
+}
+
+func (r *JobRepo) List(ctx context.Context, state, kind string, limit, offset int) ([]*domain.Job, int, error) {
+	q := conn(ctx, r.pool)
+	const where = `WHERE ($1 = '' OR state = $1) AND ($2 = '' OR kind = $2)`
+
+	var total int
+	if err := q.QueryRow(ctx, `SELECT COUNT(*) FROM jobs `+where, state, kind).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count jobs: %w", err)
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT `+jobColumns+` FROM jobs `+where+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`, state, kind, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.Job{}
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan job: %w", err)
+		}
+		res = append(res, j)
+	}
+	return res, total, rows.Err()
+}
+
+func (r *JobRepo) Retry(ctx context.Context, id int64) (*domain.Job, error) {
+	q := conn(ctx, r.pool)
+	j, err := scanJob(q.QueryRow(ctx, `
+		UPDATE jobs
+		SET state = 'pending', attempts = 0, run_at = NOW(), finished_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND state = 'failed'
+		RETURNING `+jobColumns, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Отличаем отсутствующую задачу от задачи в другом состоянии.
+		if _, err := r.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, storage.ErrConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("retry job: %w", err)
+	}
+	return j, nil
+}
+
+func (r *JobRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := conn(ctx, r.pool).Exec(ctx,
+		`DELETE FROM jobs WHERE state = 'completed' AND finished_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge jobs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ClaimSchedule полагается на блокировку строки: из одновременных UPDATE
+// условие next_run_at <= now после первого выполнится только у него.
+func (r *JobRepo) ClaimSchedule(ctx context.Context, name string, now, next time.Time) (bool, error) {
+	q := conn(ctx, r.pool)
+	_, err := q.Exec(ctx, `
+		INSERT INTO job_schedules (name, next_run_at) VALUES ($1, $2)
+		ON CONFLICT (name) DO NOTHING`, name, next)
+	if err != nil {
+		return false, fmt.Errorf("register job schedule: %w", err)
+	}
+	tag, err := q.Exec(ctx, `
+		UPDATE job_schedules SET next_run_at = $3
+		WHERE name = $1 AND next_run_at <= $2`, name, now, next)
+	if err != nil {
+		return false, fmt.Errorf("claim job schedule: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func scanJob(row pgx.Row) (*domain.Job, error) {
+	var j domain.Job
+	err := row.Scan(&j.ID, &j.Kind, &j.Args, &j.State, &j.Attempts, &j.MaxAttempts, &j.RunAt, &j.LockedUntil,
+		&j.UniqueKey, &j.LastError, &j.FinishedAt, &j.CreatedAt, &j.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
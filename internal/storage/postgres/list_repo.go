@@ -8,12 +8,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"todo-api/internal/domain"
+	"todo-api/internal/storage"
 )
 
-var ErrNotFound = errors.New("not found")
+// ErrNotFound — storage.ErrNotFound, чтобы сервисы и обработчики отличали
+// отсутствие сущности от сбоя базы.
+var ErrNotFound = storage.ErrNotFound
 
 type ListRepo struct {
 	pool *pgxpool.Pool
@@ -31,14 +35,15 @@ func (r *ListRepo) Create(ctx context.Context, list *domain.List) (*domain.List,
 	query := `
         INSERT INTO lists (id, title, description)
         VALUES ($1, $2, $3)
-        RETURNING created_at
+        RETURNING version, created_at
     `
-	err := r.pool.QueryRow(ctx, query, list.ID, list.Title, list.Description).Scan(&list.CreatedAt)
+	q := conn(ctx, r.pool)
+	err := q.QueryRow(ctx, query, list.ID, list.Title, list.Description).Scan(&list.Version, &list.CreatedAt)
 	if err != nil {
 		return list, fmt.Errorf("create list: %w", err)
 	}
 
-	return list, nil
+	return list, notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventListCreated, ListID: list.ID, List: list})
 }
 
 func (r *ListRepo) GetByID(ctx context.Context, id string) (*domain.List, error) {
@@ -47,14 +52,14 @@ func (r *ListRepo) GetByID(ctx context.Context, id string) (*domain.List, error)
 	defer cancel()
 
 	query := `
-        SELECT id, title, description, created_at
+        SELECT id, title, description, version, archived_at, folder_id, created_at
         FROM lists
         WHERE id = $1
     `
 	var list domain.List
-	err := r.pool.QueryRow(ctx, query, id).Scan(&list.ID, &list.Title, &list.Description, &list.CreatedAt)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(&list.ID, &list.Title, &list.Description, &list.Version, &list.ArchivedAt, &list.FolderID, &list.CreatedAt)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
 			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("get list by id: %w", err)
@@ -69,11 +74,20 @@ func (r *ListRepo) Update(ctx context.Context, list *domain.List) error {
 
 	query := `
         UPDATE lists
-        SET title = $2, description = $3
+        SET title = $2, description = $3, archived_at = $4, folder_id = $5, version = version + 1
         WHERE id = $1
-        RETURNING id, title, description, created_at
+        RETURNING id, title, description, version, archived_at, folder_id, created_at
     `
-	return r.pool.QueryRow(ctx, query, list.ID, list.Title, list.Description).Scan(&list.ID, &list.Title, &list.Description, &list.CreatedAt)
+	q := conn(ctx, r.pool)
+	err := q.QueryRow(ctx, query, list.ID, list.Title, list.Description, list.ArchivedAt, list.FolderID).
+		Scan(&list.ID, &list.Title, &list.Description, &list.Version, &list.ArchivedAt, &list.FolderID, &list.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || isForeignKeyViolation(err) || isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update list: %w", err)
+	}
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventListUpdated, ListID: list.ID, List: list})
 }
 
 func (r *ListRepo) Delete(ctx context.Context, id string) error {
@@ -81,15 +95,16 @@ func (r *ListRepo) Delete(ctx context.Context, id string) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	q := conn(ctx, r.pool)
 	query := `DELETE FROM lists WHERE id = $1`
-	result, err := r.pool.Exec(ctx, query, id)
+	result, err := q.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("delete list: %w", err)
 	}
 	if result.RowsAffected() == 0 {
 		return ErrNotFound
 	}
-	return nil
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventListDeleted, ListID: id})
 }
 
 func (r *ListRepo) GetAll(ctx context.Context) ([]*domain.List, int) {
@@ -97,7 +112,7 @@ func (r *ListRepo) GetAll(ctx context.Context) ([]*domain.List, int) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	rows, err := r.pool.Query(ctx, `SELECT id, title, description, created_at FROM lists ORDER BY created_at DESC`)
+	rows, err := conn(ctx, r.pool).Query(ctx, `SELECT id, title, description, version, archived_at, folder_id, created_at FROM lists ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, 0
 	}
@@ -106,7 +121,7 @@ func (r *ListRepo) GetAll(ctx context.Context) ([]*domain.List, int) {
 	var lists []*domain.List
 	for rows.Next() {
 		var list domain.List
-		if err := rows.Scan(&list.ID, &list.Title, &list.Description, &list.CreatedAt); err == nil {
+		if err := rows.Scan(&list.ID, &list.Title, &list.Description, &list.Version, &list.ArchivedAt, &list.FolderID, &list.CreatedAt); err == nil {
 			lists = append(lists, &list)
 		}
 	}
@@ -118,8 +133,8 @@ func (r *ListRepo) FindWithPagination(ctx context.Context, limit, offset int) ([
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	rows, err := r.pool.Query(ctx, `
-        SELECT id, title, description, created_at
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+        SELECT id, title, description, version, archived_at, folder_id, created_at
         FROM lists
         ORDER BY created_at DESC
         LIMIT $1 OFFSET $2
@@ -132,16 +147,156 @@ func (r *ListRepo) FindWithPagination(ctx context.Context, limit, offset int) ([
 	var lists []*domain.List
 	for rows.Next() {
 		var list domain.List
-		if err := rows.Scan(&list.ID, &list.Title, &list.Description, &list.CreatedAt); err == nil {
+		if err := rows.Scan(&list.ID, &list.Title, &list.Description, &list.Version, &list.ArchivedAt, &list.FolderID, &list.CreatedAt); err == nil {
 			lists = append(lists, &list)
 		}
 	}
 
 	var total int
-	_ = r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM lists`).Scan(&total)
+	_ = conn(ctx, r.pool).QueryRow(ctx, `SELECT COUNT(*) FROM lists`).Scan(&total)
 	return lists, total
 }
 
+// Restore записывает список целиком, включая id, версию и created_at.
+// expectedVersion 0 означает, что списка быть не должно, иначе текущая версия
+// должна совпасть. При расхождении возвращается storage.ErrConflict.
+func (r *ListRepo) Restore(ctx context.Context, list *domain.List, expectedVersion int64) error {
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var (
+		q      = conn(ctx, r.pool)
+		result pgconn.CommandTag
+		err    error
+	)
+	event := domain.EventListUpdated
+	if expectedVersion == 0 {
+		event = domain.EventListCreated
+		result, err = q.Exec(ctx, `
+        INSERT INTO lists (id, title, description, version, archived_at, folder_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (id) DO NOTHING
+    `, list.ID, list.Title, list.Description, list.Version, list.ArchivedAt, list.FolderID, list.CreatedAt)
+	} else {
+		result, err = q.Exec(ctx, `
+        UPDATE lists
+        SET title = $2, description = $3, version = $4, archived_at = $6, folder_id = $7
+        WHERE id = $1 AND version = $5
+    `, list.ID, list.Title, list.Description, list.Version, expectedVersion, list.ArchivedAt, list.FolderID)
+	}
+	// Папку, в которой лежал список, с тех пор могли удалить.
+	if isForeignKeyViolation(err) {
+		return storage.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("restore list: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return storage.ErrConflict
+	}
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: event, ListID: list.ID, List: list})
+}
+
+// DeleteVersion удаляет список, только если его версия не изменилась и в нём
+// нет задач: отмена создания не должна уносить чужие задачи каскадом.
+func (r *ListRepo) DeleteVersion(ctx context.Context, id string, version int64) error {
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        DELETE FROM lists
+        WHERE id = $1 AND version = $2
+          AND NOT EXISTS (SELECT 1 FROM tasks WHERE list_id = $1)
+    `
+	q := conn(ctx, r.pool)
+	result, err := q.Exec(ctx, query, id, version)
+	if err != nil {
+		return fmt.Errorf("delete list: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return storage.ErrConflict
+	}
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventListDeleted, ListID: id})
+}
+
+// Clone копирует список и его задачи одним запросом: строки задач не
+// проходят через приложение, поэтому копия большого списка создаётся
+// быстро. id задач генерирует база, копия попадает в ту же папку.
+// Возвращает копии задач в порядке создания; о списке и о каждой задаче
+// в канал изменений отправляется событие создания.
+func (r *ListRepo) Clone(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Порядок задач определяется created_at, поэтому копии получают
+	// возрастающее время создания: с KeepOrder в порядке исходных задач,
+	// без него — в порядке их id.
+	query := `
+        WITH src AS (
+            SELECT id, folder_id FROM lists WHERE id = $1 FOR SHARE
+        ), new_list AS (
+            INSERT INTO lists (id, title, description, folder_id)
+            SELECT $2, $3, $4, folder_id FROM src
+            RETURNING version, folder_id, created_at
+        ), new_tasks AS (
+            INSERT INTO tasks (id, list_id, text, completed, completed_at, due_at, assignee,
+                               labels, priority, recurrence, created_at, updated_at)
+            SELECT gen_random_uuid(), $2, t.text,
+                   t.completed AND NOT $5, CASE WHEN $5 THEN NULL ELSE t.completed_at END,
+                   t.due_at, t.assignee,
+                   CASE WHEN $6 THEN t.labels ELSE '{}' END, t.priority, t.recurrence,
+                   NOW() + ROW_NUMBER() OVER (ORDER BY CASE WHEN $7 THEN t.created_at END, t.id) * INTERVAL '1 microsecond',
+                   NOW()
+            FROM tasks t
+            JOIN src ON t.list_id = src.id
+            WHERE $8
+            RETURNING 1
+        )
+        SELECT version, folder_id, created_at, (SELECT COUNT(*) FROM new_tasks) FROM new_list
+    `
+	q := conn(ctx, r.pool)
+	var count int
+	err := q.QueryRow(ctx, query, srcID, clone.ID, clone.Title, clone.Description,
+		opts.ResetCompletion, opts.KeepLabels, opts.KeepOrder, opts.IncludeTasks).Scan(&clone.Version, &clone.FolderID, &clone.CreatedAt, &count)
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("clone list: %w", err)
+	}
+	if err := notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventListCreated, ListID: clone.ID, List: clone}); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*domain.Task, 0, count)
+	if count == 0 {
+		return tasks, nil
+	}
+	rows, err := q.Query(ctx, `SELECT `+taskColumns+` FROM tasks WHERE list_id = $1 ORDER BY created_at, id`, clone.ID)
+	if err != nil {
+		return nil, fmt.Errorf("load cloned tasks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan cloned task: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("load cloned tasks: %w", err)
+	}
+	for _, t := range tasks {
+		if err := notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventTaskCreated, ListID: t.ListID, TaskID: t.ID, Task: t}); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
 func (r *ListRepo) CreateWithItems(ctx context.Context, title string, items []string) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -176,22 +331,22 @@ func (r *ListRepo) SearchByTitle(ctx context.Context, query string) ([]domain.Li
 	defer cancel()
 
 	sqlQuery := `
-		SELECT id, title, description, created_at
+		SELECT id, title, description, version, archived_at, folder_id, created_at
 		FROM lists
 		WHERE title ILIKE $1
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.pool.Query(ctx, sqlQuery, "%"+query+"%")
+	rows, err := conn(ctx, r.pool).Query(ctx, sqlQuery, "%"+query+"%")
 	if err != nil {
 		return nil, fmt.Errorf("search lists by title: %w", err)
 	}
 	defer rows.Close()
 
-	var lists []domain.List
+	lists := []domain.List{}
 	for rows.Next() {
 		var list domain.List
-		if err := rows.Scan(&list.ID, &list.Title, &list.Description, &list.CreatedAt); err != nil {
+		if err := rows.Scan(&list.ID, &list.Title, &list.Description, &list.Version, &list.ArchivedAt, &list.FolderID, &list.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan list: %w", err)
 		}
 		lists = append(lists, list)
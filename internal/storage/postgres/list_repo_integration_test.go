@@ -0,0 +1,140 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage/postgres"
+
+	"github.com/stretchr/testify/require"
+)
+
+// seedCloneSource создаёт список с n задачами: каждая третья выполнена,
+// у всех есть метка.
+func seedCloneSource(tb testing.TB, n int) string {
+	tb.Helper()
+	ctx := context.Background()
+	src := domain.NewList("Clone source", "desc")
+	_, err := db.Exec(ctx, `INSERT INTO lists (id, title, description) VALUES ($1, $2, $3)`, src.ID, src.Title, src.Description)
+	require.NoError(tb, err)
+	_, err = db.Exec(ctx, `
+        INSERT INTO tasks (list_id, text, completed, completed_at, labels, priority, created_at)
+        SELECT $1, 'task ' || i, i % 3 = 0, CASE WHEN i % 3 = 0 THEN NOW() END, '{work}', 'high',
+               NOW() - (($2 - i) * INTERVAL '1 second')
+        FROM generate_series(1, $2) AS i
+    `, src.ID, n)
+	require.NoError(tb, err)
+	return src.ID
+}
+
+func TestListRepository_Clone(t *testing.T) {
+	ctx := context.Background()
+	repo := postgres.NewListRepo(db)
+	_, err := db.Exec(ctx, `TRUNCATE TABLE tasks, lists RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+
+	srcID := seedCloneSource(t, 5000)
+	clone := domain.NewList("Clone", "desc")
+	tasks, err := repo.Clone(ctx, srcID, clone, domain.CloneOptions{
+		IncludeTasks: true, ResetCompletion: true, KeepOrder: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 5000)
+	require.EqualValues(t, 1, clone.Version)
+	require.Equal(t, clone.ID, tasks[0].ListID)
+	require.Equal(t, "task 1", tasks[0].Text)
+	require.Equal(t, "task 5000", tasks[4999].Text)
+
+	var texts []string
+	rows, err := db.Query(ctx, `SELECT text FROM tasks WHERE list_id = $1 ORDER BY created_at`, clone.ID)
+	require.NoError(t, err)
+	for rows.Next() {
+		var text string
+		require.NoError(t, rows.Scan(&text))
+		texts = append(texts, text)
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, texts, 5000)
+	require.Equal(t, "task 1", texts[0])
+	require.Equal(t, "task 5000", texts[4999])
+
+	var completed, labelled, shared int
+	err = db.QueryRow(ctx, `
+        SELECT COUNT(*) FILTER (WHERE completed OR completed_at IS NOT NULL),
+               COUNT(*) FILTER (WHERE labels <> '{}'),
+               COUNT(*) FILTER (WHERE id IN (SELECT id FROM tasks WHERE list_id = $2))
+        FROM tasks WHERE list_id = $1
+    `, clone.ID, srcID).Scan(&completed, &labelled, &shared)
+	require.NoError(t, err)
+	require.Zero(t, completed)
+	require.Zero(t, labelled)
+	require.Zero(t, shared)
+
+	// Без KeepOrder у копий тоже разное время создания, и порядок задач
+	// не зависит от плана запроса.
+	unordered := domain.NewList("Unordered clone", "")
+	tasks, err = repo.Clone(ctx, srcID, unordered, domain.CloneOptions{IncludeTasks: true})
+	require.NoError(t, err)
+	require.Len(t, tasks, 5000)
+	for i := 1; i < len(tasks); i++ {
+		require.True(t, tasks[i].CreatedAt.After(tasks[i-1].CreatedAt), "task %d shares created_at with the previous one", i)
+	}
+
+	empty := domain.NewList("Empty clone", "")
+	tasks, err = repo.Clone(ctx, srcID, empty, domain.CloneOptions{})
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+
+	_, err = repo.Clone(ctx, domain.NewList("missing", "").ID, domain.NewList("x", ""), domain.CloneOptions{IncludeTasks: true})
+	require.ErrorIs(t, err, postgres.ErrNotFound)
+}
+
+func TestListRepository_UpdateAndArchive(t *testing.T) {
+	ctx := context.Background()
+	repo := postgres.NewListRepo(db)
+	_, err := db.Exec(ctx, `TRUNCATE TABLE tasks, lists RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+
+	list, err := repo.Create(ctx, domain.NewList("Покупки", ""))
+	require.NoError(t, err)
+
+	list.Title, list.Description = "Продукты", "на неделю"
+	require.NoError(t, repo.Update(ctx, list))
+	require.EqualValues(t, 2, list.Version)
+	require.Nil(t, list.ArchivedAt)
+
+	archivedAt := time.Now().UTC().Truncate(time.Microsecond)
+	list.ArchivedAt = &archivedAt
+	require.NoError(t, repo.Update(ctx, list))
+	require.EqualValues(t, 3, list.Version)
+	stored, err := repo.GetByID(ctx, list.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Продукты", stored.Title)
+	require.Equal(t, "на неделю", stored.Description)
+	require.NotNil(t, stored.ArchivedAt)
+	require.True(t, stored.ArchivedAt.Equal(archivedAt))
+
+	list.ArchivedAt = nil
+	require.NoError(t, repo.Update(ctx, list))
+	stored, err = repo.GetByID(ctx, list.ID)
+	require.NoError(t, err)
+	require.Nil(t, stored.ArchivedAt)
+
+	require.ErrorIs(t, repo.Update(ctx, domain.NewList("missing", "")), postgres.ErrNotFound)
+}
+
+func BenchmarkListRepository_Clone(b *testing.B) {
+	ctx := context.Background()
+	repo := postgres.NewListRepo(db)
+	srcID := seedCloneSource(b, 5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clone := domain.NewList("Bench clone", "")
+		if _, err := repo.Clone(ctx, srcID, clone, domain.CloneOptions{IncludeTasks: true, KeepLabels: true, KeepOrder: true}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type NotificationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepo(pool *pgxpool.Pool) *NotificationRepo {
+	return &NotificationRepo{pool: pool}
+}
+
+func (r *NotificationRepo) Settings(ctx context.Context, userID string) (*domain.NotificationSettings, error) {
+	q := conn(ctx, r.pool)
+	s := domain.NotificationSettings{UserID: userID, Preferences: map[string]map[string]bool{}, DigestLists: []string{}}
+	err := q.QueryRow(ctx,
+		`SELECT email, locale, timezone FROM notification_settings WHERE user_id = $1`, userID).
+		Scan(&s.Email, &s.Locale, &s.TimeZone)
+	found := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get notification settings: %w", err)
+	}
+
+	rows, err := q.Query(ctx,
+		`SELECT event, channel, enabled FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+	for rows.Next() {
+		var (
+			event, channel string
+			enabled        bool
+		)
+		if err := rows.Scan(&event, &channel, &enabled); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan notification preference: %w", err)
+		}
+		if s.Preferences[event] == nil {
+			s.Preferences[event] = map[string]bool{}
+		}
+		s.Preferences[event][channel] = enabled
+		found = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+
+	rows, err = q.Query(ctx,
+		`SELECT list_id::text FROM digest_subscriptions WHERE user_id = $1 ORDER BY created_at, list_id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get digest subscriptions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var listID string
+		if err := rows.Scan(&listID); err != nil {
+			return nil, fmt.Errorf("scan digest subscription: %w", err)
+		}
+		s.DigestLists = append(s.DigestLists, listID)
+		found = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get digest subscriptions: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (r *NotificationRepo) SaveSettings(ctx context.Context, s *domain.NotificationSettings) error {
+	q := conn(ctx, r.pool)
+	_, err := q.Exec(ctx, `
+		INSERT INTO notification_settings (user_id, email, locale, timezone) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email, locale = EXCLUDED.locale, timezone = EXCLUDED.timezone, updated_at = NOW()`,
+		s.UserID, s.Email, s.Locale, s.TimeZone)
+	if err != nil {
+		return fmt.Errorf("save notification settings: %w", err)
+	}
+
+	if _, err := q.Exec(ctx, `DELETE FROM notification_preferences WHERE user_id = $1`, s.UserID); err != nil {
+		return fmt.Errorf("save notification preferences: %w", err)
+	}
+	var events, channels []string
+	var enabled []bool
+	for event, byChannel := range s.Preferences {
+		for channel, on := range byChannel {
+			events = append(events, event)
+			channels = append(channels, channel)
+			enabled = append(enabled, on)
+		}
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO notification_preferences (user_id, event, channel, enabled)
+		SELECT $1, p.event, p.channel, p.enabled
+		FROM unnest($2::text[], $3::text[], $4::boolean[]) AS p(event, channel, enabled)`,
+		s.UserID, events, channels, enabled)
+	if err != nil {
+		return fmt.Errorf("save notification preferences: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) Subscribe(ctx context.Context, userID, listID string) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO digest_subscriptions (user_id, list_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, userID, listID)
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("subscribe to digest: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) Unsubscribe(ctx context.Context, userID, listID string) error {
+	_, err := conn(ctx, r.pool).Exec(ctx,
+		`DELETE FROM digest_subscriptions WHERE user_id = $1 AND list_id = $2`, userID, listID)
+	if err != nil {
+		return fmt.Errorf("unsubscribe from digest: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) DigestSubscribers(ctx context.Context) ([]*domain.NotificationSettings, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+		SELECT s.user_id, s.email, s.locale, s.timezone, array_agg(d.list_id::text ORDER BY d.created_at, d.list_id)
+		FROM notification_settings s
+		JOIN digest_subscriptions d ON d.user_id = s.user_id
+		WHERE s.email <> ''
+		GROUP BY s.user_id
+		ORDER BY s.user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list digest subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.NotificationSettings
+	for rows.Next() {
+		var s domain.NotificationSettings
+		if err := rows.Scan(&s.UserID, &s.Email, &s.Locale, &s.TimeZone, &s.DigestLists); err != nil {
+			return nil, fmt.Errorf("scan digest subscriber: %w", err)
+		}
+		res = append(res, &s)
+	}
+	return res, rows.Err()
+}
+
+func (r *NotificationRepo) OpenTasks(ctx context.Context, listID string, limit int) ([]*domain.Task, error) {
+	return r.queryTasks(ctx, `
+		SELECT `+taskColumns+` FROM tasks
+		WHERE list_id = $1 AND NOT completed
+		ORDER BY due_at NULLS LAST, created_at
+		LIMIT $2`, listID, limit)
+}
+
+func (r *NotificationRepo) DueBetween(ctx context.Context, from, to time.Time) ([]*domain.Task, error) {
+	return r.queryTasks(ctx, `
+		SELECT `+taskColumns+` FROM tasks
+		WHERE assignee <> '' AND NOT completed AND due_at >= $1 AND due_at < $2
+		ORDER BY due_at`, from, to)
+}
+
+func (r *NotificationRepo) queryTasks(ctx context.Context, query string, args ...any) ([]*domain.Task, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+func (r *NotificationRepo) Enqueue(ctx context.Context, ns []*domain.Notification) error {
+	q := conn(ctx, r.pool)
+	for _, n := range ns {
+		var dedupKey *string
+		if n.DedupKey != "" {
+			dedupKey = &n.DedupKey
+		}
+		_, err := q.Exec(ctx, `
+			INSERT INTO notifications (user_id, event, channel, payload, dedup_key)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (channel, dedup_key) DO NOTHING`,
+			n.UserID, n.Event, n.Channel, n.Payload, dedupKey)
+		if err != nil {
+			return fmt.Errorf("enqueue notification: %w", err)
+		}
+	}
+	return nil
+}
+
+// ClaimDue захватывает уведомления так же, как WebhookRepo.ClaimDue: SKIP
+// LOCKED не даёт двум экземплярам взять одно уведомление, а после lease
+// уведомление упавшего отправителя снова становится доступным.
+func (r *NotificationRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.Notification, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+		WITH due AS (
+			SELECT id FROM notifications
+			WHERE state = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notifications n
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due
+		WHERE n.id = due.id
+		RETURNING n.id, n.user_id, n.event, n.channel, n.payload, COALESCE(n.dedup_key, ''), n.state,
+		          n.attempts, n.next_attempt_at, COALESCE(n.last_error, ''), n.sent_at, n.created_at`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Notification
+	for rows.Next() {
+		var n domain.Notification
+		err := rows.Scan(&n.ID, &n.UserID, &n.Event, &n.Channel, &n.Payload, &n.DedupKey, &n.State,
+			&n.Attempts, &n.NextAttemptAt, &n.LastError, &n.SentAt, &n.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan notification: %w", err)
+		}
+		res = append(res, &n)
+	}
+	return res, rows.Err()
+}
+
+func (r *NotificationRepo) Complete(ctx context.Context, n *domain.Notification) error {
+	var lastError *string
+	if n.LastError != "" {
+		lastError = &n.LastError
+	}
+	_, err := r.pool.Exec(ctx, `
+		UPDATE notifications
+		SET state = $2, attempts = $3, next_attempt_at = $4, last_error = $5, sent_at = $6
+		WHERE id = $1`,
+		n.ID, n.State, n.Attempts, n.NextAttemptAt, lastError, n.SentAt)
+	if err != nil {
+		return fmt.Errorf("complete notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type ReminderRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReminderRepo(pool *pgxpool.Pool) *ReminderRepo {
+	return &ReminderRepo{pool: pool}
+}
+
+// reminderColumns — колонки в порядке scanReminder. fire_at пересчитывают
+// триггеры при изменении напоминания и срока задачи.
+const reminderColumns = `r.id, r.task_id, t.list_id, r.user_id, r.remind_at, r.due_offset_minutes, r.channels,
+	r.state, r.snoozed_until, r.fire_at, r.fired_at, r.created_at, r.updated_at`
+
+// reminderTaskColumns — колонки задачи напоминания в порядке scanTask.
+const reminderTaskColumns = `t.id, t.list_id, t.text, t.completed, t.due_at, t.completed_at, t.version, t.created_at, t.updated_at, t.assignee,
+	t.labels, t.priority, t.recurrence`
+
+func (r *ReminderRepo) Create(ctx context.Context, rem *domain.Reminder) error {
+	err := conn(ctx, r.pool).QueryRow(ctx, `
+		INSERT INTO reminders (id, task_id, user_id, remind_at, due_offset_minutes, channels, state)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at`,
+		rem.ID, rem.TaskID, rem.UserID, rem.RemindAt, rem.DueOffsetMinutes, rem.Channels, rem.State).
+		Scan(&rem.CreatedAt, &rem.UpdatedAt)
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("create reminder: %w", err)
+	}
+	return nil
+}
+
+func (r *ReminderRepo) Get(ctx context.Context, userID, id string) (*domain.Reminder, error) {
+	rem, err := scanReminder(conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT `+reminderColumns+`
+		FROM reminders r JOIN tasks t ON t.id = r.task_id
+		WHERE r.id = $1 AND r.user_id = $2`, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get reminder: %w", err)
+	}
+	return rem, nil
+}
+
+// getByID читает напоминание любого пользователя для события изменений.
+func (r *ReminderRepo) getByID(ctx context.Context, id string) (*domain.Reminder, error) {
+	rem, err := scanReminder(conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT `+reminderColumns+`
+		FROM reminders r JOIN tasks t ON t.id = r.task_id
+		WHERE r.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get reminder: %w", err)
+	}
+	return rem, nil
+}
+
+func (r *ReminderRepo) ListByTask(ctx context.Context, userID, taskID string) ([]*domain.Reminder, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+		SELECT `+reminderColumns+`
+		FROM reminders r JOIN tasks t ON t.id = r.task_id
+		WHERE r.task_id = $1 AND r.user_id = $2
+		ORDER BY r.created_at, r.id`, taskID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list reminders: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.Reminder{}
+	for rows.Next() {
+		rem, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan reminder: %w", err)
+		}
+		res = append(res, rem)
+	}
+	return res, rows.Err()
+}
+
+func (r *ReminderRepo) Update(ctx context.Context, rem *domain.Reminder) error {
+	err := conn(ctx, r.pool).QueryRow(ctx, `
+		UPDATE reminders
+		SET state = $3, snoozed_until = $4, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at`,
+		rem.ID, rem.UserID, rem.State, rem.SnoozedUntil).Scan(&rem.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update reminder: %w", err)
+	}
+	return nil
+}
+
+func (r *ReminderRepo) Delete(ctx context.Context, userID, id string) error {
+	tag, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM reminders WHERE id = $1 AND user_id = $2`, id, userID)
+	if isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("delete reminder: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ClaimDue выбирает напоминания с FOR UPDATE SKIP LOCKED: пока транзакция
+// открыта, другие экземпляры сервиса их не видят, а после фиксации они уже
+// в состоянии fired. Напоминания выполненных задач не срабатывают.
+func (r *ReminderRepo) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.Reminder, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+		SELECT `+reminderColumns+`, `+reminderTaskColumns+`
+		FROM reminders r JOIN tasks t ON t.id = r.task_id
+		WHERE r.state = 'pending' AND r.fire_at <= $1 AND NOT t.completed
+		ORDER BY r.fire_at
+		LIMIT $2
+		FOR UPDATE OF r SKIP LOCKED`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Reminder
+	for rows.Next() {
+		var (
+			rem domain.Reminder
+			t   domain.Task
+		)
+		err := rows.Scan(&rem.ID, &rem.TaskID, &rem.ListID, &rem.UserID, &rem.RemindAt, &rem.DueOffsetMinutes, &rem.Channels,
+			&rem.State, &rem.SnoozedUntil, &rem.FireAt, &rem.FiredAt, &rem.CreatedAt, &rem.UpdatedAt,
+			&t.ID, &t.ListID, &t.Text, &t.Completed, &t.DueAt, &t.CompletedAt, &t.Version, &t.CreatedAt, &t.UpdatedAt, &t.Assignee,
+			&t.Labels, &t.Priority, &t.Recurrence)
+		if err != nil {
+			return nil, fmt.Errorf("scan reminder: %w", err)
+		}
+		rem.Task = &t
+		res = append(res, &rem)
+	}
+	return res, rows.Err()
+}
+
+func (r *ReminderRepo) MarkFired(ctx context.Context, ids []string, at time.Time) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, `
+		UPDATE reminders SET state = 'fired', fired_at = $2, updated_at = NOW()
+		WHERE id = ANY($1::uuid[])`, ids, at)
+	if err != nil {
+		return fmt.Errorf("mark reminders fired: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure откладывает повтор на минуту, удваивая паузу после каждой
+// неудачи подряд, но не больше чем на час.
+func (r *ReminderRepo) RecordFailure(ctx context.Context, id string, at time.Time) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, `
+		UPDATE reminders
+		SET fire_at = $2::timestamptz + LEAST(INTERVAL '1 minute' * power(2, delivery_attempts), INTERVAL '1 hour'),
+		    delivery_attempts = delivery_attempts + 1, updated_at = NOW()
+		WHERE id = $1 AND state = 'pending'`, id, at)
+	if err != nil {
+		return fmt.Errorf("record reminder failure: %w", err)
+	}
+	return nil
+}
+
+func (r *ReminderRepo) Publish(ctx context.Context, ev domain.ChangeEvent) error {
+	return notifyChange(ctx, conn(ctx, r.pool), ev)
+}
+
+func scanReminder(row pgx.Row) (*domain.Reminder, error) {
+	var rem domain.Reminder
+	err := row.Scan(&rem.ID, &rem.TaskID, &rem.ListID, &rem.UserID, &rem.RemindAt, &rem.DueOffsetMinutes, &rem.Channels,
+		&rem.State, &rem.SnoozedUntil, &rem.FireAt, &rem.FiredAt, &rem.CreatedAt, &rem.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rem, nil
+}
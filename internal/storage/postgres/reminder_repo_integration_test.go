@@ -0,0 +1,65 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage/postgres"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderRepository_FireAt(t *testing.T) {
+	ctx := context.Background()
+	repo := postgres.NewReminderRepo(db)
+	_, err := db.Exec(ctx, `TRUNCATE TABLE tasks, lists RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	list := domain.NewList("Работа", "")
+	taskID := uuid.NewString()
+	_, err = db.Exec(ctx, `INSERT INTO lists (id, title) VALUES ($1, $2)`, list.ID, list.Title)
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, `INSERT INTO tasks (id, list_id, text, due_at) VALUES ($1, $2, 'Отчёт', $3)`, taskID, list.ID, due)
+	require.NoError(t, err)
+
+	offset := -30
+	rem := &domain.Reminder{ID: uuid.NewString(), TaskID: taskID, UserID: "olga", DueOffsetMinutes: &offset,
+		Channels: []string{domain.ReminderChannelSSE}, State: domain.ReminderPending}
+	require.NoError(t, repo.Create(ctx, rem))
+	got, err := repo.Get(ctx, "olga", rem.ID)
+	require.NoError(t, err)
+	require.True(t, got.FireAt.Equal(due.Add(-30*time.Minute)))
+
+	// Относительное напоминание следует за переносом срока.
+	due = due.Add(24 * time.Hour)
+	_, err = db.Exec(ctx, `UPDATE tasks SET due_at = $2 WHERE id = $1`, taskID, due)
+	require.NoError(t, err)
+	got, err = repo.Get(ctx, "olga", rem.ID)
+	require.NoError(t, err)
+	require.True(t, got.FireAt.Equal(due.Add(-30*time.Minute)))
+
+	// Неудачная доставка откладывает повтор, срабатывания до него нет.
+	now := due
+	require.NoError(t, repo.RecordFailure(ctx, rem.ID, now))
+	got, err = repo.Get(ctx, "olga", rem.ID)
+	require.NoError(t, err)
+	require.True(t, got.FireAt.Equal(now.Add(time.Minute)))
+	claimed, err := repo.ClaimDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+	claimed, err = repo.ClaimDue(ctx, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	// Отложенное напоминание срабатывает в snoozed_until.
+	snoozed := now.Add(2 * time.Hour)
+	got.SnoozedUntil = &snoozed
+	require.NoError(t, repo.Update(ctx, got))
+	got, err = repo.Get(ctx, "olga", rem.ID)
+	require.NoError(t, err)
+	require.True(t, got.FireAt.Equal(snoozed))
+}
@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type RuleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRuleRepo(pool *pgxpool.Pool) *RuleRepo {
+	return &RuleRepo{pool: pool}
+}
+
+const ruleColumns = `id, owner_id, list_id, name, trigger, conditions, actions, enabled, created_at, updated_at`
+
+func (r *RuleRepo) Create(ctx context.Context, rule *domain.Rule) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conditions, actions, err := marshalRule(rule)
+	if err != nil {
+		return err
+	}
+	query := `
+        INSERT INTO rules (id, owner_id, list_id, name, trigger, conditions, actions, enabled)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING created_at, updated_at
+    `
+	err = conn(ctx, r.pool).QueryRow(ctx, query, rule.ID, rule.OwnerID, rule.ListID, rule.Name, rule.Trigger,
+		conditions, actions, rule.Enabled).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("create rule: %w", err)
+	}
+	return nil
+}
+
+func (r *RuleRepo) Get(ctx context.Context, ownerID, id string) (*domain.Rule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rule, err := scanRule(conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT `+ruleColumns+` FROM rules WHERE id = $1 AND owner_id = $2`, id, ownerID))
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *RuleRepo) ListByOwner(ctx context.Context, ownerID, listID string) ([]*domain.Rule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return r.query(ctx, `
+        SELECT `+ruleColumns+`
+        FROM rules
+        WHERE owner_id = $1 AND ($2 = '' OR list_id::text = $2)
+        ORDER BY created_at
+    `, ownerID, listID)
+}
+
+func (r *RuleRepo) Update(ctx context.Context, rule *domain.Rule) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conditions, actions, err := marshalRule(rule)
+	if err != nil {
+		return err
+	}
+	query := `
+        UPDATE rules
+        SET list_id = $3, name = $4, trigger = $5, conditions = $6, actions = $7, enabled = $8, updated_at = NOW()
+        WHERE id = $1 AND owner_id = $2
+        RETURNING updated_at
+    `
+	err = conn(ctx, r.pool).QueryRow(ctx, query, rule.ID, rule.OwnerID, rule.ListID, rule.Name, rule.Trigger,
+		conditions, actions, rule.Enabled).Scan(&rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || isForeignKeyViolation(err) || isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update rule: %w", err)
+	}
+	return nil
+}
+
+func (r *RuleRepo) Delete(ctx context.Context, ownerID, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM rules WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("delete rule: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListEnabled вызывается из хука изменений внутри его транзакции.
+func (r *RuleRepo) ListEnabled(ctx context.Context, listID string, triggers []string) ([]*domain.Rule, error) {
+	return r.query(ctx, `
+        SELECT `+ruleColumns+`
+        FROM rules
+        WHERE list_id = $1 AND enabled AND trigger = ANY($2)
+        ORDER BY created_at
+    `, listID, triggers)
+}
+
+func (r *RuleRepo) GetEnabled(ctx context.Context, id string) (*domain.Rule, error) {
+	rule, err := scanRule(conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT `+ruleColumns+` FROM rules WHERE id = $1 AND enabled`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *RuleRepo) AppendExecution(ctx context.Context, e *domain.RuleExecution) error {
+	actions, err := json.Marshal(e.Actions)
+	if err != nil {
+		return fmt.Errorf("marshal rule actions: %w", err)
+	}
+	var taskID, errText *string
+	if e.TaskID != "" {
+		taskID = &e.TaskID
+	}
+	if e.Error != "" {
+		errText = &e.Error
+	}
+	err = conn(ctx, r.pool).QueryRow(ctx, `
+        INSERT INTO rule_executions (rule_id, trigger, list_id, task_id, status, actions, error, depth)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id, created_at
+    `, e.RuleID, e.Trigger, e.ListID, taskID, e.Status, actions, errText, e.Depth).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("append rule execution: %w", err)
+	}
+	return nil
+}
+
+func (r *RuleRepo) ListExecutions(ctx context.Context, ruleID string, limit, offset int) ([]*domain.RuleExecution, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	q := conn(ctx, r.pool)
+	var total int
+	if err := q.QueryRow(ctx, `SELECT COUNT(*) FROM rule_executions WHERE rule_id = $1`, ruleID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count rule executions: %w", err)
+	}
+
+	rows, err := q.Query(ctx, `
+        SELECT id, rule_id, trigger, list_id, COALESCE(task_id::text, ''), status, actions, COALESCE(error, ''), depth, created_at
+        FROM rule_executions
+        WHERE rule_id = $1
+        ORDER BY id DESC
+        LIMIT $2 OFFSET $3
+    `, ruleID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list rule executions: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.RuleExecution{}
+	for rows.Next() {
+		var (
+			e       domain.RuleExecution
+			actions []byte
+		)
+		if err := rows.Scan(&e.ID, &e.RuleID, &e.Trigger, &e.ListID, &e.TaskID, &e.Status, &actions, &e.Error, &e.Depth, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan rule execution: %w", err)
+		}
+		if err := json.Unmarshal(actions, &e.Actions); err != nil {
+			return nil, 0, fmt.Errorf("unmarshal rule actions: %w", err)
+		}
+		res = append(res, &e)
+	}
+	return res, total, rows.Err()
+}
+
+func (r *RuleRepo) query(ctx context.Context, sql string, args ...any) ([]*domain.Rule, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*domain.Rule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func marshalRule(rule *domain.Rule) (conditions, actions []byte, err error) {
+	if conditions, err = json.Marshal(rule.Conditions); err != nil {
+		return nil, nil, fmt.Errorf("marshal rule conditions: %w", err)
+	}
+	if actions, err = json.Marshal(rule.Actions); err != nil {
+		return nil, nil, fmt.Errorf("marshal rule actions: %w", err)
+	}
+	return conditions, actions, nil
+}
+
+func scanRule(row pgx.Row) (*domain.Rule, error) {
+	var (
+		rule                domain.Rule
+		conditions, actions []byte
+	)
+	err := row.Scan(&rule.ID, &rule.OwnerID, &rule.ListID, &rule.Name, &rule.Trigger, &conditions, &actions,
+		&rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("unmarshal rule conditions: %w", err)
+	}
+	if err := json.Unmarshal(actions, &rule.Actions); err != nil {
+		return nil, fmt.Errorf("unmarshal rule actions: %w", err)
+	}
+	return &rule, nil
+}
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+// Совпадения в сниппете база отмечает символами из области частного
+// использования: текст экранируется уже в Go, и только потом метки
+// заменяются на <mark>, поэтому HTML из текста задач не попадает в ответ.
+const (
+	markStart       = "\ue000"
+	markStop        = "\ue001"
+	headlineOptions = "StartSel=" + markStart + ", StopSel=" + markStop + ", MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+var markReplacer = strings.NewReplacer(markStart, "<mark>", markStop, "</mark>")
+
+// highlight экранирует сниппет и превращает метки совпадений в <mark>.
+func highlight(snippet string) string {
+	return markReplacer.Replace(html.EscapeString(snippet))
+}
+
+type SearchRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSearchRepo(pool *pgxpool.Pool) *SearchRepo {
+	return &SearchRepo{pool: pool}
+}
+
+// Search ищет по названиям и описаниям списков и по тексту задач.
+// Запрос разбирается обеими конфигурациями (russian и english), результаты
+// сортируются по рангу. Сниппеты строятся только для текущей страницы той
+// конфигурацией, по которой нашлось совпадение.
+func (r *SearchRepo) Search(ctx context.Context, query string, limit, offset int) ([]domain.SearchHit, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	sqlQuery := `
+		WITH q AS (
+			SELECT ru || en AS query, ru, en
+			FROM websearch_to_tsquery('russian', $1) AS ru, websearch_to_tsquery('english', $1) AS en
+		),
+		hits AS (
+			SELECT 'list' AS kind, l.id, l.id AS list_id, l.title,
+			       l.title || ' ' || coalesce(l.description, '') AS body,
+			       ts_rank(l.search_vector, q.query) AS rank,
+			       l.created_at
+			FROM lists l, q
+			WHERE l.search_vector @@ q.query
+			UNION ALL
+			SELECT 'task', t.id, t.list_id, t.text,
+			       t.text,
+			       ts_rank(t.search_vector, q.query),
+			       t.created_at::timestamptz
+			FROM tasks t, q
+			WHERE t.search_vector @@ q.query
+		),
+		page AS (
+			SELECT kind, id, list_id, title, body, rank, created_at, count(*) OVER () AS total
+			FROM hits
+			ORDER BY rank DESC, created_at DESC
+			LIMIT $2 OFFSET $3
+		)
+		SELECT page.kind, page.id, page.list_id, page.title,
+		       ts_headline(c.cfg, page.body, CASE c.cfg WHEN 'russian'::regconfig THEN q.ru ELSE q.en END, $4),
+		       page.rank, page.total
+		FROM page
+		CROSS JOIN q
+		CROSS JOIN LATERAL (
+			SELECT CASE WHEN to_tsvector('russian', page.body) @@ q.ru
+			            THEN 'russian'::regconfig ELSE 'english'::regconfig END AS cfg
+		) c
+		ORDER BY page.rank DESC, page.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, sqlQuery, query, limit, offset, headlineOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []domain.SearchHit{}
+	total := 0
+	for rows.Next() {
+		var hit domain.SearchHit
+		if err := rows.Scan(&hit.Type, &hit.ID, &hit.ListID, &hit.Title, &hit.Snippet, &hit.Rank, &total); err != nil {
+			return nil, 0, fmt.Errorf("scan search hit: %w", err)
+		}
+		hit.Snippet = highlight(hit.Snippet)
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	if len(hits) == 0 && offset > 0 {
+		if err := r.pool.QueryRow(ctx, `
+			WITH q AS (
+				SELECT websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1) AS query
+			)
+			SELECT (SELECT count(*) FROM lists l, q WHERE l.search_vector @@ q.query) +
+			       (SELECT count(*) FROM tasks t, q WHERE t.search_vector @@ q.query)
+		`, query).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("count search hits: %w", err)
+		}
+	}
+
+	return hits, total, nil
+}
+
+// FuzzySearch ищет по триграммам с учётом опечаток: совпадением считается
+// любое слово названия списка или текста задачи, похожее на запрос не меньше
+// чем на threshold (0..1).
+func (r *SearchRepo) FuzzySearch(ctx context.Context, query string, threshold float64, limit, offset int) ([]domain.SearchHit, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	hits := []domain.SearchHit{}
+	total := 0
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := setSimilarityThreshold(ctx, tx, threshold); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			WITH hits AS (
+				SELECT 'list' AS kind, l.id, l.id AS list_id, l.title,
+				       word_similarity($1, l.title) AS rank, l.created_at
+				FROM lists l
+				WHERE $1 <% l.title
+				UNION ALL
+				SELECT 'task', t.id, t.list_id, t.text,
+				       word_similarity($1, t.text), t.created_at::timestamptz
+				FROM tasks t
+				WHERE $1 <% t.text
+			)
+			SELECT kind, id, list_id, title, rank, count(*) OVER () AS total
+			FROM hits
+			ORDER BY rank DESC, created_at DESC
+			LIMIT $2 OFFSET $3
+		`, query, limit, offset)
+		if err != nil {
+			return fmt.Errorf("fuzzy search: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var hit domain.SearchHit
+			if err := rows.Scan(&hit.Type, &hit.ID, &hit.ListID, &hit.Title, &hit.Rank, &total); err != nil {
+				return fmt.Errorf("scan search hit: %w", err)
+			}
+			// Сниппет — HTML, как и в полнотекстовом поиске.
+			hit.Snippet = html.EscapeString(hit.Title)
+			hits = append(hits, hit)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("rows error: %w", err)
+		}
+
+		if len(hits) == 0 && offset > 0 {
+			if err := tx.QueryRow(ctx, `
+				SELECT (SELECT count(*) FROM lists WHERE $1 <% title) +
+				       (SELECT count(*) FROM tasks WHERE $1 <% text)
+			`, query).Scan(&total); err != nil {
+				return fmt.Errorf("count search hits: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return hits, total, nil
+}
+
+// Suggest возвращает подсказки для автодополнения: сначала совпадения по
+// префиксу, затем нечёткие. Каждая ветка ограничена limit, чтобы запрос
+// оставался дешёвым при вызове на каждое нажатие клавиши.
+func (r *SearchRepo) Suggest(ctx context.Context, query string, threshold float64, limit int) ([]domain.Suggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	prefix := escapeLike(query) + "%"
+	suggestions := []domain.Suggestion{}
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := setSimilarityThreshold(ctx, tx, threshold); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT kind, id, list_id, text, score
+			FROM (
+				(SELECT 'list' AS kind, l.id, l.id AS list_id, l.title AS text,
+				        (word_similarity($1, l.title) + CASE WHEN l.title ILIKE $2 THEN 1 ELSE 0 END)::real AS score
+				 FROM lists l
+				 WHERE l.title ILIKE $2 OR $1 <% l.title
+				 ORDER BY score DESC
+				 LIMIT $3)
+				UNION ALL
+				(SELECT 'task', t.id, t.list_id, t.text,
+				        (word_similarity($1, t.text) + CASE WHEN t.text ILIKE $2 THEN 1 ELSE 0 END)::real AS score
+				 FROM tasks t
+				 WHERE t.text ILIKE $2 OR $1 <% t.text
+				 ORDER BY score DESC
+				 LIMIT $3)
+			) s
+			ORDER BY score DESC, length(text)
+			LIMIT $3
+		`, query, prefix, limit)
+		if err != nil {
+			return fmt.Errorf("suggest: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var s domain.Suggestion
+			if err := rows.Scan(&s.Type, &s.ID, &s.ListID, &s.Text, &s.Score); err != nil {
+				return fmt.Errorf("scan suggestion: %w", err)
+			}
+			suggestions = append(suggestions, s)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func setSimilarityThreshold(ctx context.Context, tx pgx.Tx, threshold float64) error {
+	_, err := tx.Exec(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`,
+		strconv.FormatFloat(threshold, 'f', -1, 64))
+	if err != nil {
+		return fmt.Errorf("set similarity threshold: %w", err)
+	}
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
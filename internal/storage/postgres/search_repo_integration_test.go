@@ -0,0 +1,38 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"todo-api/internal/domain"
+	"todo-api/internal/storage/postgres"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRepository_SnippetIsEscaped(t *testing.T) {
+	ctx := context.Background()
+	repo := postgres.NewSearchRepo(db)
+	_, err := db.Exec(ctx, `TRUNCATE TABLE tasks, lists RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+
+	list := domain.NewList("Покупки", "")
+	_, err = db.Exec(ctx, `INSERT INTO lists (id, title) VALUES ($1, $2)`, list.ID, list.Title)
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, `INSERT INTO tasks (list_id, text) VALUES ($1, $2), ($1, $3)`, list.ID,
+		`Купить молоко <script>alert(1)</script>`, `Running shoes for the marathon`)
+	require.NoError(t, err)
+
+	hits, total, err := repo.Search(ctx, "молоко", 20, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Contains(t, hits[0].Snippet, "<mark>молоко</mark>")
+	require.Contains(t, hits[0].Snippet, "&lt;script&gt;")
+	require.NotContains(t, hits[0].Snippet, "<script>")
+
+	// Английская морфология: «run» находит и подсвечивает «Running».
+	hits, _, err = repo.Search(ctx, "run", 20, 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Contains(t, hits[0].Snippet, "<mark>Running</mark>")
+}
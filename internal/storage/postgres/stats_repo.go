@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type StatsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStatsRepo(pool *pgxpool.Pool) *StatsRepo {
+	return &StatsRepo{pool: pool}
+}
+
+// TaskStats считает статистику по задачам списка listID (или по всем задачам,
+// если listID пустой). Все показатели вычисляются агрегатами на стороне БД.
+func (r *StatsRepo) TaskStats(ctx context.Context, listID string, rng domain.StatsRange) (*domain.TaskStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var list *string
+	if listID != "" {
+		list = &listID
+	}
+
+	stats := &domain.TaskStats{
+		ListID:   listID,
+		From:     rng.From,
+		To:       rng.To,
+		Interval: rng.Interval,
+		Velocity: []domain.VelocityPoint{},
+	}
+
+	err := r.pool.QueryRow(ctx, `
+		SELECT count(*),
+		       count(*) FILTER (WHERE completed),
+		       count(*) FILTER (WHERE NOT completed),
+		       coalesce(round(100.0 * count(*) FILTER (WHERE completed) / nullif(count(*), 0), 2), 0)::float8,
+		       extract(epoch FROM avg(completed_at - (created_at AT TIME ZONE 'UTC')) FILTER (
+		           WHERE completed AND completed_at >= $2 AND completed_at < $3
+		       ))::float8
+		FROM tasks
+		WHERE $1::uuid IS NULL OR list_id = $1::uuid
+	`, list, rng.From, rng.To).Scan(&stats.Total, &stats.Completed, &stats.Open, &stats.CompletionRate, &stats.AvgTimeToComplete)
+	if err != nil {
+		return nil, fmt.Errorf("task stats: %w", err)
+	}
+
+	// Периоды считаются в UTC независимо от TimeZone сессии: и границы, и
+	// completed_at переводятся во время UTC до date_trunc.
+	rows, err := r.pool.Query(ctx, `
+		SELECT b.bucket, count(t.id)
+		FROM generate_series(
+		         date_trunc($4, $2::timestamptz AT TIME ZONE 'UTC'),
+		         ($3::timestamptz AT TIME ZONE 'UTC') - interval '1 microsecond',
+		         ('1 ' || $4)::interval
+		     ) AS b(bucket)
+		LEFT JOIN tasks t
+		       ON t.completed
+		      AND t.completed_at >= $2 AND t.completed_at < $3
+		      AND date_trunc($4, t.completed_at AT TIME ZONE 'UTC') = b.bucket
+		      AND ($1::uuid IS NULL OR t.list_id = $1::uuid)
+		GROUP BY b.bucket
+		ORDER BY b.bucket
+	`, list, rng.From, rng.To, rng.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("completion velocity: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.VelocityPoint
+		if err := rows.Scan(&p.PeriodStart, &p.Completed); err != nil {
+			return nil, fmt.Errorf("scan velocity: %w", err)
+		}
+		stats.Velocity = append(stats.Velocity, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type SyncRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSyncRepo(pool *pgxpool.Pool) *SyncRepo {
+	return &SyncRepo{pool: pool}
+}
+
+// Changes возвращает до limit изменений окна курсора в порядке change_seq.
+//
+// Окно закрывается сверху границей xmin текущего снимка: все транзакции
+// с меньшими номерами уже завершены, а любая транзакция, которая изменит
+// строку позже, получит номер не меньше границы. Поэтому следующее окно,
+// начатое с этой границы, не пропустит изменений, зафиксированных после
+// запроса, даже если они получили меньший change_seq.
+func (r *SyncRepo) Changes(ctx context.Context, cur domain.SyncCursor, limit int) (*domain.SyncPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := conn(ctx, r.pool)
+	if cur.Until == 0 {
+		var until string
+		if err := q.QueryRow(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text`).Scan(&until); err != nil {
+			return nil, fmt.Errorf("get sync watermark: %w", err)
+		}
+		var err error
+		if cur.Until, err = strconv.ParseUint(until, 10, 64); err != nil {
+			return nil, fmt.Errorf("parse sync watermark: %w", err)
+		}
+		cur.AfterSeq = 0
+	}
+	since := strconv.FormatUint(cur.Since, 10)
+	until := strconv.FormatUint(cur.Until, 10)
+	window := `change_xid >= $1::text::xid8 AND change_xid < $2::text::xid8 AND change_seq > $3
+	           ORDER BY change_seq LIMIT $4`
+
+	type item struct {
+		seq  int64
+		list *domain.List
+		task *domain.Task
+		dead *domain.SyncTombstone
+	}
+	var items []item
+
+	rows, err := q.Query(ctx, `SELECT id, title, description, version, archived_at, folder_id, created_at, change_seq
+	                           FROM lists WHERE `+window, since, until, cur.AfterSeq, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("sync lists: %w", err)
+	}
+	for rows.Next() {
+		var (
+			l   domain.List
+			seq int64
+		)
+		if err := rows.Scan(&l.ID, &l.Title, &l.Description, &l.Version, &l.ArchivedAt, &l.FolderID, &l.CreatedAt, &seq); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan list: %w", err)
+		}
+		items = append(items, item{seq: seq, list: &l})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sync lists: %w", err)
+	}
+
+	rows, err = q.Query(ctx, `SELECT `+taskColumns+`, change_seq FROM tasks WHERE `+window, since, until, cur.AfterSeq, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("sync tasks: %w", err)
+	}
+	for rows.Next() {
+		var (
+			t   domain.Task
+			seq int64
+		)
+		err := rows.Scan(&t.ID, &t.ListID, &t.Text, &t.Completed, &t.DueAt, &t.CompletedAt,
+			&t.Version, &t.CreatedAt, &t.UpdatedAt, &t.Assignee, &t.Labels, &t.Priority, &t.Recurrence, &seq)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		items = append(items, item{seq: seq, task: &t})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sync tasks: %w", err)
+	}
+
+	// Первой синхронизации надгробия не нужны: у клиента ещё ничего нет.
+	if cur.Since > 0 {
+		rows, err = q.Query(ctx, `SELECT entity_type, entity_id, list_id, deleted_at, change_seq
+		                          FROM sync_tombstones WHERE `+window, since, until, cur.AfterSeq, limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("sync tombstones: %w", err)
+		}
+		for rows.Next() {
+			var (
+				d   domain.SyncTombstone
+				seq int64
+			)
+			if err := rows.Scan(&d.EntityType, &d.ID, &d.ListID, &d.DeletedAt, &seq); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan tombstone: %w", err)
+			}
+			items = append(items, item{seq: seq, dead: &d})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("sync tombstones: %w", err)
+		}
+	}
+
+	// Каждый запрос вернул свои первые limit+1 строк, значит первые limit
+	// из объединения — ровно первые limit изменений окна.
+	slices.SortFunc(items, func(a, b item) int { return int(a.seq - b.seq) })
+	page := &domain.SyncPage{Lists: []*domain.List{}, Tasks: []*domain.Task{}, Deleted: []domain.SyncTombstone{}}
+	if len(items) > limit {
+		items = items[:limit]
+		page.HasMore = true
+	}
+	for _, it := range items {
+		switch {
+		case it.list != nil:
+			page.Lists = append(page.Lists, it.list)
+		case it.task != nil:
+			page.Tasks = append(page.Tasks, it.task)
+		default:
+			page.Deleted = append(page.Deleted, *it.dead)
+		}
+	}
+
+	if page.HasMore {
+		page.Next = domain.SyncCursor{Since: cur.Since, Until: cur.Until, AfterSeq: items[len(items)-1].seq}
+	} else {
+		page.Next = domain.SyncCursor{Since: cur.Until}
+	}
+	return page, nil
+}
+
+// LockList блокирует список до конца транзакции и возвращает его вместе
+// с временем изменения полей. Если списка нет, возвращает nil без ошибки.
+func (r *SyncRepo) LockList(ctx context.Context, id string) (*domain.List, domain.FieldClock, error) {
+	var (
+		l     domain.List
+		clock domain.FieldClock
+	)
+	err := conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT id, title, description, version, archived_at, folder_id, created_at, field_clock FROM lists WHERE id = $1 FOR UPDATE`, id).
+		Scan(&l.ID, &l.Title, &l.Description, &l.Version, &l.ArchivedAt, &l.FolderID, &l.CreatedAt, &clock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock list: %w", err)
+	}
+	return &l, clock, nil
+}
+
+// LockTask — то же, что LockList, для задачи.
+func (r *SyncRepo) LockTask(ctx context.Context, id string) (*domain.Task, domain.FieldClock, error) {
+	var (
+		t     domain.Task
+		clock domain.FieldClock
+	)
+	err := conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT `+taskColumns+`, field_clock FROM tasks WHERE id = $1 FOR UPDATE`, id).
+		Scan(&t.ID, &t.ListID, &t.Text, &t.Completed, &t.DueAt, &t.CompletedAt,
+			&t.Version, &t.CreatedAt, &t.UpdatedAt, &t.Assignee, &t.Labels, &t.Priority, &t.Recurrence, &clock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock task: %w", err)
+	}
+	return &t, clock, nil
+}
+
+// DeletedAt возвращает время удаления сущности или nil, если надгробия нет.
+func (r *SyncRepo) DeletedAt(ctx context.Context, id string) (*time.Time, error) {
+	var deletedAt time.Time
+	err := conn(ctx, r.pool).QueryRow(ctx, `SELECT deleted_at FROM sync_tombstones WHERE entity_id = $1`, id).Scan(&deletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get tombstone: %w", err)
+	}
+	return &deletedAt, nil
+}
+
+// SetFieldClock записывает время изменения полей, заданное клиентом.
+// Триггер sync_stamp не перезаписывает field_clock, заданный явно.
+func (r *SyncRepo) SetFieldClock(ctx context.Context, entityType, id string, clock domain.FieldClock) error {
+	table := "tasks"
+	if entityType == domain.EntityList {
+		table = "lists"
+	}
+	raw, err := json.Marshal(clock)
+	if err != nil {
+		return fmt.Errorf("marshal field clock: %w", err)
+	}
+	if _, err := conn(ctx, r.pool).Exec(ctx, `UPDATE `+table+` SET field_clock = $2 WHERE id = $1`, id, raw); err != nil {
+		return fmt.Errorf("set field clock: %w", err)
+	}
+	return nil
+}
@@ -2,16 +2,25 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"todo-api/internal/domain"
+	"todo-api/internal/storage"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// taskColumns — порядок колонок, который ожидает scanTask.
+const taskColumns = `id, list_id, text, completed, due_at, completed_at, version, created_at, updated_at, assignee,
+	labels, priority, recurrence`
+
 type taskRepo struct {
 	pool *pgxpool.Pool
 }
@@ -21,26 +30,96 @@ func NewTaskRepo(pool *pgxpool.Pool) *taskRepo {
 }
 
 func (r *taskRepo) Create(ctx context.Context, t *domain.Task) error {
-	query := `INSERT INTO tasks (id, list_id, text, completed)
-	          VALUES ($1,$2,$3,$4)
-	          RETURNING created_at, updated_at`
-	return r.pool.QueryRow(ctx, query, t.ID, t.ListID, t.Text, t.Completed).
-		Scan(&t.CreatedAt, &t.UpdatedAt)
+	query := `INSERT INTO tasks (id, list_id, text, completed, due_at, assignee, labels, priority, recurrence)
+	          VALUES ($1,$2,$3,$4,$5,$6,COALESCE($7::text[], '{}'),$8,$9)
+	          RETURNING version, created_at, updated_at`
+	q := conn(ctx, r.pool)
+	if err := q.QueryRow(ctx, query, t.ID, t.ListID, t.Text, t.Completed, t.DueAt, t.Assignee, t.Labels, t.Priority, t.Recurrence).
+		Scan(&t.Version, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return err
+	}
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventTaskCreated, ListID: t.ListID, TaskID: t.ID, Task: t})
+}
+
+// CreateMany вставляет задачи одним запросом. created_at задач растёт в
+// порядке tasks, чтобы они сортировались как при создании по одной;
+// completed_at выполненных задач — время вставки. События об отдельных
+// задачах не отправляются.
+func (r *taskRepo) CreateMany(ctx context.Context, tasks []*domain.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	var (
+		ids, listIDs, texts, assignees, priorities, recurrences []string
+		completed                                               []bool
+		dueAt                                                   []*time.Time
+		labels                                                  = make([][]string, 0, len(tasks))
+		byID                                                    = make(map[string]*domain.Task, len(tasks))
+	)
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+		listIDs = append(listIDs, t.ListID)
+		texts = append(texts, t.Text)
+		completed = append(completed, t.Completed)
+		dueAt = append(dueAt, t.DueAt)
+		assignees = append(assignees, t.Assignee)
+		priorities = append(priorities, t.Priority)
+		recurrences = append(recurrences, t.Recurrence)
+		if t.Labels == nil {
+			labels = append(labels, []string{})
+		} else {
+			labels = append(labels, t.Labels)
+		}
+		byID[t.ID] = t
+	}
+	// Метки у задач разной длины, а многомерный массив Postgres должен быть
+	// прямоугольным, поэтому они передаются массивом JSON.
+	rawLabels, err := json.Marshal(labels)
+	if err != nil {
+		return fmt.Errorf("encode labels: %w", err)
+	}
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+	    INSERT INTO tasks (id, list_id, text, completed, completed_at, due_at, assignee, labels, priority, recurrence,
+	                       created_at, updated_at)
+	    SELECT t.id, t.list_id, t.text, t.completed, CASE WHEN t.completed THEN NOW() END, t.due_at, t.assignee,
+	           ARRAY(SELECT jsonb_array_elements_text($9::jsonb -> (t.ord - 1)::int)), t.priority, t.recurrence,
+	           NOW() + t.ord * INTERVAL '1 microsecond', NOW()
+	    FROM unnest($1::uuid[], $2::uuid[], $3::text[], $4::boolean[], $5::timestamptz[], $6::text[], $7::text[], $8::text[])
+	         WITH ORDINALITY AS t(id, list_id, text, completed, due_at, assignee, priority, recurrence, ord)
+	    RETURNING id, version, created_at, updated_at, completed_at`,
+		ids, listIDs, texts, completed, dueAt, assignees, priorities, recurrences, rawLabels)
+	if err != nil {
+		return fmt.Errorf("create tasks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var created domain.Task
+		if err := rows.Scan(&id, &created.Version, &created.CreatedAt, &created.UpdatedAt, &created.CompletedAt); err != nil {
+			return fmt.Errorf("create tasks: %w", err)
+		}
+		t := byID[id]
+		t.Version, t.CreatedAt, t.UpdatedAt, t.CompletedAt = created.Version, created.CreatedAt, created.UpdatedAt, created.CompletedAt
+	}
+	if err := rows.Err(); isForeignKeyViolation(err) {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("create tasks: %w", err)
+	}
+	return nil
 }
 
 func (r *taskRepo) GetByID(ctx context.Context, id string) (*domain.Task, error) {
-	var t domain.Task
-	err := r.pool.QueryRow(ctx, `SELECT id, list_id, text, completed, created_at, updated_at FROM tasks WHERE id=$1`, id).
-		Scan(&t.ID, &t.ListID, &t.Text, &t.Completed, &t.CreatedAt, &t.UpdatedAt)
-	if errors.Is(err, pgx.ErrNoRows) {
+	t, err := scanTask(conn(ctx, r.pool).QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
 		return nil, ErrNotFound
 	}
-	return &t, err
+	return t, err
 }
 
 func (r *taskRepo) ListByListID(ctx context.Context, listID string, limit, offset int) ([]*domain.Task, int, error) {
-	rows, err := r.pool.Query(ctx,
-		`SELECT id, list_id, text, completed, created_at, updated_at
+	rows, err := conn(ctx, r.pool).Query(ctx,
+		`SELECT `+taskColumns+`
 		 FROM tasks WHERE list_id=$1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`, listID, limit, offset)
 	if err != nil {
 		return nil, 0, err
@@ -48,32 +127,256 @@ func (r *taskRepo) ListByListID(ctx context.Context, listID string, limit, offse
 	defer rows.Close()
 	var res []*domain.Task
 	for rows.Next() {
-		var t domain.Task
-		_ = rows.Scan(&t.ID, &t.ListID, &t.Text, &t.Completed, &t.CreatedAt, &t.UpdatedAt)
-		res = append(res, &t)
+		if t, err := scanTask(rows); err == nil {
+			res = append(res, t)
+		}
 	}
 	var total int
-	_ = r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM tasks WHERE list_id=$1`, listID).Scan(&total)
+	_ = conn(ctx, r.pool).QueryRow(ctx, `SELECT COUNT(*) FROM tasks WHERE list_id=$1`, listID).Scan(&total)
+	return res, total, rows.Err()
+}
+
+// FindByQuery возвращает задачи из всех списков, подходящие под фильтр.
+// Сначала идут задачи с ближайшим сроком, затем без срока.
+func (r *taskRepo) FindByQuery(ctx context.Context, q domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if len(q.ListIDs) > 0 {
+		conds = append(conds, "list_id = ANY("+arg(q.ListIDs)+"::uuid[])")
+	}
+	if q.Completed != nil {
+		conds = append(conds, "completed = "+arg(*q.Completed))
+	}
+	if q.Text != "" {
+		conds = append(conds, "text ILIKE "+arg("%"+escapeLike(q.Text)+"%"))
+	}
+	if q.CreatedFrom != nil {
+		conds = append(conds, "created_at >= "+arg(*q.CreatedFrom)+"::timestamptz")
+	}
+	if q.CreatedTo != nil {
+		conds = append(conds, "created_at < "+arg(*q.CreatedTo)+"::timestamptz")
+	}
+	if q.DueFrom != nil {
+		conds = append(conds, "due_at >= "+arg(*q.DueFrom))
+	}
+	if q.DueTo != nil {
+		conds = append(conds, "due_at < "+arg(*q.DueTo))
+	}
+	if q.NoDueDate {
+		conds = append(conds, "due_at IS NULL")
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := conn(ctx, r.pool).QueryRow(ctx, `SELECT COUNT(*) FROM tasks `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count tasks: %w", err)
+	}
+
+	query := `SELECT ` + taskColumns + `
+	          FROM tasks ` + where + `
+	          ORDER BY due_at ASC NULLS LAST, created_at DESC
+	          LIMIT ` + arg(limit) + ` OFFSET ` + arg(offset)
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find tasks: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan task: %w", err)
+		}
+		res = append(res, t)
+	}
 	return res, total, rows.Err()
 }
 
 func (r *taskRepo) Update(ctx context.Context, t *domain.Task) error {
 	t.UpdatedAt = time.Now().UTC()
-	query := `UPDATE tasks SET text=$2, completed=$3, updated_at=$4 WHERE id=$1`
-	ct, err := r.pool.Exec(ctx, query, t.ID, t.Text, t.Completed, t.UpdatedAt)
+	// completed_at выставляется при переходе в выполненные и сбрасывается
+	// при возврате в невыполненные; повторное выполнение его не сдвигает.
+	query := `UPDATE tasks SET list_id=$7, text=$2, completed=$3, due_at=$4, updated_at=$5, assignee=$6,
+	              labels=COALESCE($8::text[], '{}'), priority=$9, recurrence=$10, version=version+1,
+	              completed_at = CASE
+	                  WHEN NOT $3 THEN NULL
+	                  WHEN completed THEN completed_at
+	                  ELSE now()
+	              END
+	          WHERE id=$1
+	          RETURNING completed_at, version`
+	q := conn(ctx, r.pool)
+	err := q.QueryRow(ctx, query, t.ID, t.Text, t.Completed, t.DueAt, t.UpdatedAt, t.Assignee, t.ListID,
+		t.Labels, t.Priority, t.Recurrence).Scan(&t.CompletedAt, &t.Version)
+	// Нарушение внешнего ключа — задачу переносят в удалённый список.
+	if errors.Is(err, pgx.ErrNoRows) || isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("update task: %w", err)
 	}
-	if ct.RowsAffected() == 0 {
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventTaskUpdated, ListID: t.ListID, TaskID: t.ID, Task: t})
+}
+
+func (r *taskRepo) Delete(ctx context.Context, id string) error {
+	q := conn(ctx, r.pool)
+	var listID string
+	err := q.QueryRow(ctx, `DELETE FROM tasks WHERE id=$1 RETURNING list_id`, id).Scan(&listID)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return ErrNotFound
 	}
-	return nil
+	if err != nil {
+		return err
+	}
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventTaskDeleted, ListID: listID, TaskID: id})
 }
 
-func (r *taskRepo) Delete(ctx context.Context, id string) error {
-	tag, err := r.pool.Exec(ctx, `DELETE FROM tasks WHERE id=$1`, id)
+// ListByListIDs возвращает до perList последних задач каждого из списков
+// одним запросом.
+func (r *taskRepo) ListByListIDs(ctx context.Context, listIDs []string, perList int) ([]*domain.Task, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx,
+		`SELECT `+taskColumns+`
+		 FROM (
+		     SELECT `+taskColumns+`, row_number() OVER (PARTITION BY list_id ORDER BY created_at DESC) AS rn
+		     FROM tasks WHERE list_id = ANY($1::uuid[])
+		 ) t
+		 WHERE rn <= $2
+		 ORDER BY list_id, created_at DESC`, listIDs, perList)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks by lists: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+// CountByListIDs считает задачи каждого из списков одним запросом.
+// Списков без задач в результате нет.
+func (r *taskRepo) CountByListIDs(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx,
+		`SELECT list_id, COUNT(*), COUNT(*) FILTER (WHERE completed)
+		 FROM tasks WHERE list_id = ANY($1::uuid[])
+		 GROUP BY list_id`, listIDs)
+	if err != nil {
+		return nil, fmt.Errorf("count tasks by lists: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[string]domain.TaskCounts, len(listIDs))
+	for rows.Next() {
+		var (
+			listID string
+			c      domain.TaskCounts
+		)
+		if err := rows.Scan(&listID, &c.Total, &c.Completed); err != nil {
+			return nil, fmt.Errorf("scan task counts: %w", err)
+		}
+		c.Open = c.Total - c.Completed
+		res[listID] = c
+	}
+	return res, rows.Err()
+}
+
+// ListAllByListID возвращает все задачи списка без пагинации.
+func (r *taskRepo) ListAllByListID(ctx context.Context, listID string) ([]*domain.Task, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx,
+		`SELECT `+taskColumns+` FROM tasks WHERE list_id=$1 ORDER BY created_at`, listID)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+// Restore записывает задачу целиком, включая id, версию и временные метки.
+// expectedVersion 0 означает, что задачи быть не должно (восстановление
+// удалённой), иначе текущая версия должна совпасть. При расхождении
+// возвращается storage.ErrConflict.
+func (r *taskRepo) Restore(ctx context.Context, t *domain.Task, expectedVersion int64) error {
+	var (
+		q   = conn(ctx, r.pool)
+		tag pgconn.CommandTag
+		err error
+	)
+	event := domain.EventTaskUpdated
+	if expectedVersion == 0 {
+		event = domain.EventTaskCreated
+		tag, err = q.Exec(ctx,
+			`INSERT INTO tasks (id, list_id, text, completed, due_at, completed_at, version, created_at, updated_at, assignee,
+			                   labels, priority, recurrence)
+			 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,COALESCE($11::text[], '{}'),$12,$13)
+			 ON CONFLICT (id) DO NOTHING`,
+			t.ID, t.ListID, t.Text, t.Completed, t.DueAt, t.CompletedAt, t.Version, t.CreatedAt, t.UpdatedAt, t.Assignee,
+			t.Labels, t.Priority, t.Recurrence)
+	} else {
+		tag, err = q.Exec(ctx,
+			`UPDATE tasks SET list_id=$2, text=$3, completed=$4, due_at=$5, completed_at=$6, version=$7, updated_at=$8, assignee=$10,
+			                  labels=COALESCE($11::text[], '{}'), priority=$12, recurrence=$13
+			 WHERE id=$1 AND version=$9`,
+			t.ID, t.ListID, t.Text, t.Completed, t.DueAt, t.CompletedAt, t.Version, t.UpdatedAt, expectedVersion, t.Assignee,
+			t.Labels, t.Priority, t.Recurrence)
+	}
+	if isForeignKeyViolation(err) {
+		return storage.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("restore task: %w", err)
+	}
 	if tag.RowsAffected() == 0 {
-		return ErrNotFound
+		return storage.ErrConflict
+	}
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: event, ListID: t.ListID, TaskID: t.ID, Task: t})
+}
+
+// DeleteVersion удаляет задачу, только если её версия не изменилась.
+func (r *taskRepo) DeleteVersion(ctx context.Context, id string, version int64) error {
+	q := conn(ctx, r.pool)
+	var listID string
+	err := q.QueryRow(ctx, `DELETE FROM tasks WHERE id=$1 AND version=$2 RETURNING list_id`, id, version).Scan(&listID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("delete task: %w", err)
+	}
+	return notifyChange(ctx, q, domain.ChangeEvent{Type: domain.EventTaskDeleted, ListID: listID, TaskID: id})
+}
+
+func scanTask(row pgx.Row) (*domain.Task, error) {
+	var t domain.Task
+	err := row.Scan(&t.ID, &t.ListID, &t.Text, &t.Completed, &t.DueAt, &t.CompletedAt, &t.Version, &t.CreatedAt, &t.UpdatedAt, &t.Assignee,
+		&t.Labels, &t.Priority, &t.Recurrence)
+	if err != nil {
+		return nil, err
 	}
-	return err
+	return &t, nil
 }
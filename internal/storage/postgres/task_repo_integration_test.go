@@ -31,6 +31,47 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+func TestTaskRepository_CreateMany(t *testing.T) {
+	ctx := context.Background()
+	repo := postgres.NewTaskRepo(db)
+	_, err := db.Exec(ctx, `TRUNCATE TABLE tasks, lists RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+
+	listID := uuid.New().String()
+	_, err = db.Exec(ctx, `INSERT INTO lists (id, title) VALUES ($1, 'Bulk')`, listID)
+	require.NoError(t, err)
+
+	due := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []*domain.Task{
+		{ID: uuid.New().String(), ListID: listID, Text: "first", Labels: []string{"home", "urgent"}, Priority: domain.TaskPriorityHigh},
+		{ID: uuid.New().String(), ListID: listID, Text: "second", Completed: true, DueAt: &due},
+		{ID: uuid.New().String(), ListID: listID, Text: "third", Labels: []string{"work"}, Recurrence: domain.RecurrenceWeekly},
+	}
+	require.NoError(t, repo.CreateMany(ctx, tasks))
+	require.True(t, tasks[0].CreatedAt.Before(tasks[1].CreatedAt))
+	require.True(t, tasks[1].CreatedAt.Before(tasks[2].CreatedAt))
+	require.NotNil(t, tasks[1].CompletedAt)
+	require.Nil(t, tasks[0].CompletedAt)
+
+	stored, err := repo.ListAllByListID(ctx, listID)
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+	byText := map[string]*domain.Task{}
+	for _, s := range stored {
+		byText[s.Text] = s
+	}
+	require.Equal(t, []string{"home", "urgent"}, byText["first"].Labels)
+	require.Equal(t, domain.TaskPriorityHigh, byText["first"].Priority)
+	require.True(t, byText["second"].Completed)
+	require.True(t, byText["second"].DueAt.Equal(due))
+	require.Empty(t, byText["second"].Labels)
+	require.Equal(t, []string{"work"}, byText["third"].Labels)
+	require.Equal(t, domain.RecurrenceWeekly, byText["third"].Recurrence)
+
+	missing := &domain.Task{ID: uuid.New().String(), ListID: uuid.New().String(), Text: "orphan"}
+	require.ErrorIs(t, repo.CreateMany(ctx, []*domain.Task{missing}), postgres.ErrNotFound)
+}
+
 func TestTaskRepository_CRUD(t *testing.T) {
 	ctx := context.Background()
 	repo := postgres.NewTaskRepo(db)
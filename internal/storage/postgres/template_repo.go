@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type TemplateRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTemplateRepo(pool *pgxpool.Pool) *TemplateRepo {
+	return &TemplateRepo{pool: pool}
+}
+
+const templateColumns = `id, owner_id, name, title, description, tasks, shared, created_at, updated_at`
+
+func (r *TemplateRepo) Create(ctx context.Context, t *domain.Template) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tasks, err := json.Marshal(t.Tasks)
+	if err != nil {
+		return fmt.Errorf("marshal template tasks: %w", err)
+	}
+	query := `
+        INSERT INTO templates (id, owner_id, name, title, description, tasks, shared)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING created_at, updated_at
+    `
+	err = conn(ctx, r.pool).QueryRow(ctx, query, t.ID, t.OwnerID, t.Name, t.Title, t.Description, tasks, t.Shared).
+		Scan(&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create template: %w", err)
+	}
+	return nil
+}
+
+func (r *TemplateRepo) Get(ctx context.Context, userID, id string) (*domain.Template, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	t, err := scanTemplate(conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT `+templateColumns+` FROM templates WHERE id = $1 AND (owner_id = $2 OR shared)`, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get template: %w", err)
+	}
+	return t, nil
+}
+
+// List возвращает сначала шаблоны пользователя, затем общие шаблоны
+// остальных пользователей.
+func (r *TemplateRepo) List(ctx context.Context, userID string) ([]*domain.Template, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := conn(ctx, r.pool).Query(ctx, `
+        SELECT `+templateColumns+`
+        FROM templates
+        WHERE owner_id = $1 OR shared
+        ORDER BY owner_id <> $1, created_at
+    `, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*domain.Template{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *TemplateRepo) Update(ctx context.Context, t *domain.Template) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tasks, err := json.Marshal(t.Tasks)
+	if err != nil {
+		return fmt.Errorf("marshal template tasks: %w", err)
+	}
+	query := `
+        UPDATE templates
+        SET name = $3, title = $4, description = $5, tasks = $6, shared = $7, updated_at = NOW()
+        WHERE id = $1 AND owner_id = $2
+        RETURNING updated_at
+    `
+	err = conn(ctx, r.pool).QueryRow(ctx, query, t.ID, t.OwnerID, t.Name, t.Title, t.Description, tasks, t.Shared).
+		Scan(&t.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update template: %w", err)
+	}
+	return nil
+}
+
+func (r *TemplateRepo) Delete(ctx context.Context, ownerID, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM templates WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanTemplate(row pgx.Row) (*domain.Template, error) {
+	var (
+		t     domain.Template
+		tasks []byte
+	)
+	err := row.Scan(&t.ID, &t.OwnerID, &t.Name, &t.Title, &t.Description, &tasks, &t.Shared, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tasks, &t.Tasks); err != nil {
+		return nil, fmt.Errorf("unmarshal template tasks: %w", err)
+	}
+	return &t, nil
+}
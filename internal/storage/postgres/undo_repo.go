@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type UndoRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewUndoRepo(pool *pgxpool.Pool) *UndoRepo {
+	return &UndoRepo{pool: pool}
+}
+
+// Create сохраняет запись отмены. Вызывается внутри транзакции изменения.
+func (r *UndoRepo) Create(ctx context.Context, e *domain.UndoEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	before, err := json.Marshal(e.Before)
+	if err != nil {
+		return fmt.Errorf("marshal undo before: %w", err)
+	}
+	after, err := json.Marshal(e.After)
+	if err != nil {
+		return fmt.Errorf("marshal undo after: %w", err)
+	}
+
+	query := `
+        INSERT INTO undo_entries (token, actor, action, before, after, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING created_at
+    `
+	err = conn(ctx, r.pool).QueryRow(ctx, query, e.Token, e.Actor, e.Action, before, after, e.ExpiresAt).
+		Scan(&e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create undo entry: %w", err)
+	}
+	return nil
+}
+
+func (r *UndoRepo) GetForUpdate(ctx context.Context, token string) (*domain.UndoEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        SELECT token, actor, action, before, after, expires_at, redeemed_at, created_at
+        FROM undo_entries
+        WHERE token = $1
+        FOR UPDATE
+    `
+	var (
+		e             domain.UndoEntry
+		before, after []byte
+	)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, token).
+		Scan(&e.Token, &e.Actor, &e.Action, &before, &after, &e.ExpiresAt, &e.RedeemedAt, &e.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get undo entry: %w", err)
+	}
+	if err := json.Unmarshal(before, &e.Before); err != nil {
+		return nil, fmt.Errorf("unmarshal undo before: %w", err)
+	}
+	if err := json.Unmarshal(after, &e.After); err != nil {
+		return nil, fmt.Errorf("unmarshal undo after: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *UndoRepo) MarkRedeemed(ctx context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := conn(ctx, r.pool).Exec(ctx,
+		`UPDATE undo_entries SET redeemed_at = NOW() WHERE token = $1 AND redeemed_at IS NULL`, token)
+	if err != nil {
+		return fmt.Errorf("redeem undo entry: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *UndoRepo) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM undo_entries WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge undo entries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type ViewRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewViewRepo(pool *pgxpool.Pool) *ViewRepo {
+	return &ViewRepo{pool: pool}
+}
+
+func (r *ViewRepo) Create(ctx context.Context, view *domain.View) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("marshal filter: %w", err)
+	}
+
+	query := `
+        INSERT INTO views (id, owner_id, name, filter)
+        VALUES ($1, $2, $3, $4)
+        RETURNING created_at, updated_at
+    `
+	err = r.pool.QueryRow(ctx, query, view.ID, view.OwnerID, view.Name, filter).Scan(&view.CreatedAt, &view.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create view: %w", err)
+	}
+	return nil
+}
+
+func (r *ViewRepo) GetByID(ctx context.Context, ownerID, id string) (*domain.View, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        SELECT id, owner_id, name, filter, created_at, updated_at
+        FROM views
+        WHERE id = $1 AND owner_id = $2
+    `
+	view, err := scanView(r.pool.QueryRow(ctx, query, id, ownerID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get view by id: %w", err)
+	}
+	return view, nil
+}
+
+func (r *ViewRepo) ListByOwner(ctx context.Context, ownerID string) ([]*domain.View, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+        SELECT id, owner_id, name, filter, created_at, updated_at
+        FROM views
+        WHERE owner_id = $1
+        ORDER BY created_at
+    `, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*domain.View
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan view: %w", err)
+		}
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+func (r *ViewRepo) Update(ctx context.Context, view *domain.View) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("marshal filter: %w", err)
+	}
+
+	query := `
+        UPDATE views
+        SET name = $3, filter = $4, updated_at = NOW()
+        WHERE id = $1 AND owner_id = $2
+        RETURNING updated_at
+    `
+	err = r.pool.QueryRow(ctx, query, view.ID, view.OwnerID, view.Name, filter).Scan(&view.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("update view: %w", err)
+	}
+	return nil
+}
+
+func (r *ViewRepo) Delete(ctx context.Context, ownerID, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.pool.Exec(ctx, `DELETE FROM views WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if isInvalidID(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("delete view: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanView(row pgx.Row) (*domain.View, error) {
+	var (
+		view   domain.View
+		filter []byte
+	)
+	if err := row.Scan(&view.ID, &view.OwnerID, &view.Name, &filter, &view.CreatedAt, &view.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filter, &view.Filter); err != nil {
+		return nil, fmt.Errorf("unmarshal filter: %w", err)
+	}
+	return &view, nil
+}
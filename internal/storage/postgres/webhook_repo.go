@@ -0,0 +1,333 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"todo-api/internal/domain"
+)
+
+type WebhookRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepo(pool *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{pool: pool}
+}
+
+const subscriptionColumns = `id, owner_id, url, event_types, COALESCE(list_id::text, ''), secret, active, created_at, updated_at`
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        INSERT INTO webhook_subscriptions (id, owner_id, url, event_types, list_id, secret, active)
+        VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6, $7)
+        RETURNING created_at, updated_at
+    `
+	err := r.pool.QueryRow(ctx, query, s.ID, s.OwnerID, s.URL, s.EventTypes, s.ListID, s.Secret, s.Active).
+		Scan(&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, ownerID, id string) (*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1 AND owner_id = $2`
+	s, err := scanSubscription(r.pool.QueryRow(ctx, query, id, ownerID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get webhook subscription: %w", err)
+	}
+	return s, nil
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+        SELECT `+subscriptionColumns+`
+        FROM webhook_subscriptions
+        WHERE owner_id = $1
+        ORDER BY created_at
+    `, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []*domain.WebhookSubscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook subscription: %w", err)
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+func (r *WebhookRepo) UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        UPDATE webhook_subscriptions
+        SET url = $3, event_types = $4, list_id = NULLIF($5, '')::uuid, secret = $6, active = $7, updated_at = NOW()
+        WHERE id = $1 AND owner_id = $2
+        RETURNING updated_at
+    `
+	err := r.pool.QueryRow(ctx, query, s.ID, s.OwnerID, s.URL, s.EventTypes, s.ListID, s.Secret, s.Active).Scan(&s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("update webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, ownerID, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue записывает событие и по доставке на каждую подходящую активную
+// подписку. Вызывается внутри транзакции изменения; если подписок нет,
+// ничего не записывается.
+func (r *WebhookRepo) Enqueue(ctx context.Context, eventType, listID string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        WITH subs AS (
+            SELECT id FROM webhook_subscriptions
+            WHERE active AND $1 = ANY(event_types)
+              AND (list_id IS NULL OR list_id = NULLIF($2, '')::uuid)
+        ), ev AS (
+            INSERT INTO webhook_events (event_type, list_id, payload)
+            SELECT $1, NULLIF($2, '')::uuid, $3
+            WHERE EXISTS (SELECT 1 FROM subs)
+            RETURNING id
+        )
+        INSERT INTO webhook_deliveries (subscription_id, event_id)
+        SELECT subs.id, ev.id FROM subs, ev
+    `
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, eventType, listID, payload); err != nil {
+		return fmt.Errorf("enqueue webhook event: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue захватывает до limit доставок, время которых наступило, сдвигая
+// их следующую попытку на lease вперёд. Если отправитель упадёт, не записав
+// результат, доставка снова станет доступной после lease. Несколько
+// экземпляров сервиса не захватят одну доставку благодаря SKIP LOCKED.
+func (r *WebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        WITH due AS (
+            SELECT d.id
+            FROM webhook_deliveries d
+            JOIN webhook_subscriptions s ON s.id = d.subscription_id AND s.active
+            WHERE d.state = 'pending' AND d.next_attempt_at <= NOW()
+            ORDER BY d.next_attempt_at
+            LIMIT $1
+            FOR UPDATE OF d SKIP LOCKED
+        )
+        UPDATE webhook_deliveries d
+        SET next_attempt_at = NOW() + make_interval(secs => $2), updated_at = NOW()
+        FROM due, webhook_subscriptions s, webhook_events e
+        WHERE d.id = due.id AND s.id = d.subscription_id AND e.id = d.event_id
+        RETURNING d.id, d.subscription_id, d.event_id, e.event_type, d.state, d.attempts,
+                  d.next_attempt_at, d.last_status_code, COALESCE(d.last_error, ''), d.delivered_at,
+                  d.created_at, e.payload, s.url, s.secret
+    `
+	rows, err := r.pool.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.State, &d.Attempts,
+			&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.DeliveredAt,
+			&d.CreatedAt, &d.Payload, &d.URL, &d.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		res = append(res, &d)
+	}
+	return res, rows.Err()
+}
+
+// CompleteAttempt записывает попытку в журнал и сохраняет состояние доставки,
+// рассчитанное отправителем, одним запросом.
+func (r *WebhookRepo) CompleteAttempt(ctx context.Context, d *domain.WebhookDelivery, a domain.WebhookAttempt) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        WITH attempt AS (
+            INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms)
+            VALUES ($1, $2, NULLIF($3, ''), $4)
+        )
+        UPDATE webhook_deliveries
+        SET state = $5, attempts = $6, next_attempt_at = $7, last_status_code = $2,
+            last_error = NULLIF($3, ''), delivered_at = $8, updated_at = NOW()
+        WHERE id = $1
+    `
+	_, err := r.pool.Exec(ctx, query, d.ID, a.StatusCode, a.Error, a.DurationMS,
+		d.State, d.Attempts, d.NextAttemptAt, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("complete webhook attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]*domain.WebhookDelivery, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var total int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = $1`, subscriptionID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count webhook deliveries: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+        SELECT `+deliveryColumns+`
+        FROM webhook_deliveries d
+        JOIN webhook_events e ON e.id = d.event_id
+        WHERE d.subscription_id = $1
+        ORDER BY d.id DESC
+        LIMIT $2 OFFSET $3
+    `, subscriptionID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	res := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		res = append(res, d)
+	}
+	return res, total, rows.Err()
+}
+
+// GetDelivery возвращает доставку вместе с телом события и журналом попыток.
+func (r *WebhookRepo) GetDelivery(ctx context.Context, subscriptionID string, id int64) (*domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+        SELECT ` + deliveryColumns + `, e.payload
+        FROM webhook_deliveries d
+        JOIN webhook_events e ON e.id = d.event_id
+        WHERE d.id = $1 AND d.subscription_id = $2
+    `
+	var d domain.WebhookDelivery
+	err := r.pool.QueryRow(ctx, query, id, subscriptionID).Scan(append(deliveryDest(&d), &d.Payload)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get webhook delivery: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+        SELECT status_code, COALESCE(error, ''), duration_ms, created_at
+        FROM webhook_delivery_attempts
+        WHERE delivery_id = $1
+        ORDER BY id
+    `, id)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook attempts: %w", err)
+	}
+	defer rows.Close()
+
+	d.Log = []domain.WebhookAttempt{}
+	for rows.Next() {
+		var a domain.WebhookAttempt
+		if err := rows.Scan(&a.StatusCode, &a.Error, &a.DurationMS, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan webhook attempt: %w", err)
+		}
+		d.Log = append(d.Log, a)
+	}
+	return &d, rows.Err()
+}
+
+// Redeliver ставит доставку в очередь заново с немедленной попыткой
+// и новым счётчиком попыток, в том числе из состояний succeeded и dead.
+func (r *WebhookRepo) Redeliver(ctx context.Context, subscriptionID string, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.pool.Exec(ctx, `
+        UPDATE webhook_deliveries
+        SET state = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+        WHERE id = $1 AND subscription_id = $2
+    `, id, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("redeliver webhook: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const deliveryColumns = `d.id, d.subscription_id, d.event_id, e.event_type, d.state, d.attempts,
+               d.next_attempt_at, d.last_status_code, COALESCE(d.last_error, ''), d.delivered_at, d.created_at`
+
+func deliveryDest(d *domain.WebhookDelivery) []any {
+	return []any{&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.State, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt}
+}
+
+func scanDelivery(row pgx.Row) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	if err := row.Scan(deliveryDest(&d)...); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func scanSubscription(row pgx.Row) (*domain.WebhookSubscription, error) {
+	var s domain.WebhookSubscription
+	err := row.Scan(&s.ID, &s.OwnerID, &s.URL, &s.EventTypes, &s.ListID, &s.Secret, &s.Active, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
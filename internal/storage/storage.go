@@ -2,23 +2,281 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"time"
 	"todo-api/internal/domain"
 )
 
+// ErrConflict — условная запись не выполнена: сущность изменилась или
+// появилась/исчезла после того, как было снято ожидаемое состояние.
+var ErrConflict = errors.New("conflict")
+
+// ErrNotFound — сущности нет или она не видна пользователю. Остальные
+// ошибки репозиториев означают сбой хранилища.
+var ErrNotFound = errors.New("not found")
+
+// Transactor выполняет fn в одной транзакции: все вызовы репозиториев
+// с переданным в fn контекстом либо фиксируются вместе, либо откатываются.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type ListRepository interface {
 	Create(ctx context.Context, list *domain.List) (*domain.List, error)
 	GetByID(ctx context.Context, id string) (*domain.List, error)
+	// Update сохраняет название, описание, архив и папку списка.
+	// ErrNotFound — нет списка или папки.
 	Update(ctx context.Context, list *domain.List) error
 	Delete(ctx context.Context, id string) error
 	GetAll(ctx context.Context) ([]*domain.List, int)
 	FindWithPagination(ctx context.Context, limit, offset int) ([]*domain.List, int)
 	SearchByTitle(ctx context.Context, query string) ([]domain.List, error)
+	Restore(ctx context.Context, list *domain.List, expectedVersion int64) error
+	DeleteVersion(ctx context.Context, id string, version int64) error
+	// Clone создаёт список clone с копиями задач списка srcID одной
+	// операцией и возвращает копии задач в порядке создания. ErrNotFound —
+	// исходного списка нет.
+	Clone(ctx context.Context, srcID string, clone *domain.List, opts domain.CloneOptions) ([]*domain.Task, error)
 }
 
 type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) error
+	// CreateMany вставляет задачи одной операцией; ErrNotFound — нет
+	// какого-то из списков.
+	CreateMany(ctx context.Context, tasks []*domain.Task) error
 	GetByID(ctx context.Context, id string) (*domain.Task, error)
 	ListByListID(ctx context.Context, listID string, limit, offset int) ([]*domain.Task, int, error)
+	ListByListIDs(ctx context.Context, listIDs []string, perList int) ([]*domain.Task, error)
+	CountByListIDs(ctx context.Context, listIDs []string) (map[string]domain.TaskCounts, error)
+	FindByQuery(ctx context.Context, query domain.TaskQuery, limit, offset int) ([]*domain.Task, int, error)
 	Update(ctx context.Context, task *domain.Task) error
 	Delete(ctx context.Context, id string) error
+	ListAllByListID(ctx context.Context, listID string) ([]*domain.Task, error)
+	Restore(ctx context.Context, task *domain.Task, expectedVersion int64) error
+	DeleteVersion(ctx context.Context, id string, version int64) error
+}
+
+type ViewRepository interface {
+	Create(ctx context.Context, view *domain.View) error
+	GetByID(ctx context.Context, ownerID, id string) (*domain.View, error)
+	ListByOwner(ctx context.Context, ownerID string) ([]*domain.View, error)
+	Update(ctx context.Context, view *domain.View) error
+	Delete(ctx context.Context, ownerID, id string) error
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, query string, limit, offset int) ([]domain.SearchHit, int, error)
+	FuzzySearch(ctx context.Context, query string, threshold float64, limit, offset int) ([]domain.SearchHit, int, error)
+	Suggest(ctx context.Context, query string, threshold float64, limit int) ([]domain.Suggestion, error)
+}
+
+type StatsRepository interface {
+	TaskStats(ctx context.Context, listID string, r domain.StatsRange) (*domain.TaskStats, error)
+}
+
+type AuditRepository interface {
+	Append(ctx context.Context, event *domain.AuditEvent) error
+	ListByList(ctx context.Context, listID string, limit, offset int) ([]*domain.AuditEvent, int, error)
+	ListByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*domain.AuditEvent, int, error)
+}
+
+type UndoRepository interface {
+	Create(ctx context.Context, entry *domain.UndoEntry) error
+	// GetForUpdate блокирует запись до конца транзакции.
+	GetForUpdate(ctx context.Context, token string) (*domain.UndoEntry, error)
+	MarkRedeemed(ctx context.Context, token string) error
+	// PurgeExpired удаляет записи с окном отмены, истёкшим до before.
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	GetSubscription(ctx context.Context, ownerID, id string) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, ownerID, id string) error
+
+	// Enqueue записывает событие в outbox; вызывается в транзакции изменения.
+	Enqueue(ctx context.Context, eventType, listID string, payload []byte) error
+	// ClaimDue захватывает доставки, время которых наступило, на время lease.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+	CompleteAttempt(ctx context.Context, d *domain.WebhookDelivery, a domain.WebhookAttempt) error
+	ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]*domain.WebhookDelivery, int, error)
+	GetDelivery(ctx context.Context, subscriptionID string, id int64) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID string, id int64) error
+}
+
+// CalendarRepository хранит токены календаря списков и ресурсы CalDAV.
+type CalendarRepository interface {
+	// SetToken заменяет токен списка; хранится только хеш.
+	SetToken(ctx context.Context, listID, tokenHash string) error
+	// ListIDByToken возвращает список токена или "", если токена нет.
+	ListIDByToken(ctx context.Context, tokenHash string) (string, error)
+	// Objects возвращает все задачи списка как ресурсы календаря.
+	Objects(ctx context.Context, listID string) ([]*domain.CalendarObject, error)
+	// Object находит ресурс по имени и блокирует задачу до конца
+	// транзакции; nil — ресурса нет.
+	Object(ctx context.Context, listID, name string) (*domain.CalendarObject, error)
+	// SaveObject запоминает имя ресурса и UID задачи, созданной клиентом.
+	SaveObject(ctx context.Context, listID string, o *domain.CalendarObject) error
+}
+
+// NotificationRepository хранит настройки уведомлений и outbox уведомлений.
+type NotificationRepository interface {
+	// Settings возвращает сохранённые настройки пользователя: в Preferences
+	// только явно заданные значения. nil — пользователь настроек не сохранял
+	// и не подписан на сводки.
+	Settings(ctx context.Context, userID string) (*domain.NotificationSettings, error)
+	// SaveSettings сохраняет адрес, язык, часовой пояс и заменяет Preferences.
+	SaveSettings(ctx context.Context, s *domain.NotificationSettings) error
+	// Subscribe подписывает пользователя на сводку списка; ErrNotFound — списка нет.
+	Subscribe(ctx context.Context, userID, listID string) error
+	Unsubscribe(ctx context.Context, userID, listID string) error
+	// DigestSubscribers возвращает пользователей с адресом и хотя бы одной
+	// подпиской на сводку.
+	DigestSubscribers(ctx context.Context) ([]*domain.NotificationSettings, error)
+	// OpenTasks возвращает до limit невыполненных задач списка: сначала
+	// с ближайшим сроком.
+	OpenTasks(ctx context.Context, listID string, limit int) ([]*domain.Task, error)
+	// DueBetween возвращает невыполненные задачи с исполнителем и сроком
+	// в полуинтервале [from, to).
+	DueBetween(ctx context.Context, from, to time.Time) ([]*domain.Task, error)
+
+	// Enqueue ставит уведомления в очередь. Уведомление с уже известными
+	// каналом и DedupKey пропускается.
+	Enqueue(ctx context.Context, ns []*domain.Notification) error
+	// ClaimDue захватывает до limit уведомлений, время отправки которых
+	// наступило, сдвигая следующую попытку на lease вперёд.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.Notification, error)
+	// Complete сохраняет состояние, число попыток и ошибку после отправки.
+	Complete(ctx context.Context, n *domain.Notification) error
+}
+
+// ReminderRepository хранит напоминания о задачах. Get, ListByTask и Delete
+// видят только напоминания пользователя userID; чужое — ErrNotFound.
+type ReminderRepository interface {
+	Create(ctx context.Context, r *domain.Reminder) error
+	Get(ctx context.Context, userID, id string) (*domain.Reminder, error)
+	ListByTask(ctx context.Context, userID, taskID string) ([]*domain.Reminder, error)
+	// Update сохраняет состояние и время, на которое напоминание отложено.
+	Update(ctx context.Context, r *domain.Reminder) error
+	Delete(ctx context.Context, userID, id string) error
+
+	// ClaimDue блокирует до limit напоминаний, время которых наступило
+	// к now, вместе с их задачами. Вызывается в транзакции: заблокированные
+	// напоминания другие экземпляры сервиса пропускают до её конца.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.Reminder, error)
+	// MarkFired переводит напоминания в состояние fired.
+	MarkFired(ctx context.Context, ids []string, at time.Time) error
+	// RecordFailure откладывает напоминание, которое не удалось доставить
+	// в at, с растущей паузой, чтобы оно не задерживало остальные.
+	RecordFailure(ctx context.Context, id string, at time.Time) error
+	// Publish отправляет событие в поток изменений списка после фиксации
+	// транзакции.
+	Publish(ctx context.Context, ev domain.ChangeEvent) error
+}
+
+// JobRepository хранит очередь фоновых задач.
+type JobRepository interface {
+	// Enqueue ставит задачу в очередь и заполняет ID и время создания.
+	// false — задача с тем же UniqueKey уже ждёт или выполняется.
+	Enqueue(ctx context.Context, j *domain.Job) (bool, error)
+	// ClaimDue захватывает до limit задач указанных видов, время которых
+	// наступило, а также брошенные задачи с истёкшим locked_until. Задача
+	// переходит в running, её attempts растёт, а скрыта она до now+visibility.
+	ClaimDue(ctx context.Context, kinds []string, limit int, visibility time.Duration) ([]*domain.Job, error)
+	// Complete сохраняет результат попытки. ErrConflict — задачу после
+	// истечения locked_until уже захватил другой обработчик.
+	Complete(ctx context.Context, j *domain.Job) error
+	Get(ctx context.Context, id int64) (*domain.Job, error)
+	// List возвращает задачи в порядке от новых к старым; пустые state
+	// и kind не фильтруют.
+	List(ctx context.Context, state, kind string, limit, offset int) ([]*domain.Job, int, error)
+	// Retry возвращает задачу в состоянии failed в очередь со сброшенными
+	// попытками. ErrConflict — задача не в состоянии failed.
+	Retry(ctx context.Context, id int64) (*domain.Job, error)
+	// Purge удаляет завершённые задачи, закончившиеся до before.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	// ClaimSchedule сдвигает время запуска расписания name на next, если
+	// оно наступило к now, и сообщает, сдвинуло ли. Новое расписание
+	// впервые срабатывает в next.
+	ClaimSchedule(ctx context.Context, name string, now, next time.Time) (bool, error)
+}
+
+// RuleRepository хранит правила автоматизации и журнал их выполнения.
+// Правила видны только владельцу; чужое — ErrNotFound.
+type RuleRepository interface {
+	Create(ctx context.Context, r *domain.Rule) error
+	Get(ctx context.Context, ownerID, id string) (*domain.Rule, error)
+	// ListByOwner возвращает правила владельца; listID "" — всех списков.
+	ListByOwner(ctx context.Context, ownerID, listID string) ([]*domain.Rule, error)
+	Update(ctx context.Context, r *domain.Rule) error
+	Delete(ctx context.Context, ownerID, id string) error
+	// ListEnabled возвращает включённые правила списка с одним из triggers.
+	ListEnabled(ctx context.Context, listID string, triggers []string) ([]*domain.Rule, error)
+	// GetEnabled возвращает правило для выполнения или nil без ошибки, если
+	// его удалили или выключили.
+	GetEnabled(ctx context.Context, id string) (*domain.Rule, error)
+	AppendExecution(ctx context.Context, e *domain.RuleExecution) error
+	// ListExecutions возвращает журнал правила от новых записей к старым.
+	ListExecutions(ctx context.Context, ruleID string, limit, offset int) ([]*domain.RuleExecution, int, error)
+}
+
+// TemplateRepository хранит шаблоны списков. Get и List видят шаблоны
+// пользователя и общие шаблоны остальных; Update и Delete — только свои,
+// чужие — ErrNotFound.
+type TemplateRepository interface {
+	Create(ctx context.Context, t *domain.Template) error
+	Get(ctx context.Context, userID, id string) (*domain.Template, error)
+	List(ctx context.Context, userID string) ([]*domain.Template, error)
+	Update(ctx context.Context, t *domain.Template) error
+	Delete(ctx context.Context, ownerID, id string) error
+}
+
+// FolderRepository хранит папки и принадлежность списков папкам.
+type FolderRepository interface {
+	// Create сохраняет папку; ErrNotFound — нет родительской папки.
+	Create(ctx context.Context, f *domain.Folder) error
+	Get(ctx context.Context, id string) (*domain.Folder, error)
+	List(ctx context.Context) ([]*domain.Folder, error)
+	// Rename меняет название папки, Move — её родителя; остальные поля
+	// не перезаписываются. ErrNotFound — нет папки или нового родителя.
+	Rename(ctx context.Context, id, name string) (*domain.Folder, error)
+	Move(ctx context.Context, id string, parentID *string) (*domain.Folder, error)
+	// Delete удаляет папку, а её вложенные папки переносит в родительскую.
+	// Списки из папки нужно перенести до удаления, см. ListIDs.
+	Delete(ctx context.Context, id string) error
+	// LockTree блокирует изменения дерева папок до конца транзакции.
+	LockTree(ctx context.Context) error
+	// Ancestors возвращает id папки и всех её предков, от неё к верхнему
+	// уровню; число элементов — глубина папки. ErrNotFound — папки нет.
+	Ancestors(ctx context.Context, id string) ([]string, error)
+	// Height возвращает число уровней поддерева папки, включая её саму.
+	Height(ctx context.Context, id string) (int, error)
+	// ListIDs возвращает id списков, лежащих непосредственно в папке, и
+	// блокирует папку до конца транзакции. ErrNotFound — папки нет.
+	ListIDs(ctx context.Context, folderID string) ([]string, error)
+	// Lists возвращает списки, лежащие непосредственно в папке.
+	Lists(ctx context.Context, folderID string, limit, offset int) ([]*domain.ListNode, int, error)
+	// Tree возвращает все папки с глубиной и числом невыполненных задач в
+	// поддереве, а также все списки с числом невыполненных задач.
+	Tree(ctx context.Context) ([]*domain.FolderNode, []*domain.ListNode, error)
+}
+
+// ExportRepository читает списки с задачами для выгрузки потоком, не
+// загружая их в память целиком.
+type ExportRepository interface {
+	// Stream вызывает fn для каждой задачи (list, task) в порядке списков,
+	// а для списка без задач — один раз с task == nil. listID "" — все списки.
+	Stream(ctx context.Context, listID string, fn func(list *domain.List, task *domain.Task) error) error
+}
+
+// SyncRepository хранит поток изменений для офлайн-синхронизации.
+type SyncRepository interface {
+	Changes(ctx context.Context, cur domain.SyncCursor, limit int) (*domain.SyncPage, error)
+	// LockList и LockTask блокируют сущность до конца транзакции; nil — сущности нет.
+	LockList(ctx context.Context, id string) (*domain.List, domain.FieldClock, error)
+	LockTask(ctx context.Context, id string) (*domain.Task, domain.FieldClock, error)
+	DeletedAt(ctx context.Context, id string) (*time.Time, error)
+	SetFieldClock(ctx context.Context, entityType, id string, clock domain.FieldClock) error
 }
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+DROP INDEX IF EXISTS idx_lists_search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE lists DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,20 @@
+-- Полнотекстовый поиск по спискам и задачам.
+-- Каждый документ индексируется сразу двумя конфигурациями (russian и english),
+-- чтобы стемминг работал для текста на обоих языках.
+ALTER TABLE lists ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE tasks ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('russian', coalesce(text, '')) ||
+    to_tsvector('english', coalesce(text, ''))
+) STORED;
+
+CREATE INDEX idx_lists_search_vector ON lists USING GIN (search_vector);
+CREATE INDEX idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+COMMENT ON COLUMN lists.search_vector IS 'Поисковый вектор по title (вес A) и description (вес B)';
+COMMENT ON COLUMN tasks.search_vector IS 'Поисковый вектор по тексту задачи';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_text_trgm;
+DROP INDEX IF EXISTS idx_lists_title_trgm;
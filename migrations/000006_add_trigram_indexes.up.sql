@@ -0,0 +1,5 @@
+-- Нечёткий поиск по триграммам (опечатки в названиях списков и тексте задач)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_lists_title_trgm ON lists USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_tasks_text_trgm ON tasks USING GIN (text gin_trgm_ops);
@@ -0,0 +1,22 @@
+-- Срок выполнения задачи (нужен для представлений «Сегодня» и «Предстоящие»)
+ALTER TABLE tasks ADD COLUMN due_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_tasks_due_at ON tasks(due_at) WHERE due_at IS NOT NULL;
+
+COMMENT ON COLUMN tasks.due_at IS 'Срок выполнения задачи (необязательное поле)';
+
+-- Сохранённые фильтры (умные списки) пользователей
+CREATE TABLE IF NOT EXISTS views (
+    id UUID PRIMARY KEY,
+    owner_id VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL CHECK (length(name) >= 1 AND length(name) <= 100),
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_views_owner_id ON views(owner_id, created_at);
+
+COMMENT ON TABLE views IS 'Сохранённые фильтры задач (умные списки)';
+COMMENT ON COLUMN views.owner_id IS 'Идентификатор пользователя-владельца';
+COMMENT ON COLUMN views.filter IS 'Определение фильтра (domain.TaskFilter)';
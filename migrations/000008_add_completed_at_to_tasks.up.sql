@@ -0,0 +1,9 @@
+-- Время выполнения задачи (для статистики и скорости выполнения)
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE;
+
+-- Для уже выполненных задач лучшее приближение — время последнего изменения
+UPDATE tasks SET completed_at = updated_at WHERE completed;
+
+CREATE INDEX idx_tasks_completed_at ON tasks(completed_at) WHERE completed_at IS NOT NULL;
+
+COMMENT ON COLUMN tasks.completed_at IS 'Когда задача была отмечена выполненной';
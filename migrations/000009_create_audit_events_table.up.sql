@@ -0,0 +1,33 @@
+-- Журнал изменений списков и задач (только добавление)
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(100) NOT NULL,
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    list_id UUID,
+    action VARCHAR(20) NOT NULL,
+    before JSONB,
+    after JSONB,
+    diff JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- list_id намеренно без внешнего ключа: история удалённого списка должна сохраняться
+CREATE INDEX idx_audit_events_list_id ON audit_events(list_id, id DESC);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id, id DESC);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+COMMENT ON TABLE audit_events IS 'Журнал всех изменений списков и задач';
+COMMENT ON COLUMN audit_events.actor IS 'Пользователь, выполнивший изменение (X-User-Id)';
+COMMENT ON COLUMN audit_events.request_id IS 'Идентификатор HTTP-запроса (X-Request-Id)';
+COMMENT ON COLUMN audit_events.diff IS 'Изменённые поля: {"поле": {"before": ..., "after": ...}}';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS undo_entries;
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
+ALTER TABLE lists DROP COLUMN IF EXISTS version;
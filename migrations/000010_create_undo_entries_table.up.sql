@@ -0,0 +1,23 @@
+-- Версии строк: по ним отмена проверяет, что сущность не менялась после операции
+ALTER TABLE lists ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- Операции, которые можно отменить в течение окна отмены
+CREATE TABLE IF NOT EXISTS undo_entries (
+    token UUID PRIMARY KEY,
+    actor VARCHAR(100) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    before JSONB NOT NULL,
+    after JSONB NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    redeemed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_undo_entries_expires_at ON undo_entries(expires_at);
+
+COMMENT ON COLUMN lists.version IS 'Увеличивается при каждом изменении списка';
+COMMENT ON COLUMN tasks.version IS 'Увеличивается при каждом изменении задачи';
+COMMENT ON TABLE undo_entries IS 'Токены отмены изменений списков и задач';
+COMMENT ON COLUMN undo_entries.before IS 'Состояние затронутых сущностей до операции: {"lists": [...], "tasks": [...]}';
+COMMENT ON COLUMN undo_entries.after IS 'Состояние затронутых сущностей после операции';
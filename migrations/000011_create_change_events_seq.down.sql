@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS change_events_seq;
@@ -0,0 +1,2 @@
+-- Номера событий об изменениях (NOTIFY todo_changes), используются как Last-Event-ID
+CREATE SEQUENCE IF NOT EXISTS change_events_seq;
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS trg_tasks_sync_tombstone ON tasks;
+DROP TRIGGER IF EXISTS trg_lists_sync_tombstone ON lists;
+DROP TRIGGER IF EXISTS trg_tasks_sync_stamp ON tasks;
+DROP TRIGGER IF EXISTS trg_lists_sync_stamp ON lists;
+DROP FUNCTION IF EXISTS sync_tombstone();
+DROP FUNCTION IF EXISTS sync_stamp();
+DROP TABLE IF EXISTS sync_tombstones;
+ALTER TABLE tasks DROP COLUMN IF EXISTS field_clock, DROP COLUMN IF EXISTS change_xid, DROP COLUMN IF EXISTS change_seq;
+ALTER TABLE lists DROP COLUMN IF EXISTS field_clock, DROP COLUMN IF EXISTS change_xid, DROP COLUMN IF EXISTS change_seq;
+DROP SEQUENCE IF EXISTS sync_seq;
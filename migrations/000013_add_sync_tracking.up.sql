@@ -0,0 +1,90 @@
+-- Последовательность изменений для дельта-синхронизации
+CREATE SEQUENCE IF NOT EXISTS sync_seq;
+
+-- change_seq упорядочивает изменения, change_xid — транзакция последнего
+-- изменения: токен синхронизации хранит границу по транзакциям, поэтому
+-- изменения ещё не зафиксированных транзакций не теряются.
+-- field_clock — время последнего изменения каждого поля для last-writer-wins.
+ALTER TABLE lists
+    ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT nextval('sync_seq'),
+    ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT pg_current_xact_id(),
+    ADD COLUMN IF NOT EXISTS field_clock JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT nextval('sync_seq'),
+    ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT pg_current_xact_id(),
+    ADD COLUMN IF NOT EXISTS field_clock JSONB NOT NULL DEFAULT '{}';
+
+-- Существующим строкам время изменения полей берётся из известных меток
+UPDATE lists SET field_clock = jsonb_build_object('title', created_at, 'description', created_at);
+UPDATE tasks SET field_clock = jsonb_build_object(
+    'text', updated_at AT TIME ZONE 'UTC',
+    'completed', updated_at AT TIME ZONE 'UTC',
+    'due_at', updated_at AT TIME ZONE 'UTC');
+
+CREATE INDEX idx_lists_change_xid ON lists(change_xid, change_seq);
+CREATE INDEX idx_tasks_change_xid ON tasks(change_xid, change_seq);
+
+-- Надгробия удалённых списков и задач
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    entity_id UUID PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL,
+    list_id UUID NOT NULL,
+    change_seq BIGINT NOT NULL DEFAULT nextval('sync_seq'),
+    change_xid xid8 NOT NULL DEFAULT pg_current_xact_id(),
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sync_tombstones_change_xid ON sync_tombstones(change_xid, change_seq);
+
+-- Аргументы триггера — синхронизируемые поля. Если запрос не задал
+-- field_clock сам (синхронизация задаёт), изменённым полям ставится текущее время.
+CREATE OR REPLACE FUNCTION sync_stamp() RETURNS trigger AS $$
+BEGIN
+    NEW.change_seq := nextval('sync_seq');
+    NEW.change_xid := pg_current_xact_id();
+
+    IF TG_OP = 'INSERT' THEN
+        DELETE FROM sync_tombstones WHERE entity_id = NEW.id;
+        IF NEW.field_clock = '{}' THEN
+            NEW.field_clock := (
+                SELECT jsonb_object_agg(f, to_jsonb(clock_timestamp()))
+                FROM unnest(TG_ARGV) AS f
+            );
+        END IF;
+    ELSIF NEW.field_clock = OLD.field_clock THEN
+        NEW.field_clock := OLD.field_clock || COALESCE((
+            SELECT jsonb_object_agg(f, to_jsonb(clock_timestamp()))
+            FROM unnest(TG_ARGV) AS f
+            WHERE to_jsonb(NEW) -> f IS DISTINCT FROM to_jsonb(OLD) -> f
+        ), '{}');
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION sync_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity_id, entity_type, list_id)
+    VALUES (OLD.id, TG_ARGV[0], COALESCE((to_jsonb(OLD) ->> 'list_id')::uuid, OLD.id))
+    ON CONFLICT (entity_id) DO UPDATE
+    SET change_seq = nextval('sync_seq'), change_xid = pg_current_xact_id(), deleted_at = NOW();
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_lists_sync_stamp
+    BEFORE INSERT OR UPDATE ON lists
+    FOR EACH ROW EXECUTE FUNCTION sync_stamp('title', 'description');
+CREATE TRIGGER trg_tasks_sync_stamp
+    BEFORE INSERT OR UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION sync_stamp('text', 'completed', 'due_at');
+CREATE TRIGGER trg_lists_sync_tombstone
+    AFTER DELETE ON lists
+    FOR EACH ROW EXECUTE FUNCTION sync_tombstone('list');
+CREATE TRIGGER trg_tasks_sync_tombstone
+    AFTER DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION sync_tombstone('task');
+
+COMMENT ON TABLE sync_tombstones IS 'Удалённые списки и задачи для дельта-синхронизации';
+COMMENT ON COLUMN lists.field_clock IS 'Время последнего изменения полей: {"title": "...", "description": "..."}';
+COMMENT ON COLUMN tasks.field_clock IS 'Время последнего изменения полей: {"text": "...", "completed": "...", "due_at": "..."}';
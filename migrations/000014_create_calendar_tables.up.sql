@@ -0,0 +1,19 @@
+-- Токены календаря: один на список, хранится SHA-256 токена
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+    list_id UUID PRIMARY KEY REFERENCES lists(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Задачи, созданные клиентами CalDAV: клиент сам выбирает имя ресурса и UID.
+-- У остальных задач имя ресурса — <id>.ics, UID — id задачи.
+CREATE TABLE IF NOT EXISTS calendar_objects (
+    task_id UUID PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    uid VARCHAR(255) NOT NULL,
+    UNIQUE (list_id, name)
+);
+
+COMMENT ON TABLE calendar_tokens IS 'Токены доступа к ленте iCalendar и CalDAV списка';
+COMMENT ON TABLE calendar_objects IS 'Имена ресурсов и UID задач, созданных через CalDAV';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS digest_subscriptions;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notification_settings;
+DROP INDEX IF EXISTS idx_tasks_assignee_due;
+ALTER TABLE tasks DROP COLUMN IF EXISTS assignee;
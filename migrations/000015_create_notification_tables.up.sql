@@ -0,0 +1,57 @@
+-- Исполнитель задачи: идентификатор пользователя, пустая строка — не назначен
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee_due ON tasks(due_at)
+    WHERE assignee <> '' AND NOT completed AND due_at IS NOT NULL;
+
+-- Настройки уведомлений пользователя
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id VARCHAR(100) PRIMARY KEY,
+    email VARCHAR(254) NOT NULL DEFAULT '',
+    locale VARCHAR(5) NOT NULL DEFAULT 'en',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Отключённые и включённые вручную пары «событие — канал»; для остальных
+-- действует значение по умолчанию
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id VARCHAR(100) NOT NULL,
+    event VARCHAR(30) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event, channel)
+);
+
+-- Подписки на ежедневную сводку открытых задач списка
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    user_id VARCHAR(100) NOT NULL,
+    list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, list_id)
+);
+
+-- Outbox уведомлений: записываются в транзакции изменения или планировщиком,
+-- отправляются отдельно
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    event VARCHAR(30) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    payload JSONB NOT NULL,
+    dedup_key VARCHAR(200),
+    state VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_notifications_dedup ON notifications(channel, dedup_key);
+CREATE INDEX idx_notifications_due ON notifications(next_attempt_at) WHERE state = 'pending';
+
+COMMENT ON COLUMN notification_settings.locale IS 'Язык писем: ru или en';
+COMMENT ON COLUMN notification_settings.timezone IS 'Часовой пояс IANA: в нём показываются сроки и наступает время сводки';
+COMMENT ON COLUMN notifications.dedup_key IS 'Ключ, по которому планировщик не ставит уведомление повторно';
+COMMENT ON COLUMN notifications.state IS 'pending, sent, skipped (канал отключён или нет адреса) или dead';
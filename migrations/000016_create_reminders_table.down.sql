@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_tasks_reminders_fire_at ON tasks;
+DROP FUNCTION IF EXISTS tasks_reminders_fire_at();
+DROP TABLE IF EXISTS reminders;
+DROP FUNCTION IF EXISTS reminders_fire_at();
@@ -0,0 +1,58 @@
+-- Напоминания о задачах: на конкретное время или относительно срока задачи
+CREATE TABLE IF NOT EXISTS reminders (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(100) NOT NULL,
+    remind_at TIMESTAMP WITH TIME ZONE,
+    due_offset_minutes INT,
+    channels TEXT[] NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'pending',
+    snoozed_until TIMESTAMP WITH TIME ZONE,
+    fire_at TIMESTAMP WITH TIME ZONE,
+    delivery_attempts INT NOT NULL DEFAULT 0,
+    fired_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT reminders_time_check CHECK ((remind_at IS NULL) <> (due_offset_minutes IS NULL))
+);
+
+CREATE INDEX idx_reminders_task_user ON reminders(task_id, user_id);
+-- Время срабатывания хранится в самих напоминаниях, чтобы выборка
+-- наступивших шла по индексу.
+CREATE INDEX idx_reminders_pending ON reminders(fire_at) WHERE state = 'pending';
+
+-- Новое время срабатывания сбрасывает счётчик неудачных доставок.
+CREATE OR REPLACE FUNCTION reminders_fire_at() RETURNS trigger AS $$
+BEGIN
+    NEW.fire_at := COALESCE(NEW.snoozed_until, NEW.remind_at,
+        (SELECT due_at FROM tasks WHERE id = NEW.task_id) + make_interval(mins => NEW.due_offset_minutes));
+    IF TG_OP = 'INSERT' OR NEW.fire_at IS DISTINCT FROM OLD.fire_at THEN
+        NEW.delivery_attempts := 0;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Относительные напоминания следуют за сроком задачи.
+CREATE OR REPLACE FUNCTION tasks_reminders_fire_at() RETURNS trigger AS $$
+BEGIN
+    UPDATE reminders
+    SET fire_at = NEW.due_at + make_interval(mins => due_offset_minutes), delivery_attempts = 0
+    WHERE task_id = NEW.id AND due_offset_minutes IS NOT NULL AND snoozed_until IS NULL;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_reminders_fire_at
+    BEFORE INSERT OR UPDATE OF remind_at, due_offset_minutes, snoozed_until ON reminders
+    FOR EACH ROW EXECUTE FUNCTION reminders_fire_at();
+CREATE TRIGGER trg_tasks_reminders_fire_at
+    AFTER UPDATE OF due_at ON tasks
+    FOR EACH ROW WHEN (OLD.due_at IS DISTINCT FROM NEW.due_at)
+    EXECUTE FUNCTION tasks_reminders_fire_at();
+
+COMMENT ON COLUMN reminders.due_offset_minutes IS 'Смещение от срока задачи в минутах, отрицательное — до срока';
+COMMENT ON COLUMN reminders.state IS 'pending, fired или dismissed';
+COMMENT ON COLUMN reminders.snoozed_until IS 'Время, на которое напоминание отложено; важнее remind_at и срока';
+COMMENT ON COLUMN reminders.fire_at IS 'Время срабатывания: snoozed_until, remind_at или срок задачи со смещением; после неудачной доставки — время повтора';
+COMMENT ON COLUMN reminders.delivery_attempts IS 'Неудачные доставки подряд; определяют паузу до повтора';
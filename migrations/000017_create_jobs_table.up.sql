@@ -0,0 +1,32 @@
+-- Очередь фоновых задач
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(100) NOT NULL,
+    args JSONB NOT NULL DEFAULT '{}',
+    state VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE,
+    unique_key VARCHAR(200),
+    last_error TEXT,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jobs_due ON jobs(run_at) WHERE state = 'pending';
+CREATE INDEX idx_jobs_locked ON jobs(locked_until) WHERE state = 'running';
+CREATE INDEX idx_jobs_state_kind ON jobs(state, kind, created_at);
+-- Уникальная задача может стоять в очереди или выполняться только одна
+CREATE UNIQUE INDEX idx_jobs_unique ON jobs(unique_key) WHERE state IN ('pending', 'running');
+
+-- Расписания периодических задач: время следующего запуска общее для всех
+-- экземпляров сервиса
+CREATE TABLE IF NOT EXISTS job_schedules (
+    name VARCHAR(100) PRIMARY KEY,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON COLUMN jobs.state IS 'pending, running, completed или failed (попытки исчерпаны)';
+COMMENT ON COLUMN jobs.locked_until IS 'До какого времени задача скрыта от других обработчиков; после — считается брошенной';
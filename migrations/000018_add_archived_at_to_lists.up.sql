@@ -0,0 +1,4 @@
+-- Архивирование списка (вручную или правилом автоматизации)
+ALTER TABLE lists ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN lists.archived_at IS 'Когда список отправлен в архив; NULL — список активен';
@@ -0,0 +1,36 @@
+-- Правила автоматизации списков: триггер → условия → действия
+CREATE TABLE IF NOT EXISTS rules (
+    id UUID PRIMARY KEY,
+    owner_id VARCHAR(100) NOT NULL,
+    list_id UUID NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    trigger VARCHAR(50) NOT NULL,
+    conditions JSONB NOT NULL DEFAULT '[]',
+    actions JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_rules_owner_id ON rules(owner_id);
+CREATE INDEX idx_rules_list_trigger ON rules(list_id, trigger) WHERE enabled;
+
+-- Журнал выполнения правил
+CREATE TABLE IF NOT EXISTS rule_executions (
+    id BIGSERIAL PRIMARY KEY,
+    rule_id UUID NOT NULL REFERENCES rules(id) ON DELETE CASCADE,
+    trigger VARCHAR(50) NOT NULL,
+    list_id UUID NOT NULL,
+    task_id UUID,
+    status VARCHAR(20) NOT NULL,
+    actions JSONB NOT NULL DEFAULT '[]',
+    error TEXT,
+    depth INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_rule_executions_rule ON rule_executions(rule_id, id DESC);
+
+COMMENT ON COLUMN rules.trigger IS 'task.created, task.updated, task.completed или list.completed';
+COMMENT ON COLUMN rule_executions.status IS 'applied, failed или loop_prevented';
+COMMENT ON COLUMN rule_executions.depth IS 'Сколько правил в цепочке привело к этому выполнению';
@@ -0,0 +1,3 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence;
+ALTER TABLE tasks DROP COLUMN IF EXISTS priority;
+ALTER TABLE tasks DROP COLUMN IF EXISTS labels;
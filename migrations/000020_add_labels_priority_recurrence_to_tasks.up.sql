@@ -0,0 +1,7 @@
+-- Метки, приоритет и повторение задачи; задаются в том числе быстрым
+-- добавлением. Пустые значения — не заданы
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT ''
+    CHECK (priority IN ('', 'low', 'medium', 'high'));
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence VARCHAR(20) NOT NULL DEFAULT ''
+    CHECK (recurrence IN ('', 'daily', 'weekly', 'monthly', 'yearly'));
@@ -0,0 +1,18 @@
+-- Шаблоны списков: задачи хранятся со сроками относительно даты начала
+CREATE TABLE IF NOT EXISTS templates (
+    id UUID PRIMARY KEY,
+    owner_id VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL CHECK (length(name) >= 1 AND length(name) <= 100),
+    title VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    tasks JSONB NOT NULL DEFAULT '[]',
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_templates_owner_id ON templates(owner_id, created_at);
+CREATE INDEX idx_templates_shared ON templates(created_at) WHERE shared;
+
+COMMENT ON COLUMN templates.tasks IS 'Задачи шаблона (domain.TemplateTask)';
+COMMENT ON COLUMN templates.shared IS 'Шаблон виден всем пользователям';
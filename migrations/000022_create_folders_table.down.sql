@@ -0,0 +1,2 @@
+ALTER TABLE lists DROP COLUMN IF EXISTS folder_id;
+DROP TABLE IF EXISTS folders;
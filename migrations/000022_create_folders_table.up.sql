@@ -0,0 +1,18 @@
+-- Папки для группировки списков; вложенность ограничивает сервис
+CREATE TABLE IF NOT EXISTS folders (
+    id UUID PRIMARY KEY,
+    parent_id UUID REFERENCES folders(id),
+    name VARCHAR(100) NOT NULL CHECK (length(name) >= 1 AND length(name) <= 100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX idx_folders_parent_id ON folders(parent_id);
+
+ALTER TABLE lists ADD COLUMN folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_lists_folder_id ON lists(folder_id);
+
+COMMENT ON COLUMN folders.parent_id IS 'Родительская папка; NULL — папка верхнего уровня';
+COMMENT ON COLUMN lists.folder_id IS 'Папка списка; NULL — список вне папок';